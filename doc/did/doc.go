@@ -66,6 +66,8 @@ const (
 	jsonldPublicKeyPem       = "publicKeyPem"
 	jsonldPublicKeyjwk       = "publicKeyJwk"
 
+	jsonldBlockchainAccountID = "blockchainAccountId"
+
 	// service type that needed for v011 did-doc resolution.
 	legacyServiceType = "IndyAgent"
 )
@@ -179,9 +181,70 @@ type Context interface{}
 
 // DocResolution did resolution.
 type DocResolution struct {
-	Context          Context
-	DIDDocument      *Doc
-	DocumentMetadata *DocumentMetadata
+	Context            Context
+	DIDDocument        *Doc
+	DocumentMetadata   *DocumentMetadata
+	ResolutionMetadata *ResolutionMetadata
+}
+
+const (
+	// ContentTypeDIDLDJSON is the JSON-LD representation media type of a DID document.
+	ContentTypeDIDLDJSON = "application/did+ld+json"
+	// ContentTypeDIDJSON is the JSON representation media type of a DID document.
+	ContentTypeDIDJSON = "application/did+json"
+	// ContentTypeDIDResolution is the media type of a DID resolution result.
+	ContentTypeDIDResolution = `application/ld+json;profile="https://w3id.org/did-resolution"`
+	// ContentTypeURIList is the media type of a dereferenced service endpoint URL.
+	ContentTypeURIList = "text/uri-list"
+)
+
+// DID resolution error codes (https://www.w3.org/TR/did-spec-registries/#error).
+const (
+	// ResolutionErrorInvalidDID the DID supplied to the resolution function does not conform to valid syntax.
+	ResolutionErrorInvalidDID = "invalidDid"
+	// ResolutionErrorInvalidDIDURL the DID URL supplied to the dereferencing function is not valid.
+	ResolutionErrorInvalidDIDURL = "invalidDidUrl"
+	// ResolutionErrorNotFound the DID resolver was unable to find the DID document.
+	ResolutionErrorNotFound = "notFound"
+	// ResolutionErrorRepresentationNotSupported the requested representation is not supported.
+	ResolutionErrorRepresentationNotSupported = "representationNotSupported"
+	// ResolutionErrorMethodNotSupported the DID method is not supported by the resolver.
+	ResolutionErrorMethodNotSupported = "methodNotSupported"
+	// ResolutionErrorInvalidPublicKeyType the public key of the DID or the requested public key format is not valid.
+	ResolutionErrorInvalidPublicKeyType = "invalidPublicKeyType"
+	// ResolutionErrorInternal an unexpected error occurred during resolution.
+	ResolutionErrorInternal = "internalError"
+)
+
+// ResolutionMetadata did resolution metadata.
+type ResolutionMetadata struct {
+	// ContentType is the media type of the returned DID document representation.
+	ContentType string `json:"contentType,omitempty"`
+	// Error is the error code of a failed resolution.
+	Error string `json:"error,omitempty"`
+	// ErrorMessage is a human-readable description of the error.
+	ErrorMessage string `json:"errorMessage,omitempty"`
+	// Duration is the resolution duration in milliseconds.
+	Duration int64 `json:"duration,omitempty"`
+}
+
+// DereferencingResult did url dereferencing result.
+type DereferencingResult struct {
+	// ContentStream is the dereferenced resource, one of *Doc, *VerificationMethod, *Service or
+	// the service endpoint URL string selected through the service query parameter.
+	ContentStream interface{}
+	// ContentMetadata is the document metadata of the DID document the resource was dereferenced from.
+	ContentMetadata *DocumentMetadata
+	// DereferencingMetadata holds the metadata of the dereferencing process.
+	DereferencingMetadata *DereferencingMetadata
+}
+
+// DereferencingMetadata did url dereferencing metadata.
+type DereferencingMetadata struct {
+	// ContentType is the media type of the content stream.
+	ContentType string `json:"contentType,omitempty"`
+	// Error is the error code of a failed dereferencing.
+	Error string `json:"error,omitempty"`
 }
 
 // MethodMetadata method metadata.
@@ -224,6 +287,10 @@ type ProtocolOperation struct {
 type DocumentMetadata struct {
 	// VersionID is version ID key.
 	VersionID string `json:"versionId,omitempty"`
+	// Created is the time the DID was created.
+	Created string `json:"created,omitempty"`
+	// Updated is the time the resolved version of the DID document was created.
+	Updated string `json:"updated,omitempty"`
 	// Deactivated is deactivated flag key.
 	Deactivated bool `json:"deactivated,omitempty"`
 	// CanonicalID is canonical ID key.
@@ -235,9 +302,10 @@ type DocumentMetadata struct {
 }
 
 type rawDocResolution struct {
-	Context          Context         `json:"@context"`
-	DIDDocument      json.RawMessage `json:"didDocument,omitempty"`
-	DocumentMetadata json.RawMessage `json:"didDocumentMetadata,omitempty"`
+	Context            Context             `json:"@context"`
+	DIDDocument        json.RawMessage     `json:"didDocument,omitempty"`
+	DocumentMetadata   json.RawMessage     `json:"didDocumentMetadata,omitempty"`
+	ResolutionMetadata *ResolutionMetadata `json:"didResolutionMetadata,omitempty"`
 }
 
 // ParseDocumentResolution parse document resolution.
@@ -267,7 +335,12 @@ func ParseDocumentResolution(data []byte) (*DocResolution, error) {
 
 	context, _ := parseContext(raw.Context)
 
-	return &DocResolution{Context: context, DIDDocument: doc, DocumentMetadata: docMeta}, nil
+	return &DocResolution{
+		Context:            context,
+		DIDDocument:        doc,
+		DocumentMetadata:   docMeta,
+		ResolutionMetadata: raw.ResolutionMetadata,
+	}, nil
 }
 
 // Doc DID Document definition.
@@ -297,6 +370,7 @@ type processingMeta struct {
 // VerificationMethod DID doc verification method.
 // The value of the verification method is defined either as raw public key bytes (Value field) or as JSON Web Key.
 // In the first case the Type field can hold additional information to understand the nature of the raw public key.
+// Verification methods of blockchain accounts may hold a CAIP-10 BlockchainAccountID instead of a public key.
 type VerificationMethod struct {
 	ID         string
 	Type       string
@@ -304,6 +378,8 @@ type VerificationMethod struct {
 
 	Value []byte
 
+	BlockchainAccountID string
+
 	jsonWebKey        *jwk.JWK
 	relativeURL       bool
 	multibaseEncoding multibase.Encoding
@@ -335,7 +411,7 @@ func NewVerificationMethodFromBytes(id, keyType, controller string, value []byte
 		relativeURL = true
 	}
 
-	if keyType == "Ed25519VerificationKey2020" {
+	if keyType == "Ed25519VerificationKey2020" || keyType == "Multikey" {
 		return NewVerificationMethodFromBytesWithMultibase(id, keyType, controller, value, multibase.Base58BTC)
 	}
 
@@ -370,6 +446,18 @@ func NewVerificationMethodFromJWK(id, keyType, controller string, j *jwk.JWK) (*
 	}, nil
 }
 
+// NewVerificationMethodFromBlockchainAccountID creates a new VerificationMethod based on CAIP-10 blockchain
+// account ID.
+func NewVerificationMethodFromBlockchainAccountID(id, keyType, controller, accountID string) *VerificationMethod {
+	return &VerificationMethod{
+		ID:                  id,
+		Type:                keyType,
+		Controller:          controller,
+		BlockchainAccountID: accountID,
+		relativeURL:         strings.HasPrefix(id, "#"),
+	}
+}
+
 // JSONWebKey returns JSON Web key if defined.
 func (pk *VerificationMethod) JSONWebKey() *jwk.JWK {
 	return pk.jsonWebKey
@@ -951,7 +1039,9 @@ func populateVerificationMethod(context, didID, baseURI string,
 	return verificationMethods, nil
 }
 
-func decodeVM(vm *VerificationMethod, rawPK map[string]interface{}) error {
+func decodeVM(vm *VerificationMethod, rawPK map[string]interface{}) error { //nolint:gocyclo
+	vm.BlockchainAccountID = stringEntry(rawPK[jsonldBlockchainAccountID])
+
 	if stringEntry(rawPK[jsonldPublicKeyBase58]) != "" {
 		vm.Value = base58.Decode(stringEntry(rawPK[jsonldPublicKeyBase58]))
 		return nil
@@ -995,6 +1085,10 @@ func decodeVM(vm *VerificationMethod, rawPK map[string]interface{}) error {
 		return decodeVMJwk(jwkMap, vm)
 	}
 
+	if vm.BlockchainAccountID != "" {
+		return nil
+	}
+
 	return errors.New("public key encoding not supported")
 }
 
@@ -1160,9 +1254,10 @@ func (docResolution *DocResolution) JSONBytes() ([]byte, error) {
 	}
 
 	raw := &rawDocResolution{
-		Context:          docResolution.Context,
-		DIDDocument:      didBytes,
-		DocumentMetadata: documentMetadataBytes,
+		Context:            docResolution.Context,
+		DIDDocument:        didBytes,
+		DocumentMetadata:   documentMetadataBytes,
+		ResolutionMetadata: docResolution.ResolutionMetadata,
 	}
 
 	byteDoc, err := json.Marshal(raw)
@@ -1546,7 +1641,7 @@ func populateRawVerificationMethod(context, didID, baseURI string,
 		}
 
 		rawVM[jsonldPublicKeyjwk] = json.RawMessage(jwkBytes)
-	} else if vm.Type == "Ed25519VerificationKey2020" {
+	} else if vm.Type == "Ed25519VerificationKey2020" || vm.Type == "Multikey" {
 		var err error
 
 		rawVM[jsonldPublicKeyMultibase], err = multibase.Encode(vm.multibaseEncoding, vm.Value)
@@ -1557,6 +1652,10 @@ func populateRawVerificationMethod(context, didID, baseURI string,
 		rawVM[jsonldPublicKeyBase58] = base58.Encode(vm.Value)
 	}
 
+	if vm.BlockchainAccountID != "" {
+		rawVM[jsonldBlockchainAccountID] = vm.BlockchainAccountID
+	}
+
 	return rawVM, nil
 }
 
@@ -21,6 +21,7 @@ import (
 
 	"github.com/btcsuite/btcutil/base58"
 	gojose "github.com/go-jose/go-jose/v3"
+	"github.com/multiformats/go-multibase"
 	"github.com/stretchr/testify/require"
 
 	"github.com/trustbloc/did-go/doc/internal/mock/signature"
@@ -199,6 +200,22 @@ func TestDocResolution(t *testing.T) {
 		require.Equal(t, "did:ex:123333", d.DocumentMetadata.CanonicalID)
 	})
 
+	t.Run("test resolution metadata round trip", func(t *testing.T) {
+		d, err := ParseDocumentResolution([]byte(validDocResolution))
+		require.NoError(t, err)
+		require.Nil(t, d.ResolutionMetadata)
+
+		d.ResolutionMetadata = &ResolutionMetadata{ContentType: ContentTypeDIDLDJSON, Duration: 42}
+
+		bytes, err := d.JSONBytes()
+		require.NoError(t, err)
+		require.Contains(t, string(bytes), `"didResolutionMetadata":{"contentType":"application/did+ld+json"`)
+
+		d, err = ParseDocumentResolution(bytes)
+		require.NoError(t, err)
+		require.Equal(t, &ResolutionMetadata{ContentType: ContentTypeDIDLDJSON, Duration: 42}, d.ResolutionMetadata)
+	})
+
 	t.Run("test did doc not exists", func(t *testing.T) {
 		_, err := ParseDocumentResolution([]byte(validDoc))
 		require.Error(t, err)
@@ -1404,6 +1421,50 @@ func TestJSONWebKey(t *testing.T) {
 	require.Equal(t, didDocBytes, parsedDidDocBytes)
 }
 
+func TestBlockchainAccountID(t *testing.T) {
+	const accountID = "eip155:1:0xb9c5714089478a327f09197987f16f9e5d936e8a"
+
+	vm := NewVerificationMethodFromBlockchainAccountID(creator, "EcdsaSecp256k1RecoveryMethod2020", did, accountID)
+
+	didDoc := &Doc{
+		Context:            []string{ContextV1},
+		ID:                 did,
+		VerificationMethod: []VerificationMethod{*vm},
+	}
+
+	didDocBytes, err := didDoc.JSONBytes()
+	require.NoError(t, err)
+	require.Contains(t, string(didDocBytes), `"blockchainAccountId":"`+accountID+`"`)
+
+	parsedDidDoc, err := ParseDocument(didDocBytes)
+	require.NoError(t, err)
+	require.Equal(t, accountID, parsedDidDoc.VerificationMethod[0].BlockchainAccountID)
+	require.Empty(t, parsedDidDoc.VerificationMethod[0].Value)
+}
+
+func TestMultikey(t *testing.T) {
+	const publicKeyMultibase = "zQ3shXjHeiBuRCKmM36cuYnm7YEMzhGnCmCyW92sRJ9pribSF"
+
+	_, value, err := multibase.Decode(publicKeyMultibase)
+	require.NoError(t, err)
+
+	vm := NewVerificationMethodFromBytes(did+"#atproto", "Multikey", did, value)
+
+	didDoc := &Doc{
+		Context:            []string{ContextV1},
+		ID:                 did,
+		VerificationMethod: []VerificationMethod{*vm},
+	}
+
+	didDocBytes, err := didDoc.JSONBytes()
+	require.NoError(t, err)
+	require.Contains(t, string(didDocBytes), `"publicKeyMultibase":"`+publicKeyMultibase+`"`)
+
+	parsedDidDoc, err := ParseDocument(didDocBytes)
+	require.NoError(t, err)
+	require.Equal(t, value, parsedDidDoc.VerificationMethod[0].Value)
+}
+
 func TestVerifyProof(t *testing.T) {
 	docs := []string{validDoc, validDocV011}
 	for _, d := range docs {
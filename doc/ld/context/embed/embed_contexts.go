@@ -54,6 +54,8 @@ var (
 	ed255192020 []byte
 	//go:embed third_party/w3c-ccg.github.io/revocationList2021.jsonld
 	revocationList2021 []byte
+	//go:embed third_party/identity.foundation/did-configuration_v1.jsonld
+	didConfiguration []byte
 )
 
 // Contexts contains JSON-LD contexts embedded into a Go binary.
@@ -168,4 +170,9 @@ var Contexts = []ldcontext.Document{ //nolint:gochecknoglobals
 		DocumentURL: "https://digitalbazaar.github.io/ed25519-signature-2020-context/contexts/ed25519-signature-2020-v1.jsonld", //nolint: lll
 		Content:     ed255192020,
 	},
+	{
+		URL:         "https://identity.foundation/.well-known/did-configuration/v1",
+		DocumentURL: "https://identity.foundation/.well-known/did-configuration/v1",
+		Content:     didConfiguration,
+	},
 }
@@ -0,0 +1,129 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dht
+
+import (
+	"crypto/ed25519"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+const (
+	// maxPacketSize is the maximum size of the value of a BEP44 item.
+	maxPacketSize = 1000
+
+	zbase32Alphabet = "ybndrfg8ejkmcpqxot1uwisza345h769"
+)
+
+var (
+	// ErrInvalidPacket is returned when the signed packet of a DID does not verify or is malformed.
+	ErrInvalidPacket = errors.New("invalid did:dht packet")
+
+	// ErrSequenceRollback is returned when the sequence number of a DID is lower than the one previously resolved.
+	ErrSequenceRollback = errors.New("did:dht sequence number rollback")
+
+	// zbase32 is the z-base-32 encoding of the identity keys in DIDs.
+	zbase32 = base32.NewEncoding(zbase32Alphabet).WithPadding(base32.NoPadding)
+)
+
+// Signer signs the packets of a DID with its identity key.
+type Signer interface {
+	// IdentityKey returns the Ed25519 identity key of the DID.
+	IdentityKey() ed25519.PublicKey
+	// Sign returns the Ed25519 signature of data.
+	Sign(data []byte) ([]byte, error)
+}
+
+type ed25519Signer struct {
+	privateKey ed25519.PrivateKey
+}
+
+// NewEd25519Signer creates a signer for the Ed25519 private identity key.
+func NewEd25519Signer(privateKey ed25519.PrivateKey) Signer {
+	return &ed25519Signer{privateKey: privateKey}
+}
+
+// IdentityKey returns the public key of the signer.
+func (s *ed25519Signer) IdentityKey() ed25519.PublicKey {
+	if len(s.privateKey) != ed25519.PrivateKeySize {
+		return nil
+	}
+
+	return s.privateKey.Public().(ed25519.PublicKey) //nolint:forcetypeassert
+}
+
+// Sign returns the Ed25519 signature of data.
+func (s *ed25519Signer) Sign(data []byte) ([]byte, error) {
+	if len(s.privateKey) != ed25519.PrivateKeySize {
+		return nil, errors.New("invalid ed25519 private key")
+	}
+
+	return ed25519.Sign(s.privateKey, data), nil
+}
+
+// SignedPacket is the DNS packet of a DID stored as a BEP44 mutable item, with its sequence number and signature.
+type SignedPacket struct {
+	Sig    []byte
+	Seq    int64
+	Packet []byte
+}
+
+// bep44Signable returns the bencoded seq and v of a BEP44 mutable item, which its signature covers.
+func bep44Signable(seq int64, v []byte) []byte {
+	signable := []byte("3:seqi" + strconv.FormatInt(seq, 10) + "e1:v" + strconv.Itoa(len(v)) + ":")
+
+	return append(signable, v...)
+}
+
+func signPacket(signer Signer, seq int64, packet []byte) (*SignedPacket, error) {
+	if len(packet) > maxPacketSize {
+		return nil, fmt.Errorf("packet of %d bytes exceeds the %d bytes of a BEP44 item", len(packet), maxPacketSize)
+	}
+
+	sig, err := signer.Sign(bep44Signable(seq, packet))
+	if err != nil {
+		return nil, fmt.Errorf("sign packet: %w", err)
+	}
+
+	return &SignedPacket{Sig: sig, Seq: seq, Packet: packet}, nil
+}
+
+// verify verifies the BEP44 signature of the packet with the identity key of the DID.
+func (p *SignedPacket) verify(identityKey ed25519.PublicKey) error {
+	switch {
+	case len(p.Packet) > maxPacketSize:
+		return fmt.Errorf("%w: packet of %d bytes exceeds the %d bytes of a BEP44 item", ErrInvalidPacket,
+			len(p.Packet), maxPacketSize)
+	case p.Seq < 0:
+		return fmt.Errorf("%w: negative sequence number", ErrInvalidPacket)
+	case !ed25519.Verify(identityKey, bep44Signable(p.Seq, p.Packet), p.Sig):
+		return fmt.Errorf("%w: invalid signature", ErrInvalidPacket)
+	}
+
+	return nil
+}
+
+// identityKeyFromID decodes the z-base-32 identity key of a DID.
+func identityKeyFromID(id string) (ed25519.PublicKey, error) {
+	key, err := zbase32.DecodeString(id)
+	if err != nil {
+		return nil, fmt.Errorf("decode z-base-32 identity key: %w", err)
+	}
+
+	if len(key) != ed25519.PublicKeySize || zbase32.EncodeToString(key) != id {
+		return nil, errors.New("identity key must be a z-base-32 encoded Ed25519 public key")
+	}
+
+	return key, nil
+}
+
+// didFromIdentityKey returns the DID of an identity key.
+func didFromIdentityKey(identityKey ed25519.PublicKey) string {
+	return "did:" + DIDMethod + ":" + zbase32.EncodeToString(identityKey)
+}
@@ -0,0 +1,172 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dht
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+// SignerOpt is the Signer of the identity key of the DID, which signs the packets published by Create, Update and
+// Deactivate.
+const SignerOpt = "signer"
+
+// Create creates the did:dht DID of the identity key of SignerOpt and publishes didDoc as its DNS packet.
+//
+// The verification method #0 is the identity key, it is added to the DID document if missing. Verification methods
+// without ID are identified by the RFC 7638 thumbprint of their key.
+func (v *VDR) Create(didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	return v.CreateContext(context.Background(), didDoc, opts...)
+}
+
+// CreateContext creates the did:dht DID of the identity key of SignerOpt, the request to the relay is bound to the
+// given context.
+func (v *VDR) CreateContext(ctx context.Context, didDoc *did.Doc,
+	opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	signer, err := signerOpt(opts)
+	if err != nil {
+		return nil, fmt.Errorf("error creating did:dht did --> %w", err)
+	}
+
+	docResolution, err := v.publish(ctx, didDoc, signer)
+	if err != nil {
+		return nil, fmt.Errorf("error creating did:dht did --> %w", err)
+	}
+
+	return docResolution, nil
+}
+
+// Update publishes didDoc as the new DNS packet of its DID, signed by SignerOpt.
+func (v *VDR) Update(didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) error {
+	return v.UpdateContext(context.Background(), didDoc, opts...)
+}
+
+// UpdateContext publishes didDoc as the new DNS packet of its DID, the request to the relay is bound to the given
+// context.
+func (v *VDR) UpdateContext(ctx context.Context, didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) error {
+	signer, err := signerOpt(opts)
+	if err != nil {
+		return fmt.Errorf("error updating did:dht did --> %w", err)
+	}
+
+	if err = checkSigner(didDoc.ID, signer); err != nil {
+		return fmt.Errorf("error updating did:dht did --> %w", err)
+	}
+
+	if _, err = v.publish(ctx, didDoc, signer); err != nil {
+		return fmt.Errorf("error updating did:dht did --> %w", err)
+	}
+
+	return nil
+}
+
+// Deactivate publishes a DNS packet without records for the DID, signed by SignerOpt.
+func (v *VDR) Deactivate(didID string, opts ...vdrapi.DIDMethodOption) error {
+	return v.DeactivateContext(context.Background(), didID, opts...)
+}
+
+// DeactivateContext publishes a DNS packet without records for the DID, the request to the relay is bound to the
+// given context.
+func (v *VDR) DeactivateContext(ctx context.Context, didID string, opts ...vdrapi.DIDMethodOption) error {
+	signer, err := signerOpt(opts)
+	if err != nil {
+		return fmt.Errorf("error deactivating did:dht did --> %w", err)
+	}
+
+	if err = checkSigner(didID, signer); err != nil {
+		return fmt.Errorf("error deactivating did:dht did --> %w", err)
+	}
+
+	packet, err := encodePacket(nil)
+	if err != nil {
+		return fmt.Errorf("error deactivating did:dht did --> %w", err)
+	}
+
+	if _, err = v.put(ctx, signer, packet); err != nil {
+		return fmt.Errorf("error deactivating did:dht did --> %w", err)
+	}
+
+	return nil
+}
+
+// publish encodes and publishes the DID document, and returns its resolution.
+func (v *VDR) publish(ctx context.Context, didDoc *did.Doc, signer Signer) (*did.DocResolution, error) {
+	records, err := encodeDoc(didDoc, signer.IdentityKey())
+	if err != nil {
+		return nil, fmt.Errorf("encode did document: %w", err)
+	}
+
+	packet, err := encodePacket(records)
+	if err != nil {
+		return nil, fmt.Errorf("encode dns packet: %w", err)
+	}
+
+	signed, err := v.put(ctx, signer, packet)
+	if err != nil {
+		return nil, err
+	}
+
+	return createResolution(signed, signer.IdentityKey())
+}
+
+// put signs the packet with the next sequence number of the DID and puts it to the relay.
+func (v *VDR) put(ctx context.Context, signer Signer, packet []byte) (*SignedPacket, error) {
+	didID := didFromIdentityKey(signer.IdentityKey())
+
+	signed, err := signPacket(signer, v.nextSeq(didID), packet)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = v.relay.Put(ctx, signer.IdentityKey(), signed); err != nil {
+		return nil, fmt.Errorf("publish packet: %w", err)
+	}
+
+	if err = v.checkSeq(didID, signed.Seq); err != nil {
+		return nil, err
+	}
+
+	return signed, nil
+}
+
+func signerOpt(opts []vdrapi.DIDMethodOption) (Signer, error) {
+	didOpts := &vdrapi.DIDMethodOpts{Values: make(map[string]interface{})}
+	// Apply options
+	for _, opt := range opts {
+		opt(didOpts)
+	}
+
+	signer, ok := didOpts.Values[SignerOpt].(Signer)
+	if !ok || signer == nil {
+		return nil, errors.New("signer opt must be a dht.Signer")
+	}
+
+	if len(signer.IdentityKey()) != ed25519.PublicKeySize {
+		return nil, errors.New("signer must have an Ed25519 identity key")
+	}
+
+	return signer, nil
+}
+
+// checkSigner checks the identity key of the signer is the key of the DID.
+func checkSigner(didID string, signer Signer) error {
+	identityKey, err := parseDID(didID)
+	if err != nil {
+		return err
+	}
+
+	if !identityKey.Equal(signer.IdentityKey()) {
+		return errors.New("signer identity key does not match the DID")
+	}
+
+	return nil
+}
@@ -0,0 +1,298 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dht
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/doc/did/endpoint"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+// blockingRelay is a relay whose requests only return once their context is done.
+type blockingRelay struct{}
+
+func (r *blockingRelay) Put(ctx context.Context, _ ed25519.PublicKey, _ *SignedPacket) error {
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+func (r *blockingRelay) Get(ctx context.Context, _ ed25519.PublicKey) (*SignedPacket, error) {
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+func newSigner(t *testing.T) Signer {
+	t.Helper()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return NewEd25519Signer(privateKey)
+}
+
+func newJWKVM(t *testing.T, id string, key interface{}) *did.VerificationMethod {
+	t.Helper()
+
+	j, err := jwksupport.JWKFromKey(key)
+	require.NoError(t, err)
+
+	vm, err := did.NewVerificationMethodFromJWK(id, jsonWebKey2020, "", j)
+	require.NoError(t, err)
+
+	return vm
+}
+
+func TestCreate(t *testing.T) {
+	t.Run("test create did", func(t *testing.T) {
+		p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		k256Key, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		x25519Key, err := jwksupport.JWKFromX25519Key(make([]byte, 32))
+		require.NoError(t, err)
+
+		x25519VM, err := did.NewVerificationMethodFromJWK("#enc", jsonWebKey2020, "", x25519Key)
+		require.NoError(t, err)
+
+		p256VM := newJWKVM(t, "#p256", &p256Key.PublicKey)
+		k256VM := newJWKVM(t, "", k256Key.PubKey().ToECDSA())
+
+		doc := &did.Doc{
+			AlsoKnownAs:        []string{"https://example.com/alice"},
+			VerificationMethod: []did.VerificationMethod{*p256VM, *k256VM},
+			AssertionMethod:    []did.Verification{*did.NewReferencedVerification(p256VM, did.AssertionMethod)},
+			KeyAgreement:       []did.Verification{*did.NewEmbeddedVerification(x25519VM, did.KeyAgreement)},
+			Service: []did.Service{
+				{
+					ID:              "#dwn",
+					Type:            "DecentralizedWebNode",
+					ServiceEndpoint: endpoint.NewDIDCoreEndpoint([]string{"https://a.example.com", "https://b.example.com"}),
+				},
+				{
+					ID:              "#hub",
+					Type:            "LinkedDomains",
+					ServiceEndpoint: endpoint.NewDIDCommV1Endpoint("https://example.com?a=b"),
+				},
+			},
+		}
+
+		signer := newSigner(t)
+		v := New(WithRelay(NewMemoryRelay()))
+
+		created, err := v.Create(doc, vdrapi.WithOption(SignerOpt, signer))
+		require.NoError(t, err)
+
+		didID := created.DIDDocument.ID
+		require.Equal(t, "did:dht:"+zbase32.EncodeToString(signer.IdentityKey()), didID)
+		require.Len(t, strings.TrimPrefix(didID, "did:dht:"), 52)
+
+		resolved, err := v.Read(didID)
+		require.NoError(t, err)
+		require.Equal(t, created.DocumentMetadata.VersionID, resolved.DocumentMetadata.VersionID)
+
+		resolvedDoc := resolved.DIDDocument
+		require.Equal(t, []string{"https://example.com/alice"}, resolvedDoc.AlsoKnownAs)
+		require.Len(t, resolvedDoc.VerificationMethod, 4)
+
+		ids := make([]string, len(resolvedDoc.VerificationMethod))
+		for i, vm := range resolvedDoc.VerificationMethod {
+			ids[i] = vm.ID
+			require.Equal(t, jsonWebKey2020, vm.Type)
+			require.Equal(t, didID, vm.Controller)
+		}
+
+		k256ID, err := vmID(k256VM, keyTypeSecp256k1, k256Key.PubKey().SerializeCompressed())
+		require.NoError(t, err)
+		require.Equal(t, []string{didID + "#p256", didID + "#" + k256ID, didID + "#enc", didID + "#0"}, ids)
+
+		require.Equal(t, "secp256k1", resolvedDoc.VerificationMethod[1].JSONWebKey().Crv)
+		require.True(t, p256Key.PublicKey.Equal(resolvedDoc.VerificationMethod[0].JSONWebKey().Key))
+		require.Equal(t, ed25519.PublicKey(resolvedDoc.VerificationMethod[3].Value), signer.IdentityKey())
+
+		relationshipIDs := func(verifications []did.Verification) []string {
+			var vmIDs []string
+
+			for _, verification := range verifications {
+				vmIDs = append(vmIDs, verification.VerificationMethod.ID)
+			}
+
+			return vmIDs
+		}
+
+		require.Equal(t, []string{didID + "#0"}, relationshipIDs(resolvedDoc.Authentication))
+		require.Equal(t, []string{didID + "#0", didID + "#p256"}, relationshipIDs(resolvedDoc.AssertionMethod))
+		require.Equal(t, []string{didID + "#enc"}, relationshipIDs(resolvedDoc.KeyAgreement))
+		require.Equal(t, []string{didID + "#0"}, relationshipIDs(resolvedDoc.CapabilityInvocation))
+		require.Equal(t, []string{didID + "#0"}, relationshipIDs(resolvedDoc.CapabilityDelegation))
+
+		require.Len(t, resolvedDoc.Service, 2)
+		require.Equal(t, didID+"#dwn", resolvedDoc.Service[0].ID)
+		require.Equal(t, "DecentralizedWebNode", resolvedDoc.Service[0].Type)
+
+		uri, err := resolvedDoc.Service[1].ServiceEndpoint.URI()
+		require.NoError(t, err)
+		require.Equal(t, "https://example.com?a=b", uri)
+
+		endpointJSON, err := resolvedDoc.Service[0].ServiceEndpoint.MarshalJSON()
+		require.NoError(t, err)
+		require.JSONEq(t, `["https://a.example.com","https://b.example.com"]`, string(endpointJSON))
+	})
+
+	t.Run("test create did with identity key and raw keys", func(t *testing.T) {
+		signer := newSigner(t)
+
+		k256Key, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		doc := &did.Doc{VerificationMethod: []did.VerificationMethod{
+			*did.NewVerificationMethodFromBytes("#0", "Ed25519VerificationKey2020", "", signer.IdentityKey()),
+			*did.NewVerificationMethodFromBytes("#k256", "EcdsaSecp256k1VerificationKey2019", "did:example:controller",
+				k256Key.PubKey().SerializeUncompressed()),
+			*did.NewVerificationMethodFromBytes("#multikey", "Multikey", "",
+				append([]byte{0xed, 0x01}, signer.IdentityKey()...)),
+		}}
+
+		created, err := New(WithRelay(NewMemoryRelay())).Create(doc, vdrapi.WithOption(SignerOpt, signer))
+		require.NoError(t, err)
+
+		vms := created.DIDDocument.VerificationMethod
+		require.Len(t, vms, 3)
+		require.Equal(t, created.DIDDocument.ID+"#0", vms[0].ID)
+		require.Equal(t, "did:example:controller", vms[1].Controller)
+		require.Equal(t, "Ed25519", vms[2].JSONWebKey().Crv)
+	})
+
+	t.Run("test invalid did documents", func(t *testing.T) {
+		signer := newSigner(t)
+		v := New(WithRelay(NewMemoryRelay()))
+
+		p256Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+
+		otherVM := newJWKVM(t, "#other", &p256Key.PublicKey)
+
+		for _, doc := range []*did.Doc{
+			{VerificationMethod: []did.VerificationMethod{*newJWKVM(t, "#0", newSigner(t).IdentityKey())}},
+			{VerificationMethod: []did.VerificationMethod{*otherVM}},
+			{VerificationMethod: []did.VerificationMethod{{ID: "#bls", Type: "Bls12381G2Key2020"}}},
+			{VerificationMethod: []did.VerificationMethod{{ID: "#multikey", Type: "Multikey", Value: []byte{0x80}}}},
+			{Authentication: []did.Verification{*did.NewReferencedVerification(otherVM, did.Authentication)}},
+			{Service: []did.Service{{ID: "#s", Type: []string{"a", "b"}}}},
+			{Service: []did.Service{{ID: "#s", Type: "t", ServiceEndpoint: endpoint.NewDIDCoreEndpoint(1)}}},
+			{Service: []did.Service{{ID: "#s", Type: "a;b", ServiceEndpoint: endpoint.NewDIDCommV1Endpoint("uri")}}},
+			{AlsoKnownAs: []string{"a,b"}},
+			{Service: []did.Service{{
+				ID: "#s", Type: "t", ServiceEndpoint: endpoint.NewDIDCommV1Endpoint(strings.Repeat("a", 1000)),
+			}}},
+		} {
+			_, err = v.Create(doc, vdrapi.WithOption(SignerOpt, signer))
+			require.Error(t, err)
+		}
+	})
+
+	t.Run("test invalid signer", func(t *testing.T) {
+		v := New(WithRelay(NewMemoryRelay()))
+
+		_, err := v.Create(&did.Doc{})
+		require.ErrorContains(t, err, "signer opt must be a dht.Signer")
+
+		_, err = v.Create(&did.Doc{}, vdrapi.WithOption(SignerOpt, NewEd25519Signer(nil)))
+		require.ErrorContains(t, err, "signer must have an Ed25519 identity key")
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	signer := newSigner(t)
+	v := New(WithRelay(NewMemoryRelay()))
+
+	created, err := v.Create(&did.Doc{}, vdrapi.WithOption(SignerOpt, signer))
+	require.NoError(t, err)
+
+	didID := created.DIDDocument.ID
+
+	t.Run("test update did", func(t *testing.T) {
+		err = v.Update(&did.Doc{ID: didID, AlsoKnownAs: []string{"https://example.com"}},
+			vdrapi.WithOption(SignerOpt, signer))
+		require.NoError(t, err)
+
+		resolved, err := v.Read(didID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"https://example.com"}, resolved.DIDDocument.AlsoKnownAs)
+		require.Greater(t, resolved.DocumentMetadata.VersionID, created.DocumentMetadata.VersionID)
+	})
+
+	t.Run("test update errors", func(t *testing.T) {
+		require.ErrorContains(t, v.Update(&did.Doc{ID: didID}), "signer opt")
+		require.ErrorContains(t, v.Update(&did.Doc{ID: didID}, vdrapi.WithOption(SignerOpt, newSigner(t))),
+			"signer identity key does not match the DID")
+		require.ErrorIs(t, v.Update(&did.Doc{ID: "did:dht:abc"}, vdrapi.WithOption(SignerOpt, signer)),
+			vdrapi.ErrInvalidDID)
+		require.Error(t, v.Update(&did.Doc{ID: didID, AlsoKnownAs: []string{"a;b"}},
+			vdrapi.WithOption(SignerOpt, signer)))
+	})
+}
+
+func TestDeactivate(t *testing.T) {
+	signer := newSigner(t)
+	v := New(WithRelay(NewMemoryRelay()))
+	v.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	created, err := v.Create(&did.Doc{}, vdrapi.WithOption(SignerOpt, signer))
+	require.NoError(t, err)
+	require.Equal(t, "1700000000", created.DocumentMetadata.VersionID)
+
+	didID := created.DIDDocument.ID
+
+	require.ErrorContains(t, v.Deactivate(didID), "signer opt")
+	require.ErrorContains(t, v.Deactivate(didID, vdrapi.WithOption(SignerOpt, newSigner(t))),
+		"signer identity key does not match the DID")
+	require.NoError(t, v.Deactivate(didID, vdrapi.WithOption(SignerOpt, signer)))
+
+	resolved, err := v.Read(didID)
+	require.NoError(t, err)
+	require.True(t, resolved.DocumentMetadata.Deactivated)
+	require.Equal(t, "1700000001", resolved.DocumentMetadata.VersionID)
+	require.Empty(t, resolved.DIDDocument.VerificationMethod)
+}
+
+func TestPublishContext(t *testing.T) {
+	signer := newSigner(t)
+	didID := didFromIdentityKey(signer.IdentityKey())
+	v := New(WithRelay(&blockingRelay{}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := v.CreateContext(ctx, &did.Doc{}, vdrapi.WithOption(SignerOpt, signer))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	err = v.UpdateContext(ctx, &did.Doc{ID: didID}, vdrapi.WithOption(SignerOpt, signer))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	err = v.DeactivateContext(ctx, didID, vdrapi.WithOption(SignerOpt, signer))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = v.ReadContext(ctx, didID)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,193 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dht
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	dnsHeaderSize = 12
+	// dnsFlags are the flags of an authoritative answer.
+	dnsFlags = 0x8400
+
+	dnsTypeTXT = 16
+	dnsClassIN = 1
+	recordTTL  = 7200
+
+	maxLabelSize           = 63
+	maxCharacterStringSize = 255
+	maxNamePointers        = 16
+	compressionMask        = 0xc0
+)
+
+// txtRecord is a TXT record of the DNS packet of a DID.
+type txtRecord struct {
+	name  string
+	value string
+}
+
+// encodePacket encodes the records as the answers of a DNS response message, long values being split into several
+// character strings.
+func encodePacket(records []txtRecord) ([]byte, error) {
+	packet := make([]byte, dnsHeaderSize)
+	binary.BigEndian.PutUint16(packet[2:], dnsFlags)
+	binary.BigEndian.PutUint16(packet[6:], uint16(len(records))) //nolint:gosec
+
+	for _, record := range records {
+		for _, label := range strings.Split(strings.TrimSuffix(record.name, "."), ".") {
+			if label == "" || len(label) > maxLabelSize {
+				return nil, fmt.Errorf("invalid record name [%s]", record.name)
+			}
+
+			packet = append(packet, byte(len(label)))
+			packet = append(packet, label...)
+		}
+
+		packet = append(packet, 0)
+		packet = binary.BigEndian.AppendUint16(packet, dnsTypeTXT)
+		packet = binary.BigEndian.AppendUint16(packet, dnsClassIN)
+		packet = binary.BigEndian.AppendUint32(packet, recordTTL)
+
+		var data []byte
+
+		for value := record.value; ; {
+			size := min(len(value), maxCharacterStringSize)
+
+			data = append(data, byte(size))
+			data = append(data, value[:size]...)
+
+			if value = value[size:]; value == "" {
+				break
+			}
+		}
+
+		packet = binary.BigEndian.AppendUint16(packet, uint16(len(data))) //nolint:gosec
+		packet = append(packet, data...)
+	}
+
+	return packet, nil
+}
+
+// decodePacket decodes the TXT records of the answers of a DNS message, the character strings of a record being
+// concatenated. Other records are ignored.
+func decodePacket(packet []byte) ([]txtRecord, error) {
+	if len(packet) < dnsHeaderSize {
+		return nil, errors.New("dns packet is too short")
+	}
+
+	questions := int(binary.BigEndian.Uint16(packet[4:]))
+	answers := int(binary.BigEndian.Uint16(packet[6:]))
+	offset := dnsHeaderSize
+
+	for range questions {
+		_, next, err := readName(packet, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		// question type and class.
+		offset = next + 4 //nolint:mnd
+	}
+
+	var records []txtRecord
+
+	for range answers {
+		name, next, err := readName(packet, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		// type, class, TTL and data length.
+		if next+10 > len(packet) { //nolint:mnd
+			return nil, errors.New("truncated dns record")
+		}
+
+		recordType := binary.BigEndian.Uint16(packet[next:])
+		recordClass := binary.BigEndian.Uint16(packet[next+2:])
+		dataSize := int(binary.BigEndian.Uint16(packet[next+8:]))
+		offset = next + 10 + dataSize //nolint:mnd
+
+		if offset > len(packet) {
+			return nil, errors.New("truncated dns record data")
+		}
+
+		if recordType != dnsTypeTXT || recordClass != dnsClassIN {
+			continue
+		}
+
+		value, err := readCharacterStrings(packet[offset-dataSize : offset])
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, txtRecord{name: name, value: value})
+	}
+
+	return records, nil
+}
+
+// readName reads the name at offset, following compression pointers, and returns the offset after it.
+func readName(packet []byte, offset int) (string, int, error) {
+	var (
+		labels   []string
+		next     = -1
+		pointers = 0
+	)
+
+	for {
+		if offset >= len(packet) {
+			return "", 0, errors.New("truncated dns name")
+		}
+
+		size := int(packet[offset])
+
+		switch {
+		case size == 0:
+			if next < 0 {
+				next = offset + 1
+			}
+
+			return strings.Join(labels, ".") + ".", next, nil
+		case size&compressionMask == compressionMask:
+			if offset+1 >= len(packet) || pointers == maxNamePointers {
+				return "", 0, errors.New("invalid dns name pointer")
+			}
+
+			if next < 0 {
+				next = offset + 2 //nolint:mnd
+			}
+
+			pointers++
+			offset = int(binary.BigEndian.Uint16(packet[offset:]) & 0x3fff) //nolint:mnd
+		case size > maxLabelSize || offset+1+size > len(packet):
+			return "", 0, errors.New("invalid dns label")
+		default:
+			labels = append(labels, string(packet[offset+1:offset+1+size]))
+			offset += 1 + size
+		}
+	}
+}
+
+func readCharacterStrings(data []byte) (string, error) {
+	var value strings.Builder
+
+	for len(data) > 0 {
+		size := int(data[0])
+		if 1+size > len(data) {
+			return "", errors.New("truncated dns character string")
+		}
+
+		value.Write(data[1 : 1+size])
+		data = data[1+size:]
+	}
+
+	return value.String(), nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dht
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacket(t *testing.T) {
+	t.Run("test long values are split into character strings", func(t *testing.T) {
+		records := []txtRecord{
+			{name: "_did.example.", value: strings.Repeat("a", 600)},
+			{name: "_s0._did.", value: "id=s0;t=t;se=uri"},
+		}
+
+		packet, err := encodePacket(records)
+		require.NoError(t, err)
+
+		decoded, err := decodePacket(packet)
+		require.NoError(t, err)
+		require.Equal(t, records, decoded)
+	})
+
+	t.Run("test compressed names and other records", func(t *testing.T) {
+		packet := []byte{
+			0, 0, 0x84, 0, 0, 1, 0, 3, 0, 0, 0, 0,
+			// question _did.example. TXT IN
+			4, '_', 'd', 'i', 'd', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 0, 0, 16, 0, 1,
+			// _k0 + pointer to _did.example. TXT IN
+			3, '_', 'k', '0', 0xc0, 12, 0, 16, 0, 1, 0, 0, 0x1c, 0x20, 0, 4, 3, 'a', '=', 'b',
+			// pointer to _did.example. A IN
+			0xc0, 12, 0, 1, 0, 1, 0, 0, 0x1c, 0x20, 0, 4, 127, 0, 0, 1,
+			// pointer to _did.example. TXT IN with two character strings
+			0xc0, 12, 0, 16, 0, 1, 0, 0, 0x1c, 0x20, 0, 4, 1, 'v', 1, '0',
+		}
+
+		records, err := decodePacket(packet)
+		require.NoError(t, err)
+		require.Equal(t, []txtRecord{
+			{name: "_k0._did.example.", value: "a=b"},
+			{name: "_did.example.", value: "v0"},
+		}, records)
+	})
+
+	t.Run("test invalid packets", func(t *testing.T) {
+		_, err := encodePacket([]txtRecord{{name: "_did..", value: "v"}})
+		require.ErrorContains(t, err, "invalid record name")
+
+		for _, packet := range [][]byte{
+			{0, 0, 0x84, 0},
+			{0, 0, 0x84, 0, 0, 0, 0, 1, 0, 0, 0, 0},
+			{0, 0, 0x84, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 16},
+			{0, 0, 0x84, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 16, 0, 1, 0, 0, 0, 0, 0, 2, 1},
+			{0, 0, 0x84, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 16, 0, 1, 0, 0, 0, 0, 0, 1, 1},
+			{0, 0, 0x84, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0xc0, 12},
+			{0, 0, 0x84, 0, 0, 0, 0, 1, 0, 0, 0, 0, 5, 'a'},
+		} {
+			_, err = decodePacket(packet)
+			require.Error(t, err)
+		}
+	})
+}
@@ -0,0 +1,579 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dht
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+	"github.com/trustbloc/kms-go/doc/util/fingerprint"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/doc/did/endpoint"
+	didjwk "github.com/trustbloc/did-go/method/jwk"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	schemaDIDV1    = "https://www.w3.org/ns/did/v1"
+	jwsSuiteV1     = "https://w3id.org/security/suites/jws-2020/v1"
+	jsonWebKey2020 = "JsonWebKey2020"
+
+	// identityKeyID is the verification method ID of the identity key.
+	identityKeyID = "0"
+	version       = "0"
+
+	rootLabel = "_did"
+	aliasName = "_aka._did."
+
+	// multicodec codes of the Multikey verification methods, missing from the kms-go fingerprint package.
+	secp256k1PubKeyMultiCodec = 0xe7
+)
+
+// key type indexes of the did:dht registry.
+const (
+	keyTypeEd25519 = iota
+	keyTypeSecp256k1
+	keyTypeP256
+	keyTypeX25519
+)
+
+// relationships are the root record properties listing the verification relationships.
+var relationships = []struct {
+	property     string
+	relationship did.VerificationRelationship
+}{
+	{"auth", did.Authentication},
+	{"asm", did.AssertionMethod},
+	{"agm", did.KeyAgreement},
+	{"inv", did.CapabilityInvocation},
+	{"del", did.CapabilityDelegation},
+}
+
+// encodeDoc encodes the DID document as the TXT records of the DID: the _did root record lists the _kN key records
+// of the verification methods, their verification relationships and the _sN service records.
+//
+// The identity key is the verification method #0, which is added if missing, and is always listed in the
+// authentication, assertionMethod, capabilityInvocation and capabilityDelegation relationships.
+//
+//nolint:funlen,gocyclo
+func encodeDoc(doc *did.Doc, identityKey ed25519.PublicKey) ([]txtRecord, error) {
+	didID := didFromIdentityKey(identityKey)
+
+	vms := slices.Clone(doc.VerificationMethod)
+
+	// embedded verification methods are encoded as the other ones.
+	for _, verification := range verifications(doc) {
+		if verification.Embedded {
+			vms = append(vms, verification.VerificationMethod)
+		}
+	}
+
+	var (
+		records  []txtRecord
+		vmNames  []string
+		names    = make(map[string]string)
+		hasIdent = false
+	)
+
+	for i := range vms {
+		typeIndex, key, err := keyOf(&vms[i])
+		if err != nil {
+			return nil, fmt.Errorf("verification method [%s]: %w", vms[i].ID, err)
+		}
+
+		id, err := vmID(&vms[i], typeIndex, key)
+		if err != nil {
+			return nil, fmt.Errorf("verification method [%s]: %w", vms[i].ID, err)
+		}
+
+		if _, ok := names[id]; ok {
+			continue
+		}
+
+		if id == identityKeyID {
+			if typeIndex != keyTypeEd25519 || !ed25519.PublicKey(key).Equal(identityKey) {
+				return nil, errors.New("verification method #0 must be the identity key")
+			}
+
+			hasIdent = true
+		}
+
+		name := "k" + strconv.Itoa(len(vmNames))
+		names[id] = name
+		vmNames = append(vmNames, name)
+
+		value := "id=" + id + ";t=" + strconv.Itoa(typeIndex) + ";k=" + base64.RawURLEncoding.EncodeToString(key)
+
+		if vms[i].Controller != "" && vms[i].Controller != didID {
+			value += ";c=" + vms[i].Controller
+		}
+
+		records = append(records, txtRecord{name: "_" + name + "._did.", value: value})
+	}
+
+	if !hasIdent {
+		name := "k" + strconv.Itoa(len(vmNames))
+		names[identityKeyID] = name
+		vmNames = append(vmNames, name)
+
+		records = append(records, txtRecord{
+			name: "_" + name + "._did.",
+			value: "id=" + identityKeyID + ";t=" + strconv.Itoa(keyTypeEd25519) + ";k=" +
+				base64.RawURLEncoding.EncodeToString(identityKey),
+		})
+	}
+
+	rootProperties := []string{"v=" + version, "vm=" + strings.Join(vmNames, ",")}
+
+	for _, r := range relationships {
+		var listed []string
+
+		if r.relationship != did.KeyAgreement {
+			listed = append(listed, names[identityKeyID])
+		}
+
+		for _, verification := range doc.VerificationMethods(r.relationship)[r.relationship] {
+			name, ok := names[fragment(verification.VerificationMethod.ID)]
+			if !ok {
+				return nil, fmt.Errorf("unknown verification method [%s] of relationship [%s]",
+					verification.VerificationMethod.ID, r.property)
+			}
+
+			if !slices.Contains(listed, name) {
+				listed = append(listed, name)
+			}
+		}
+
+		if len(listed) > 0 {
+			rootProperties = append(rootProperties, r.property+"="+strings.Join(listed, ","))
+		}
+	}
+
+	var serviceNames []string
+
+	for i := range doc.Service {
+		value, err := encodeService(&doc.Service[i])
+		if err != nil {
+			return nil, fmt.Errorf("service [%s]: %w", doc.Service[i].ID, err)
+		}
+
+		name := "s" + strconv.Itoa(i)
+		serviceNames = append(serviceNames, name)
+		records = append(records, txtRecord{name: "_" + name + "._did.", value: value})
+	}
+
+	if len(serviceNames) > 0 {
+		rootProperties = append(rootProperties, "svc="+strings.Join(serviceNames, ","))
+	}
+
+	if len(doc.AlsoKnownAs) > 0 {
+		if err := checkListValues(doc.AlsoKnownAs); err != nil {
+			return nil, fmt.Errorf("alsoKnownAs: %w", err)
+		}
+
+		records = append(records, txtRecord{name: aliasName, value: strings.Join(doc.AlsoKnownAs, ",")})
+	}
+
+	root := txtRecord{name: rootLabel + "." + zbase32.EncodeToString(identityKey) + ".",
+		value: strings.Join(rootProperties, ";")}
+
+	return append([]txtRecord{root}, records...), nil
+}
+
+func verifications(doc *did.Doc) []did.Verification {
+	var all []did.Verification
+
+	for _, r := range relationships {
+		all = append(all, doc.VerificationMethods(r.relationship)[r.relationship]...)
+	}
+
+	return all
+}
+
+// vmID returns the ID of the verification method in the key record, its fragment or the RFC 7638 thumbprint of its
+// key.
+func vmID(vm *did.VerificationMethod, typeIndex int, key []byte) (string, error) {
+	if id := fragment(vm.ID); id != "" {
+		return id, checkValue(id)
+	}
+
+	j, err := jwkFromKey(typeIndex, key)
+	if err != nil {
+		return "", err
+	}
+
+	keyBytes, err := j.MarshalJSON()
+	if err != nil {
+		return "", fmt.Errorf("marshal key: %w", err)
+	}
+
+	return didjwk.Thumbprint(keyBytes)
+}
+
+func fragment(id string) string {
+	if i := strings.LastIndex(id, "#"); i >= 0 {
+		return id[i+1:]
+	}
+
+	return id
+}
+
+func encodeService(service *did.Service) (string, error) {
+	serviceType, ok := service.Type.(string)
+	if !ok {
+		return "", errors.New("service type must be a string")
+	}
+
+	endpointJSON, err := service.ServiceEndpoint.MarshalJSON()
+	if err != nil {
+		return "", fmt.Errorf("marshal service endpoint: %w", err)
+	}
+
+	var (
+		uris []string
+		uri  string
+	)
+
+	if err = json.Unmarshal(endpointJSON, &uri); err == nil {
+		uris = []string{uri}
+	} else if err = json.Unmarshal(endpointJSON, &uris); err != nil || len(uris) == 0 {
+		return "", errors.New("service endpoint must be a URI or a list of URIs")
+	}
+
+	id := fragment(service.ID)
+
+	for _, value := range []string{id, serviceType} {
+		if err = checkValue(value); err != nil {
+			return "", err
+		}
+	}
+
+	if err = checkListValues(uris); err != nil {
+		return "", err
+	}
+
+	return "id=" + id + ";t=" + serviceType + ";se=" + strings.Join(uris, ","), nil
+}
+
+// checkValue checks the value can be a record property value.
+func checkValue(value string) error {
+	if value == "" || strings.Contains(value, ";") {
+		return fmt.Errorf("value [%s] must not be empty nor contain ';'", value)
+	}
+
+	return nil
+}
+
+// checkListValues checks the values can be listed in a record property value.
+func checkListValues(values []string) error {
+	for _, value := range values {
+		if err := checkValue(value); err != nil {
+			return err
+		}
+
+		if strings.Contains(value, ",") {
+			return fmt.Errorf("value [%s] must not contain ','", value)
+		}
+	}
+
+	return nil
+}
+
+// decodeDoc decodes the DID document from the TXT records of the DID.
+//
+//nolint:funlen,gocyclo
+func decodeDoc(records []txtRecord, identityKey ed25519.PublicKey) (*did.Doc, error) {
+	didID := didFromIdentityKey(identityKey)
+	rootName := rootLabel + "." + zbase32.EncodeToString(identityKey) + "."
+
+	values := make(map[string]string)
+
+	for _, record := range records {
+		values[strings.ToLower(record.name)] = record.value
+	}
+
+	rootValue, ok := values[rootName]
+	if !ok {
+		return nil, errors.New("missing _did root record")
+	}
+
+	root := properties(rootValue)
+
+	if root["v"] != version {
+		return nil, fmt.Errorf("unsupported version [%s]", root["v"])
+	}
+
+	doc := &did.Doc{
+		Context: []string{schemaDIDV1, jwsSuiteV1},
+		ID:      didID,
+	}
+
+	vms := make(map[string]*did.VerificationMethod)
+
+	for _, name := range splitList(root["vm"]) {
+		value, exists := values["_"+name+"._did."]
+		if !exists {
+			return nil, fmt.Errorf("missing key record [%s]", name)
+		}
+
+		vm, err := decodeKey(didID, properties(value))
+		if err != nil {
+			return nil, fmt.Errorf("key record [%s]: %w", name, err)
+		}
+
+		if vm.ID == didID+"#"+identityKeyID && !identityKey.Equal(ed25519.PublicKey(vm.Value)) {
+			return nil, errors.New("verification method #0 is not the identity key")
+		}
+
+		vms[name] = vm
+		doc.VerificationMethod = append(doc.VerificationMethod, *vm)
+	}
+
+	if !slices.ContainsFunc(doc.VerificationMethod, func(vm did.VerificationMethod) bool {
+		return vm.ID == didID+"#"+identityKeyID
+	}) {
+		return nil, errors.New("missing identity key record")
+	}
+
+	for _, r := range relationships {
+		for _, name := range splitList(root[r.property]) {
+			vm, exists := vms[name]
+			if !exists {
+				return nil, fmt.Errorf("unknown key record [%s] of relationship [%s]", name, r.property)
+			}
+
+			verification := *did.NewReferencedVerification(vm, r.relationship)
+
+			switch r.relationship { //nolint:exhaustive
+			case did.Authentication:
+				doc.Authentication = append(doc.Authentication, verification)
+			case did.AssertionMethod:
+				doc.AssertionMethod = append(doc.AssertionMethod, verification)
+			case did.KeyAgreement:
+				doc.KeyAgreement = append(doc.KeyAgreement, verification)
+			case did.CapabilityInvocation:
+				doc.CapabilityInvocation = append(doc.CapabilityInvocation, verification)
+			case did.CapabilityDelegation:
+				doc.CapabilityDelegation = append(doc.CapabilityDelegation, verification)
+			}
+		}
+	}
+
+	for _, name := range splitList(root["svc"]) {
+		value, exists := values["_"+name+"._did."]
+		if !exists {
+			return nil, fmt.Errorf("missing service record [%s]", name)
+		}
+
+		service := properties(value)
+		uris := splitList(service["se"])
+
+		if service["id"] == "" || service["t"] == "" || len(uris) == 0 {
+			return nil, fmt.Errorf("service record [%s] must have an id, a type and endpoints", name)
+		}
+
+		serviceEndpoint := endpoint.NewDIDCoreEndpoint(uris)
+		if len(uris) == 1 {
+			serviceEndpoint = endpoint.NewDIDCommV1Endpoint(uris[0])
+		}
+
+		doc.Service = append(doc.Service, did.Service{
+			ID:              didID + "#" + service["id"],
+			Type:            service["t"],
+			ServiceEndpoint: serviceEndpoint,
+		})
+	}
+
+	if aliases, exists := values[aliasName]; exists {
+		doc.AlsoKnownAs = splitList(aliases)
+	}
+
+	return doc, nil
+}
+
+func decodeKey(didID string, key map[string]string) (*did.VerificationMethod, error) {
+	typeIndex, err := strconv.Atoi(key["t"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid key type index [%s]", key["t"])
+	}
+
+	keyBytes, err := base64.RawURLEncoding.DecodeString(key["k"])
+	if err != nil {
+		return nil, fmt.Errorf("decode key: %w", err)
+	}
+
+	if key["id"] == "" {
+		return nil, errors.New("missing key id")
+	}
+
+	j, err := jwkFromKey(typeIndex, keyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	j.Algorithm = key["a"]
+
+	controller := didID
+	if key["c"] != "" {
+		controller = key["c"]
+	}
+
+	vm, err := did.NewVerificationMethodFromJWK(didID+"#"+key["id"], jsonWebKey2020, controller, j)
+	if err != nil {
+		return nil, fmt.Errorf("create verification method: %w", err)
+	}
+
+	return vm, nil
+}
+
+// properties parses the semicolon separated properties of a record value.
+func properties(value string) map[string]string {
+	props := make(map[string]string)
+
+	for _, property := range strings.Split(value, ";") {
+		if k, v, ok := strings.Cut(property, "="); ok {
+			props[k] = v
+		}
+	}
+
+	return props
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	return strings.Split(value, ",")
+}
+
+// keyOf returns the key type index and the key bytes of a JsonWebKey2020, Multikey, Ed25519, X25519 or secp256k1
+// verification method: Ed25519 and X25519 keys are raw keys, EC keys compressed keys.
+//
+//nolint:gocyclo
+func keyOf(vm *did.VerificationMethod) (int, []byte, error) {
+	if j := vm.JSONWebKey(); j != nil {
+		switch key := j.Key.(type) {
+		case ed25519.PublicKey:
+			return keyTypeEd25519, key, nil
+		case []byte:
+			if j.Crv == "X25519" {
+				return keyTypeX25519, key, nil
+			}
+		case *ecdsa.PublicKey:
+			switch key.Curve {
+			case elliptic.P256():
+				return keyTypeP256, elliptic.MarshalCompressed(key.Curve, key.X, key.Y), nil
+			case btcec.S256():
+				var x, y btcec.FieldVal
+
+				x.SetByteSlice(key.X.Bytes())
+				y.SetByteSlice(key.Y.Bytes())
+
+				return keyTypeSecp256k1, btcec.NewPublicKey(&x, &y).SerializeCompressed(), nil
+			}
+		}
+
+		return 0, nil, fmt.Errorf("unsupported public key [%s %s]: %w", j.Kty, j.Crv, vdrapi.ErrInvalidPublicKeyType)
+	}
+
+	typeIndex, key := -1, vm.Value
+
+	switch vm.Type {
+	case "Ed25519VerificationKey2018", "Ed25519VerificationKey2020":
+		typeIndex = keyTypeEd25519
+	case "X25519KeyAgreementKey2019", "X25519KeyAgreementKey2020":
+		typeIndex = keyTypeX25519
+	case "EcdsaSecp256k1VerificationKey2019":
+		typeIndex = keyTypeSecp256k1
+	case "Multikey":
+		var (
+			code uint64
+			err  error
+		)
+
+		key, code, err = fingerprint.PubKeyFromFingerprint("z" + base58.Encode(vm.Value))
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid Multikey value: %w", err)
+		}
+
+		typeIndex = slices.Index([]uint64{fingerprint.ED25519PubKeyMultiCodec, secp256k1PubKeyMultiCodec,
+			fingerprint.P256PubKeyMultiCodec, fingerprint.X25519PubKeyMultiCodec}, code)
+	}
+
+	if typeIndex < 0 {
+		return 0, nil, fmt.Errorf("unsupported verification method type [%s]: %w", vm.Type,
+			vdrapi.ErrInvalidPublicKeyType)
+	}
+
+	// the key is parsed to check it, and to compress secp256k1 keys.
+	j, err := jwkFromKey(typeIndex, key)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	jwkVM, err := did.NewVerificationMethodFromJWK(vm.ID, jsonWebKey2020, vm.Controller, j)
+	if err != nil {
+		return 0, nil, fmt.Errorf("create verification method: %w", err)
+	}
+
+	return keyOf(jwkVM)
+}
+
+// jwkFromKey creates the JWK of the key bytes of a key record.
+func jwkFromKey(typeIndex int, key []byte) (*jwk.JWK, error) {
+	var (
+		j   *jwk.JWK
+		err error
+	)
+
+	switch typeIndex {
+	case keyTypeEd25519:
+		if len(key) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+
+		j, err = jwksupport.JWKFromKey(ed25519.PublicKey(key))
+	case keyTypeX25519:
+		j, err = jwksupport.JWKFromX25519Key(key)
+	case keyTypeSecp256k1:
+		publicKey, e := btcec.ParsePubKey(key)
+		if e != nil {
+			return nil, fmt.Errorf("invalid secp256k1 key: %w", e)
+		}
+
+		j, err = jwksupport.JWKFromKey(publicKey.ToECDSA())
+	case keyTypeP256:
+		x, y := elliptic.UnmarshalCompressed(elliptic.P256(), key)
+		if x == nil {
+			return nil, errors.New("invalid P-256 key")
+		}
+
+		j, err = jwksupport.JWKFromKey(&ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y})
+	default:
+		return nil, fmt.Errorf("unsupported key type index [%d]: %w", typeIndex, vdrapi.ErrInvalidPublicKeyType)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("create JWK: %w", err)
+	}
+
+	return j, nil
+}
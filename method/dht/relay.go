@@ -0,0 +1,159 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dht
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	sigSize = ed25519.SignatureSize
+	seqSize = 8
+
+	relayContentType = "application/pkarr.org/relays#payload"
+)
+
+// Relay publishes and fetches the signed packets of DIDs, keyed by their identity key. The requests are bound to the
+// given context, which has no deadline when the VDR is called without one: a relay must enforce its own timeouts.
+type Relay interface {
+	// Put publishes the signed packet of the identity key.
+	Put(ctx context.Context, identityKey ed25519.PublicKey, packet *SignedPacket) error
+	// Get fetches the signed packet of the identity key, it returns an error wrapping vdrapi.ErrNotFound if there is
+	// none.
+	Get(ctx context.Context, identityKey ed25519.PublicKey) (*SignedPacket, error)
+}
+
+// HTTPRelay is a Pkarr HTTP relay (https://pkarr.org/relays) of the Mainline DHT.
+type HTTPRelay struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewHTTPRelay creates a Pkarr relay client for the relay URL.
+func NewHTTPRelay(relayURL string, httpClient *http.Client) *HTTPRelay {
+	return &HTTPRelay{url: strings.TrimSuffix(relayURL, "/"), httpClient: httpClient}
+}
+
+// Put publishes the signed packet: its signature, its big endian sequence number and the packet.
+func (r *HTTPRelay) Put(ctx context.Context, identityKey ed25519.PublicKey, packet *SignedPacket) error {
+	payload := make([]byte, 0, sigSize+seqSize+len(packet.Packet))
+	payload = append(payload, packet.Sig...)
+	payload = binary.BigEndian.AppendUint64(payload, uint64(packet.Seq))
+	payload = append(payload, packet.Packet...)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, r.keyURL(identityKey), bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("create relay request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", relayContentType)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("put packet to relay: %w", err)
+	}
+
+	defer closeResponseBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("put packet to relay: unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Get fetches the signed packet of the identity key.
+func (r *HTTPRelay) Get(ctx context.Context, identityKey ed25519.PublicKey) (*SignedPacket, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.keyURL(identityKey), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create relay request: %w", err)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("get packet from relay: %w", err)
+	}
+
+	defer closeResponseBody(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("get packet from relay: %w", vdrapi.ErrNotFound)
+	default:
+		return nil, fmt.Errorf("get packet from relay: unexpected status %d", resp.StatusCode)
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(resp.Body, sigSize+seqSize+maxPacketSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read relay response: %w", err)
+	}
+
+	if len(payload) < sigSize+seqSize || len(payload) > sigSize+seqSize+maxPacketSize {
+		return nil, fmt.Errorf("%w: relay payload of %d bytes", ErrInvalidPacket, len(payload))
+	}
+
+	return &SignedPacket{
+		Sig:    payload[:sigSize],
+		Seq:    int64(binary.BigEndian.Uint64(payload[sigSize : sigSize+seqSize])), //nolint:gosec
+		Packet: payload[sigSize+seqSize:],
+	}, nil
+}
+
+func (r *HTTPRelay) keyURL(identityKey ed25519.PublicKey) string {
+	return r.url + "/" + zbase32.EncodeToString(identityKey)
+}
+
+func closeResponseBody(respBody io.Closer) {
+	if err := respBody.Close(); err != nil {
+		errorLogger.Printf("Failed to close response body: %v", err)
+	}
+}
+
+// MemoryRelay is an in-memory relay, which stores the last packet put for each identity key without verifying it.
+type MemoryRelay struct {
+	mutex   sync.RWMutex
+	packets map[string]*SignedPacket
+}
+
+// NewMemoryRelay creates an in-memory relay.
+func NewMemoryRelay() *MemoryRelay {
+	return &MemoryRelay{packets: make(map[string]*SignedPacket)}
+}
+
+// Put stores the signed packet of the identity key.
+func (r *MemoryRelay) Put(_ context.Context, identityKey ed25519.PublicKey, packet *SignedPacket) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.packets[string(identityKey)] = packet
+
+	return nil
+}
+
+// Get returns the signed packet of the identity key.
+func (r *MemoryRelay) Get(_ context.Context, identityKey ed25519.PublicKey) (*SignedPacket, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	packet, ok := r.packets[string(identityKey)]
+	if !ok {
+		return nil, fmt.Errorf("no packet for identity key: %w", vdrapi.ErrNotFound)
+	}
+
+	return packet, nil
+}
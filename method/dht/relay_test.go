@@ -0,0 +1,113 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dht
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+func TestHTTPRelay(t *testing.T) {
+	t.Run("test publish and resolve through the relay", func(t *testing.T) {
+		var (
+			mutex    sync.Mutex
+			payloads = make(map[string][]byte)
+		)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			key := strings.TrimPrefix(r.URL.Path, "/")
+
+			switch r.Method {
+			case http.MethodPut:
+				require.Equal(t, relayContentType, r.Header.Get("Content-Type"))
+
+				payload, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+
+				payloads[key] = payload
+
+				w.WriteHeader(http.StatusOK)
+			case http.MethodGet:
+				payload, ok := payloads[key]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+
+					return
+				}
+
+				_, err := w.Write(payload)
+				require.NoError(t, err)
+			}
+		}))
+		defer srv.Close()
+
+		signer := newSigner(t)
+		v := New(WithRelay(NewHTTPRelay(srv.URL+"/", srv.Client())))
+
+		created, err := v.Create(&did.Doc{}, vdrapi.WithOption(SignerOpt, signer))
+		require.NoError(t, err)
+
+		payload := payloads[zbase32.EncodeToString(signer.IdentityKey())]
+		require.Greater(t, len(payload), sigSize+seqSize)
+
+		resolved, err := v.Read(created.DIDDocument.ID)
+		require.NoError(t, err)
+		require.Equal(t, created.DIDDocument.ID, resolved.DIDDocument.ID)
+		require.Equal(t, created.DocumentMetadata.VersionID, resolved.DocumentMetadata.VersionID)
+
+		_, err = v.Read(didFromIdentityKey(newSigner(t).IdentityKey()))
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+	})
+
+	t.Run("test relay errors", func(t *testing.T) {
+		var payload []byte
+
+		status := http.StatusInternalServerError
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(status)
+
+			_, err := w.Write(payload)
+			require.NoError(t, err)
+		}))
+		defer srv.Close()
+
+		relay := NewHTTPRelay(srv.URL, srv.Client())
+		identityKey := newSigner(t).IdentityKey()
+
+		err := relay.Put(context.Background(), identityKey, &SignedPacket{})
+		require.ErrorContains(t, err, "unexpected status 500")
+
+		_, err = relay.Get(context.Background(), identityKey)
+		require.ErrorContains(t, err, "unexpected status 500")
+
+		status = http.StatusOK
+
+		for _, size := range []int{sigSize + seqSize - 1, sigSize + seqSize + maxPacketSize + 1} {
+			payload = make([]byte, size)
+
+			_, err = relay.Get(context.Background(), identityKey)
+			require.ErrorIs(t, err, ErrInvalidPacket)
+		}
+
+		_, err = NewHTTPRelay("http://[::1]:namedport", srv.Client()).Get(context.Background(), identityKey)
+		require.ErrorContains(t, err, "create relay request")
+	})
+}
@@ -0,0 +1,104 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dht
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const schemaResV1 = "https://w3id.org/did-resolution/v1"
+
+// Read resolves a did:dht DID.
+func (v *VDR) Read(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	return v.ReadContext(context.Background(), didID, opts...)
+}
+
+// ReadContext resolves a did:dht DID, the request to the relay is bound to the given context.
+//
+// The signed packet of the DID is fetched from the relay, its BEP44 signature is verified with the identity key of
+// the DID and its sequence number must not be lower than the one of the previous resolution of the DID by the VDR.
+// The DID document is decoded from the TXT records of the packet, a packet without records deactivates the DID.
+func (v *VDR) ReadContext(ctx context.Context, didID string,
+	_ ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	identityKey, err := parseDID(didID)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving did:dht did --> %w", err)
+	}
+
+	packet, err := v.relay.Get(ctx, identityKey)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving did:dht did --> %w", err)
+	}
+
+	if err = packet.verify(identityKey); err != nil {
+		return nil, fmt.Errorf("error resolving did:dht did --> %w", err)
+	}
+
+	if err = v.checkSeq(didID, packet.Seq); err != nil {
+		return nil, fmt.Errorf("error resolving did:dht did --> %w", err)
+	}
+
+	docResolution, err := createResolution(packet, identityKey)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving did:dht did --> %w", err)
+	}
+
+	return docResolution, nil
+}
+
+func parseDID(didID string) (ed25519.PublicKey, error) {
+	parsed, err := did.Parse(didID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DID: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	if parsed.Method != DIDMethod {
+		return nil, fmt.Errorf("invalid method: %s: %w", parsed.Method, vdrapi.ErrInvalidDID)
+	}
+
+	identityKey, err := identityKeyFromID(parsed.MethodSpecificID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	return identityKey, nil
+}
+
+// createResolution decodes the DID document of a verified packet.
+func createResolution(packet *SignedPacket, identityKey ed25519.PublicKey) (*did.DocResolution, error) {
+	records, err := decodePacket(packet.Packet)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPacket, err)
+	}
+
+	metadata := &did.DocumentMetadata{
+		VersionID: strconv.FormatInt(packet.Seq, 10),
+		Updated:   time.Unix(packet.Seq, 0).UTC().Format(time.RFC3339),
+	}
+
+	doc := &did.Doc{Context: []string{schemaDIDV1}, ID: didFromIdentityKey(identityKey)}
+
+	if len(records) == 0 {
+		metadata.Deactivated = true
+	} else if doc, err = decodeDoc(records, identityKey); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPacket, err)
+	}
+
+	return &did.DocResolution{
+		Context:            []string{schemaResV1},
+		DIDDocument:        doc,
+		DocumentMetadata:   metadata,
+		ResolutionMetadata: &did.ResolutionMetadata{ContentType: did.ContentTypeDIDLDJSON},
+	}, nil
+}
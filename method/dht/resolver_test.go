@@ -0,0 +1,349 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dht
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/doc/did/endpoint"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+// Test vectors of the did:dht spec (https://did-dht.com/#test-vectors).
+const (
+	vectorIdentityKey = "YCcHYL2sYNPDlKaALcEmll2HHyT968M4UWbr-9CFGWE"
+	vectorDID         = "did:dht:cyuoqaf7itop8ohww4yn5ojg13qaq83r9zihgqntc5i9zwrfdfoo"
+
+	vectorSecp256k1X   = "1_o0IKHGNamet8-3VYNUTiKlhVK-LilcKrhJSPHSNP0"
+	vectorSecp256k1Y   = "qzU8qqh0wKB6JC_9HCu8pHE-ZPkDpw4AdJ-MsV2InVY"
+	vectorSecp256k1Kid = "0GkvkdCGu3DL7Mkv0W1DhTMCBT9-z0CkFqZoJQtw7vw"
+
+	// vector1Packet is the DNS packet of the records of vector 1, without questions and with the answers in the
+	// order of the spec.
+	vector1Packet = "000084000000000200000000" +
+		// _did.cyuoqaf7itop8ohww4yn5ojg13qaq83r9zihgqntc5i9zwrfdfoo. TXT IN 7200
+		// "v=0;vm=k0;auth=k0;asm=k0;inv=k0;del=k0"
+		"045f646964346379756f7161663769746f70386f68777734796e356f6a67313371617138" +
+		"3372397a696867716e74633569397a77726664666f6f000010000100001c20002726763d303b766d3d6b303b61757468" +
+		"3d6b303b61736d3d6b303b696e763d6b303b64656c3d6b30" +
+		// _k0._did. TXT IN 7200 "id=0;t=0;k=YCcHYL2sYNPDlKaALcEmll2HHyT968M4UWbr-9CFGWE"
+		"035f6b30045f646964000010000100001c2000373669643d" +
+		"303b743d303b6b3d59436348594c3273594e50446c4b61414c63456d6c6c32484879543936384d34555762722d394346" +
+		"475745"
+)
+
+func TestRead(t *testing.T) {
+	t.Run("test resolve did", func(t *testing.T) {
+		signer := newSigner(t)
+		relay := NewMemoryRelay()
+
+		created, err := New(WithRelay(relay)).Create(&did.Doc{AlsoKnownAs: []string{"https://example.com"}},
+			vdrapi.WithOption(SignerOpt, signer))
+		require.NoError(t, err)
+
+		resolved, err := New(WithRelay(relay)).Read(created.DIDDocument.ID)
+		require.NoError(t, err)
+		require.Equal(t, created.DIDDocument.ID, resolved.DIDDocument.ID)
+		require.Equal(t, []string{schemaDIDV1, jwsSuiteV1}, resolved.DIDDocument.Context)
+		require.Equal(t, did.ContentTypeDIDLDJSON, resolved.ResolutionMetadata.ContentType)
+		require.NotEmpty(t, resolved.DocumentMetadata.Updated)
+		require.False(t, resolved.DocumentMetadata.Deactivated)
+	})
+
+	t.Run("test invalid did", func(t *testing.T) {
+		v := New(WithRelay(NewMemoryRelay()))
+		identityKey := newSigner(t).IdentityKey()
+
+		for _, didID := range []string{
+			"did:dht",
+			"did:web:example.com",
+			"did:dht:abc",
+			"did:dht:" + zbase32.EncodeToString(identityKey[:31]),
+			// the last character has non-zero trailing bits.
+			"did:dht:" + zbase32.EncodeToString(identityKey)[:51] + "9",
+		} {
+			_, err := v.Read(didID)
+			require.ErrorIs(t, err, vdrapi.ErrInvalidDID, didID)
+		}
+	})
+
+	t.Run("test did not found", func(t *testing.T) {
+		_, err := New(WithRelay(NewMemoryRelay())).Read(didFromIdentityKey(newSigner(t).IdentityKey()))
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+	})
+
+	t.Run("test invalid packets", func(t *testing.T) {
+		signer := newSigner(t)
+		relay := NewMemoryRelay()
+		v := New(WithRelay(relay))
+
+		created, err := v.Create(&did.Doc{}, vdrapi.WithOption(SignerOpt, signer))
+		require.NoError(t, err)
+
+		didID := created.DIDDocument.ID
+
+		packet, err := relay.Get(context.Background(), signer.IdentityKey())
+		require.NoError(t, err)
+
+		putPacket := func(p *SignedPacket) {
+			require.NoError(t, relay.Put(context.Background(), signer.IdentityKey(), p))
+		}
+
+		tampered := append([]byte{}, packet.Packet...)
+		tampered[len(tampered)-1] ^= 1
+
+		putPacket(&SignedPacket{Sig: packet.Sig, Seq: packet.Seq, Packet: tampered})
+		_, err = v.Read(didID)
+		require.ErrorIs(t, err, ErrInvalidPacket)
+
+		putPacket(&SignedPacket{Sig: packet.Sig, Seq: packet.Seq + 1, Packet: packet.Packet})
+		_, err = v.Read(didID)
+		require.ErrorIs(t, err, ErrInvalidPacket)
+
+		otherSigner := newSigner(t)
+		otherPacket, err := signPacket(otherSigner, packet.Seq, packet.Packet)
+		require.NoError(t, err)
+
+		putPacket(otherPacket)
+		_, err = v.Read(didID)
+		require.ErrorIs(t, err, ErrInvalidPacket)
+
+		// a packet without the identity key.
+		records, err := encodeDoc(&did.Doc{}, otherSigner.IdentityKey())
+		require.NoError(t, err)
+
+		otherKeyPacket, err := encodePacket(records)
+		require.NoError(t, err)
+
+		signed, err := signPacket(signer, packet.Seq, otherKeyPacket)
+		require.NoError(t, err)
+
+		putPacket(signed)
+		_, err = v.Read(didID)
+		require.ErrorIs(t, err, ErrInvalidPacket)
+
+		signed, err = signPacket(signer, packet.Seq, []byte("not a dns packet"))
+		require.NoError(t, err)
+
+		putPacket(signed)
+		_, err = v.Read(didID)
+		require.ErrorIs(t, err, ErrInvalidPacket)
+	})
+
+	t.Run("test sequence number rollback", func(t *testing.T) {
+		signer := newSigner(t)
+		relay := NewMemoryRelay()
+		v := New(WithRelay(relay))
+
+		created, err := v.Create(&did.Doc{}, vdrapi.WithOption(SignerOpt, signer))
+		require.NoError(t, err)
+
+		didID := created.DIDDocument.ID
+
+		older, err := relay.Get(context.Background(), signer.IdentityKey())
+		require.NoError(t, err)
+
+		require.NoError(t, v.Update(&did.Doc{ID: didID}, vdrapi.WithOption(SignerOpt, signer)))
+
+		_, err = v.Read(didID)
+		require.NoError(t, err)
+
+		require.NoError(t, relay.Put(context.Background(), signer.IdentityKey(), older))
+
+		_, err = v.Read(didID)
+		require.ErrorIs(t, err, ErrSequenceRollback)
+
+		// a new VDR has not seen the later sequence number.
+		_, err = New(WithRelay(relay)).Read(didID)
+		require.NoError(t, err)
+	})
+}
+
+func TestVDR(t *testing.T) {
+	v := New()
+
+	require.True(t, v.Accept(DIDMethod))
+	require.False(t, v.Accept("web"))
+	require.NoError(t, v.Close())
+}
+
+func TestCheckSeq(t *testing.T) {
+	t.Run("test least recently used dids evicted", func(t *testing.T) {
+		v := New(WithMaxSeqs(2))
+		v.now = func() time.Time { return time.Unix(5, 0) }
+
+		require.NoError(t, v.checkSeq("did:dht:a", 10))
+		require.NoError(t, v.checkSeq("did:dht:b", 10))
+		require.NoError(t, v.checkSeq("did:dht:a", 11))
+		require.NoError(t, v.checkSeq("did:dht:c", 10))
+		require.Len(t, v.seqs, 2)
+
+		require.ErrorIs(t, v.checkSeq("did:dht:a", 10), ErrSequenceRollback)
+		require.Equal(t, int64(12), v.nextSeq("did:dht:a"))
+		require.NoError(t, v.checkSeq("did:dht:b", 9))
+		require.Equal(t, int64(11), v.nextSeq("did:dht:c"))
+		require.Equal(t, int64(5), v.nextSeq("did:dht:a"))
+	})
+
+	t.Run("test rollback detection disabled", func(t *testing.T) {
+		v := New(WithMaxSeqs(0))
+
+		require.NoError(t, v.checkSeq("did:dht:a", 10))
+		require.NoError(t, v.checkSeq("did:dht:a", 9))
+		require.Empty(t, v.seqs)
+	})
+}
+
+func TestSpecVectors(t *testing.T) {
+	identityKey, err := base64.RawURLEncoding.DecodeString(vectorIdentityKey)
+	require.NoError(t, err)
+	require.Equal(t, vectorDID, didFromIdentityKey(identityKey))
+
+	identityVM := did.NewVerificationMethodFromBytes("#0", "Ed25519VerificationKey2020", "", identityKey)
+
+	t.Run("test vector 1", func(t *testing.T) {
+		records, err := encodeDoc(&did.Doc{VerificationMethod: []did.VerificationMethod{*identityVM}}, identityKey)
+		require.NoError(t, err)
+		require.Equal(t, []txtRecord{
+			{name: "_did.cyuoqaf7itop8ohww4yn5ojg13qaq83r9zihgqntc5i9zwrfdfoo.",
+				value: "v=0;vm=k0;auth=k0;asm=k0;inv=k0;del=k0"},
+			{name: "_k0._did.", value: "id=0;t=0;k=YCcHYL2sYNPDlKaALcEmll2HHyT968M4UWbr-9CFGWE"},
+		}, records)
+
+		packet, err := encodePacket(records)
+		require.NoError(t, err)
+		require.Equal(t, vector1Packet, hex.EncodeToString(packet))
+
+		packet, err = hex.DecodeString(vector1Packet)
+		require.NoError(t, err)
+
+		resolved, err := createResolution(&SignedPacket{Seq: 1, Packet: packet}, identityKey)
+		require.NoError(t, err)
+
+		doc := resolved.DIDDocument
+		require.Equal(t, vectorDID, doc.ID)
+		require.Len(t, doc.VerificationMethod, 1)
+		require.Equal(t, vectorDID+"#0", doc.VerificationMethod[0].ID)
+		require.Equal(t, vectorDID, doc.VerificationMethod[0].Controller)
+		require.Equal(t, "Ed25519", doc.VerificationMethod[0].JSONWebKey().Crv)
+		require.Equal(t, ed25519.PublicKey(identityKey), doc.VerificationMethod[0].JSONWebKey().Key)
+
+		for _, verifications := range [][]did.Verification{
+			doc.Authentication, doc.AssertionMethod, doc.CapabilityInvocation, doc.CapabilityDelegation,
+		} {
+			require.Len(t, verifications, 1)
+			require.Equal(t, vectorDID+"#0", verifications[0].VerificationMethod.ID)
+		}
+
+		require.Empty(t, doc.KeyAgreement)
+		require.Empty(t, doc.Service)
+	})
+
+	t.Run("test vector 2", func(t *testing.T) {
+		x, err := base64.RawURLEncoding.DecodeString(vectorSecp256k1X)
+		require.NoError(t, err)
+
+		y, err := base64.RawURLEncoding.DecodeString(vectorSecp256k1Y)
+		require.NoError(t, err)
+
+		secp256k1Key, err := btcec.ParsePubKey(append([]byte{0x02 + y[len(y)-1]&1}, x...))
+		require.NoError(t, err)
+
+		// the key ID is the RFC 7638 thumbprint of the key.
+		thumbprintID, err := vmID(newJWKVM(t, "", secp256k1Key.ToECDSA()), keyTypeSecp256k1,
+			secp256k1Key.SerializeCompressed())
+		require.NoError(t, err)
+		require.Equal(t, vectorSecp256k1Kid, thumbprintID)
+
+		secp256k1VM := newJWKVM(t, "#"+vectorSecp256k1Kid, secp256k1Key.ToECDSA())
+
+		// vector 2 also has the _typ._did. record "id=7,6" of the DID types, which did.Doc does not model.
+		spec := []txtRecord{
+			{name: "_did.cyuoqaf7itop8ohww4yn5ojg13qaq83r9zihgqntc5i9zwrfdfoo.",
+				value: "v=0;vm=k0,k1;svc=s0;auth=k0;asm=k0,k1;inv=k0,k1;del=k0"},
+			{name: "_typ._did.", value: "id=7,6"},
+			{name: "_k0._did.", value: "id=0;t=0;k=YCcHYL2sYNPDlKaALcEmll2HHyT968M4UWbr-9CFGWE"},
+			{name: "_k1._did.", value: "id=0GkvkdCGu3DL7Mkv0W1DhTMCBT9-z0CkFqZoJQtw7vw;t=1;" +
+				"k=Atf6NCChxjWpnrfPt1WDVE4ipYVSvi4pXCq4SUjx0jT9"},
+			{name: "_s0._did.",
+				value: "id=service-1;t=TestService;se=https://test-service.com/1,https://test-service.com/2"},
+		}
+
+		records, err := encodeDoc(&did.Doc{
+			VerificationMethod: []did.VerificationMethod{*identityVM, *secp256k1VM},
+			AssertionMethod:    []did.Verification{*did.NewReferencedVerification(secp256k1VM, did.AssertionMethod)},
+			CapabilityInvocation: []did.Verification{
+				*did.NewReferencedVerification(secp256k1VM, did.CapabilityInvocation),
+			},
+			Service: []did.Service{{
+				ID:   "#service-1",
+				Type: "TestService",
+				ServiceEndpoint: endpoint.NewDIDCoreEndpoint([]string{
+					"https://test-service.com/1", "https://test-service.com/2",
+				}),
+			}},
+		}, identityKey)
+		require.NoError(t, err)
+
+		// the properties of the root record are unordered.
+		require.Equal(t, spec[0].name, records[0].name)
+		require.Equal(t, properties(spec[0].value), properties(records[0].value))
+		require.Equal(t, spec[2:], records[1:])
+
+		doc, err := decodeDoc(spec, identityKey)
+		require.NoError(t, err)
+		require.Len(t, doc.VerificationMethod, 2)
+
+		vm := doc.VerificationMethod[1]
+		require.Equal(t, vectorDID+"#"+vectorSecp256k1Kid, vm.ID)
+
+		jwkBytes, err := vm.JSONWebKey().MarshalJSON()
+		require.NoError(t, err)
+		require.JSONEq(t, `{"kty":"EC","crv":"secp256k1","x":"`+base64.RawURLEncoding.EncodeToString(x)+
+			`","y":"`+base64.RawURLEncoding.EncodeToString(y)+`"}`, string(jwkBytes))
+
+		require.Len(t, doc.AssertionMethod, 2)
+		require.Equal(t, vm.ID, doc.AssertionMethod[1].VerificationMethod.ID)
+		require.Len(t, doc.CapabilityInvocation, 2)
+		require.Len(t, doc.CapabilityDelegation, 1)
+
+		require.Len(t, doc.Service, 1)
+		require.Equal(t, vectorDID+"#service-1", doc.Service[0].ID)
+		require.Equal(t, "TestService", doc.Service[0].Type)
+
+		endpointJSON, err := doc.Service[0].ServiceEndpoint.MarshalJSON()
+		require.NoError(t, err)
+		require.JSONEq(t, `["https://test-service.com/1","https://test-service.com/2"]`, string(endpointJSON))
+	})
+
+	t.Run("test BEP44 mutable item vector", func(t *testing.T) {
+		// Test vector 1 of BEP44 (https://www.bittorrent.org/beps/bep_0044.html).
+		publicKey, err := hex.DecodeString("77ff84905a91936367c01360803104f92432fcd904a43511876df5cdf3e7e548")
+		require.NoError(t, err)
+
+		sig, err := hex.DecodeString("305ac8aeb6c9c151fa120f120ea2cfb923564e11552d06a5d856091e5e853cff" +
+			"1260d3f39e4999684aa92eb73ffd136e6f4f3ecbfda0ce53a1608ecd7ae21f01")
+		require.NoError(t, err)
+
+		require.Equal(t, "3:seqi1e1:v12:Hello World!", string(bep44Signable(1, []byte("Hello World!"))))
+
+		packet := &SignedPacket{Sig: sig, Seq: 1, Packet: []byte("Hello World!")}
+		require.NoError(t, packet.verify(publicKey))
+
+		packet.Seq = 2
+		require.ErrorIs(t, packet.verify(publicKey), ErrInvalidPacket)
+	})
+}
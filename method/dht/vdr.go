@@ -0,0 +1,146 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package dht implements the did:dht method (https://did-dht.com). The DID document is encoded as a DNS packet of
+// TXT records, signed with the Ed25519 identity key of the DID and stored as a BEP44 mutable item of the Mainline
+// DHT, which the VDR publishes and fetches through a Pkarr relay.
+package dht
+
+import (
+	"container/list"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	// DIDMethod did method.
+	DIDMethod = "dht"
+
+	// DefaultRelayURL is the URL of the public Pkarr relay.
+	DefaultRelayURL = "https://relay.pkarr.org"
+
+	defaultTimeout = 10 * time.Second
+	defaultMaxSeqs = 1000
+)
+
+var errorLogger = log.New(os.Stderr, " [did-go/vdr/dht] ", log.Ldate|log.Ltime|log.LUTC)
+
+// VDR implements did:dht method support.
+type VDR struct {
+	relay Relay
+	now   func() time.Time
+
+	mutex sync.Mutex
+	// seqs maps DIDs to the highest sequence numbers seen for them, a relay returning a lower one is rolling back the
+	// DID. Least recently used entries are evicted once maxSeqs is reached.
+	seqs    map[string]*list.Element
+	lru     *list.List
+	maxSeqs int
+}
+
+type seqEntry struct {
+	didID string
+	seq   int64
+}
+
+// Option configures the did:dht vdr.
+type Option func(opts *VDR)
+
+// WithRelay sets the relay the DIDs are published to and fetched from, an HTTP relay of DefaultRelayURL by default.
+func WithRelay(relay Relay) Option {
+	return func(opts *VDR) {
+		opts.relay = relay
+	}
+}
+
+// New returns new instance of VDR that works with did:dht method.
+func New(opts ...Option) *VDR {
+	v := &VDR{
+		relay:   NewHTTPRelay(DefaultRelayURL, &http.Client{Timeout: defaultTimeout}),
+		now:     time.Now,
+		seqs:    make(map[string]*list.Element),
+		lru:     list.New(),
+		maxSeqs: defaultMaxSeqs,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// WithMaxSeqs sets for how many DIDs the highest sequence number seen is kept to detect rollbacks, 1000 by default.
+// A zero size disables rollback detection.
+func WithMaxSeqs(size int) Option {
+	return func(opts *VDR) {
+		opts.maxSeqs = size
+	}
+}
+
+// Accept accepts did:dht method.
+func (v *VDR) Accept(method string, opts ...vdrapi.DIDMethodOption) bool {
+	return method == DIDMethod
+}
+
+// Close frees resources being maintained by VDR.
+func (v *VDR) Close() error {
+	return nil
+}
+
+// checkSeq records the sequence number of a DID, and fails if a higher one has been seen.
+func (v *VDR) checkSeq(didID string, seq int64) error {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if elem, ok := v.seqs[didID]; ok {
+		e := elem.Value.(*seqEntry) //nolint:errcheck,forcetypeassert
+
+		if seq < e.seq {
+			return fmt.Errorf("%w: sequence number %d is lower than %d", ErrSequenceRollback, seq, e.seq)
+		}
+
+		e.seq = seq
+		v.lru.MoveToFront(elem)
+
+		return nil
+	}
+
+	if v.maxSeqs <= 0 {
+		return nil
+	}
+
+	v.seqs[didID] = v.lru.PushFront(&seqEntry{didID: didID, seq: seq})
+
+	for v.lru.Len() > v.maxSeqs {
+		e := v.lru.Remove(v.lru.Back()).(*seqEntry) //nolint:errcheck,forcetypeassert
+
+		delete(v.seqs, e.didID)
+	}
+
+	return nil
+}
+
+// nextSeq returns the sequence number of the next packet of a DID: the current time in seconds, above every sequence
+// number seen for the DID.
+func (v *VDR) nextSeq(didID string) int64 {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	next := v.now().Unix()
+
+	if elem, ok := v.seqs[didID]; ok {
+		next = max(next, elem.Value.(*seqEntry).seq+1) //nolint:forcetypeassert
+	}
+
+	return next
+}
@@ -0,0 +1,85 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ebsi
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/method/jwk"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	jsonWebKey2020 = "JsonWebKey2020"
+
+	// naturalPersonVersion is the first byte of natural person identifiers, legal entities use 0x01.
+	naturalPersonVersion = 0x02
+)
+
+// Create creates the natural person DID of the JsonWebKey2020 verification method of didDoc.
+func (v *VDR) Create(didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	if len(didDoc.VerificationMethod) != 1 {
+		return nil, errors.New("did document must have exactly one verification method")
+	}
+
+	if didDoc.VerificationMethod[0].Type != jsonWebKey2020 {
+		return nil, fmt.Errorf("verification method type[%s] is not supported", didDoc.VerificationMethod[0].Type)
+	}
+
+	key := didDoc.VerificationMethod[0].JSONWebKey()
+	if key == nil {
+		return nil, errors.New("missing JWK")
+	}
+
+	keyBytes, err := key.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("marshal key: %w", err)
+	}
+
+	thumbprint, err := jwk.Thumbprint(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error creating DID: %w", err)
+	}
+
+	didID, err := naturalPersonDID(thumbprint)
+	if err != nil {
+		return nil, fmt.Errorf("error creating DID: %w", err)
+	}
+
+	doc, err := createDoc(didID, keyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &did.DocResolution{Context: []string{schemaResV1}, DIDDocument: doc}, nil
+}
+
+// naturalPersonDID returns the natural person DID of a base64url encoded key thumbprint: the version byte followed by
+// the thumbprint, multibase base58btc encoded.
+func naturalPersonDID(thumbprint string) (string, error) {
+	hash, err := base64.RawURLEncoding.DecodeString(thumbprint)
+	if err != nil {
+		return "", fmt.Errorf("decode thumbprint: %w", err)
+	}
+
+	return fmt.Sprintf("did:%s:z%s", DIDMethod, base58.Encode(append([]byte{naturalPersonVersion}, hash...))), nil
+}
+
+// createDoc creates the did:jwk layout DID document of the key, the key thumbprint being the verification method ID.
+func createDoc(didID string, keyBytes []byte) (*did.Doc, error) {
+	doc, err := jwk.DocFromKey(didID, keyBytes, vdrapi.WithOption(jwk.ThumbprintKeyIDOpt, true))
+	if err != nil {
+		return nil, fmt.Errorf("create did document: %w", err)
+	}
+
+	return doc, nil
+}
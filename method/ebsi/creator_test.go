@@ -0,0 +1,65 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ebsi_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/method/ebsi"
+)
+
+func TestCreate(t *testing.T) {
+	t.Run("test create", func(t *testing.T) {
+		var key jwk.JWK
+
+		require.NoError(t, json.Unmarshal([]byte(rsaJWK), &key))
+
+		vm, err := did.NewVerificationMethodFromJWK("", "JsonWebKey2020", "", &key)
+		require.NoError(t, err)
+
+		docResolution, err := ebsi.New().Create(&did.Doc{VerificationMethod: []did.VerificationMethod{*vm}})
+		require.NoError(t, err)
+
+		doc := docResolution.DIDDocument
+		require.Equal(t, rsaDID, doc.ID)
+		require.Equal(t, rsaDID+"#NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", doc.VerificationMethod[0].ID)
+		require.Equal(t, doc.VerificationMethod[0].ID, doc.AssertionMethod[0].VerificationMethod.ID)
+	})
+
+	t.Run("error - private key", func(t *testing.T) {
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		key, err := jwksupport.JWKFromKey(privateKey)
+		require.NoError(t, err)
+
+		vm, err := did.NewVerificationMethodFromJWK("", "JsonWebKey2020", "", key)
+		require.NoError(t, err)
+
+		_, err = ebsi.New().Create(&did.Doc{VerificationMethod: []did.VerificationMethod{*vm}})
+		require.ErrorContains(t, err, "must not contain private key material")
+	})
+
+	t.Run("error - invalid verification methods", func(t *testing.T) {
+		_, err := ebsi.New().Create(&did.Doc{})
+		require.ErrorContains(t, err, "exactly one verification method")
+
+		_, err = ebsi.New().Create(&did.Doc{VerificationMethod: []did.VerificationMethod{{Type: "Multikey"}}})
+		require.ErrorContains(t, err, "verification method type[Multikey] is not supported")
+
+		_, err = ebsi.New().Create(&did.Doc{VerificationMethod: []did.VerificationMethod{{Type: "JsonWebKey2020"}}})
+		require.ErrorContains(t, err, "missing JWK")
+	})
+}
@@ -0,0 +1,132 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ebsi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+	kmsjwk "github.com/trustbloc/kms-go/doc/jose/jwk"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/method/jwk"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	schemaResV1 = "https://w3id.org/did-resolution/v1"
+
+	legalEntityVersion = 0x01
+	thumbprintSize     = 32
+)
+
+// Read resolves a did:ebsi natural person DID from its key, given with the JWKOpt option.
+//
+// The identifier of a natural person DID is derived from the RFC 7638 thumbprint of its key, so that the DID
+// document, which has the did:jwk layout, is created offline. Legal entity DIDs are only resolvable from the EBSI
+// DID registry.
+func (v *VDR) Read(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	parsed, err := did.Parse(didID)
+	if err != nil {
+		return nil, fmt.Errorf("ebsi-vdr read: failed to parse DID: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	if parsed.Method != DIDMethod {
+		return nil, fmt.Errorf("ebsi-vdr read: invalid method: %s: %w", parsed.Method, vdrapi.ErrInvalidDID)
+	}
+
+	if err = checkNaturalPersonID(parsed.MethodSpecificID); err != nil {
+		return nil, fmt.Errorf("ebsi-vdr read: %w", err)
+	}
+
+	keyBytes, err := keyOpt(opts)
+	if err != nil {
+		return nil, fmt.Errorf("ebsi-vdr read: %w", err)
+	}
+
+	thumbprint, err := jwk.Thumbprint(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("ebsi-vdr read: invalid key: %w", err)
+	}
+
+	keyDID, err := naturalPersonDID(thumbprint)
+	if err != nil {
+		return nil, fmt.Errorf("ebsi-vdr read: %w", err)
+	}
+
+	if keyDID != didID {
+		return nil, fmt.Errorf("ebsi-vdr read: %w", ErrKeyMismatch)
+	}
+
+	doc, err := createDoc(didID, keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("ebsi-vdr read: %w", err)
+	}
+
+	return &did.DocResolution{
+		Context:            []string{schemaResV1},
+		DIDDocument:        doc,
+		ResolutionMetadata: &did.ResolutionMetadata{ContentType: did.ContentTypeDIDLDJSON},
+	}, nil
+}
+
+// ReadContext resolves a did:ebsi natural person DID. Resolution is done offline, so the context is only checked
+// for cancellation.
+func (v *VDR) ReadContext(ctx context.Context, didID string,
+	opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return v.Read(didID, opts...)
+}
+
+// checkNaturalPersonID checks the method specific ID is a multibase base58btc encoded natural person identifier.
+func checkNaturalPersonID(id string) error {
+	if len(id) < 2 || id[0] != 'z' {
+		return fmt.Errorf("method specific ID must be base58btc multibase encoded: %w", vdrapi.ErrInvalidDID)
+	}
+
+	decoded := base58.Decode(id[1:])
+
+	switch {
+	case len(decoded) == 0:
+		return fmt.Errorf("invalid base58btc method specific ID: %w", vdrapi.ErrInvalidDID)
+	case decoded[0] == legalEntityVersion:
+		return fmt.Errorf("legal entity DIDs are resolved from the EBSI DID registry: %w", vdrapi.ErrNotFound)
+	case decoded[0] != naturalPersonVersion || len(decoded) != thumbprintSize+1:
+		return fmt.Errorf("not a natural person identifier: %w", vdrapi.ErrInvalidDID)
+	}
+
+	return nil
+}
+
+func keyOpt(opts []vdrapi.DIDMethodOption) ([]byte, error) {
+	didOpts := &vdrapi.DIDMethodOpts{Values: make(map[string]interface{})}
+	// Apply options
+	for _, opt := range opts {
+		opt(didOpts)
+	}
+
+	switch key := didOpts.Values[JWKOpt].(type) {
+	case *kmsjwk.JWK:
+		keyBytes, err := key.MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("marshal jwk opt: %w", err)
+		}
+
+		return keyBytes, nil
+	case []byte:
+		return key, nil
+	case nil:
+		return nil, errors.New("missing jwk opt")
+	default:
+		return nil, errors.New("jwk opt must be a *jwk.JWK or []byte")
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ebsi_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/method/ebsi"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+// RFC 7638 section 3.1 example key, its thumbprint is NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs.
+//
+//nolint:lll
+const (
+	rsaJWK = `{"kty":"RSA","n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw","e":"AQAB","alg":"RS256","kid":"2011-04-29"}`
+	rsaDID = "did:ebsi:zfBAdKZ6QeM6gKy9Sb3AGfjELBW5XzBJYYjV3uCk2Lr58"
+)
+
+func TestRead(t *testing.T) {
+	t.Run("test resolve did", func(t *testing.T) {
+		docResolution, err := ebsi.New().Read(rsaDID, vdrapi.WithOption(ebsi.JWKOpt, []byte(rsaJWK)))
+		require.NoError(t, err)
+
+		doc := docResolution.DIDDocument
+		require.Equal(t, rsaDID, doc.ID)
+		require.Len(t, doc.VerificationMethod, 1)
+		require.Equal(t, rsaDID+"#NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", doc.VerificationMethod[0].ID)
+		require.Equal(t, "JsonWebKey2020", doc.VerificationMethod[0].Type)
+		require.Equal(t, rsaDID, doc.VerificationMethod[0].Controller)
+		require.Len(t, doc.Authentication, 1)
+		require.Len(t, doc.AssertionMethod, 1)
+	})
+
+	t.Run("test resolve did with jwk", func(t *testing.T) {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		key, err := jwksupport.JWKFromKey(&privateKey.PublicKey)
+		require.NoError(t, err)
+
+		vm, err := did.NewVerificationMethodFromJWK("", "JsonWebKey2020", "", key)
+		require.NoError(t, err)
+
+		created, err := ebsi.New().Create(&did.Doc{VerificationMethod: []did.VerificationMethod{*vm}})
+		require.NoError(t, err)
+
+		docResolution, err := ebsi.New().ReadContext(context.Background(), created.DIDDocument.ID,
+			vdrapi.WithOption(ebsi.JWKOpt, key))
+		require.NoError(t, err)
+		require.Equal(t, created.DIDDocument.VerificationMethod[0].ID, docResolution.DIDDocument.VerificationMethod[0].ID)
+	})
+
+	t.Run("test key mismatch", func(t *testing.T) {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		key, err := jwksupport.JWKFromKey(&privateKey.PublicKey)
+		require.NoError(t, err)
+
+		_, err = ebsi.New().Read(rsaDID, vdrapi.WithOption(ebsi.JWKOpt, key))
+		require.ErrorIs(t, err, ebsi.ErrKeyMismatch)
+	})
+
+	t.Run("test invalid dids", func(t *testing.T) {
+		for _, didID := range []string{
+			"did:ebsi",
+			"did:key:zfBAdKZ6QeM6gKy9Sb3AGfjELBW5XzBJYYjV3uCk2Lr58",
+			"did:ebsi:2L91pL1QthRXTRjW1HEJTBs5c1Ex7S7fMEGUhAiT5FEFz",
+			"did:ebsi:z0OIl",
+			"did:ebsi:z" + base58.Encode(append([]byte{0x02}, make([]byte, 16)...)),
+			"did:ebsi:z" + base58.Encode(append([]byte{0x03}, make([]byte, 32)...)),
+		} {
+			_, err := ebsi.New().Read(didID, vdrapi.WithOption(ebsi.JWKOpt, []byte(rsaJWK)))
+			require.ErrorIs(t, err, vdrapi.ErrInvalidDID, didID)
+		}
+
+		_, err := ebsi.New().Read("did:ebsi:z" + base58.Encode(append([]byte{0x01}, make([]byte, 16)...)))
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+	})
+
+	t.Run("test invalid keys", func(t *testing.T) {
+		_, err := ebsi.New().Read(rsaDID)
+		require.ErrorContains(t, err, "missing jwk opt")
+
+		_, err = ebsi.New().Read(rsaDID, vdrapi.WithOption(ebsi.JWKOpt, rsaJWK))
+		require.ErrorContains(t, err, "jwk opt must be a *jwk.JWK or []byte")
+
+		_, err = ebsi.New().Read(rsaDID, vdrapi.WithOption(ebsi.JWKOpt, []byte(`{"kty":"oct","k":"AAAA"}`)))
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+
+		_, err = ebsi.New().Read(rsaDID, vdrapi.WithOption(ebsi.JWKOpt, []byte(`{"kty":"EC","crv":"P-192"}`)))
+		require.ErrorIs(t, err, vdrapi.ErrInvalidPublicKeyType)
+	})
+
+	t.Run("test cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := ebsi.New().ReadContext(ctx, rsaDID, vdrapi.WithOption(ebsi.JWKOpt, []byte(rsaJWK)))
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestVDR(t *testing.T) {
+	v := ebsi.New()
+	require.True(t, v.Accept(ebsi.DIDMethod))
+	require.False(t, v.Accept("key"))
+	require.NoError(t, v.Close())
+	require.ErrorContains(t, v.Update(&did.Doc{}), "not supported")
+	require.ErrorContains(t, v.Deactivate(rsaDID), "not supported")
+}
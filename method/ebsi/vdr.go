@@ -0,0 +1,55 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package ebsi implements the did:ebsi method for natural persons
+// (https://hub.ebsi.eu/vc-framework/did/natural-person), resolved offline from the key of the DID.
+package ebsi
+
+import (
+	"errors"
+
+	diddoc "github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	// DIDMethod did method.
+	DIDMethod = "ebsi"
+	// JWKOpt is the public key of the DID on Read, either as a *jwk.JWK or as its JSON []byte. The RFC 7638
+	// thumbprint of the key must match the DID.
+	JWKOpt = "jwk"
+)
+
+// ErrKeyMismatch is returned when the thumbprint of the key given with the JWKOpt option does not match the DID.
+var ErrKeyMismatch = errors.New("key does not match the did:ebsi identifier")
+
+// VDR implements did:ebsi method support for natural persons.
+type VDR struct{}
+
+// New returns new instance of VDR that works with did:ebsi method.
+func New() *VDR {
+	return &VDR{}
+}
+
+// Accept accepts did:ebsi method.
+func (v *VDR) Accept(method string, opts ...vdrapi.DIDMethodOption) bool {
+	return method == DIDMethod
+}
+
+// Close frees resources being maintained by VDR.
+func (v *VDR) Close() error {
+	return nil
+}
+
+// Update did doc.
+func (v *VDR) Update(didDoc *diddoc.Doc, opts ...vdrapi.DIDMethodOption) error {
+	return errors.New("not supported")
+}
+
+// Deactivate did doc.
+func (v *VDR) Deactivate(didID string, opts ...vdrapi.DIDMethodOption) error {
+	return errors.New("not supported")
+}
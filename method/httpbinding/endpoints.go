@@ -0,0 +1,195 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpbinding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// Strategy selects the order the resolver endpoints are tried in.
+type Strategy int
+
+const (
+	// Failover tries the endpoints in the configured order, the next endpoint is used when one fails.
+	Failover Strategy = iota
+	// RoundRobin starts every resolution with the next endpoint, spreading the load over all endpoints.
+	RoundRobin
+)
+
+const defaultCoolDown = 30 * time.Second
+
+// retryableError is a resolution failure that may succeed on another endpoint or a later attempt,
+// e.g. a timeout or a 5xx response.
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+type endpoint struct {
+	url            string
+	unhealthyUntil time.Time
+}
+
+// endpointPool tracks the health of the resolver endpoints, an endpoint that failed is skipped for the cool-down
+// period unless all endpoints failed.
+type endpointPool struct {
+	mutex     sync.Mutex
+	endpoints []*endpoint
+	strategy  Strategy
+	coolDown  time.Duration
+	next      int
+}
+
+func newEndpointPool(urls []string, strategy Strategy, coolDown time.Duration) (*endpointPool, error) {
+	pool := &endpointPool{strategy: strategy, coolDown: coolDown}
+
+	for _, u := range urls {
+		if _, err := url.ParseRequestURI(u); err != nil {
+			return nil, fmt.Errorf("base URL invalid: %w", err)
+		}
+
+		pool.endpoints = append(pool.endpoints, &endpoint{url: u})
+	}
+
+	return pool, nil
+}
+
+// ordered returns the endpoints to try, healthy endpoints first.
+func (p *endpointPool) ordered() []*endpoint {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	start := 0
+
+	if p.strategy == RoundRobin {
+		start = p.next
+		p.next = (p.next + 1) % len(p.endpoints)
+	}
+
+	now := time.Now()
+
+	var healthy, unhealthy []*endpoint
+
+	for i := range p.endpoints {
+		e := p.endpoints[(start+i)%len(p.endpoints)]
+
+		if now.Before(e.unhealthyUntil) {
+			unhealthy = append(unhealthy, e)
+		} else {
+			healthy = append(healthy, e)
+		}
+	}
+
+	if len(healthy) == 0 {
+		return unhealthy
+	}
+
+	return healthy
+}
+
+func (p *endpointPool) failed(e *endpoint) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	e.unhealthyUntil = time.Now().Add(p.coolDown)
+}
+
+func (p *endpointPool) succeeded(e *endpoint) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	e.unhealthyUntil = time.Time{}
+}
+
+// resolve resolves the DID with the endpoints, failing over on retryable errors and retrying with exponential
+// backoff once every endpoint failed.
+func (v *VDR) resolve(ctx context.Context, didID, query string) (*resolverResponse, error) {
+	var resp *resolverResponse
+
+	retries := backoff.WithContext(backoff.WithMaxRetries(backoff.NewExponentialBackOff(
+		backoff.WithInitialInterval(v.retryInterval)), v.maxRetries), ctx)
+
+	err := backoff.Retry(func() error {
+		var err error
+
+		resp, err = v.resolveEndpoints(ctx, didID, query)
+
+		var retryErr *retryableError
+		if err != nil && !errors.As(err, &retryErr) {
+			return backoff.Permanent(err)
+		}
+
+		return err
+	}, retries)
+
+	return resp, err
+}
+
+func (v *VDR) resolveEndpoints(ctx context.Context, didID, query string) (*resolverResponse, error) {
+	var lastErr error
+
+	for _, e := range v.endpoints.ordered() {
+		reqURL, err := url.ParseRequestURI(e.url)
+		if err != nil {
+			return nil, fmt.Errorf("url parse request uri failed: %w", err)
+		}
+
+		joinDID(reqURL, didID)
+		reqURL.RawQuery = query
+
+		resp, err := v.resolveDID(ctx, reqURL.String())
+
+		// a cancelled caller says nothing about the health of the endpoint
+		if err != nil && ctx.Err() != nil {
+			return nil, backoff.Permanent(err)
+		}
+
+		var retryErr *retryableError
+		if errors.As(err, &retryErr) {
+			errLogger.Printf("DID resolver %s failed: %v", e.url, err)
+			v.endpoints.failed(e)
+
+			lastErr = err
+
+			continue
+		}
+
+		v.endpoints.succeeded(e)
+
+		return resp, err
+	}
+
+	return nil, lastErr
+}
+
+// joinDID appends the DID to the endpoint path, percent-encoded characters of the DID are sent as they are.
+func joinDID(reqURL *url.URL, didID string) {
+	unescaped, err := url.PathUnescape(didID)
+	if err != nil {
+		reqURL.Path = path.Join(reqURL.Path, didID)
+
+		return
+	}
+
+	reqURL.RawPath = path.Join(reqURL.EscapedPath(), didID)
+	reqURL.Path = path.Join(reqURL.Path, unescaped)
+}
@@ -0,0 +1,265 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpbinding
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+type countingResolver struct {
+	calls    atomic.Int32
+	statuses []int
+}
+
+func (r *countingResolver) ServeHTTP(res http.ResponseWriter, _ *http.Request) {
+	call := int(r.calls.Add(1))
+
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status = r.statuses[min(call, len(r.statuses))-1]
+	}
+
+	if status != http.StatusOK {
+		res.WriteHeader(status)
+
+		return
+	}
+
+	res.Header().Add("Content-Type", "application/did+ld+json")
+	res.WriteHeader(http.StatusOK)
+	_, _ = res.Write([]byte(doc)) //nolint:errcheck
+}
+
+func TestEndpointFailover(t *testing.T) {
+	t.Run("test failover to the next endpoint", func(t *testing.T) {
+		failing := &countingResolver{statuses: []int{http.StatusServiceUnavailable}}
+		healthy := &countingResolver{}
+
+		failingServer := httptest.NewServer(failing)
+		defer failingServer.Close()
+
+		healthyServer := httptest.NewServer(healthy)
+		defer healthyServer.Close()
+
+		v, err := New(failingServer.URL, WithEndpoints(healthyServer.URL))
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			docResolution, e := v.Read("did:example:334455")
+			require.NoError(t, e)
+			require.Equal(t, "did:peer:21tDAKCERh95uGgKbJNHYp", docResolution.DIDDocument.ID)
+		}
+
+		// the failing endpoint is skipped during its cool-down period
+		require.Equal(t, int32(1), failing.calls.Load())
+		require.Equal(t, int32(3), healthy.calls.Load())
+	})
+
+	t.Run("test failing endpoint is used again after cool-down", func(t *testing.T) {
+		failing := &countingResolver{statuses: []int{http.StatusBadGateway, http.StatusOK}}
+		healthy := &countingResolver{}
+
+		failingServer := httptest.NewServer(failing)
+		defer failingServer.Close()
+
+		healthyServer := httptest.NewServer(healthy)
+		defer healthyServer.Close()
+
+		v, err := New(failingServer.URL, WithEndpoints(healthyServer.URL), WithEndpointCoolDown(time.Millisecond))
+		require.NoError(t, err)
+
+		_, err = v.Read("did:example:334455")
+		require.NoError(t, err)
+
+		time.Sleep(5 * time.Millisecond)
+
+		_, err = v.Read("did:example:334455")
+		require.NoError(t, err)
+		require.Equal(t, int32(2), failing.calls.Load())
+		require.Equal(t, int32(1), healthy.calls.Load())
+	})
+
+	t.Run("test all endpoints failing", func(t *testing.T) {
+		first := &countingResolver{statuses: []int{http.StatusInternalServerError}}
+		second := &countingResolver{statuses: []int{http.StatusInternalServerError}}
+
+		firstServer := httptest.NewServer(first)
+		defer firstServer.Close()
+
+		secondServer := httptest.NewServer(second)
+		defer secondServer.Close()
+
+		v, err := New(firstServer.URL, WithEndpoints(secondServer.URL))
+		require.NoError(t, err)
+
+		_, err = v.Read("did:example:334455")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported response from DID resolver [500]")
+
+		// all endpoints are unhealthy, they are still tried
+		_, err = v.Read("did:example:334455")
+		require.Error(t, err)
+		require.Equal(t, int32(2), first.calls.Load())
+		require.Equal(t, int32(2), second.calls.Load())
+	})
+
+	t.Run("test no failover for non retryable errors", func(t *testing.T) {
+		first := &countingResolver{statuses: []int{http.StatusNotFound}}
+		second := &countingResolver{}
+
+		firstServer := httptest.NewServer(first)
+		defer firstServer.Close()
+
+		secondServer := httptest.NewServer(second)
+		defer secondServer.Close()
+
+		v, err := New(firstServer.URL, WithEndpoints(secondServer.URL))
+		require.NoError(t, err)
+
+		_, err = v.Read("did:example:334455")
+		require.Equal(t, vdrapi.ErrNotFound, err)
+		require.Equal(t, int32(0), second.calls.Load())
+	})
+
+	t.Run("test not found after failover", func(t *testing.T) {
+		first := &countingResolver{statuses: []int{http.StatusServiceUnavailable}}
+		second := &countingResolver{statuses: []int{http.StatusNotFound}}
+
+		firstServer := httptest.NewServer(first)
+		defer firstServer.Close()
+
+		secondServer := httptest.NewServer(second)
+		defer secondServer.Close()
+
+		v, err := New(firstServer.URL, WithEndpoints(secondServer.URL))
+		require.NoError(t, err)
+
+		_, err = v.Read("did:example:334455")
+		require.Equal(t, vdrapi.ErrNotFound, err)
+	})
+
+	t.Run("test cancelled read leaves the endpoint healthy", func(t *testing.T) {
+		blocked := make(chan struct{})
+
+		slowServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			close(blocked)
+			<-req.Context().Done()
+		}))
+		defer slowServer.Close()
+
+		healthy := &countingResolver{}
+
+		healthyServer := httptest.NewServer(healthy)
+		defer healthyServer.Close()
+
+		v, err := New(slowServer.URL, WithEndpoints(healthyServer.URL))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		go func() {
+			<-blocked
+			cancel()
+		}()
+
+		_, err = v.ReadContext(ctx, "did:example:334455")
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, int32(0), healthy.calls.Load())
+
+		endpoints := v.endpoints.ordered()
+		require.Len(t, endpoints, 2)
+
+		for _, e := range endpoints {
+			require.True(t, e.unhealthyUntil.IsZero())
+		}
+	})
+
+	t.Run("test invalid endpoint", func(t *testing.T) {
+		_, err := New("https://resolver.example.com", WithEndpoints("invalid"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "base URL invalid")
+	})
+}
+
+func TestEndpointRoundRobin(t *testing.T) {
+	first := &countingResolver{}
+	second := &countingResolver{}
+
+	firstServer := httptest.NewServer(first)
+	defer firstServer.Close()
+
+	secondServer := httptest.NewServer(second)
+	defer secondServer.Close()
+
+	v, err := New(firstServer.URL, WithEndpoints(secondServer.URL), WithEndpointStrategy(RoundRobin))
+	require.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		_, err = v.Read("did:example:334455")
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, int32(2), first.calls.Load())
+	require.Equal(t, int32(2), second.calls.Load())
+}
+
+func TestRetry(t *testing.T) {
+	t.Run("test retry with backoff", func(t *testing.T) {
+		resolver := &countingResolver{statuses: []int{
+			http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK,
+		}}
+
+		server := httptest.NewServer(resolver)
+		defer server.Close()
+
+		v, err := New(server.URL, WithRetry(3, time.Millisecond))
+		require.NoError(t, err)
+
+		docResolution, err := v.Read("did:example:334455")
+		require.NoError(t, err)
+		require.Equal(t, "did:peer:21tDAKCERh95uGgKbJNHYp", docResolution.DIDDocument.ID)
+		require.Equal(t, int32(3), resolver.calls.Load())
+	})
+
+	t.Run("test retries exhausted", func(t *testing.T) {
+		resolver := &countingResolver{statuses: []int{http.StatusServiceUnavailable}}
+
+		server := httptest.NewServer(resolver)
+		defer server.Close()
+
+		v, err := New(server.URL, WithRetry(2, time.Millisecond))
+		require.NoError(t, err)
+
+		_, err = v.Read("did:example:334455")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported response from DID resolver [503]")
+		require.Equal(t, int32(3), resolver.calls.Load())
+	})
+
+	t.Run("test no retry for non retryable errors", func(t *testing.T) {
+		resolver := &countingResolver{statuses: []int{http.StatusNotImplemented}}
+
+		server := httptest.NewServer(resolver)
+		defer server.Close()
+
+		v, err := New(server.URL, WithRetry(2, time.Millisecond))
+		require.NoError(t, err)
+
+		_, err = v.Read("did:example:334455")
+		require.Error(t, err)
+		require.Equal(t, int32(1), resolver.calls.Load())
+	})
+}
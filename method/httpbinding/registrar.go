@@ -0,0 +1,381 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpbinding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	// MethodOpt is the DID method to create, it is taken from the DID document ID when not set.
+	MethodOpt = "method"
+	// RegistrationOptionsOpt is the map[string]interface{} of options sent to the registrar.
+	RegistrationOptionsOpt = "registrationOptions"
+	// SecretOpt is the map[string]interface{} of secrets sent to the registrar.
+	SecretOpt = "secret"
+	// JobIDOpt continues the registration job with the given ID, e.g. after an action was performed.
+	JobIDOpt = "jobId"
+
+	// StateFinished the registration is completed.
+	StateFinished = "finished"
+	// StateFailed the registration failed.
+	StateFailed = "failed"
+	// StateAction the client has to perform an action before the registration continues.
+	StateAction = "action"
+	// StateWait the client has to wait before the registration continues.
+	StateWait = "wait"
+
+	setDIDDocumentOperation = "setDidDocument"
+
+	defaultPollAttempts = 10
+	defaultPollInterval = time.Second
+	defaultMaxPollWait  = 30 * time.Second
+)
+
+var errNoRegistrar = errors.New("registrar endpoint is not configured")
+
+// DIDState is the state of a DID registration (https://identity.foundation/did-registration/#didstate).
+type DIDState struct {
+	State                      string                 `json:"state"`
+	DID                        string                 `json:"did,omitempty"`
+	DIDDocument                json.RawMessage        `json:"didDocument,omitempty"`
+	Secret                     map[string]interface{} `json:"secret,omitempty"`
+	Reason                     string                 `json:"reason,omitempty"`
+	Action                     string                 `json:"action,omitempty"`
+	RedirectURL                string                 `json:"redirectUrl,omitempty"`
+	SigningRequest             map[string]interface{} `json:"signingRequest,omitempty"`
+	VerificationMethodTemplate []interface{}          `json:"verificationMethodTemplate,omitempty"`
+	Wait                       string                 `json:"wait,omitempty"`
+	WaitTime                   int64                  `json:"waitTime,omitempty"`
+}
+
+// RegistrationStateError is returned when the registration requires an action of the client, or still waits once
+// the polling attempts are exhausted or the registrar asks to wait longer than the maximum wait time.
+// The registration is continued by sending the request again with JobIDOpt.
+type RegistrationStateError struct {
+	JobID string
+	State *DIDState
+}
+
+func (e *RegistrationStateError) Error() string {
+	if e.State.State == StateAction {
+		return fmt.Sprintf("registration job %s requires action: %s", e.JobID, e.State.Action)
+	}
+
+	return fmt.Sprintf("registration job %s is in state %s: %s", e.JobID, e.State.State, e.State.Wait)
+}
+
+type registrarRequest struct {
+	JobID                string                 `json:"jobId,omitempty"`
+	DID                  string                 `json:"did,omitempty"`
+	Options              map[string]interface{} `json:"options,omitempty"`
+	Secret               map[string]interface{} `json:"secret,omitempty"`
+	DIDDocumentOperation []string               `json:"didDocumentOperation,omitempty"`
+	DIDDocument          interface{}            `json:"didDocument,omitempty"`
+}
+
+type registrarResponse struct {
+	JobID                   string                 `json:"jobId,omitempty"`
+	DIDState                *DIDState              `json:"didState"`
+	DIDRegistrationMetadata map[string]interface{} `json:"didRegistrationMetadata,omitempty"`
+	DIDDocumentMetadata     *did.DocumentMetadata  `json:"didDocumentMetadata,omitempty"`
+}
+
+// Create registers a DID for didDoc with the registrar set by WithRegistrarURL (POST /1.0/create).
+func (v *VDR) Create(didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	return v.CreateContext(context.Background(), didDoc, opts...)
+}
+
+// CreateContext registers a DID for didDoc with the registrar, the requests and the polling wait are bound to the
+// given context.
+func (v *VDR) CreateContext(ctx context.Context, didDoc *did.Doc,
+	opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	if didDoc == nil {
+		return nil, errors.New("registrar create: did document is required")
+	}
+
+	didMethodOpts := applyOpts(opts)
+
+	method, ok := didMethodOpts.Values[MethodOpt].(string)
+	if !ok && didDoc.ID != "" {
+		parsed, err := did.Parse(didDoc.ID)
+		if err != nil {
+			return nil, fmt.Errorf("registrar create: %w: %w", err, vdrapi.ErrInvalidDID)
+		}
+
+		method = parsed.Method
+	}
+
+	if method == "" {
+		return nil, errors.New("registrar create: method opt is required")
+	}
+
+	request, err := newRegistrarRequest(didMethodOpts)
+	if err != nil {
+		return nil, fmt.Errorf("registrar create: %w", err)
+	}
+
+	if request.DIDDocument, err = didDocumentJSON(didDoc); err != nil {
+		return nil, fmt.Errorf("registrar create: %w", err)
+	}
+
+	response, err := v.register(ctx, "create", url.Values{"method": {method}}, request)
+	if err != nil {
+		return nil, fmt.Errorf("registrar create: %w", err)
+	}
+
+	docResolution, err := v.docResolution(response)
+	if err != nil {
+		return nil, fmt.Errorf("registrar create: %w", err)
+	}
+
+	return docResolution, nil
+}
+
+// Update replaces the DID document of didDoc.ID with the registrar (POST /1.0/update).
+func (v *VDR) Update(didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) error {
+	return v.UpdateContext(context.Background(), didDoc, opts...)
+}
+
+// UpdateContext replaces the DID document of didDoc.ID with the registrar, the requests and the polling wait are
+// bound to the given context.
+func (v *VDR) UpdateContext(ctx context.Context, didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) error {
+	request, err := newRegistrarRequest(applyOpts(opts))
+	if err != nil {
+		return fmt.Errorf("registrar update: %w", err)
+	}
+
+	document, err := didDocumentJSON(didDoc)
+	if err != nil {
+		return fmt.Errorf("registrar update: %w", err)
+	}
+
+	request.DID = didDoc.ID
+	request.DIDDocumentOperation = []string{setDIDDocumentOperation}
+	request.DIDDocument = []json.RawMessage{document}
+
+	if _, err = v.register(ctx, "update", nil, request); err != nil {
+		return fmt.Errorf("registrar update: %w", err)
+	}
+
+	return nil
+}
+
+// Deactivate deactivates the DID with the registrar (POST /1.0/deactivate).
+func (v *VDR) Deactivate(didID string, opts ...vdrapi.DIDMethodOption) error {
+	return v.DeactivateContext(context.Background(), didID, opts...)
+}
+
+// DeactivateContext deactivates the DID with the registrar, the requests and the polling wait are bound to the
+// given context.
+func (v *VDR) DeactivateContext(ctx context.Context, didID string, opts ...vdrapi.DIDMethodOption) error {
+	request, err := newRegistrarRequest(applyOpts(opts))
+	if err != nil {
+		return fmt.Errorf("registrar deactivate: %w", err)
+	}
+
+	request.DID = didID
+
+	if _, err = v.register(ctx, "deactivate", nil, request); err != nil {
+		return fmt.Errorf("registrar deactivate: %w", err)
+	}
+
+	return nil
+}
+
+// register sends the request until the registration job is finished, polling while the registrar asks to wait.
+func (v *VDR) register(ctx context.Context, operation string, query url.Values,
+	request *registrarRequest) (*registrarResponse, error) {
+	if v.registrarURL == "" {
+		return nil, errNoRegistrar
+	}
+
+	reqURL, err := url.ParseRequestURI(v.registrarURL)
+	if err != nil {
+		return nil, fmt.Errorf("url parse request uri failed: %w", err)
+	}
+
+	reqURL.Path = path.Join(reqURL.Path, operation)
+	reqURL.RawQuery = query.Encode()
+
+	for attempt := 1; ; attempt++ {
+		response, err := v.sendRegistrarRequest(ctx, reqURL.String(), request)
+		if err != nil {
+			return nil, err
+		}
+
+		switch response.DIDState.State {
+		case StateFinished:
+			return response, nil
+		case StateFailed:
+			return nil, fmt.Errorf("registration failed: %s", response.DIDState.Reason)
+		case StateWait:
+			if attempt >= v.pollAttempts || response.JobID == "" {
+				return nil, &RegistrationStateError{JobID: response.JobID, State: response.DIDState}
+			}
+
+			interval := v.pollInterval
+			if response.DIDState.WaitTime > 0 {
+				if response.DIDState.WaitTime > v.maxPollWait.Milliseconds() {
+					return nil, &RegistrationStateError{JobID: response.JobID, State: response.DIDState}
+				}
+
+				interval = time.Duration(response.DIDState.WaitTime) * time.Millisecond
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(interval):
+			}
+
+			request.JobID = response.JobID
+		case StateAction:
+			return nil, &RegistrationStateError{JobID: response.JobID, State: response.DIDState}
+		default:
+			return nil, fmt.Errorf("unsupported registration state: %s", response.DIDState.State)
+		}
+	}
+}
+
+func (v *VDR) sendRegistrarRequest(ctx context.Context, uri string,
+	request *registrarRequest) (*registrarResponse, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("marshal registrar request failed: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, bytes.NewReader(requestBytes))
+	if err != nil {
+		return nil, fmt.Errorf("HTTP create post request failed: %w", err)
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+
+	if v.registrarAuthToken != "" {
+		req.Header.Add("Authorization", v.registrarAuthToken)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP Post request failed: %w", err)
+	}
+
+	defer closeResponseBody(resp.Body)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body failed: %w", err)
+	}
+
+	response := &registrarResponse{}
+
+	if err = json.Unmarshal(body, response); err != nil || response.DIDState == nil {
+		return nil, fmt.Errorf("unsupported response from DID registrar [%v] body [%s]", resp.StatusCode, body)
+	}
+
+	// an error response is only accepted for a failed registration, which carries the reason
+	if (resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices) &&
+		response.DIDState.State != StateFailed {
+		return nil, fmt.Errorf("unsupported response from DID registrar [%v] body [%s]", resp.StatusCode, body)
+	}
+
+	return response, nil
+}
+
+// docResolution maps the finished registration to the resolution of the registered DID.
+func (v *VDR) docResolution(response *registrarResponse) (*did.DocResolution, error) {
+	if len(response.DIDState.DIDDocument) == 0 {
+		if response.DIDState.DID == "" {
+			return nil, errors.New("registrar response has neither did nor did document")
+		}
+
+		return v.Read(response.DIDState.DID)
+	}
+
+	doc, err := did.ParseDocument(response.DIDState.DIDDocument)
+	if err != nil {
+		return nil, fmt.Errorf("parse registered did document: %w", err)
+	}
+
+	docMetadata := response.DIDDocumentMetadata
+	if docMetadata == nil {
+		docMetadata = &did.DocumentMetadata{}
+	}
+
+	return &did.DocResolution{
+		DIDDocument:        doc,
+		DocumentMetadata:   docMetadata,
+		ResolutionMetadata: &did.ResolutionMetadata{ContentType: didLDJson},
+	}, nil
+}
+
+func newRegistrarRequest(didMethodOpts *vdrapi.DIDMethodOpts) (*registrarRequest, error) {
+	request := &registrarRequest{}
+
+	var ok bool
+
+	if value := didMethodOpts.Values[RegistrationOptionsOpt]; value != nil {
+		if request.Options, ok = value.(map[string]interface{}); !ok {
+			return nil, errors.New("registrationOptions opt is not map[string]interface{}")
+		}
+	}
+
+	if value := didMethodOpts.Values[SecretOpt]; value != nil {
+		if request.Secret, ok = value.(map[string]interface{}); !ok {
+			return nil, errors.New("secret opt is not map[string]interface{}")
+		}
+	}
+
+	if value := didMethodOpts.Values[JobIDOpt]; value != nil {
+		if request.JobID, ok = value.(string); !ok {
+			return nil, errors.New("jobId opt is not string")
+		}
+	}
+
+	return request, nil
+}
+
+func didDocumentJSON(didDoc *did.Doc) (json.RawMessage, error) {
+	if didDoc == nil {
+		return nil, errors.New("did document is required")
+	}
+
+	docBytes, err := didDoc.JSONBytes()
+	if err != nil {
+		return nil, fmt.Errorf("marshal did document: %w", err)
+	}
+
+	// an empty id is left to the registrar
+	if didDoc.ID == "" {
+		var rawDoc map[string]interface{}
+
+		if err = json.Unmarshal(docBytes, &rawDoc); err != nil {
+			return nil, fmt.Errorf("unmarshal did document: %w", err)
+		}
+
+		delete(rawDoc, "id")
+
+		if docBytes, err = json.Marshal(rawDoc); err != nil {
+			return nil, fmt.Errorf("marshal did document: %w", err)
+		}
+	}
+
+	return docBytes, nil
+}
@@ -0,0 +1,350 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpbinding
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const registeredDoc = `{
+  "@context": ["https://www.w3.org/ns/did/v1"],
+  "id": "did:example:123",
+  "verificationMethod": [{
+    "id": "did:example:123#key-1",
+    "type": "Ed25519VerificationKey2018",
+    "controller": "did:example:123",
+    "publicKeyBase58": "H3C2AVvLMv6gmMNam3uVAjZpfkcJCwDwnZn6z3wXmqPV"
+  }]
+}`
+
+type registrarCall struct {
+	path    string
+	query   string
+	auth    string
+	request map[string]interface{}
+}
+
+func newRegistrar(t *testing.T, responses ...string) (*httptest.Server, *[]registrarCall) {
+	t.Helper()
+
+	return newRegistrarWithStatus(t, http.StatusOK, responses...)
+}
+
+func newRegistrarWithStatus(t *testing.T, status int, responses ...string) (*httptest.Server, *[]registrarCall) {
+	t.Helper()
+
+	var calls []registrarCall
+
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		require.Equal(t, http.MethodPost, req.Method)
+
+		call := registrarCall{path: req.URL.Path, query: req.URL.RawQuery, auth: req.Header.Get("Authorization")}
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&call.request))
+
+		response := responses[len(calls)]
+		calls = append(calls, call)
+
+		res.Header().Set("Content-Type", "application/json")
+		res.WriteHeader(status)
+		_, err := res.Write([]byte(response))
+		require.NoError(t, err)
+	}))
+
+	return server, &calls
+}
+
+func TestCreate(t *testing.T) {
+	t.Run("test create finished", func(t *testing.T) {
+		server, calls := newRegistrar(t, `{"jobId":"job-1","didState":{"state":"finished","did":"did:example:123",`+
+			`"didDocument":`+registeredDoc+`},"didDocumentMetadata":{"versionId":"1"}}`)
+		defer server.Close()
+
+		v, err := New("https://resolver.example.com/1.0/identifiers",
+			WithRegistrarURL(server.URL+"/1.0"), WithRegistrarAuthToken("token"))
+		require.NoError(t, err)
+
+		doc, err := did.ParseDocument([]byte(registeredDoc))
+		require.NoError(t, err)
+
+		doc.ID = ""
+
+		docResolution, err := v.Create(doc,
+			vdrapi.WithOption(MethodOpt, "example"),
+			vdrapi.WithOption(RegistrationOptionsOpt, map[string]interface{}{"network": "testnet"}),
+			vdrapi.WithOption(SecretOpt, map[string]interface{}{"seed": "secret"}))
+		require.NoError(t, err)
+		require.Equal(t, "did:example:123", docResolution.DIDDocument.ID)
+		require.Equal(t, "1", docResolution.DocumentMetadata.VersionID)
+
+		require.Len(t, *calls, 1)
+
+		call := (*calls)[0]
+		require.Equal(t, "/1.0/create", call.path)
+		require.Equal(t, "method=example", call.query)
+		require.Equal(t, "Bearer token", call.auth)
+		require.Equal(t, map[string]interface{}{"network": "testnet"}, call.request["options"])
+		require.Equal(t, map[string]interface{}{"seed": "secret"}, call.request["secret"])
+		require.NotContains(t, call.request["didDocument"], "id")
+	})
+
+	t.Run("test create polls while waiting", func(t *testing.T) {
+		server, calls := newRegistrar(t,
+			`{"jobId":"job-1","didState":{"state":"wait","wait":"anchoring","waitTime":1}}`,
+			`{"jobId":"job-1","didState":{"state":"finished","did":"did:example:123","didDocument":`+registeredDoc+`}}`)
+		defer server.Close()
+
+		v, err := New("https://resolver.example.com", WithRegistrarURL(server.URL))
+		require.NoError(t, err)
+
+		docResolution, err := v.Create(&did.Doc{ID: "did:example:123"})
+		require.NoError(t, err)
+		require.Equal(t, "did:example:123", docResolution.DIDDocument.ID)
+
+		require.Len(t, *calls, 2)
+		require.Equal(t, "method=example", (*calls)[0].query)
+		require.NotContains(t, (*calls)[0].request, "jobId")
+		require.Equal(t, "job-1", (*calls)[1].request["jobId"])
+	})
+
+	t.Run("test create still waiting after polling", func(t *testing.T) {
+		server, calls := newRegistrar(t,
+			`{"jobId":"job-1","didState":{"state":"wait","wait":"anchoring"}}`,
+			`{"jobId":"job-1","didState":{"state":"wait","wait":"anchoring"}}`)
+		defer server.Close()
+
+		v, err := New("https://resolver.example.com", WithRegistrarURL(server.URL),
+			WithRegistrarPolling(2, time.Millisecond))
+		require.NoError(t, err)
+
+		_, err = v.Create(&did.Doc{}, vdrapi.WithOption(MethodOpt, "example"))
+		require.Error(t, err)
+
+		var stateErr *RegistrationStateError
+
+		require.True(t, errors.As(err, &stateErr))
+		require.Equal(t, "job-1", stateErr.JobID)
+		require.Equal(t, StateWait, stateErr.State.State)
+		require.Len(t, *calls, 2)
+	})
+
+	t.Run("test create waiting longer than max wait", func(t *testing.T) {
+		server, calls := newRegistrar(t, `{"jobId":"job-1","didState":{"state":"wait","wait":"anchoring",`+
+			`"waitTime":3600000}}`)
+		defer server.Close()
+
+		v, err := New("https://resolver.example.com", WithRegistrarURL(server.URL),
+			WithRegistrarMaxWait(time.Second))
+		require.NoError(t, err)
+
+		_, err = v.Create(&did.Doc{}, vdrapi.WithOption(MethodOpt, "example"))
+
+		var stateErr *RegistrationStateError
+
+		require.True(t, errors.As(err, &stateErr))
+		require.Equal(t, "job-1", stateErr.JobID)
+		require.Equal(t, StateWait, stateErr.State.State)
+		require.Len(t, *calls, 1)
+	})
+
+	t.Run("test create wait bound to the context", func(t *testing.T) {
+		server, calls := newRegistrar(t, `{"jobId":"job-1","didState":{"state":"wait","wait":"anchoring",`+
+			`"waitTime":60000}}`)
+		defer server.Close()
+
+		v, err := New("https://resolver.example.com", WithRegistrarURL(server.URL),
+			WithRegistrarMaxWait(time.Hour))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = v.CreateContext(ctx, &did.Doc{}, vdrapi.WithOption(MethodOpt, "example"))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Len(t, *calls, 1)
+	})
+
+	t.Run("test create error status", func(t *testing.T) {
+		server, _ := newRegistrarWithStatus(t, http.StatusInternalServerError,
+			`{"didState":{"state":"finished","did":"did:example:123","didDocument":`+registeredDoc+`}}`,
+			`{"didState":{"state":"failed","reason":"registrar unavailable"}}`)
+		defer server.Close()
+
+		v, err := New("https://resolver.example.com", WithRegistrarURL(server.URL))
+		require.NoError(t, err)
+
+		_, err = v.Create(&did.Doc{}, vdrapi.WithOption(MethodOpt, "example"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported response from DID registrar [500]")
+
+		_, err = v.Create(&did.Doc{}, vdrapi.WithOption(MethodOpt, "example"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "registration failed: registrar unavailable")
+	})
+
+	t.Run("test create requires action", func(t *testing.T) {
+		server, _ := newRegistrar(t, `{"jobId":"job-2","didState":{"state":"action","action":"signPayload",`+
+			`"signingRequest":{"signingRequest1":{"alg":"EdDSA","payload":"e30"}}}}`)
+		defer server.Close()
+
+		v, err := New("https://resolver.example.com", WithRegistrarURL(server.URL))
+		require.NoError(t, err)
+
+		_, err = v.Create(&did.Doc{}, vdrapi.WithOption(MethodOpt, "example"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "registration job job-2 requires action: signPayload")
+
+		var stateErr *RegistrationStateError
+
+		require.True(t, errors.As(err, &stateErr))
+		require.Contains(t, stateErr.State.SigningRequest, "signingRequest1")
+	})
+
+	t.Run("test create continues job", func(t *testing.T) {
+		server, calls := newRegistrar(t,
+			`{"jobId":"job-2","didState":{"state":"finished","did":"did:example:123","didDocument":`+registeredDoc+`}}`)
+		defer server.Close()
+
+		v, err := New("https://resolver.example.com", WithRegistrarURL(server.URL))
+		require.NoError(t, err)
+
+		_, err = v.Create(&did.Doc{}, vdrapi.WithOption(MethodOpt, "example"), vdrapi.WithOption(JobIDOpt, "job-2"))
+		require.NoError(t, err)
+		require.Equal(t, "job-2", (*calls)[0].request["jobId"])
+	})
+
+	t.Run("test create failed", func(t *testing.T) {
+		server, _ := newRegistrar(t, `{"didState":{"state":"failed","reason":"invalid document"}}`)
+		defer server.Close()
+
+		v, err := New("https://resolver.example.com", WithRegistrarURL(server.URL))
+		require.NoError(t, err)
+
+		_, err = v.Create(&did.Doc{}, vdrapi.WithOption(MethodOpt, "example"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "registration failed: invalid document")
+	})
+
+	t.Run("test create resolves the registered did", func(t *testing.T) {
+		resolver := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			res.Header().Add("Content-type", "application/did+ld+json")
+			res.WriteHeader(http.StatusOK)
+			_, err := res.Write([]byte(registeredDoc))
+			require.NoError(t, err)
+		}))
+		defer resolver.Close()
+
+		server, _ := newRegistrar(t, `{"didState":{"state":"finished","did":"did:example:123"}}`)
+		defer server.Close()
+
+		v, err := New(resolver.URL, WithRegistrarURL(server.URL))
+		require.NoError(t, err)
+
+		docResolution, err := v.Create(&did.Doc{}, vdrapi.WithOption(MethodOpt, "example"))
+		require.NoError(t, err)
+		require.Equal(t, "did:example:123", docResolution.DIDDocument.ID)
+	})
+
+	t.Run("test invalid responses and options", func(t *testing.T) {
+		server, _ := newRegistrar(t, `{}`, `{"didState":{"state":"unknown"}}`, `{"didState":{"state":"finished"}}`)
+		defer server.Close()
+
+		v, err := New("https://resolver.example.com", WithRegistrarURL(server.URL))
+		require.NoError(t, err)
+
+		_, err = v.Create(&did.Doc{}, vdrapi.WithOption(MethodOpt, "example"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported response from DID registrar")
+
+		_, err = v.Create(&did.Doc{}, vdrapi.WithOption(MethodOpt, "example"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported registration state: unknown")
+
+		_, err = v.Create(&did.Doc{}, vdrapi.WithOption(MethodOpt, "example"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "registrar response has neither did nor did document")
+
+		_, err = v.Create(&did.Doc{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "method opt is required")
+
+		_, err = v.Create(&did.Doc{}, vdrapi.WithOption(MethodOpt, "example"), vdrapi.WithOption(SecretOpt, "seed"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "secret opt is not map[string]interface{}")
+
+		_, err = v.Create(&did.Doc{}, vdrapi.WithOption(MethodOpt, "example"),
+			vdrapi.WithOption(RegistrationOptionsOpt, "options"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "registrationOptions opt is not map[string]interface{}")
+
+		_, err = v.Create(&did.Doc{}, vdrapi.WithOption(MethodOpt, "example"), vdrapi.WithOption(JobIDOpt, 1))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "jobId opt is not string")
+	})
+}
+
+func TestRegistrarUpdate(t *testing.T) {
+	server, calls := newRegistrar(t, `{"didState":{"state":"finished","did":"did:example:123"}}`)
+	defer server.Close()
+
+	v, err := New("https://resolver.example.com", WithRegistrarURL(server.URL))
+	require.NoError(t, err)
+
+	doc, err := did.ParseDocument([]byte(registeredDoc))
+	require.NoError(t, err)
+
+	require.NoError(t, v.Update(doc, vdrapi.WithOption(SecretOpt, map[string]interface{}{"key": "value"})))
+
+	call := (*calls)[0]
+	require.Equal(t, "/update", call.path)
+	require.Equal(t, "did:example:123", call.request["did"])
+	require.Equal(t, []interface{}{"setDidDocument"}, call.request["didDocumentOperation"])
+	require.Len(t, call.request["didDocument"], 1)
+
+	require.Error(t, v.Update(nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.ErrorIs(t, v.UpdateContext(ctx, doc), context.Canceled)
+}
+
+func TestRegistrarDeactivate(t *testing.T) {
+	server, calls := newRegistrar(t,
+		`{"didState":{"state":"finished","did":"did:example:123"}}`,
+		`{"didState":{"state":"failed","reason":"not authorized"}}`)
+	defer server.Close()
+
+	v, err := New("https://resolver.example.com", WithRegistrarURL(server.URL))
+	require.NoError(t, err)
+
+	require.NoError(t, v.Deactivate("did:example:123"))
+
+	call := (*calls)[0]
+	require.Equal(t, "/deactivate", call.path)
+	require.Equal(t, "did:example:123", call.request["did"])
+	require.NotContains(t, call.request, "didDocument")
+
+	err = v.Deactivate("did:example:123")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not authorized")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.ErrorIs(t, v.DeactivateContext(ctx, "did:example:123"), context.Canceled)
+}
@@ -6,12 +6,13 @@ SPDX-License-Identifier: Apache-2.0
 package httpbinding
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"mime"
 	"net/http"
-	"net/url"
-	"path"
 	"strings"
 
 	"github.com/trustbloc/did-go/doc/did"
@@ -20,20 +21,62 @@ import (
 
 const (
 	// VersionIDOpt version id opt this option is not mandatory.
-	VersionIDOpt = "versionID"
+	VersionIDOpt = vdrapi.VersionIDOpt
 	// VersionTimeOpt version time opt this option is not mandatory.
-	VersionTimeOpt = "versionTime"
+	VersionTimeOpt = vdrapi.VersionTimeOpt
 	didLDJson      = "application/did+ld+json"
+
+	ldJSON            = "application/ld+json"
+	plainJSON         = "application/json"
+	resolutionProfile = "https://w3id.org/did-resolution"
 )
 
-// resolveDID makes DID resolution via HTTP.
-func (v *VDR) resolveDID(uri string) ([]byte, error) {
-	req, err := http.NewRequest(http.MethodGet, uri, nil)
+// ResolverError is returned when the DID resolver responds without a DID document. Code is the DID Resolution
+// error code reported by the resolver, if any, and Err the matching vdrapi error.
+type ResolverError struct {
+	StatusCode  int
+	ContentType string
+	Code        string
+	Body        []byte
+	Err         error
+}
+
+func (e *ResolverError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("DID resolver returned error [%v] body [%s]: %v", e.StatusCode, e.Body, e.Err)
+	}
+
+	msg := fmt.Sprintf("unsupported response from DID resolver [%v] header [%s] body [%s]",
+		e.StatusCode, e.ContentType, e.Body)
+
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+func (e *ResolverError) Unwrap() error {
+	return e.Err
+}
+
+// resolverResponse is a DID document or DID resolution result returned by the DID resolver.
+type resolverResponse struct {
+	body             []byte
+	statusCode       int
+	contentType      string
+	resolutionResult bool
+	deactivated      bool
+}
+
+// resolveDID makes DID resolution via HTTP, deactivated is set when the resolver reports a deactivated DID.
+func (v *VDR) resolveDID(ctx context.Context, uri string) (*resolverResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
 	if err != nil {
 		return nil, fmt.Errorf("HTTP create get request failed: %w", err)
 	}
 
-	req.Header.Add("Accept", didLDJson)
+	req.Header.Add("Accept", v.acceptHeader)
 
 	authToken := v.resolveAuthToken
 
@@ -52,7 +95,11 @@ func (v *VDR) resolveDID(uri string) ([]byte, error) {
 
 	resp, err := v.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("HTTP Get request failed: %w", err)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("HTTP Get request failed: %w", err)
+		}
+
+		return nil, &retryableError{err: fmt.Errorf("HTTP Get request failed: %w", err)}
 	}
 
 	defer closeResponseBody(resp.Body)
@@ -64,18 +111,97 @@ func (v *VDR) resolveDID(uri string) ([]byte, error) {
 		return nil, fmt.Errorf("reading response body failed: %w", err)
 	}
 
-	if resp.StatusCode == http.StatusOK && strings.Contains(resp.Header.Get("Content-Type"), didLDJson) {
-		return gotBody, nil
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		mediaType = ""
+	}
+
+	if (resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusGone) && isSupportedMediaType(mediaType) {
+		return &resolverResponse{
+			body:             gotBody,
+			statusCode:       resp.StatusCode,
+			contentType:      mediaType,
+			resolutionResult: mediaType == ldJSON && params["profile"] == resolutionProfile,
+			deactivated:      resp.StatusCode == http.StatusGone,
+		}, nil
 	} else if resp.StatusCode == http.StatusNotFound {
 		return nil, vdrapi.ErrNotFound
 	}
 
-	return nil, fmt.Errorf("unsupported response from DID resolver [%v] header [%s] body [%s]",
-		resp.StatusCode, resp.Header.Get("Content-Type"), gotBody)
+	resolverErr := &ResolverError{
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        gotBody,
+	}
+
+	if code := resolutionError(gotBody); code == did.ResolutionErrorNotFound {
+		return nil, vdrapi.ErrNotFound
+	} else if code != "" {
+		resolverErr.Code = code
+		resolverErr.Err = vdrapi.ErrorFromCode(code)
+	} else if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNotAcceptable {
+		resolverErr.Err = vdrapi.ErrRepresentationNotSupported
+	}
+
+	if isRetryable(resp.StatusCode) {
+		return nil, &retryableError{err: resolverErr}
+	}
+
+	return nil, resolverErr
+}
+
+// isSupportedMediaType checks if the media type is a DID document or DID resolution result representation.
+func isSupportedMediaType(mediaType string) bool {
+	switch mediaType {
+	case didLDJson, did.ContentTypeDIDJSON, ldJSON, plainJSON:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRetryable checks if the status code reports a transient failure of the resolver.
+func isRetryable(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests ||
+		(statusCode >= http.StatusInternalServerError && statusCode != http.StatusNotImplemented)
+}
+
+// resolutionError returns the error code of a DID resolution result body, if any.
+func resolutionError(body []byte) string {
+	var result struct {
+		ResolutionMetadata *did.ResolutionMetadata `json:"didResolutionMetadata"`
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil || result.ResolutionMetadata == nil {
+		return ""
+	}
+
+	return result.ResolutionMetadata.Error
+}
+
+// acceptHeader returns the Accept header requesting the media types in order of preference.
+func acceptHeader(mediaTypes []string) string {
+	ranges := make([]string, len(mediaTypes))
+
+	for i, mediaType := range mediaTypes {
+		ranges[i] = mediaType
+
+		if i > 0 {
+			ranges[i] += fmt.Sprintf(";q=%.1f", max(1-float64(i)/10, 0.1)) //nolint:mnd
+		}
+	}
+
+	return strings.Join(ranges, ", ")
 }
 
 // Read implements didresolver.DidMethod.Read interface (https://w3c-ccg.github.io/did-resolution/#resolving-input)
-func (v *VDR) Read(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) { //nolint: funlen,gocyclo
+func (v *VDR) Read(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	return v.ReadContext(context.Background(), didID, opts...)
+}
+
+// ReadContext resolves the did via HTTP, the request is bound to the given context.
+func (v *VDR) ReadContext(ctx context.Context, didID string, //nolint: funlen,gocyclo
+	opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
 	didMethodOpts := &vdrapi.DIDMethodOpts{Values: make(map[string]interface{})}
 
 	// Apply options
@@ -108,41 +234,131 @@ func (v *VDR) Read(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolu
 		return nil, errors.New("versionID and versionTime can not set at same time")
 	}
 
-	reqURL, err := url.ParseRequestURI(v.endpointURL)
-	if err != nil {
-		return nil, fmt.Errorf("url parse request uri failed: %w", err)
-	}
-
-	reqURL.Path = path.Join(reqURL.Path, didID)
+	query := ""
 
 	if versionID != "" {
-		reqURL.RawQuery = fmt.Sprintf("versionId=%s", versionID) //nolint:perfsprint
+		query = fmt.Sprintf("versionId=%s", versionID) //nolint:perfsprint
 	}
 
 	if versionTime != "" {
-		reqURL.RawQuery = fmt.Sprintf("versionTime=%s", versionTime) //nolint:perfsprint
+		query = fmt.Sprintf("versionTime=%s", versionTime) //nolint:perfsprint
 	}
 
-	data, err := v.resolveDID(reqURL.String())
+	resp, err := v.resolve(ctx, didID, query)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(data) == 0 {
+	if len(resp.body) == 0 {
 		return nil, vdrapi.ErrNotFound
 	}
 
-	// TODO: this code is looking strange, ParseDocumentResolution can return ErrDIDDocumentNotExist
-	// Is it possible that we find did, but it not contains did document?
-	documentResolution, err := did.ParseDocumentResolution(data)
+	docResolution, err := parseResponse(resp)
 	if err != nil {
-		if !errors.Is(err, did.ErrDIDDocumentNotExist) {
-			return nil, err
+		return nil, err
+	}
+
+	if docResolution.DIDDocument, err = v.process(didID, docResolution.DIDDocument); err != nil {
+		return nil, err
+	}
+
+	if resp.deactivated {
+		if docResolution.DocumentMetadata == nil {
+			docResolution.DocumentMetadata = &did.DocumentMetadata{}
 		}
 
-		errLogger.Printf("parse document resolution failed %v", err)
-	} else {
-		return documentResolution, nil
+		docResolution.DocumentMetadata.Deactivated = true
+	}
+
+	return docResolution, nil
+}
+
+// process runs the processors registered for the method of the resolved DID.
+func (v *VDR) process(didID string, doc *did.Doc) (*did.Doc, error) {
+	parsed, err := did.Parse(didID)
+	if err != nil {
+		return doc, nil //nolint:nilerr
+	}
+
+	for _, processor := range v.processors[parsed.Method] {
+		if doc, err = processor(doc); err != nil {
+			return nil, fmt.Errorf("process did document: %w", err)
+		}
+	}
+
+	return doc, nil
+}
+
+// parseResponse parses the DID document or DID resolution result of the resolver response, a resolution
+// result reporting an error is returned as ResolverError.
+func parseResponse(resp *resolverResponse) (*did.DocResolution, error) {
+	var result map[string]json.RawMessage
+
+	if err := json.Unmarshal(resp.body, &result); err != nil {
+		return nil, fmt.Errorf("parse DID resolver response: %w", err)
+	}
+
+	rawDoc, isResult := result["didDocument"]
+	rawMetadata, hasMetadata := result["didResolutionMetadata"]
+
+	if !resp.resolutionResult && !isResult && !hasMetadata {
+		return parseDocument(resp)
+	}
+
+	if hasMetadata {
+		metadata := &did.ResolutionMetadata{}
+
+		if err := json.Unmarshal(rawMetadata, metadata); err != nil {
+			return nil, fmt.Errorf("parse DID resolution metadata: %w", err)
+		}
+
+		if metadata.Error == did.ResolutionErrorNotFound {
+			return nil, vdrapi.ErrNotFound
+		} else if metadata.Error != "" {
+			return nil, &ResolverError{
+				StatusCode:  resp.statusCode,
+				ContentType: resp.contentType,
+				Code:        metadata.Error,
+				Body:        resp.body,
+				Err:         vdrapi.ErrorFromCode(metadata.Error),
+			}
+		}
+	}
+
+	if len(rawDoc) == 0 || string(rawDoc) == "null" {
+		return nil, vdrapi.ErrNotFound
+	}
+
+	rawDoc, hadContext, err := withDefaultContext(rawDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	result["didDocument"] = rawDoc
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("marshal DID resolution result: %w", err)
+	}
+
+	docResolution, err := did.ParseDocumentResolution(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if docResolution.ResolutionMetadata == nil {
+		docResolution.ResolutionMetadata = &did.ResolutionMetadata{ContentType: representation(resp, hadContext)}
+	}
+
+	return docResolution, nil
+}
+
+// parseDocument parses the DID document of the resolver response, a document without @context is in the JSON
+// representation and gets the DID core context.
+func parseDocument(resp *resolverResponse) (*did.DocResolution, error) {
+	data, hadContext, err := withDefaultContext(resp.body)
+	if err != nil {
+		return nil, err
 	}
 
 	didDoc, err := did.ParseDocument(data)
@@ -150,7 +366,41 @@ func (v *VDR) Read(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolu
 		return nil, err
 	}
 
-	didDoc = interopPreprocess(didDoc)
+	return &did.DocResolution{
+		DIDDocument:        didDoc,
+		ResolutionMetadata: &did.ResolutionMetadata{ContentType: representation(resp, hadContext)},
+	}, nil
+}
+
+// representation returns the media type of the DID document representation returned by the resolver.
+func representation(resp *resolverResponse, hadContext bool) string {
+	switch {
+	case resp.contentType == didLDJson || resp.contentType == did.ContentTypeDIDJSON:
+		return resp.contentType
+	case hadContext:
+		return didLDJson
+	default:
+		return did.ContentTypeDIDJSON
+	}
+}
+
+func withDefaultContext(rawDoc []byte) ([]byte, bool, error) {
+	var doc map[string]interface{}
+
+	if err := json.Unmarshal(rawDoc, &doc); err != nil {
+		return nil, false, fmt.Errorf("parse DID document: %w", err)
+	}
+
+	if _, ok := doc["@context"]; ok {
+		return rawDoc, true, nil
+	}
+
+	doc["@context"] = []interface{}{did.ContextV1}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, false, fmt.Errorf("marshal DID document: %w", err)
+	}
 
-	return &did.DocResolution{DIDDocument: didDoc}, nil
+	return data, false, nil
 }
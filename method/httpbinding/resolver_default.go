@@ -9,9 +9,9 @@ SPDX-License-Identifier: Apache-2.0
 
 package httpbinding
 
-import diddoc "github.com/trustbloc/did-go/doc/did"
+import vdrapi "github.com/trustbloc/did-go/vdr/api"
 
-// interopPreprocess in a !ACAPyInterop build, this is a noop.
-func interopPreprocess(doc *diddoc.Doc) *diddoc.Doc {
-	return doc
+// defaultProcessors in a !ACAPyInterop build, no processor is registered by default.
+func defaultProcessors() map[string][]vdrapi.DocProcessor {
+	return make(map[string][]vdrapi.DocProcessor)
 }
@@ -5,6 +5,7 @@ SPDX-License-Identifier: Apache-2.0
 package httpbinding
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -202,6 +203,31 @@ func TestRead_DIDDoc(t *testing.T) {
 	})
 }
 
+func TestReadContext(t *testing.T) {
+	t.Run("test context deadline exceeded", func(t *testing.T) {
+		done := make(chan struct{})
+
+		testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			<-done
+		}))
+
+		defer func() {
+			close(done)
+			testServer.Close()
+		}()
+
+		resolver, err := New(testServer.URL)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err = resolver.ReadContext(ctx, "did:example:334455")
+		require.Error(t, err)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
 func TestRead_DIDDocWithBasePath(t *testing.T) {
 	testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
 		require.Equal(t, "/document/did:example:334455", req.URL.String())
@@ -273,6 +299,237 @@ func TestRead_UnsupportedStatus(t *testing.T) {
 	require.Contains(t, err.Error(), "unsupported response from DID resolver")
 }
 
+func TestRead_ResolutionError(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-Type", did.ContentTypeDIDResolution)
+		res.WriteHeader(http.StatusBadRequest)
+		_, err := res.Write([]byte(`{"didResolutionMetadata":{"error":"invalidDid"}}`))
+		require.NoError(t, err)
+	}))
+
+	defer func() { testServer.Close() }()
+
+	resolver, err := New(testServer.URL)
+	require.NoError(t, err)
+	_, err = resolver.Read("did:example:334455")
+	require.Error(t, err)
+	require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+}
+
+func TestRead_Deactivated(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-Type", "application/did+ld+json")
+		res.WriteHeader(http.StatusGone)
+		_, err := res.Write([]byte(doc))
+		require.NoError(t, err)
+	}))
+
+	defer func() { testServer.Close() }()
+
+	resolver, err := New(testServer.URL)
+	require.NoError(t, err)
+	docResolution, err := resolver.Read("did:example:334455")
+	require.NoError(t, err)
+	require.Equal(t, "did:peer:21tDAKCERh95uGgKbJNHYp", docResolution.DIDDocument.ID)
+	require.True(t, docResolution.DocumentMetadata.Deactivated)
+}
+
+func TestRead_UnsupportedContentType(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-Type", "text/html")
+		res.WriteHeader(http.StatusOK)
+	}))
+
+	defer func() { testServer.Close() }()
+
+	resolver, err := New(testServer.URL)
+	require.NoError(t, err)
+	_, err = resolver.Read("did:example:334455")
+	require.Error(t, err)
+	require.ErrorIs(t, err, vdrapi.ErrRepresentationNotSupported)
+}
+
+func TestRead_Representations(t *testing.T) {
+	const jsonDoc = `{
+  "id": "did:example:334455",
+  "verificationMethod": [{
+    "id": "did:example:334455#key-1",
+    "type": "Ed25519VerificationKey2018",
+    "controller": "did:example:334455",
+    "publicKeyBase58": "H3C2AVvLMv6gmMNam3uVAjZpfkcJCwDwnZn6z3wXmqPV"
+  }]
+}`
+
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		representation string
+		id             string
+	}{
+		{"did+json", did.ContentTypeDIDJSON, jsonDoc, did.ContentTypeDIDJSON, "did:example:334455"},
+		{"plain json", "application/json; charset=utf-8", jsonDoc, did.ContentTypeDIDJSON, "did:example:334455"},
+		{"json-ld", "application/ld+json", doc, didLDJson, "did:peer:21tDAKCERh95uGgKbJNHYp"},
+		{
+			"resolution result", did.ContentTypeDIDResolution,
+			`{"didDocument":` + jsonDoc + `,"didResolutionMetadata":{"contentType":"application/did+json"},` +
+				`"didDocumentMetadata":{"versionId":"2"}}`,
+			did.ContentTypeDIDJSON, "did:example:334455",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				res.Header().Add("Content-Type", tc.contentType)
+				res.WriteHeader(http.StatusOK)
+				_, err := res.Write([]byte(tc.body))
+				require.NoError(t, err)
+			}))
+			defer testServer.Close()
+
+			resolver, err := New(testServer.URL)
+			require.NoError(t, err)
+
+			docResolution, err := resolver.Read("did:example:334455")
+			require.NoError(t, err)
+			require.Equal(t, tc.id, docResolution.DIDDocument.ID)
+			require.Equal(t, tc.representation, docResolution.ResolutionMetadata.ContentType)
+		})
+	}
+}
+
+func TestRead_AcceptMediaTypes(t *testing.T) {
+	var accept string
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		accept = req.Header.Get("Accept")
+
+		res.Header().Add("Content-Type", did.ContentTypeDIDResolution)
+		res.WriteHeader(http.StatusOK)
+		_, err := res.Write([]byte(didResolutionData))
+		require.NoError(t, err)
+	}))
+	defer testServer.Close()
+
+	resolver, err := New(testServer.URL)
+	require.NoError(t, err)
+
+	_, err = resolver.Read("did:example:334455")
+	require.NoError(t, err)
+	require.Equal(t, didLDJson, accept)
+
+	resolver, err = New(testServer.URL, WithAcceptMediaTypes(did.ContentTypeDIDResolution, did.ContentTypeDIDJSON))
+	require.NoError(t, err)
+
+	_, err = resolver.Read("did:example:334455")
+	require.NoError(t, err)
+	require.Equal(t, did.ContentTypeDIDResolution+", application/did+json;q=0.9", accept)
+}
+
+func TestRead_ResolutionResultErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		err    error
+	}{
+		{"error in successful response", http.StatusOK, `{"didResolutionMetadata":{"error":"invalidDid"}}`,
+			vdrapi.ErrInvalidDID},
+		{"not found", http.StatusOK, `{"didDocument":null,"didResolutionMetadata":{"error":"notFound"}}`,
+			vdrapi.ErrNotFound},
+		{"no document", http.StatusOK, `{"didDocument":null,"didResolutionMetadata":{}}`, vdrapi.ErrNotFound},
+		{"method not supported", http.StatusNotImplemented, `{"didResolutionMetadata":{"error":"methodNotSupported"}}`,
+			vdrapi.ErrMethodNotSupported},
+		{"not acceptable", http.StatusNotAcceptable, ``, vdrapi.ErrRepresentationNotSupported},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				res.Header().Add("Content-Type", did.ContentTypeDIDResolution)
+				res.WriteHeader(tc.status)
+				_, err := res.Write([]byte(tc.body))
+				require.NoError(t, err)
+			}))
+			defer testServer.Close()
+
+			resolver, err := New(testServer.URL)
+			require.NoError(t, err)
+
+			_, err = resolver.Read("did:example:334455")
+			require.ErrorIs(t, err, tc.err)
+
+			if tc.err != vdrapi.ErrNotFound {
+				var resolverErr *ResolverError
+
+				require.ErrorAs(t, err, &resolverErr)
+				require.Equal(t, tc.status, resolverErr.StatusCode)
+			}
+		})
+	}
+}
+
+func TestRead_DocProcessor(t *testing.T) {
+	const sovDoc = `{
+  "@context": "https://w3id.org/did/v1",
+  "id": "did:sov:WRfXPg8dantKVubE3HX8pw",
+  "verificationMethod": [{
+    "id": "did:sov:WRfXPg8dantKVubE3HX8pw#key-1",
+    "type": "Ed25519VerificationKey2018",
+    "controller": "did:sov:WRfXPg8dantKVubE3HX8pw",
+    "publicKeyBase58": "H3C2AVvLMv6gmMNam3uVAjZpfkcJCwDwnZn6z3wXmqPV"
+  }],
+  "service": [{
+    "id": "did:sov:WRfXPg8dantKVubE3HX8pw;endpoint",
+    "type": "endpoint",
+    "serviceEndpoint": "https://agent.example.com"
+  }]
+}`
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-Type", "application/did+ld+json")
+		res.WriteHeader(http.StatusOK)
+		_, err := res.Write([]byte(sovDoc))
+		require.NoError(t, err)
+	}))
+	defer testServer.Close()
+
+	t.Run("test sov processor", func(t *testing.T) {
+		resolver, err := New(testServer.URL, WithDocProcessor(SovMethod, SovProcessor))
+		require.NoError(t, err)
+
+		docResolution, err := resolver.Read("did:sov:WRfXPg8dantKVubE3HX8pw")
+		require.NoError(t, err)
+
+		service, ok := did.LookupService(docResolution.DIDDocument, "did-communication")
+		require.True(t, ok)
+		require.Equal(t, []string{"did:key:z6MkvVT4kkAmhTb9srDHScsL1q7pVKt9cpUJUah2pKuYh4As"}, service.RecipientKeys)
+	})
+
+	t.Run("test processor of other method not applied", func(t *testing.T) {
+		resolver, err := New(testServer.URL, WithDocProcessor(SovMethod, SovProcessor))
+		require.NoError(t, err)
+
+		docResolution, err := resolver.Read("did:example:WRfXPg8dantKVubE3HX8pw")
+		require.NoError(t, err)
+
+		_, ok := did.LookupService(docResolution.DIDDocument, "endpoint")
+		require.True(t, ok)
+	})
+
+	t.Run("test processor error", func(t *testing.T) {
+		resolver, err := New(testServer.URL, WithDocProcessor(SovMethod, func(*did.Doc) (*did.Doc, error) {
+			return nil, errors.New("process error")
+		}))
+		require.NoError(t, err)
+
+		_, err = resolver.Read("did:sov:WRfXPg8dantKVubE3HX8pw")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "process did document: process error")
+	})
+}
+
 func TestRead_HTTPGetFailed(t *testing.T) {
 	// HTTP GET failed
 	testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
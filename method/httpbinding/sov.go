@@ -0,0 +1,40 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpbinding
+
+import (
+	"github.com/trustbloc/kms-go/doc/util/fingerprint"
+
+	diddoc "github.com/trustbloc/did-go/doc/did"
+)
+
+// SovMethod is the did:sov method handled by SovProcessor.
+const SovMethod = "sov"
+
+// SovProcessor converts public did:sov documents of Indy ledgers into a usable format: the "endpoint" service
+// becomes a did-communication service with the verification method keys as did:key recipient keys.
+//
+// It is registered by default in an ACAPyInterop build, otherwise add it with WithDocProcessor(SovMethod,
+// SovProcessor).
+func SovProcessor(doc *diddoc.Doc) (*diddoc.Doc, error) {
+	s, found := diddoc.LookupService(doc, "endpoint")
+	if !found {
+		return doc, nil
+	}
+
+	s.Type = "did-communication"
+
+	if len(s.RecipientKeys) == 0 {
+		for _, vm := range doc.VerificationMethod {
+			didKey, _ := fingerprint.CreateDIDKey(vm.Value)
+
+			s.RecipientKeys = append(s.RecipientKeys, didKey)
+		}
+	}
+
+	return doc, nil
+}
@@ -7,16 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package httpbinding
 
 import (
-	"errors"
-	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"net/url"
 	"os"
 	"time"
 
-	"github.com/trustbloc/did-go/doc/did"
+	"github.com/cenkalti/backoff/v4"
+
 	vdrapi "github.com/trustbloc/did-go/vdr/api"
 )
 
@@ -28,31 +26,59 @@ type authTokenProvider interface {
 
 // VDR via HTTP(s) endpoint.
 type VDR struct {
-	endpointURL       string
-	client            *http.Client
-	accept            Accept
-	resolveAuthToken  string
-	authTokenProvider authTokenProvider
+	endpoints          *endpointPool
+	endpointURLs       []string
+	strategy           Strategy
+	coolDown           time.Duration
+	maxRetries         uint64
+	retryInterval      time.Duration
+	registrarURL       string
+	client             *http.Client
+	accept             Accept
+	acceptMediaTypes   []string
+	acceptHeader       string
+	processors         map[string][]vdrapi.DocProcessor
+	resolveAuthToken   string
+	registrarAuthToken string
+	authTokenProvider  authTokenProvider
+	pollAttempts       int
+	pollInterval       time.Duration
+	maxPollWait        time.Duration
 }
 
 // Accept is method to accept did method.
 type Accept func(method string) bool
 
-// New creates new DID Resolver.
+// New creates new DID Resolver, endpointURL is the first of the resolver endpoints added with WithEndpoints.
 func New(endpointURL string, opts ...Option) (*VDR, error) {
-	v := &VDR{client: &http.Client{}, accept: func(method string) bool { return true }}
+	v := &VDR{
+		endpointURLs:     []string{endpointURL},
+		client:           &http.Client{},
+		accept:           func(method string) bool { return true },
+		acceptMediaTypes: []string{didLDJson},
+		processors:       defaultProcessors(),
+		coolDown:         defaultCoolDown,
+		retryInterval:    backoff.DefaultInitialInterval,
+		pollAttempts:     defaultPollAttempts,
+		pollInterval:     defaultPollInterval,
+		maxPollWait:      defaultMaxPollWait,
+	}
 
 	for _, opt := range opts {
 		opt(v)
 	}
 
-	// Validate host
-	_, err := url.ParseRequestURI(endpointURL)
+	endpoints, err := newEndpointPool(v.endpointURLs, v.strategy, v.coolDown)
 	if err != nil {
-		return nil, fmt.Errorf("base URL invalid: %w", err)
+		return nil, err
+	}
+
+	v.endpoints = endpoints
+	if len(v.acceptMediaTypes) == 0 {
+		v.acceptMediaTypes = []string{didLDJson}
 	}
 
-	v.endpointURL = endpointURL
+	v.acceptHeader = acceptHeader(v.acceptMediaTypes)
 
 	return v, nil
 }
@@ -62,26 +88,11 @@ func (v *VDR) Accept(method string, opts ...vdrapi.DIDMethodOption) bool {
 	return v.accept(method)
 }
 
-// Create did doc.
-func (v *VDR) Create(didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
-	return nil, errors.New("build not supported in http binding vdr")
-}
-
 // Close frees resources being maintained by vdr.
 func (v *VDR) Close() error {
 	return nil
 }
 
-// Update did doc.
-func (v *VDR) Update(didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) error {
-	return errors.New("not supported")
-}
-
-// Deactivate did doc.
-func (v *VDR) Deactivate(didID string, opts ...vdrapi.DIDMethodOption) error {
-	return errors.New("not supported")
-}
-
 // Option configures the peer vdr.
 type Option func(opts *VDR)
 
@@ -99,6 +110,36 @@ func WithHTTPClient(httpClient *http.Client) Option {
 	}
 }
 
+// WithEndpoints option adds resolver endpoints, used according to the strategy set with WithEndpointStrategy.
+func WithEndpoints(endpointURLs ...string) Option {
+	return func(opts *VDR) {
+		opts.endpointURLs = append(opts.endpointURLs, endpointURLs...)
+	}
+}
+
+// WithEndpointStrategy option sets the order the resolver endpoints are tried in, Failover is used by default.
+func WithEndpointStrategy(strategy Strategy) Option {
+	return func(opts *VDR) {
+		opts.strategy = strategy
+	}
+}
+
+// WithEndpointCoolDown option sets how long a failing resolver endpoint is skipped, 30 seconds by default.
+func WithEndpointCoolDown(coolDown time.Duration) Option {
+	return func(opts *VDR) {
+		opts.coolDown = coolDown
+	}
+}
+
+// WithRetry option retries a resolution failing on every endpoint with a timeout, a network error or a 5xx
+// response up to maxRetries times with exponential backoff starting at initialInterval.
+func WithRetry(maxRetries uint64, initialInterval time.Duration) Option {
+	return func(opts *VDR) {
+		opts.maxRetries = maxRetries
+		opts.retryInterval = initialInterval
+	}
+}
+
 // WithAccept option is for accept did method.
 func WithAccept(accept Accept) Option {
 	return func(opts *VDR) {
@@ -106,6 +147,23 @@ func WithAccept(accept Accept) Option {
 	}
 }
 
+// WithAcceptMediaTypes option sets the media types requested from the DID resolver in order of preference,
+// application/did+ld+json by default. DID documents in the JSON-LD or JSON representation and DID resolution results
+// are accepted whatever was requested.
+func WithAcceptMediaTypes(mediaTypes ...string) Option {
+	return func(opts *VDR) {
+		opts.acceptMediaTypes = mediaTypes
+	}
+}
+
+// WithDocProcessor option adds a processor rewriting the resolved DID documents of the given DID method, e.g.
+// SovProcessor for did:sov. Processors run in the order they were added.
+func WithDocProcessor(method string, processor vdrapi.DocProcessor) Option {
+	return func(opts *VDR) {
+		opts.processors[method] = append(opts.processors[method], processor)
+	}
+}
+
 // WithResolveAuthToken add auth token for resolve.
 func WithResolveAuthToken(authToken string) Option {
 	return func(opts *VDR) {
@@ -113,6 +171,38 @@ func WithResolveAuthToken(authToken string) Option {
 	}
 }
 
+// WithRegistrarURL option is for the DID Registration endpoint used by Create, Update and Deactivate,
+// e.g. https://uniregistrar.io/1.0.
+func WithRegistrarURL(registrarURL string) Option {
+	return func(opts *VDR) {
+		opts.registrarURL = registrarURL
+	}
+}
+
+// WithRegistrarAuthToken add auth token for registrar requests.
+func WithRegistrarAuthToken(authToken string) Option {
+	return func(opts *VDR) {
+		opts.registrarAuthToken = "Bearer " + authToken
+	}
+}
+
+// WithRegistrarPolling sets how many times a registration in wait state is polled and the interval used when the
+// registrar does not set a wait time.
+func WithRegistrarPolling(attempts int, interval time.Duration) Option {
+	return func(opts *VDR) {
+		opts.pollAttempts = attempts
+		opts.pollInterval = interval
+	}
+}
+
+// WithRegistrarMaxWait sets the longest wait time accepted from the registrar between polls, 30 seconds by default.
+// A registration asking to wait longer returns RegistrationStateError to be continued later with JobIDOpt.
+func WithRegistrarMaxWait(maxWait time.Duration) Option {
+	return func(opts *VDR) {
+		opts.maxPollWait = maxWait
+	}
+}
+
 // WithResolveAuthTokenProvider add auth token provider.
 func WithResolveAuthTokenProvider(p authTokenProvider) Option {
 	return func(opts *VDR) {
@@ -120,6 +210,16 @@ func WithResolveAuthTokenProvider(p authTokenProvider) Option {
 	}
 }
 
+func applyOpts(opts []vdrapi.DIDMethodOption) *vdrapi.DIDMethodOpts {
+	didMethodOpts := &vdrapi.DIDMethodOpts{Values: make(map[string]interface{})}
+	// Apply options
+	for _, opt := range opts {
+		opt(didMethodOpts)
+	}
+
+	return didMethodOpts
+}
+
 func closeResponseBody(respBody io.Closer) {
 	e := respBody.Close()
 	if e != nil {
@@ -10,6 +10,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
 )
 
 func TestVDR_Close(t *testing.T) {
@@ -21,36 +24,41 @@ func TestVDR_Close(t *testing.T) {
 }
 
 func TestVDR_Build(t *testing.T) {
-	t.Run("test HTTP Binding VDR build with default opts", func(t *testing.T) {
+	t.Run("test HTTP Binding VDR build without registrar", func(t *testing.T) {
 		resolver, err := New("/did:example:334455")
 		require.NoError(t, err)
 		require.NotNil(t, resolver)
 
-		result, err := resolver.Create(nil, nil)
+		result, err := resolver.Create(&did.Doc{}, vdrapi.WithOption(MethodOpt, "example"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "registrar endpoint is not configured")
+		require.Nil(t, result)
+
+		result, err = resolver.Create(nil)
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "build not supported in http binding vdr")
+		require.Contains(t, err.Error(), "did document is required")
 		require.Nil(t, result)
 	})
 }
 
 func TestUpdate(t *testing.T) {
-	t.Run("test update", func(t *testing.T) {
+	t.Run("test update without registrar", func(t *testing.T) {
 		v, err := New("/did:example:334455")
 		require.NoError(t, err)
 
-		err = v.Update(nil)
+		err = v.Update(&did.Doc{ID: "did:example:334455"})
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "not supported")
+		require.Contains(t, err.Error(), "registrar endpoint is not configured")
 	})
 }
 
 func TestDeactivate(t *testing.T) {
-	t.Run("test deactivate", func(t *testing.T) {
+	t.Run("test deactivate without registrar", func(t *testing.T) {
 		v, err := New("/did:example:334455")
 		require.NoError(t, err)
 
-		err = v.Deactivate("")
+		err = v.Deactivate("did:example:334455")
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "not supported")
+		require.Contains(t, err.Error(), "registrar endpoint is not configured")
 	})
 }
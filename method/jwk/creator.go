@@ -33,32 +33,36 @@ func (v *VDR) Create(didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) (*did.DocR
 		return nil, fmt.Errorf("verification method type[%s] is not supported", didDoc.VerificationMethod[0].Type)
 	}
 
-	key := didDoc.VerificationMethod[0].JSONWebKey()
-
-	didJWK, err := createDID(key)
+	didJWK, key, err := createDID(didDoc.VerificationMethod[0].JSONWebKey())
 	if err != nil {
 		return nil, fmt.Errorf("error creating DID: %w", err)
 	}
 
-	return createJWKResolutionResult(didJWK, key)
+	return createJWKResolutionResult(didJWK, key, opts)
 }
 
-func createDID(key *jwk.JWK) (string, error) {
+func createDID(key *jwk.JWK) (string, *publicJWK, error) {
 	if key == nil {
-		return "", errors.New("missing JWK")
+		return "", nil, errors.New("missing JWK")
 	}
 
 	keyBytes, err := key.MarshalJSON()
 	if err != nil {
-		return "", fmt.Errorf("marshal key: %w", err)
+		return "", nil, fmt.Errorf("marshal key: %w", err)
 	}
 
 	canonicalBytes, err := canonicalizer.MarshalCanonical(keyBytes)
 	if err != nil {
-		return "", fmt.Errorf("marshal canonical: %w", err)
+		return "", nil, fmt.Errorf("marshal canonical: %w", err)
+	}
+
+	// the key is validated as on Read, so that private keys are never published.
+	publicKey, err := parseJWK(canonicalBytes)
+	if err != nil {
+		return "", nil, err
 	}
 
 	didJWK := fmt.Sprintf("did:%s:%s", DIDMethod, base64.RawURLEncoding.EncodeToString(canonicalBytes))
 
-	return didJWK, nil
+	return didJWK, publicKey, nil
 }
@@ -7,8 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package jwk_test
 
 import (
+	"crypto"
 	"crypto/ed25519"
 	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
 	"testing"
 
@@ -19,6 +21,7 @@ import (
 
 	"github.com/trustbloc/did-go/doc/did"
 	"github.com/trustbloc/did-go/method/jwk"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
 )
 
 func TestCreate(t *testing.T) {
@@ -118,7 +121,7 @@ func TestCreate(t *testing.T) {
 	})
 
 	t.Run("test create - generated Ed25519 key", func(t *testing.T) {
-		_, pk, err := ed25519.GenerateKey(rand.Reader)
+		pk, _, err := ed25519.GenerateKey(rand.Reader)
 		require.NoError(t, err)
 
 		key, err := jwksupport.JWKFromKey(pk)
@@ -139,6 +142,47 @@ func TestCreate(t *testing.T) {
 
 		err = prettyPrint(didDoc)
 		require.NoError(t, err)
+
+		require.Equal(t, didDoc.ID+"#0", didDoc.VerificationMethod[0].ID)
+		require.Equal(t, 1, len(didDoc.Authentication))
+		require.Equal(t, 1, len(didDoc.AssertionMethod))
+		require.Equal(t, 1, len(didDoc.KeyAgreement))
+	})
+
+	t.Run("test create - thumbprint key ID", func(t *testing.T) {
+		var key jwkapi.JWK
+
+		err := json.Unmarshal([]byte(testP256), &key)
+		require.NoError(t, err)
+
+		vm, err := did.NewVerificationMethodFromJWK("", "JsonWebKey2020", "", &key)
+		require.NoError(t, err)
+
+		result, err := jwk.New().Create(&did.Doc{VerificationMethod: []did.VerificationMethod{*vm}},
+			vdrapi.WithOption(jwk.ThumbprintKeyIDOpt, true))
+		require.NoError(t, err)
+
+		thumbprint, err := key.Thumbprint(crypto.SHA256)
+		require.NoError(t, err)
+
+		require.Equal(t, result.DIDDocument.ID+"#"+base64.RawURLEncoding.EncodeToString(thumbprint),
+			result.DIDDocument.VerificationMethod[0].ID)
+	})
+
+	t.Run("error - private key", func(t *testing.T) {
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		key, err := jwksupport.JWKFromKey(privateKey)
+		require.NoError(t, err)
+
+		vm, err := did.NewVerificationMethodFromJWK("", "JsonWebKey2020", "", key)
+		require.NoError(t, err)
+
+		result, err := jwk.New().Create(&did.Doc{VerificationMethod: []did.VerificationMethod{*vm}})
+		require.ErrorIs(t, err, jwk.ErrPrivateKey)
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+		require.Nil(t, result)
 	})
 
 	t.Run("error - missing verification method", func(t *testing.T) {
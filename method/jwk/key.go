@@ -0,0 +1,176 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwk
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+
+	"github.com/trustbloc/did-go/pkg/canonicalizer"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	useSig = "sig"
+	useEnc = "enc"
+
+	crvX25519  = "X25519"
+	crvEd25519 = "Ed25519"
+)
+
+// ErrPrivateKey is returned when the JWK of a did:jwk DID contains private key members.
+var ErrPrivateKey = errors.New("did:jwk must not contain private key material")
+
+var (
+	// privateMembers are the JWK members holding private key material (RFC 7518 section 6).
+	privateMembers = []string{"d", "p", "q", "dp", "dq", "qi", "oth", "k"}
+
+	// curves are the curves supported for each key type, RSA keys have no curve.
+	curves = map[string][]string{
+		"EC":  {"P-256", "P-384", "P-521", "secp256k1"},
+		"OKP": {crvEd25519, crvX25519},
+		"RSA": {""},
+	}
+
+	// thumbprintMembers are the required members of each key type (RFC 7638 section 3.2).
+	thumbprintMembers = map[string][]string{
+		"EC":  {"crv", "kty", "x", "y"},
+		"OKP": {"crv", "kty", "x"},
+		"RSA": {"e", "kty", "n"},
+	}
+
+	sigOps = []string{"sign", "verify"}
+	encOps = []string{"encrypt", "decrypt", "wrapKey", "unwrapKey", "deriveKey", "deriveBits"}
+)
+
+// publicJWK is the public JWK of a did:jwk DID along with its JSON members.
+type publicJWK struct {
+	key     *jwk.JWK
+	members map[string]interface{}
+}
+
+// parseJWK parses and validates the JSON of a public JWK. Private key material and malformed keys are reported as
+// vdrapi.ErrInvalidDID, unsupported key types and curves as vdrapi.ErrInvalidPublicKeyType.
+func parseJWK(keyBytes []byte) (*publicJWK, error) {
+	var members map[string]interface{}
+
+	if err := json.Unmarshal(keyBytes, &members); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal key: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	for _, member := range privateMembers {
+		if _, ok := members[member]; ok {
+			return nil, fmt.Errorf("%w: found member [%s]: %w", ErrPrivateKey, member, vdrapi.ErrInvalidDID)
+		}
+	}
+
+	kty, _ := members["kty"].(string) //nolint:errcheck
+	crv, _ := members["crv"].(string) //nolint:errcheck
+
+	supported, ok := curves[kty]
+	if !ok {
+		return nil, fmt.Errorf("unsupported kty [%s]: %w", kty, vdrapi.ErrInvalidPublicKeyType)
+	}
+
+	if !slices.Contains(supported, crv) {
+		return nil, fmt.Errorf("unsupported crv [%s] for kty [%s]: %w", crv, kty, vdrapi.ErrInvalidPublicKeyType)
+	}
+
+	for _, member := range thumbprintMembers[kty] {
+		if _, ok := members[member].(string); !ok {
+			return nil, fmt.Errorf("missing member [%s]: %w", member, vdrapi.ErrInvalidDID)
+		}
+	}
+
+	var key jwk.JWK
+
+	if err := json.Unmarshal(keyBytes, &key); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal key: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	return &publicJWK{key: &key, members: members}, nil
+}
+
+// thumbprint returns the base64url encoded RFC 7638 SHA-256 thumbprint of the key.
+func (k *publicJWK) thumbprint() (string, error) {
+	required := make(map[string]interface{})
+
+	for _, member := range thumbprintMembers[k.members["kty"].(string)] { //nolint:forcetypeassert
+		required[member] = k.members[member]
+	}
+
+	// the JCS form of the required members is the RFC 7638 form: sorted members and no whitespace.
+	canonical, err := canonicalizer.MarshalCanonical(required)
+	if err != nil {
+		return "", fmt.Errorf("marshal thumbprint members: %w", err)
+	}
+
+	hash := sha256.Sum256(canonical)
+
+	return base64.RawURLEncoding.EncodeToString(hash[:]), nil
+}
+
+// usage returns whether the key is a signature key, an encryption key or both, from its use and key_ops members.
+// X25519 keys are encryption only keys.
+func (k *publicJWK) usage() (bool, bool, error) { //nolint:gocyclo
+	use, ok := k.members["use"].(string)
+	if !ok && k.members["use"] != nil || ok && use != useSig && use != useEnc {
+		return false, false, fmt.Errorf("invalid use [%v]: %w", k.members["use"], vdrapi.ErrInvalidDID)
+	}
+
+	sig, enc := use == useSig, use == useEnc
+
+	if rawOps, exists := k.members["key_ops"]; exists {
+		ops, valid := rawOps.([]interface{})
+		if !valid {
+			return false, false, fmt.Errorf("key_ops must be an array: %w", vdrapi.ErrInvalidDID)
+		}
+
+		var opsSig, opsEnc bool
+
+		for _, rawOp := range ops {
+			op, _ := rawOp.(string) //nolint:errcheck
+
+			switch {
+			case slices.Contains(sigOps, op):
+				opsSig = true
+			case slices.Contains(encOps, op):
+				opsEnc = true
+			default:
+				return false, false, fmt.Errorf("invalid key_ops [%v]: %w", rawOp, vdrapi.ErrInvalidDID)
+			}
+		}
+
+		// use and key_ops must be consistent (RFC 7517 section 4.3).
+		if use != "" && (sig && opsEnc || enc && opsSig) {
+			return false, false, fmt.Errorf("use [%s] is not consistent with key_ops: %w", use, vdrapi.ErrInvalidDID)
+		}
+
+		sig, enc = sig || opsSig, enc || opsEnc
+	}
+
+	crv, _ := k.members["crv"].(string) //nolint:errcheck
+
+	switch {
+	case crv == crvX25519 && sig:
+		return false, false, fmt.Errorf("X25519 key can not be a signature key: %w", vdrapi.ErrInvalidPublicKeyType)
+	case crv == crvEd25519 && enc && !sig:
+		return false, false, fmt.Errorf("Ed25519 key can not be an encryption key: %w", vdrapi.ErrInvalidPublicKeyType)
+	case crv == crvX25519:
+		return false, true, nil
+	case !sig && !enc:
+		return true, true, nil
+	}
+
+	return sig, enc, nil
+}
@@ -7,13 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package jwk
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
 
-	"github.com/trustbloc/kms-go/doc/jose/jwk"
-
 	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/pkg/canonicalizer"
 	vdrapi "github.com/trustbloc/did-go/vdr/api"
 )
 
@@ -25,14 +25,18 @@ const (
 )
 
 // Read expands did:jwk value to a DID document.
-func (v *VDR) Read(didJWK string, _ ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+//
+// The JWK must be a public key with a supported kty and crv, in JCS canonical form. Its use and key_ops members
+// select the verification relationships of the key, X25519 keys being key agreement keys only. The verification
+// method ID is #0, or the RFC 7638 thumbprint of the key with the ThumbprintKeyIDOpt option.
+func (v *VDR) Read(didJWK string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
 	parsed, err := did.Parse(didJWK)
 	if err != nil {
-		return nil, fmt.Errorf("jwk-vdr read: failed to parse DID: %w", err)
+		return nil, fmt.Errorf("jwk-vdr read: failed to parse DID: %w: %w", err, vdrapi.ErrInvalidDID)
 	}
 
 	if parsed.Method != DIDMethod {
-		return nil, fmt.Errorf("jwk-vdr read: invalid method: %s", parsed.Method)
+		return nil, fmt.Errorf("jwk-vdr read: invalid method: %s: %w", parsed.Method, vdrapi.ErrInvalidDID)
 	}
 
 	key, err := getJWK(parsed.MethodSpecificID)
@@ -40,54 +44,124 @@ func (v *VDR) Read(didJWK string, _ ...vdrapi.DIDMethodOption) (*did.DocResoluti
 		return nil, fmt.Errorf("jwk-vdr read: failed to get key: %w", err)
 	}
 
-	return createJWKResolutionResult(didJWK, key)
+	return createJWKResolutionResult(didJWK, key, opts)
+}
+
+// ReadContext expands did:jwk value to a DID document. Resolution is done offline, so the context
+// is only checked for cancellation.
+func (v *VDR) ReadContext(ctx context.Context, didJWK string,
+	opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return v.Read(didJWK, opts...)
+}
+
+// DocFromKey creates the DID document of didID with the did:jwk layout, for DID methods whose identifier is derived
+// from a public JWK. The key is validated, and its verification relationships selected, as on Read.
+func DocFromKey(didID string, keyBytes []byte, opts ...vdrapi.DIDMethodOption) (*did.Doc, error) {
+	key, err := parseJWK(keyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	docResolution, err := createJWKResolutionResult(didID, key, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return docResolution.DIDDocument, nil
 }
 
-func createJWKResolutionResult(didJWK string, key *jwk.JWK) (*did.DocResolution, error) {
-	//nolint:perfsprint
-	vm, err := did.NewVerificationMethodFromJWK(fmt.Sprintf("%s#0", didJWK), jsonWebKey2020, didJWK, key)
+// Thumbprint returns the base64url encoded RFC 7638 SHA-256 thumbprint of a public JWK.
+func Thumbprint(keyBytes []byte) (string, error) {
+	key, err := parseJWK(keyBytes)
+	if err != nil {
+		return "", err
+	}
+
+	return key.thumbprint()
+}
+
+func createJWKResolutionResult(didJWK string, key *publicJWK,
+	opts []vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	didOpts := &vdrapi.DIDMethodOpts{Values: make(map[string]interface{})}
+	// Apply options
+	for _, opt := range opts {
+		opt(didOpts)
+	}
+
+	keyID := "0"
+
+	if thumbprintKeyID, _ := didOpts.Values[ThumbprintKeyIDOpt].(bool); thumbprintKeyID { //nolint:errcheck
+		var err error
+
+		keyID, err = key.thumbprint()
+		if err != nil {
+			return nil, fmt.Errorf("generate resolution result: %w", err)
+		}
+	}
+
+	sig, enc, err := key.usage()
+	if err != nil {
+		return nil, fmt.Errorf("generate resolution result: %w", err)
+	}
+
+	vm, err := did.NewVerificationMethodFromJWK(didJWK+"#"+keyID, jsonWebKey2020, didJWK, key.key)
 	if err != nil {
 		return nil, fmt.Errorf("generate resolution result: error creating verification method: %w", err)
 	}
 
-	didDoc := createDoc(vm, didJWK)
+	didDoc := createDoc(vm, didJWK, sig, enc)
 
-	return &did.DocResolution{Context: []string{schemaResV1}, DIDDocument: didDoc}, nil
+	return &did.DocResolution{
+		Context:            []string{schemaResV1},
+		DIDDocument:        didDoc,
+		ResolutionMetadata: &did.ResolutionMetadata{ContentType: did.ContentTypeDIDLDJSON},
+	}, nil
 }
 
-func createDoc(pubKey *did.VerificationMethod, didJWK string) *did.Doc {
+func createDoc(pubKey *did.VerificationMethod, didJWK string, sig, enc bool) *did.Doc {
 	didDoc := &did.Doc{ //nolint:exhaustruct
 		Context:            []string{schemaDIDV1, jwsSuiteV1},
 		ID:                 didJWK,
 		VerificationMethod: []did.VerificationMethod{*pubKey},
 	}
 
-	if pubKey.JSONWebKey().Use == "" || pubKey.JSONWebKey().Use == "sig" {
+	if sig {
 		didDoc.Authentication = []did.Verification{*did.NewReferencedVerification(pubKey, did.Authentication)}
 		didDoc.AssertionMethod = []did.Verification{*did.NewReferencedVerification(pubKey, did.AssertionMethod)}
 		didDoc.CapabilityDelegation = []did.Verification{*did.NewReferencedVerification(pubKey, did.CapabilityDelegation)}
 		didDoc.CapabilityInvocation = []did.Verification{*did.NewReferencedVerification(pubKey, did.CapabilityInvocation)}
 	}
 
-	if pubKey.JSONWebKey().Use == "" || pubKey.JSONWebKey().Use == "enc" {
+	if enc {
 		didDoc.KeyAgreement = []did.Verification{*did.NewReferencedVerification(pubKey, did.KeyAgreement)}
 	}
 
 	return didDoc
 }
 
-func getJWK(jwkMethodID string) (*jwk.JWK, error) {
+func getJWK(jwkMethodID string) (*publicJWK, error) {
 	decoded, err := base64.RawURLEncoding.DecodeString(jwkMethodID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode key: %w", err)
+		return nil, fmt.Errorf("failed to decode key: %w: %w", err, vdrapi.ErrInvalidDID)
 	}
 
-	var key jwk.JWK
+	key, err := parseJWK(decoded)
+	if err != nil {
+		return nil, err
+	}
 
-	err = json.Unmarshal(decoded, &key)
+	canonical, err := canonicalizer.MarshalCanonical(decoded)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal key: %w", err)
+		return nil, fmt.Errorf("failed to canonicalize key: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	if !bytes.Equal(canonical, decoded) {
+		return nil, fmt.Errorf("key is not in JCS canonical form: %w", vdrapi.ErrInvalidDID)
 	}
 
-	return &key, nil
+	return key, nil
 }
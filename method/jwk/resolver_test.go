@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package jwk_test
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -16,6 +18,7 @@ import (
 
 	"github.com/trustbloc/did-go/doc/did"
 	"github.com/trustbloc/did-go/method/jwk"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
 )
 
 func TestReadInvalidDID(t *testing.T) {
@@ -34,6 +37,7 @@ func TestReadInvalidDID(t *testing.T) {
 		doc, err := v.Read("did:different:z6MkpTHR8VNsBxYAAWHut2Geadd9jSwuBV8xRoAnwWsdvktH")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "jwk-vdr read: invalid method: different")
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
 		require.Nil(t, doc)
 	})
 
@@ -56,6 +60,17 @@ func TestReadInvalidDID(t *testing.T) {
 			"jwk-vdr read: failed to get key: failed to decode key: illegal base64 data")
 		require.Nil(t, doc)
 	})
+
+	t.Run("validate cancelled context", func(t *testing.T) {
+		v := jwk.New()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		doc, err := v.ReadContext(ctx, "did:jwk:invalid")
+		require.ErrorIs(t, err, context.Canceled)
+		require.Nil(t, doc)
+	})
 }
 
 func TestReadP256(t *testing.T) {
@@ -145,6 +160,141 @@ func TestReadX25519(t *testing.T) {
 	})
 }
 
+func TestReadValidation(t *testing.T) {
+	v := jwk.New()
+
+	toDID := func(key string) string {
+		canonical, err := canonicalizer.MarshalCanonical([]byte(key))
+		if err != nil {
+			canonical = []byte(key)
+		}
+
+		return "did:jwk:" + base64.RawURLEncoding.EncodeToString(canonical)
+	}
+
+	t.Run("error - private key", func(t *testing.T) {
+		for _, key := range []string{
+			`{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo",` +
+				`"d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A"}`,
+			`{"kty":"oct","k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow"}`,
+		} {
+			doc, err := v.Read(toDID(key))
+			require.ErrorIs(t, err, jwk.ErrPrivateKey)
+			require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+			require.Nil(t, doc)
+		}
+	})
+
+	t.Run("error - unsupported key type", func(t *testing.T) {
+		for _, key := range []string{
+			`{"kty":"EC","crv":"P-192","x":"AAAA","y":"AAAA"}`,
+			`{"kty":"OKP","crv":"Ed448","x":"AAAA"}`,
+			`{"kty":"RSA","crv":"P-256","n":"AAAA","e":"AQAB"}`,
+			`{"kty":"unknown"}`,
+		} {
+			doc, err := v.Read(toDID(key))
+			require.ErrorIs(t, err, vdrapi.ErrInvalidPublicKeyType, key)
+			require.NotErrorIs(t, err, vdrapi.ErrInvalidDID, key)
+			require.Nil(t, doc)
+		}
+	})
+
+	t.Run("error - malformed key", func(t *testing.T) {
+		for _, key := range []string{
+			`{"kty":"EC","crv":"P-256","x":"acbIQiuMs3i8_uszEjJ2tpTtRM4EU3yz91PH6CdH2V0"}`,
+			`{"kty":"EC","crv":"P-256","x":1,"y":2}`,
+			`{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo","use":"other"}`,
+			`{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo","key_ops":"sign"}`,
+			`{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo","key_ops":["other"]}`,
+			`{"kty":"EC","crv":"P-256","x":"acbIQiuMs3i8_uszEjJ2tpTtRM4EU3yz91PH6CdH2V0",` +
+				`"y":"_KcyLj9vWMptnmKtm46GqDz8wf74I5LKgrl2GzH3nSE","use":"sig","key_ops":["deriveKey"]}`,
+		} {
+			doc, err := v.Read(toDID(key))
+			require.ErrorIs(t, err, vdrapi.ErrInvalidDID, key)
+			require.Nil(t, doc)
+		}
+	})
+
+	t.Run("use and key_ops relationships", func(t *testing.T) {
+		p256 := `"kty":"EC","crv":"P-256","x":"acbIQiuMs3i8_uszEjJ2tpTtRM4EU3yz91PH6CdH2V0",` +
+			`"y":"_KcyLj9vWMptnmKtm46GqDz8wf74I5LKgrl2GzH3nSE"`
+
+		for _, tc := range []struct {
+			key      string
+			sig, enc bool
+		}{
+			{key: `{` + p256 + `}`, sig: true, enc: true},
+			{key: `{` + p256 + `,"use":"sig"}`, sig: true},
+			{key: `{` + p256 + `,"use":"enc"}`, enc: true},
+			{key: `{` + p256 + `,"key_ops":["verify"]}`, sig: true},
+			{key: `{` + p256 + `,"key_ops":["deriveKey","deriveBits"]}`, enc: true},
+			{key: `{` + p256 + `,"use":"sig","key_ops":["sign","verify"]}`, sig: true},
+			{key: `{"kty":"OKP","crv":"X25519","x":"3p7bfXt9wbTTW2HC7OQ1Nz-DQ8hbeGdNrfx-FG-IK08"}`, enc: true},
+			{key: `{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`, sig: true, enc: true},
+		} {
+			docResolution, err := v.Read(toDID(tc.key))
+			require.NoError(t, err, tc.key)
+
+			didDoc := docResolution.DIDDocument
+			require.Equal(t, didDoc.ID+"#0", didDoc.VerificationMethod[0].ID)
+			require.Equal(t, tc.sig, len(didDoc.Authentication) == 1, tc.key)
+			require.Equal(t, tc.sig, len(didDoc.AssertionMethod) == 1, tc.key)
+			require.Equal(t, tc.sig, len(didDoc.CapabilityInvocation) == 1, tc.key)
+			require.Equal(t, tc.sig, len(didDoc.CapabilityDelegation) == 1, tc.key)
+			require.Equal(t, tc.enc, len(didDoc.KeyAgreement) == 1, tc.key)
+		}
+	})
+
+	t.Run("error - key usage not supported by the curve", func(t *testing.T) {
+		for _, key := range []string{
+			`{"kty":"OKP","crv":"X25519","x":"3p7bfXt9wbTTW2HC7OQ1Nz-DQ8hbeGdNrfx-FG-IK08","use":"sig"}`,
+			`{"kty":"OKP","crv":"X25519","x":"3p7bfXt9wbTTW2HC7OQ1Nz-DQ8hbeGdNrfx-FG-IK08","key_ops":["verify"]}`,
+			`{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo","use":"enc"}`,
+		} {
+			doc, err := v.Read(toDID(key))
+			require.ErrorIs(t, err, vdrapi.ErrInvalidPublicKeyType, key)
+			require.Nil(t, doc)
+		}
+	})
+
+	t.Run("error - key not in canonical form", func(t *testing.T) {
+		for _, key := range []string{
+			// X25519 example of the did:jwk spec.
+			`{"kty":"OKP","crv":"X25519","use":"enc","x":"3p7bfXt9wbTTW2HC7OQ1Nz-DQ8hbeGdNrfx-FG-IK08"}`,
+			`{"crv":"X25519", "kty":"OKP","use":"enc","x":"3p7bfXt9wbTTW2HC7OQ1Nz-DQ8hbeGdNrfx-FG-IK08"}`,
+			`{"crv":"X25519","kty":"OKP","use":"enc","x":"3p7bfXt9wbTTW2HC7OQ1Nz-DQ8hbeGdNrfx-FG-IK08"}` + "\n",
+		} {
+			doc, err := v.Read("did:jwk:" + base64.RawURLEncoding.EncodeToString([]byte(key)))
+			require.ErrorIs(t, err, vdrapi.ErrInvalidDID, key)
+			require.ErrorContains(t, err, "key is not in JCS canonical form", key)
+			require.Nil(t, doc)
+		}
+	})
+
+	t.Run("thumbprint key ID", func(t *testing.T) {
+		// RFC 7638 section 3.1 example.
+		rsaKey := `{"kty":"RSA","n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6t` +
+			`Soc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMi` +
+			`cAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_` +
+			`xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw","e":"AQAB","alg":"RS256","kid":"2011-04-29"}`
+
+		docResolution, err := v.Read(toDID(rsaKey), vdrapi.WithOption(jwk.ThumbprintKeyIDOpt, true))
+		require.NoError(t, err)
+		require.Equal(t, docResolution.DIDDocument.ID+"#NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs",
+			docResolution.DIDDocument.VerificationMethod[0].ID)
+		require.Equal(t, docResolution.DIDDocument.VerificationMethod[0].ID,
+			docResolution.DIDDocument.Authentication[0].VerificationMethod.ID)
+
+		thumbprint, err := jwk.Thumbprint([]byte(rsaKey))
+		require.NoError(t, err)
+		require.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", thumbprint)
+
+		doc, err := jwk.DocFromKey("did:example:123", []byte(rsaKey))
+		require.NoError(t, err)
+		require.Equal(t, "did:example:123#0", doc.VerificationMethod[0].ID)
+	})
+}
+
 func TestCreateJsonWeKey(t *testing.T) {
 	t.Run("test invalid code", func(t *testing.T) {
 	})
@@ -165,7 +315,7 @@ func prettyPrint(result interface{}) error {
 const p256JWK = `did:jwk:eyJjcnYiOiJQLTI1NiIsImt0eSI6IkVDIiwieCI6ImFjYklRaXVNczNpOF91c3pFakoydHBUdFJNNEVVM3l6OTFQSDZDZEgyVjAiLCJ5IjoiX0tjeUxqOXZXTXB0bm1LdG00NkdxRHo4d2Y3NEk1TEtncmwyR3pIM25TRSJ9`
 
 //nolint:lll
-const x25519 = `did:jwk:eyJrdHkiOiJPS1AiLCJjcnYiOiJYMjU1MTkiLCJ1c2UiOiJlbmMiLCJ4IjoiM3A3YmZYdDl3YlRUVzJIQzdPUTFOei1EUThoYmVHZE5yZngtRkctSUswOCJ9`
+const x25519 = `did:jwk:eyJjcnYiOiJYMjU1MTkiLCJrdHkiOiJPS1AiLCJ1c2UiOiJlbmMiLCJ4IjoiM3A3YmZYdDl3YlRUVzJIQzdPUTFOei1EUThoYmVHZE5yZngtRkctSUswOCJ9`
 
 //nolint:lll
 const expectedP256DIDDocument = `
@@ -202,12 +352,12 @@ const expectedX25519Document = `
     "https://www.w3.org/ns/did/v1",
     "https://w3id.org/security/suites/jws-2020/v1"
   ],
-  "id": "did:jwk:eyJrdHkiOiJPS1AiLCJjcnYiOiJYMjU1MTkiLCJ1c2UiOiJlbmMiLCJ4IjoiM3A3YmZYdDl3YlRUVzJIQzdPUTFOei1EUThoYmVHZE5yZngtRkctSUswOCJ9",
+  "id": "did:jwk:eyJjcnYiOiJYMjU1MTkiLCJrdHkiOiJPS1AiLCJ1c2UiOiJlbmMiLCJ4IjoiM3A3YmZYdDl3YlRUVzJIQzdPUTFOei1EUThoYmVHZE5yZngtRkctSUswOCJ9",
   "verificationMethod": [
     {
-      "id": "did:jwk:eyJrdHkiOiJPS1AiLCJjcnYiOiJYMjU1MTkiLCJ1c2UiOiJlbmMiLCJ4IjoiM3A3YmZYdDl3YlRUVzJIQzdPUTFOei1EUThoYmVHZE5yZngtRkctSUswOCJ9#0",
+      "id": "did:jwk:eyJjcnYiOiJYMjU1MTkiLCJrdHkiOiJPS1AiLCJ1c2UiOiJlbmMiLCJ4IjoiM3A3YmZYdDl3YlRUVzJIQzdPUTFOei1EUThoYmVHZE5yZngtRkctSUswOCJ9#0",
       "type": "JsonWebKey2020",
-      "controller": "did:jwk:eyJrdHkiOiJPS1AiLCJjcnYiOiJYMjU1MTkiLCJ1c2UiOiJlbmMiLCJ4IjoiM3A3YmZYdDl3YlRUVzJIQzdPUTFOei1EUThoYmVHZE5yZngtRkctSUswOCJ9",
+      "controller": "did:jwk:eyJjcnYiOiJYMjU1MTkiLCJrdHkiOiJPS1AiLCJ1c2UiOiJlbmMiLCJ4IjoiM3A3YmZYdDl3YlRUVzJIQzdPUTFOei1EUThoYmVHZE5yZngtRkctSUswOCJ9",
       "publicKeyJwk": {
         "kty":"OKP",
         "crv":"X25519",
@@ -216,5 +366,5 @@ const expectedX25519Document = `
       }
     }
   ],
-  "keyAgreement": ["did:jwk:eyJrdHkiOiJPS1AiLCJjcnYiOiJYMjU1MTkiLCJ1c2UiOiJlbmMiLCJ4IjoiM3A3YmZYdDl3YlRUVzJIQzdPUTFOei1EUThoYmVHZE5yZngtRkctSUswOCJ9#0"]
+  "keyAgreement": ["did:jwk:eyJjcnYiOiJYMjU1MTkiLCJrdHkiOiJPS1AiLCJ1c2UiOiJlbmMiLCJ4IjoiM3A3YmZYdDl3YlRUVzJIQzdPUTFOei1EUThoYmVHZE5yZngtRkctSUswOCJ9#0"]
 }`
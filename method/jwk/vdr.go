@@ -17,6 +17,9 @@ import (
 const (
 	// DIDMethod did method.
 	DIDMethod = "jwk"
+	// ThumbprintKeyIDOpt option, when true, uses the RFC 7638 thumbprint of the JWK as verification method ID instead
+	// of #0 on Create and Read.
+	ThumbprintKeyIDOpt = "thumbprintKeyID"
 )
 
 // VDR implements did:jwk method support.
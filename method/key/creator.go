@@ -7,26 +7,46 @@ SPDX-License-Identifier: Apache-2.0
 package key
 
 import (
+	"crypto/ecdsa"
 	"errors"
 	"fmt"
 	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/go-jose/go-jose/v3"
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
 	"github.com/trustbloc/kms-go/doc/util/fingerprint"
 	"github.com/trustbloc/kms-go/util/cryptoutil"
 
 	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/pkg/canonicalizer"
 	vdrapi "github.com/trustbloc/did-go/vdr/api"
 )
 
 const (
-	schemaResV1                = "https://w3id.org/did-resolution/v1"
-	schemaDIDV1                = "https://w3id.org/did/v1"
-	ed25519VerificationKey2018 = "Ed25519VerificationKey2018"
-	ed25519VerificationKey2020 = "Ed25519VerificationKey2020"
-	x25519KeyAgreementKey2019  = "X25519KeyAgreementKey2019"
-	x25519KeyAgreementKey2020  = "X25519KeyAgreementKey2020"
-	bls12381G2Key2020          = "Bls12381G2Key2020"
-	jsonWebKey2020             = "JsonWebKey2020"
+	schemaResV1                       = "https://w3id.org/did-resolution/v1"
+	schemaDIDV1                       = "https://w3id.org/did/v1"
+	schemaMultikeyV1                  = "https://w3id.org/security/multikey/v1"
+	ed25519VerificationKey2018        = "Ed25519VerificationKey2018"
+	ed25519VerificationKey2020        = "Ed25519VerificationKey2020"
+	x25519KeyAgreementKey2019         = "X25519KeyAgreementKey2019"
+	x25519KeyAgreementKey2020         = "X25519KeyAgreementKey2020"
+	bls12381G1Key2020                 = "Bls12381G1Key2020"
+	bls12381G2Key2020                 = "Bls12381G2Key2020"
+	ecdsaSecp256k1VerificationKey2019 = "EcdsaSecp256k1VerificationKey2019"
+	jsonWebKey2020                    = "JsonWebKey2020"
+	multikey                          = "Multikey"
+
+	// multicodec codes missing from the kms-go fingerprint package.
+	// source: https://github.com/multiformats/multicodec/blob/master/table.csv.
+	secp256k1PubKeyMultiCodec  = 0xe7
+	bls12381g1PubKeyMultiCodec = 0xea
+	jwkJCSPubMultiCodec        = 0xeb51
+
+	secp256k1Crv = "secp256k1"
+	x25519Crv    = "X25519"
 )
 
 // Create new DID document for didDoc.
@@ -54,29 +74,60 @@ func (v *VDR) Create(didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) (*did.DocR
 	}
 
 	verificationMethodType := didDoc.VerificationMethod[0].Type
+	value := didDoc.VerificationMethod[0].Value
+	// raw key, without the multicodec prefix of a Multikey value.
+	rawKey := value
 
 	switch verificationMethodType {
 	case jsonWebKey2020:
-		didKey, keyID, err = fingerprint.CreateDIDKeyByJwk(didDoc.VerificationMethod[0].JSONWebKey())
+		if jcsPub, _ := createDIDOpts.Values[JWKJCSPub].(bool); jcsPub { //nolint:errcheck
+			didKey, keyID, err = createDIDKeyByJwkJCS(didDoc.VerificationMethod[0].JSONWebKey())
+		} else {
+			didKey, keyID, err = createDIDKeyByJwk(didDoc.VerificationMethod[0].JSONWebKey())
+		}
+
 		if err != nil {
 			return nil, err
 		}
+	case multikey:
+		methodID := "z" + base58.Encode(value)
+
+		rawKey, keyCode, err = fingerprint.PubKeyFromFingerprint(methodID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Multikey value: %w", err)
+		}
+
+		didKey = "did:key:" + methodID
+		keyID = didKey + "#" + methodID
 	default:
 		keyCode, err = getKeyCode(&didDoc.VerificationMethod[0])
 		if err != nil {
 			return nil, err
 		}
 
-		didKey, keyID = fingerprint.CreateDIDKeyByCode(keyCode, didDoc.VerificationMethod[0].Value)
+		if keyCode == secp256k1PubKeyMultiCodec {
+			value, err = compressSecp256k1(value)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		didKey, keyID = fingerprint.CreateDIDKeyByCode(keyCode, value)
 	}
 
-	publicKey = did.NewVerificationMethodFromBytes(keyID, didDoc.VerificationMethod[0].Type, didKey,
-		didDoc.VerificationMethod[0].Value)
+	publicKey = did.NewVerificationMethodFromBytes(keyID, verificationMethodType, didKey, value)
+
+	if keyCode == fingerprint.X25519PubKeyMultiCodec {
+		return &did.DocResolution{
+			Context:     []string{schemaResV1},
+			DIDDocument: createKeyAgreementDoc(publicKey, didKey),
+		}, nil
+	}
 
 	if verificationMethodType == ed25519VerificationKey2018 ||
-		verificationMethodType == ed25519VerificationKey2020 {
-		keyAgr, err = keyAgreementFromEd25519(didKey, didDoc.VerificationMethod[0].Value,
-			verificationMethodType)
+		verificationMethodType == ed25519VerificationKey2020 ||
+		verificationMethodType == multikey && keyCode == fingerprint.ED25519PubKeyMultiCodec {
+		keyAgr, err = keyAgreementFromEd25519(didKey, rawKey, verificationMethodType)
 		if err != nil {
 			return nil, err
 		}
@@ -93,7 +144,13 @@ func (v *VDR) Create(didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) (*did.DocR
 		}
 	}
 
-	return &did.DocResolution{Context: []string{schemaResV1}, DIDDocument: createDoc(publicKey, keyAgr, didKey)}, nil
+	didDoc = createDoc(publicKey, keyAgr, didKey)
+
+	if verificationMethodType == multikey {
+		didDoc.Context = []string{schemaDIDV1, schemaMultikeyV1}
+	}
+
+	return &did.DocResolution{Context: []string{schemaResV1}, DIDDocument: didDoc}, nil
 }
 
 func getKeyCode(verificationMethod *did.VerificationMethod) (uint64, error) {
@@ -102,8 +159,14 @@ func getKeyCode(verificationMethod *did.VerificationMethod) (uint64, error) {
 	switch verificationMethod.Type {
 	case ed25519VerificationKey2018, ed25519VerificationKey2020:
 		keyCode = fingerprint.ED25519PubKeyMultiCodec
+	case x25519KeyAgreementKey2019, x25519KeyAgreementKey2020:
+		keyCode = fingerprint.X25519PubKeyMultiCodec
+	case bls12381G1Key2020:
+		keyCode = bls12381g1PubKeyMultiCodec
 	case bls12381G2Key2020:
 		keyCode = fingerprint.BLS12381g2PubKeyMultiCodec
+	case ecdsaSecp256k1VerificationKey2019:
+		keyCode = secp256k1PubKeyMultiCodec
 	default:
 		return 0, fmt.Errorf("not supported public key type: %s", verificationMethod.Type)
 	}
@@ -111,6 +174,69 @@ func getKeyCode(verificationMethod *did.VerificationMethod) (uint64, error) {
 	return keyCode, nil
 }
 
+// createDIDKeyByJwk extends fingerprint.CreateDIDKeyByJwk with secp256k1 keys.
+func createDIDKeyByJwk(jsonWebKey *jwk.JWK) (string, string, error) {
+	if jsonWebKey == nil || jsonWebKey.Kty != "EC" || jsonWebKey.Crv != secp256k1Crv {
+		return fingerprint.CreateDIDKeyByJwk(jsonWebKey)
+	}
+
+	key, ok := jsonWebKey.Key.(*ecdsa.PublicKey)
+	if !ok {
+		return "", "", fmt.Errorf("unexpected EC key type %T", jsonWebKey.Key)
+	}
+
+	var x, y btcec.FieldVal
+
+	x.SetByteSlice(key.X.Bytes())
+	y.SetByteSlice(key.Y.Bytes())
+
+	didKey, keyID := fingerprint.CreateDIDKeyByCode(secp256k1PubKeyMultiCodec,
+		btcec.NewPublicKey(&x, &y).SerializeCompressed())
+
+	return didKey, keyID, nil
+}
+
+// createDIDKeyByJwkJCS creates the jwk_jcs-pub did:key of a public JWK.
+func createDIDKeyByJwkJCS(jsonWebKey *jwk.JWK) (string, string, error) {
+	if jsonWebKey == nil {
+		return "", "", errors.New("missing JWK")
+	}
+
+	// only the key members are kept, so that the DID does not depend on kid, alg or use.
+	keyBytes, err := (&jwk.JWK{
+		JSONWebKey: jose.JSONWebKey{Key: jsonWebKey.Key},
+		Kty:        jsonWebKey.Kty,
+		Crv:        jsonWebKey.Crv,
+	}).MarshalJSON()
+	if err != nil {
+		return "", "", fmt.Errorf("marshal key: %w", err)
+	}
+
+	canonicalBytes, err := canonicalizer.MarshalCanonical(keyBytes)
+	if err != nil {
+		return "", "", fmt.Errorf("marshal canonical: %w", err)
+	}
+
+	// the key is validated as on Read, so that private keys are never published.
+	if _, err = jwkFromJCS(canonicalBytes); err != nil {
+		return "", "", err
+	}
+
+	didKey, keyID := fingerprint.CreateDIDKeyByCode(jwkJCSPubMultiCodec, canonicalBytes)
+
+	return didKey, keyID, nil
+}
+
+// compressSecp256k1 returns the compressed form of a secp256k1 public key, as required by did:key.
+func compressSecp256k1(pubKey []byte) ([]byte, error) {
+	key, err := btcec.ParsePubKey(pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secp256k1 public key: %w", err)
+	}
+
+	return key.SerializeCompressed(), nil
+}
+
 func createDoc(pubKey, keyAgreement *did.VerificationMethod, didKey string) *did.Doc {
 	// Created/Updated time
 	t := time.Now()
@@ -135,6 +261,21 @@ func createDoc(pubKey, keyAgreement *did.VerificationMethod, didKey string) *did
 	}
 }
 
+// createKeyAgreementDoc creates the DID document of an encryption only key, referenced as keyAgreement only.
+func createKeyAgreementDoc(keyAgreement *did.VerificationMethod, didKey string) *did.Doc {
+	// Created/Updated time
+	t := time.Now()
+
+	return &did.Doc{
+		Context:            []string{schemaDIDV1},
+		ID:                 didKey,
+		VerificationMethod: []did.VerificationMethod{*keyAgreement},
+		KeyAgreement:       []did.Verification{*did.NewReferencedVerification(keyAgreement, did.KeyAgreement)},
+		Created:            &t,
+		Updated:            &t,
+	}
+}
+
 func keyAgreementFromEd25519(
 	didKey string,
 	ed25519PubKey []byte,
@@ -147,13 +288,25 @@ func keyAgreementFromEd25519(
 
 	agreement := x25519KeyAgreementKey2019
 
-	if verificationMethodType == ed25519VerificationKey2020 {
-		agreement = x25519KeyAgreementKey2020
-	}
-
 	fp := fingerprint.KeyFingerprint(fingerprint.X25519PubKeyMultiCodec, curve25519PubKey)
 	keyID := fmt.Sprintf("%s#%s", didKey, fp)
 
+	switch verificationMethodType {
+	case ed25519VerificationKey2020, x25519KeyAgreementKey2020:
+		agreement = x25519KeyAgreementKey2020
+	case multikey:
+		// a Multikey value is the multicodec encoded key.
+		agreement = multikey
+		curve25519PubKey = base58.Decode(fp[1:])
+	case jsonWebKey2020:
+		j, err := jwksupport.JWKFromX25519Key(curve25519PubKey)
+		if err != nil {
+			return nil, fmt.Errorf("error creating JWK %w", err)
+		}
+
+		return did.NewVerificationMethodFromJWK(keyID, jsonWebKey2020, didKey, j)
+	}
+
 	pubKey := did.NewVerificationMethodFromBytes(keyID, agreement, didKey, curve25519PubKey)
 
 	return pubKey, nil
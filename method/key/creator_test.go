@@ -10,14 +10,19 @@ import (
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/elliptic"
+	"encoding/base64"
 	"math/big"
+	"strings"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcutil/base58"
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+	"github.com/trustbloc/kms-go/doc/util/fingerprint"
 
 	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
 )
 
 func TestBuild(t *testing.T) {
@@ -205,6 +210,168 @@ func TestBuild(t *testing.T) {
 	})
 }
 
+func TestBuildMulticodecKeys(t *testing.T) {
+	const (
+		didK256       = "did:key:zQ3shokFTS3brHcDQrn82RUDfCZESWL1ZdCEJwekUDPQiYBme"
+		didX25519     = "did:key:z6LSeu9HkTHSfLLeUs2nnzUSNedgDUevfNQgQjQC23ZCit6F"
+		x25519Base58  = "4Dy8E9UaZscuPUf2GLxV44RCNL7oxmEXXkgWXaug1WKV"
+		didEd25519    = "did:key:z6MkpTHR8VNsBxYAAWHut2Geadd9jSwuBV8xRoAnwWsdvktH"
+		ed25519Base58 = "B12NYF8RrR3h41TDCTJojY59usg3mbtbjnFs7Eud1Y6u"
+	)
+
+	k256, _, err := fingerprint.PubKeyFromFingerprint(strings.TrimPrefix(didK256, "did:key:"))
+	require.NoError(t, err)
+
+	k256Key, err := btcec.ParsePubKey(k256)
+	require.NoError(t, err)
+
+	t.Run("build with secp256k1 key type", func(t *testing.T) {
+		// the uncompressed key is compressed, as required by did:key.
+		for _, value := range [][]byte{k256, k256Key.SerializeUncompressed()} {
+			docResolution, err := New().Create(&did.Doc{VerificationMethod: []did.VerificationMethod{{
+				Type:  ecdsaSecp256k1VerificationKey2019,
+				Value: value,
+			}}})
+			require.NoError(t, err)
+			require.Equal(t, didK256, docResolution.DIDDocument.ID)
+			require.Equal(t, k256, docResolution.DIDDocument.VerificationMethod[0].Value)
+		}
+
+		_, err := New().Create(&did.Doc{VerificationMethod: []did.VerificationMethod{{
+			Type:  ecdsaSecp256k1VerificationKey2019,
+			Value: []byte{0x01},
+		}}})
+		require.ErrorContains(t, err, "invalid secp256k1 public key")
+	})
+
+	t.Run("build with secp256k1 JsonWebKey2020", func(t *testing.T) {
+		j, err := jwksupport.JWKFromKey(k256Key.ToECDSA())
+		require.NoError(t, err)
+
+		vm, err := did.NewVerificationMethodFromJWK("id", jsonWebKey2020, "", j)
+		require.NoError(t, err)
+
+		docResolution, err := New().Create(&did.Doc{VerificationMethod: []did.VerificationMethod{*vm}})
+		require.NoError(t, err)
+		require.Equal(t, didK256, docResolution.DIDDocument.ID)
+	})
+
+	t.Run("build with X25519 key type", func(t *testing.T) {
+		docResolution, err := New().Create(&did.Doc{VerificationMethod: []did.VerificationMethod{{
+			Type:  x25519KeyAgreementKey2019,
+			Value: base58.Decode(x25519Base58),
+		}}})
+		require.NoError(t, err)
+
+		doc := docResolution.DIDDocument
+		require.Equal(t, didX25519, doc.ID)
+		require.Len(t, doc.KeyAgreement, 1)
+		require.Empty(t, doc.Authentication)
+		require.Empty(t, doc.AssertionMethod)
+	})
+
+	t.Run("build with BLS12381G1 key type", func(t *testing.T) {
+		g1 := make([]byte, 48)
+		g1[0] = 0xc0
+
+		docResolution, err := New().Create(&did.Doc{VerificationMethod: []did.VerificationMethod{{
+			Type:  bls12381G1Key2020,
+			Value: g1,
+		}}})
+		require.NoError(t, err)
+
+		pubKey, code, err := fingerprint.PubKeyFromFingerprint(strings.TrimPrefix(docResolution.DIDDocument.ID,
+			"did:key:"))
+		require.NoError(t, err)
+		require.Equal(t, uint64(bls12381g1PubKeyMultiCodec), code)
+		require.Equal(t, g1, pubKey)
+	})
+
+	t.Run("build with Multikey key type", func(t *testing.T) {
+		docResolution, err := New().Create(&did.Doc{VerificationMethod: []did.VerificationMethod{{
+			Type:  multikey,
+			Value: base58.Decode(strings.TrimPrefix(didEd25519, "did:key:z")),
+		}}})
+		require.NoError(t, err)
+
+		doc := docResolution.DIDDocument
+		require.Equal(t, didEd25519, doc.ID)
+		require.Equal(t, []string{schemaDIDV1, schemaMultikeyV1}, doc.Context)
+		require.Equal(t, multikey, doc.KeyAgreement[0].VerificationMethod.Type)
+
+		ed25519Key, _, err := fingerprint.PubKeyFromFingerprint(strings.TrimPrefix(didEd25519, "did:key:"))
+		require.NoError(t, err)
+		require.Equal(t, base58.Decode(ed25519Base58), ed25519Key)
+
+		docResolution, err = New().Create(&did.Doc{VerificationMethod: []did.VerificationMethod{{
+			Type:  multikey,
+			Value: base58.Decode(strings.TrimPrefix(didX25519, "did:key:z")),
+		}}})
+		require.NoError(t, err)
+		require.Equal(t, didX25519, docResolution.DIDDocument.ID)
+		require.Empty(t, docResolution.DIDDocument.Authentication)
+
+		_, err = New().Create(&did.Doc{VerificationMethod: []did.VerificationMethod{{Type: multikey}}})
+		require.ErrorContains(t, err, "invalid Multikey value")
+	})
+
+	t.Run("build with jwk_jcs-pub", func(t *testing.T) {
+		j, err := jwksupport.JWKFromKey(&ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(base64URLDecode(t, "ngy44T1vxAT6Di4nr-UaM9K3Tlnz9pkoksDokKFkmNc")),
+			Y:     new(big.Int).SetBytes(base64URLDecode(t, "QCRfOKlSM31GTkb4JHx3nXB4G_jSPMsbdjzlkT_UpPc")),
+		})
+		require.NoError(t, err)
+
+		j.KeyID = "key-1"
+
+		vm, err := did.NewVerificationMethodFromJWK("id", jsonWebKey2020, "", j)
+		require.NoError(t, err)
+
+		docResolution, err := New().Create(&did.Doc{VerificationMethod: []did.VerificationMethod{*vm}},
+			vdrapi.WithOption(JWKJCSPub, true))
+		require.NoError(t, err)
+
+		// EBSI did:key prefix of P-256 keys, the kid is not part of the key.
+		require.True(t, strings.HasPrefix(docResolution.DIDDocument.ID,
+			"did:key:z2dmzD81cgPx8Vki7JbuuMmFYrWPgYoytykUZ3eyqht1j9Kbs"))
+
+		pubKey, code, err := fingerprint.PubKeyFromFingerprint(strings.TrimPrefix(docResolution.DIDDocument.ID,
+			"did:key:"))
+		require.NoError(t, err)
+		require.Equal(t, uint64(jwkJCSPubMultiCodec), code)
+		require.Equal(t, `{"crv":"P-256","kty":"EC","x":"ngy44T1vxAT6Di4nr-UaM9K3Tlnz9pkoksDokKFkmNc",`+
+			`"y":"QCRfOKlSM31GTkb4JHx3nXB4G_jSPMsbdjzlkT_UpPc"}`, string(pubKey))
+
+		_, privateKey, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+
+		j, err = jwksupport.JWKFromKey(privateKey)
+		require.NoError(t, err)
+
+		vm, err = did.NewVerificationMethodFromJWK("id", jsonWebKey2020, "", j)
+		require.NoError(t, err)
+
+		_, err = New().Create(&did.Doc{VerificationMethod: []did.VerificationMethod{*vm}},
+			vdrapi.WithOption(JWKJCSPub, true))
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+		require.ErrorContains(t, err, "private member [d]")
+
+		_, err = New().Create(&did.Doc{VerificationMethod: []did.VerificationMethod{{Type: jsonWebKey2020}}},
+			vdrapi.WithOption(JWKJCSPub, true))
+		require.ErrorContains(t, err, "missing JWK")
+	})
+}
+
+func base64URLDecode(t *testing.T, value string) []byte {
+	t.Helper()
+
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	require.NoError(t, err)
+
+	return decoded
+}
+
 func assertEd25519Doc(
 	t *testing.T,
 	doc *did.Doc,
@@ -7,76 +7,374 @@ SPDX-License-Identifier: Apache-2.0
 package key
 
 import (
+	"bytes"
+	"context"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
 	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
 	"github.com/trustbloc/kms-go/doc/util/fingerprint"
 
 	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/pkg/canonicalizer"
 	vdrapi "github.com/trustbloc/did-go/vdr/api"
 )
 
 // Read expands did:key value to a DID document.
-func (v *VDR) Read(didKey string, _ ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+//
+// The document representation follows the PublicKeyFormat, EnableExperimentalPublicKeyTypes, DefaultContext and
+// EnableEncryptionKeyDerivation resolution options of the did:key spec. The key agreement key of an Ed25519 key can
+// also be given with the EncryptionKey option, as on Create.
+func (v *VDR) Read(didKey string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	readOpts, err := readOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("pub:key vdr Read: %w", err)
+	}
+
 	parsed, err := did.Parse(didKey)
 	if err != nil {
-		return nil, fmt.Errorf("pub:key vdr Read: failed to parse DID document: %w", err)
+		return nil, fmt.Errorf("pub:key vdr Read: failed to parse DID document: %w: %w", err, vdrapi.ErrInvalidDID)
 	}
 
 	if parsed.Method != "key" {
-		return nil, fmt.Errorf("vdr Read: invalid did:key method: %s", parsed.Method)
+		return nil, fmt.Errorf("vdr Read: invalid did:key method: %s: %w", parsed.Method, vdrapi.ErrInvalidDID)
 	}
 
 	if !isValidMethodID(parsed.MethodSpecificID) {
-		return nil, fmt.Errorf("vdr Read: invalid did:key method ID: %s", parsed.MethodSpecificID)
+		return nil, fmt.Errorf("vdr Read: invalid did:key method ID: %s: %w", parsed.MethodSpecificID, vdrapi.ErrInvalidDID)
 	}
 
 	pubKeyBytes, code, err := fingerprint.PubKeyFromFingerprint(parsed.MethodSpecificID)
 	if err != nil {
-		return nil, fmt.Errorf("pub:key vdr Read: failed to get key fingerPrint: %w", err)
+		return nil, fmt.Errorf("pub:key vdr Read: failed to get key fingerPrint: %w: %w", err, vdrapi.ErrInvalidDID)
 	}
 
-	didDoc, err := createDIDDocFromPubKey(parsed.MethodSpecificID, code, pubKeyBytes)
+	didDoc, err := createDIDDocFromPubKey(parsed.MethodSpecificID, code, pubKeyBytes, readOpts)
 	if err != nil {
 		return nil, fmt.Errorf("creating did document from public key failed: %w", err)
 	}
 
-	return &did.DocResolution{Context: []string{schemaResV1}, DIDDocument: didDoc}, nil
+	return &did.DocResolution{
+		Context:            []string{schemaResV1},
+		DIDDocument:        didDoc,
+		ResolutionMetadata: &did.ResolutionMetadata{ContentType: did.ContentTypeDIDLDJSON},
+	}, nil
+}
+
+// ReadContext expands did:key value to a DID document. Resolution is done offline, so the context
+// is only checked for cancellation.
+func (v *VDR) ReadContext(ctx context.Context, didKey string,
+	opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return v.Read(didKey, opts...)
+}
+
+// resolutionOpts are the did:key resolution options.
+type resolutionOpts struct {
+	publicKeyFormat     string
+	experimental        bool
+	defaultContext      string
+	deriveEncryptionKey bool
+	encryptionKey       *did.VerificationMethod
+}
+
+func readOptions(opts []vdrapi.DIDMethodOption) (*resolutionOpts, error) {
+	didOpts := &vdrapi.DIDMethodOpts{Values: make(map[string]interface{})}
+	// Apply options
+	for _, opt := range opts {
+		opt(didOpts)
+	}
+
+	readOpts := &resolutionOpts{defaultContext: schemaDIDV1, deriveEncryptionKey: true}
+
+	err := stringOpt(didOpts, PublicKeyFormat, &readOpts.publicKeyFormat, vdrapi.ErrInvalidPublicKeyType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = boolOpt(didOpts, EnableExperimentalPublicKeyTypes, &readOpts.experimental, vdrapi.ErrInvalidDID); err != nil {
+		return nil, err
+	}
+
+	if err = stringOpt(didOpts, DefaultContext, &readOpts.defaultContext, vdrapi.ErrInvalidDID); err != nil {
+		return nil, err
+	}
+
+	if err = boolOpt(didOpts, EnableEncryptionKeyDerivation, &readOpts.deriveEncryptionKey,
+		vdrapi.ErrInvalidDID); err != nil {
+		return nil, err
+	}
+
+	if k := didOpts.Values[EncryptionKey]; k != nil {
+		var ok bool
+
+		readOpts.encryptionKey, ok = k.(*did.VerificationMethod)
+		if !ok {
+			return nil, fmt.Errorf("encryptionKey not VerificationMethod: %w", vdrapi.ErrInvalidDID)
+		}
+	}
+
+	format := readOpts.publicKeyFormat
+
+	switch {
+	case format == "" || format == multikey || format == jsonWebKey2020 || format == ed25519VerificationKey2020:
+	case experimentalPublicKeyFormats[format]:
+		if !readOpts.experimental {
+			return nil, fmt.Errorf("publicKeyFormat [%s] requires the %s option: %w", format,
+				EnableExperimentalPublicKeyTypes, vdrapi.ErrInvalidPublicKeyType)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported publicKeyFormat [%s]: %w", format, vdrapi.ErrInvalidPublicKeyType)
+	}
+
+	return readOpts, nil
+}
+
+// stringOpt reads the string option of the given name, an option of another type is reported with invalidErr.
+func stringOpt(didOpts *vdrapi.DIDMethodOpts, name string, value *string, invalidErr error) error {
+	v, ok := didOpts.Values[name]
+	if !ok {
+		return nil
+	}
+
+	if *value, ok = v.(string); !ok {
+		return fmt.Errorf("%s option must be a string: %w", name, invalidErr)
+	}
+
+	return nil
+}
+
+// boolOpt reads the bool option of the given name, an option of another type is reported with invalidErr.
+func boolOpt(didOpts *vdrapi.DIDMethodOpts, name string, value *bool, invalidErr error) error {
+	v, ok := didOpts.Values[name]
+	if !ok {
+		return nil
+	}
+
+	if *value, ok = v.(bool); !ok {
+		return fmt.Errorf("%s option must be a bool: %w", name, invalidErr)
+	}
+
+	return nil
+}
+
+var (
+	// experimentalPublicKeyFormats are the verification method types that can only be requested with the
+	// EnableExperimentalPublicKeyTypes option.
+	experimentalPublicKeyFormats = map[string]bool{
+		ed25519VerificationKey2018:        true,
+		x25519KeyAgreementKey2019:         true,
+		x25519KeyAgreementKey2020:         true,
+		bls12381G1Key2020:                 true,
+		bls12381G2Key2020:                 true,
+		ecdsaSecp256k1VerificationKey2019: true,
+	}
+
+	// suiteContexts are the JSON-LD contexts of the verification method types, added to the DID document when its
+	// representation is requested with the PublicKeyFormat option.
+	suiteContexts = map[string]string{
+		multikey:                          schemaMultikeyV1,
+		jsonWebKey2020:                    "https://w3id.org/security/suites/jws-2020/v1",
+		ed25519VerificationKey2018:        "https://w3id.org/security/suites/ed25519-2018/v1",
+		ed25519VerificationKey2020:        "https://w3id.org/security/suites/ed25519-2020/v1",
+		x25519KeyAgreementKey2019:         "https://w3id.org/security/suites/x25519-2019/v1",
+		x25519KeyAgreementKey2020:         "https://w3id.org/security/suites/x25519-2020/v1",
+		bls12381G1Key2020:                 "https://w3id.org/security/suites/bls12381-2020/v1",
+		bls12381G2Key2020:                 "https://w3id.org/security/suites/bls12381-2020/v1",
+		ecdsaSecp256k1VerificationKey2019: "https://w3id.org/security/suites/secp256k1-2019/v1",
+	}
+)
+
+func createDIDDocFromPubKey(kid string, code uint64, pubKeyBytes []byte, readOpts *resolutionOpts) (*did.Doc, error) {
+	keyType, keyAgreementType, err := verificationMethodTypes(code, readOpts.publicKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	keyAgreementOnly := code == fingerprint.X25519PubKeyMultiCodec
+
+	if code == jwkJCSPubMultiCodec {
+		j, e := jwkFromJCS(pubKeyBytes)
+		if e != nil {
+			return nil, e
+		}
+
+		keyAgreementOnly = j.Crv == x25519Crv
+	}
+
+	didKey := fmt.Sprintf("did:key:%s", kid) //nolint:perfsprint
+	keyID := fmt.Sprintf("%s#%s", didKey, kid)
+
+	publicKey, err := newVerificationMethod(keyID, keyType, didKey, kid, code, pubKeyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyAgreementOnly {
+		didDoc := createKeyAgreementDoc(publicKey, didKey)
+		didDoc.Context = documentContext(readOpts, keyType)
+
+		return didDoc, nil
+	}
+
+	// other keys than Ed25519 keys are listed as their own key agreement key.
+	keyAgr := publicKey
+	types := []string{keyType}
+
+	switch {
+	case readOpts.encryptionKey != nil:
+		keyAgr = readOpts.encryptionKey
+	case code != fingerprint.ED25519PubKeyMultiCodec:
+	case readOpts.deriveEncryptionKey:
+		keyAgr, err = keyAgreementFromEd25519(didKey, pubKeyBytes, keyAgreementType)
+		if err != nil {
+			return nil, fmt.Errorf("pub:key vdr Read: failed to fetch KeyAgreement: %w", err)
+		}
+
+		types = append(types, keyAgreementType)
+	default:
+		keyAgr = nil
+	}
+
+	didDoc := createDoc(publicKey, keyAgr, didKey)
+	didDoc.Context = documentContext(readOpts, types...)
+
+	return didDoc, nil
 }
 
-func createDIDDocFromPubKey(kid string, code uint64, pubKeyBytes []byte) (*did.Doc, error) {
+// verificationMethodTypes returns the verification method type of the key, and the type of the X25519 key agreement
+// key derived from an Ed25519 key, for the requested public key format.
+//
+//nolint:gocyclo
+func verificationMethodTypes(code uint64, format string) (string, string, error) {
+	var keyType string
+
 	switch code {
 	case fingerprint.ED25519PubKeyMultiCodec:
-		return createEd25519DIDDoc(kid, pubKeyBytes, ed25519VerificationKey2018)
+		keyType = ed25519VerificationKey2018
+	case fingerprint.X25519PubKeyMultiCodec:
+		keyType = x25519KeyAgreementKey2019
 	case fingerprint.BLS12381g2PubKeyMultiCodec, fingerprint.BLS12381g1g2PubKeyMultiCodec:
-		return createBase58DIDDoc(kid, bls12381G2Key2020, pubKeyBytes)
-	case fingerprint.P256PubKeyMultiCodec, fingerprint.P384PubKeyMultiCodec, fingerprint.P521PubKeyMultiCodec:
-		return createJSONWebKey2020DIDDoc(kid, code, pubKeyBytes)
+		keyType = bls12381G2Key2020
+	case bls12381g1PubKeyMultiCodec:
+		keyType = bls12381G1Key2020
+	case fingerprint.P256PubKeyMultiCodec, fingerprint.P384PubKeyMultiCodec, fingerprint.P521PubKeyMultiCodec,
+		secp256k1PubKeyMultiCodec, fingerprint.RSAPubKeyMultiCodec, jwkJCSPubMultiCodec:
+		keyType = jsonWebKey2020
+	default:
+		return "", "", fmt.Errorf("unsupported key multicodec code [0x%x]: %w", code, vdrapi.ErrInvalidPublicKeyType)
 	}
 
-	return nil, fmt.Errorf("unsupported key multicodec code [0x%x]", code)
+	isBLS := keyType == bls12381G1Key2020 || keyType == bls12381G2Key2020
+
+	switch {
+	case (format == "" || format == ed25519VerificationKey2018) && keyType == ed25519VerificationKey2018:
+		return keyType, x25519KeyAgreementKey2019, nil
+	case format == "", format == keyType:
+		return keyType, keyType, nil
+	case format == multikey:
+		return multikey, multikey, nil
+	case format == jsonWebKey2020 && !isBLS:
+		return jsonWebKey2020, jsonWebKey2020, nil
+	case format == ed25519VerificationKey2020 && code == fingerprint.ED25519PubKeyMultiCodec:
+		return ed25519VerificationKey2020, x25519KeyAgreementKey2020, nil
+	case format == ed25519VerificationKey2020 && code == fingerprint.X25519PubKeyMultiCodec,
+		format == x25519KeyAgreementKey2020 && code == fingerprint.X25519PubKeyMultiCodec:
+		return x25519KeyAgreementKey2020, x25519KeyAgreementKey2020, nil
+	case format == ecdsaSecp256k1VerificationKey2019 && code == secp256k1PubKeyMultiCodec:
+		return format, format, nil
+	}
+
+	return "", "", fmt.Errorf("publicKeyFormat [%s] does not apply to key multicodec code [0x%x]: %w", format, code,
+		vdrapi.ErrInvalidPublicKeyType)
 }
 
-func createBase58DIDDoc(kid, keyType string, pubKeyBytes []byte) (*did.Doc, error) {
-	didKey := fmt.Sprintf("did:key:%s", kid) //nolint:perfsprint
+// newVerificationMethod creates the verification method of the key with the given type.
+func newVerificationMethod(keyID, keyType, didKey, kid string, code uint64,
+	pubKeyBytes []byte) (*did.VerificationMethod, error) {
+	switch keyType {
+	case multikey:
+		// a Multikey value is the multicodec encoded key, its publicKeyMultibase being the method specific ID.
+		return did.NewVerificationMethodFromBytes(keyID, multikey, didKey, base58.Decode(kid[1:])), nil
+	case jsonWebKey2020:
+		j, err := jwkFromBytes(code, pubKeyBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		vm, err := did.NewVerificationMethodFromJWK(keyID, jsonWebKey2020, didKey, j)
+		if err != nil {
+			return nil, fmt.Errorf("error creating verification method %w", err)
+		}
+
+		return vm, nil
+	default:
+		return did.NewVerificationMethodFromBytes(keyID, keyType, didKey, pubKeyBytes), nil
+	}
+}
 
-	keyID := fmt.Sprintf("%s#%s", didKey, kid)
-	publicKey := did.NewVerificationMethodFromBytes(keyID, keyType, didKey, pubKeyBytes)
+// documentContext returns the DID document context, with the contexts of the requested verification method types.
+func documentContext(readOpts *resolutionOpts, types ...string) []string {
+	context := []string{readOpts.defaultContext}
 
-	didDoc := createDoc(publicKey, publicKey, didKey)
+	if readOpts.publicKeyFormat == "" {
+		return context
+	}
 
-	return didDoc, nil
+	for _, t := range types {
+		if c, ok := suiteContexts[t]; ok && !slices.Contains(context, c) {
+			context = append(context, c)
+		}
+	}
+
+	return context
 }
 
-func createJSONWebKey2020DIDDoc(kid string, code uint64, pubKeyBytes []byte) (*did.Doc, error) {
-	didKey := fmt.Sprintf("did:key:%s", kid) //nolint:perfsprint
+// jwkFromBytes creates the JWK of a did:key key.
+func jwkFromBytes(code uint64, pubKeyBytes []byte) (*jwk.JWK, error) {
+	var (
+		j   *jwk.JWK
+		err error
+	)
 
-	keyID := fmt.Sprintf("%s#%s", didKey, kid)
+	switch code {
+	case fingerprint.ED25519PubKeyMultiCodec:
+		j, err = jwksupport.JWKFromKey(ed25519.PublicKey(pubKeyBytes))
+	case fingerprint.X25519PubKeyMultiCodec:
+		j, err = jwksupport.JWKFromX25519Key(pubKeyBytes)
+	case jwkJCSPubMultiCodec:
+		j, err = jwkFromJCS(pubKeyBytes)
+	default:
+		publicKey, e := publicKeyFromBytes(code, pubKeyBytes)
+		if e != nil {
+			return nil, e
+		}
+
+		j, err = jwksupport.JWKFromKey(publicKey)
+	}
 
+	if err != nil {
+		return nil, fmt.Errorf("error creating JWK %w", err)
+	}
+
+	return j, nil
+}
+
+// publicKeyFromBytes parses the did:key bytes of an EC or RSA key.
+func publicKeyFromBytes(code uint64, pubKeyBytes []byte) (interface{}, error) {
 	var curve elliptic.Curve
 
 	switch code {
@@ -86,6 +384,20 @@ func createJSONWebKey2020DIDDoc(kid string, code uint64, pubKeyBytes []byte) (*d
 		curve = elliptic.P384()
 	case fingerprint.P521PubKeyMultiCodec:
 		curve = elliptic.P521()
+	case secp256k1PubKeyMultiCodec:
+		publicKey, err := btcec.ParsePubKey(pubKeyBytes)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshalling key bytes: %w", err)
+		}
+
+		return publicKey.ToECDSA(), nil
+	case fingerprint.RSAPubKeyMultiCodec:
+		publicKey, err := x509.ParsePKCS1PublicKey(pubKeyBytes)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshalling key bytes: %w", err)
+		}
+
+		return publicKey, nil
 	default:
 		return nil, fmt.Errorf("unsupported key multicodec code for JsonWebKey2020 [0x%x]", code)
 	}
@@ -95,45 +407,47 @@ func createJSONWebKey2020DIDDoc(kid string, code uint64, pubKeyBytes []byte) (*d
 		return nil, errors.New("error unmarshalling key bytes")
 	}
 
-	publicKey := ecdsa.PublicKey{
+	return &ecdsa.PublicKey{
 		Curve: curve,
 		X:     x,
 		Y:     y,
-	}
+	}, nil
+}
 
-	j, err := jwksupport.JWKFromKey(&publicKey)
-	if err != nil {
-		return nil, fmt.Errorf("error creating JWK %w", err)
-	}
+// privateJWKMembers are the JWK members holding private key material (RFC 7518 section 6).
+var privateJWKMembers = []string{"d", "p", "q", "dp", "dq", "qi", "oth", "k"}
 
-	vm, err := did.NewVerificationMethodFromJWK(keyID, jsonWebKey2020, didKey, j)
+// jwkFromJCS parses the key of a jwk_jcs-pub did:key, which must be the JCS canonical form of a public JWK.
+func jwkFromJCS(jcs []byte) (*jwk.JWK, error) {
+	canonical, err := canonicalizer.MarshalCanonical(jcs)
 	if err != nil {
-		return nil, fmt.Errorf("error creating verification method %w", err)
+		return nil, fmt.Errorf("invalid jwk_jcs-pub key: %w: %w", err, vdrapi.ErrInvalidDID)
 	}
 
-	didDoc := createDoc(vm, vm, didKey)
+	if !bytes.Equal(canonical, jcs) {
+		return nil, fmt.Errorf("jwk_jcs-pub key is not in JCS canonical form: %w", vdrapi.ErrInvalidDID)
+	}
 
-	return didDoc, nil
-}
+	var members map[string]interface{}
 
-func createEd25519DIDDoc(kid string, pubKeyBytes []byte, verificationKeyType string) (*did.Doc, error) {
-	didKey := fmt.Sprintf("did:key:%s", kid) //nolint:perfsprint
+	if err = json.Unmarshal(jcs, &members); err != nil {
+		return nil, fmt.Errorf("invalid jwk_jcs-pub key: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
 
-	// did:key can't add non converted encryption key as keyAgreement (unless it's added as an option just like creator,
-	// it can be added and read here if needed. Below TODO is a reminder for this)
-	// TODO find a way to get the Encryption key as in creator.go
-	// for now keeping original ed25519 to X25519 key conversion as keyAgreement.
-	keyAgr, err := keyAgreementFromEd25519(didKey, pubKeyBytes, verificationKeyType)
-	if err != nil {
-		return nil, fmt.Errorf("pub:key vdr Read: failed to fetch KeyAgreement: %w", err)
+	for _, member := range privateJWKMembers {
+		if _, ok := members[member]; ok {
+			return nil, fmt.Errorf("jwk_jcs-pub key must not contain private member [%s]: %w", member,
+				vdrapi.ErrInvalidDID)
+		}
 	}
 
-	keyID := fmt.Sprintf("%s#%s", didKey, kid)
-	publicKey := did.NewVerificationMethodFromBytes(keyID, verificationKeyType, didKey, pubKeyBytes)
+	j := &jwk.JWK{}
 
-	didDoc := createDoc(publicKey, keyAgr, didKey)
+	if err = j.UnmarshalJSON(jcs); err != nil {
+		return nil, fmt.Errorf("unsupported jwk_jcs-pub key: %w: %w", err, vdrapi.ErrInvalidPublicKeyType)
+	}
 
-	return didDoc, nil
+	return j, nil
 }
 
 func isValidMethodID(id string) bool {
@@ -7,14 +7,21 @@ SPDX-License-Identifier: Apache-2.0
 package key
 
 import (
+	"context"
+	"crypto/ecdsa"
 	"crypto/elliptic"
 	"encoding/base64"
 	"math/big"
+	"strings"
 	"testing"
 
+	"github.com/btcsuite/btcutil/base58"
 	"github.com/stretchr/testify/require"
 
 	"github.com/trustbloc/kms-go/doc/util/fingerprint"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
 )
 
 func TestReadInvalid(t *testing.T) {
@@ -24,15 +31,16 @@ func TestReadInvalid(t *testing.T) {
 		doc, err := v.Read("did:key:invalid")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "invalid did:key method ID: invalid")
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
 		require.Nil(t, doc)
 	})
 
 	t.Run("validate not supported public key", func(t *testing.T) {
 		v := New()
 
-		doc, err := v.Read("did:key:z6LSbysY2xFMRpGMhb7tFTLMpeuPRaqaWM1yECx2AtzE3KCc")
+		doc, err := v.Read("did:key:z6GPAnQrkvNq5vdxNd8AHWAWbQ9xUAMo2mN5oz3kskg9EC2zXYoBn26k39nUEmku425vdXKCcqdgVj4tis")
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "unsupported key multicodec code [0xec]") // Curve25519 public key
+		require.Contains(t, err.Error(), "unsupported key multicodec code [0x1203]") // Ed448 public key
 		require.Nil(t, doc)
 	})
 
@@ -53,6 +61,17 @@ func TestReadInvalid(t *testing.T) {
 		require.Contains(t, err.Error(), "invalid did:key method: invalid")
 		require.Nil(t, doc)
 	})
+
+	t.Run("validate cancelled context", func(t *testing.T) {
+		v := New()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		doc, err := v.ReadContext(ctx, "did:key:z6MkpTHR8VNsBxYAAWHut2Geadd9jSwuBV8xRoAnwWsdvktH")
+		require.ErrorIs(t, err, context.Canceled)
+		require.Nil(t, doc)
+	})
 }
 
 func TestReadEd25519(t *testing.T) {
@@ -67,6 +86,7 @@ func TestReadEd25519(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, docResolution.DIDDocument)
 		require.True(t, docResolution.DIDDocument.KeyAgreement[0].Embedded)
+		require.Equal(t, did.ContentTypeDIDLDJSON, docResolution.ResolutionMetadata.ContentType)
 
 		assertEd25519Doc(t, docResolution.DIDDocument, ed25519VerificationKey2018, x25519KeyAgreementKey2019)
 	})
@@ -219,16 +239,354 @@ func TestRead521(t *testing.T) {
 	})
 }
 
+func TestReadSecp256k1(t *testing.T) {
+	// did key from the secp256k1 test vectors of https://w3c-ccg.github.io/did-method-key/
+	const (
+		k1    = "did:key:zQ3shokFTS3brHcDQrn82RUDfCZESWL1ZdCEJwekUDPQiYBme"
+		k1KID = "did:key:zQ3shokFTS3brHcDQrn82RUDfCZESWL1ZdCEJwekUDPQiYBme#zQ3shokFTS3brHcDQrn82RUDfCZESWL1ZdCEJwekUDPQiYBme" //nolint:lll
+		k1X   = "h0wVx_2iDlOcblulc8E5iEw1EYh5n1RYtLQfeSTyNc0"
+		k1Y   = "O2EATIGbu6DezKFptj5scAIRntgfecanVNXxat1rnwE"
+	)
+
+	docResolution, err := New().Read(k1)
+	require.NoError(t, err)
+
+	vm := docResolution.DIDDocument.VerificationMethod[0]
+	require.Equal(t, k1KID, vm.ID)
+	require.Equal(t, jsonWebKey2020, vm.Type)
+	require.Equal(t, "EC", vm.JSONWebKey().Kty)
+	require.Equal(t, secp256k1Crv, vm.JSONWebKey().Crv)
+
+	key, ok := vm.JSONWebKey().Key.(*ecdsa.PublicKey)
+	require.True(t, ok)
+	require.Equal(t, readBigInt(t, k1X), key.X)
+	require.Equal(t, readBigInt(t, k1Y), key.Y)
+
+	require.Len(t, docResolution.DIDDocument.AssertionMethod, 1)
+	require.Len(t, docResolution.DIDDocument.Authentication, 1)
+}
+
+func TestReadX25519(t *testing.T) {
+	// did key from the x25519 test vectors of https://w3c-ccg.github.io/did-method-key/
+	const (
+		k1       = "did:key:z6LSeu9HkTHSfLLeUs2nnzUSNedgDUevfNQgQjQC23ZCit6F"
+		k1KID    = "did:key:z6LSeu9HkTHSfLLeUs2nnzUSNedgDUevfNQgQjQC23ZCit6F#z6LSeu9HkTHSfLLeUs2nnzUSNedgDUevfNQgQjQC23ZCit6F" //nolint:lll
+		k1Base58 = "4Dy8E9UaZscuPUf2GLxV44RCNL7oxmEXXkgWXaug1WKV"
+	)
+
+	for format, keyType := range map[string]string{
+		"":                         x25519KeyAgreementKey2019,
+		ed25519VerificationKey2020: x25519KeyAgreementKey2020,
+	} {
+		docResolution, err := New().Read(k1, vdrapi.WithOption(PublicKeyFormat, format))
+		require.NoError(t, err)
+
+		doc := docResolution.DIDDocument
+		require.Len(t, doc.VerificationMethod, 1)
+		assertPubKey(t, &did.VerificationMethod{ID: k1KID, Type: keyType, Controller: k1, Value: base58.Decode(k1Base58)},
+			&doc.VerificationMethod[0])
+
+		// an X25519 key can only be used for key agreement.
+		require.Len(t, doc.KeyAgreement, 1)
+		require.False(t, doc.KeyAgreement[0].Embedded)
+		require.Equal(t, k1KID, doc.KeyAgreement[0].VerificationMethod.ID)
+		require.Empty(t, doc.Authentication)
+		require.Empty(t, doc.AssertionMethod)
+		require.Empty(t, doc.CapabilityDelegation)
+		require.Empty(t, doc.CapabilityInvocation)
+	}
+}
+
+func TestReadRSA(t *testing.T) {
+	// RSA 2048-bit test vector of the did:key spec.
+	const (
+		didKey = "did:key:z4MXj1wBzi9jUstyPMS4jQqB6KdJaiatPkAtVtGc6bQEQEEsKTic4G7Rou3iBf9vPmT5dbkm9qsZsuVNjq8HCuW1w24nhBFGkRE4cd2Uf2tfrB3N7h4mnyPp1BF3ZttHTYv3DLUPi1zMdkULiow3M1GfXkoC6DoxDUm1jmN6GBj22SjVsr6dxezRVQc7aj9TxE7JLbMH1wh5X3kA58H3DFW8rnYMakFGbca5CB2Jf6CnGQZmL7o5uJAdTwXfy2iiiyPxXEGerMhHwhjTA1mKYobyk2CpeEcmvynADfNZ5MBvcCS7m3XkFCMNUYBS9NQ3fze6vMSUPsNa6GVYmKx2x6JrdEjCk3qRMMmyjnjCMfR4pXbRMZa3i" //nolint:lll
+		n      = "sbX82NTV6IylxCh7MfV4hlyvaniCajuP97GyOqSvTmoEdBOflFvZ06kR_9D6ctt45Fk6hskfnag2GG69NALVH2o4RCR6tQiLRpKcMRtDYE_thEmfBvDzm_VVkOIYfxu-Ipuo9J_S5XDNDjczx2v-3oDh5-CIHkU46hvFeCvpUS-L8TJSbgX0kjVk_m4eIb9wh63rtmD6Uz_KBtCo5mmR4TEtcLZKYdqMp3wCjN-TlgHiz_4oVXWbHUefCEe8rFnX1iQnpDHU49_SaXQoud1jCaexFn25n-Aa8f8bc5Vm-5SeRwidHa6ErvEhTvf1dz6GoNPp2iRvm-wJ1gxwWJEYPQ"                                        //nolint:lll
+	)
+
+	docResolution, err := New().Read(didKey)
+	require.NoError(t, err)
+
+	vm := docResolution.DIDDocument.VerificationMethod[0]
+	require.Equal(t, didKey+"#"+strings.TrimPrefix(didKey, "did:key:"), vm.ID)
+	require.Equal(t, jsonWebKey2020, vm.Type)
+
+	jwkBytes, err := vm.JSONWebKey().MarshalJSON()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"kty":"RSA","n":"`+n+`","e":"AQAB"}`, string(jwkBytes))
+}
+
+func TestReadJWKJCSPub(t *testing.T) {
+	const (
+		p256JWK   = `{"crv":"P-256","kty":"EC","x":"ngy44T1vxAT6Di4nr-UaM9K3Tlnz9pkoksDokKFkmNc","y":"QCRfOKlSM31GTkb4JHx3nXB4G_jSPMsbdjzlkT_UpPc"}` //nolint:lll
+		x25519JWK = `{"crv":"X25519","kty":"OKP","x":"3p7bfXt9wbTTW2HC7OQ1Nz-DQ8hbeGdNrfx-FG-IK08"}`
+	)
+
+	t.Run("P-256 key", func(t *testing.T) {
+		didKey, keyID := fingerprint.CreateDIDKeyByCode(jwkJCSPubMultiCodec, []byte(p256JWK))
+
+		docResolution, err := New().Read(didKey)
+		require.NoError(t, err)
+
+		doc := docResolution.DIDDocument
+		require.Equal(t, didKey, doc.ID)
+		require.Equal(t, keyID, doc.VerificationMethod[0].ID)
+		require.Equal(t, jsonWebKey2020, doc.VerificationMethod[0].Type)
+		require.Equal(t, "P-256", doc.VerificationMethod[0].JSONWebKey().Crv)
+		require.Len(t, doc.Authentication, 1)
+		require.Len(t, doc.AssertionMethod, 1)
+
+		docResolution, err = New().Read(didKey, vdrapi.WithOption(PublicKeyFormat, multikey))
+		require.NoError(t, err)
+		require.Equal(t, multikey, docResolution.DIDDocument.VerificationMethod[0].Type)
+		require.Equal(t, base58.Decode(strings.TrimPrefix(didKey, "did:key:z")),
+			docResolution.DIDDocument.VerificationMethod[0].Value)
+	})
+
+	t.Run("X25519 key", func(t *testing.T) {
+		didKey, _ := fingerprint.CreateDIDKeyByCode(jwkJCSPubMultiCodec, []byte(x25519JWK))
+
+		docResolution, err := New().Read(didKey)
+		require.NoError(t, err)
+
+		doc := docResolution.DIDDocument
+		require.Equal(t, "X25519", doc.VerificationMethod[0].JSONWebKey().Crv)
+		require.Len(t, doc.KeyAgreement, 1)
+		require.Empty(t, doc.Authentication)
+		require.Empty(t, doc.AssertionMethod)
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		for _, key := range []string{
+			`{"crv":"P-256", "kty":"EC","x":"ngy44T1vxAT6Di4nr-UaM9K3Tlnz9pkoksDokKFkmNc","y":"QCRfOKlSM31GTkb4JHx3nXB4G_jSPMsbdjzlkT_UpPc"}`,   //nolint:lll
+			`{"kty":"EC","crv":"P-256","x":"ngy44T1vxAT6Di4nr-UaM9K3Tlnz9pkoksDokKFkmNc","y":"QCRfOKlSM31GTkb4JHx3nXB4G_jSPMsbdjzlkT_UpPc"}`,    //nolint:lll
+			`{"crv":"Ed25519","d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A","kty":"OKP","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`, //nolint:lll
+			`not a jwk, but long enough to be a did:key method specific id`,
+		} {
+			didKey, _ := fingerprint.CreateDIDKeyByCode(jwkJCSPubMultiCodec, []byte(key))
+
+			_, err := New().Read(didKey)
+			require.ErrorIs(t, err, vdrapi.ErrInvalidDID, key)
+		}
+
+		didKey, _ := fingerprint.CreateDIDKeyByCode(jwkJCSPubMultiCodec, []byte(`{"crv":"Ed448","kty":"OKP","x":"AAAA"}`))
+
+		_, err := New().Read(didKey)
+		require.ErrorIs(t, err, vdrapi.ErrInvalidPublicKeyType)
+
+		didKey, _ = fingerprint.CreateDIDKeyByCode(jwkJCSPubMultiCodec, []byte(p256JWK))
+
+		_, err = New().Read(didKey, vdrapi.WithOption(PublicKeyFormat, ed25519VerificationKey2020))
+		require.ErrorIs(t, err, vdrapi.ErrInvalidPublicKeyType)
+	})
+}
+
+func TestReadBLS12381G1(t *testing.T) {
+	// BLS12-381 G1 test vector of the did:key spec.
+	const (
+		didKey       = "did:key:z3tEFALUKUzzCAvytMHX8X4SnsNsq6T5tC5Zb18oQEt1FqNcJXqJ3AA9umgzA9yoqPBeWA"
+		pubKeyBase58 = "6FywSzB5BPd7xehCo1G4nYHAoZPMMP3gd4PLnvgA6SsTsogtz8K7RDznqLpFPLZXAE"
+	)
+
+	docResolution, err := New().Read(didKey)
+	require.NoError(t, err)
+
+	assertBase58Doc(t, docResolution.DIDDocument, didKey, didKey+"#"+strings.TrimPrefix(didKey, "did:key:"),
+		bls12381G1Key2020, pubKeyBase58)
+}
+
+func TestReadPublicKeyFormat(t *testing.T) {
+	const (
+		didEd25519 = "did:key:z6MkpTHR8VNsBxYAAWHut2Geadd9jSwuBV8xRoAnwWsdvktH"
+		didP256    = "did:key:zDnaerDaTF5BXEavCrfRZEk316dpbLsfPDZ3WJ5hRTPFU2169"
+		didX25519  = "did:key:z6LSeu9HkTHSfLLeUs2nnzUSNedgDUevfNQgQjQC23ZCit6F"
+		didK256    = "did:key:zQ3shokFTS3brHcDQrn82RUDfCZESWL1ZdCEJwekUDPQiYBme"
+	)
+
+	t.Run("Ed25519VerificationKey2020", func(t *testing.T) {
+		docResolution, err := New().Read(didEd25519,
+			vdrapi.WithOption(PublicKeyFormat, ed25519VerificationKey2020))
+		require.NoError(t, err)
+
+		assertEd25519Doc(t, docResolution.DIDDocument, ed25519VerificationKey2020, x25519KeyAgreementKey2020)
+	})
+
+	t.Run("Multikey", func(t *testing.T) {
+		for _, didKey := range []string{didEd25519, didP256, didX25519, didK256} {
+			docResolution, err := New().Read(didKey, vdrapi.WithOption(PublicKeyFormat, multikey))
+			require.NoError(t, err)
+
+			doc := docResolution.DIDDocument
+			require.Equal(t, []string{schemaDIDV1, schemaMultikeyV1}, doc.Context)
+			require.Equal(t, multikey, doc.VerificationMethod[0].Type)
+
+			docBytes, err := doc.JSONBytes()
+			require.NoError(t, err)
+
+			methodID := strings.TrimPrefix(didKey, "did:key:")
+			require.Contains(t, string(docBytes), `"publicKeyMultibase":"`+methodID+`"`)
+		}
+
+		docResolution, err := New().Read(didEd25519, vdrapi.WithOption(PublicKeyFormat, multikey))
+		require.NoError(t, err)
+
+		keyAgreement := docResolution.DIDDocument.KeyAgreement[0].VerificationMethod
+		require.Equal(t, didEd25519+"#z6LSbysY2xFMRpGMhb7tFTLMpeuPRaqaWM1yECx2AtzE3KCc", keyAgreement.ID)
+		require.Equal(t, multikey, keyAgreement.Type)
+		require.Equal(t, base58.Decode("z6LSbysY2xFMRpGMhb7tFTLMpeuPRaqaWM1yECx2AtzE3KCc"[1:]), keyAgreement.Value)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		_, err := New().Read(didEd25519, vdrapi.WithOption(PublicKeyFormat, "PublicKeyPem"))
+		require.ErrorContains(t, err, "unsupported publicKeyFormat [PublicKeyPem]")
+
+		_, err = New().Read(didEd25519, vdrapi.WithOption(PublicKeyFormat, 1))
+		require.ErrorContains(t, err, "publicKeyFormat option must be a string")
+		require.ErrorIs(t, err, vdrapi.ErrInvalidPublicKeyType)
+	})
+}
+
+func TestReadResolutionOptions(t *testing.T) {
+	const (
+		didEd25519 = "did:key:z6MkpTHR8VNsBxYAAWHut2Geadd9jSwuBV8xRoAnwWsdvktH"
+		didBLS     = "did:key:zUC7K4ndUaGZgV7Cp2yJy6JtMoUHY6u7tkcSYUvPrEidqBmLCTLmi6d5WvwnUqejscAkERJ3bfjEiSYtdPkRSE8kSa11hFBr4sTgnbZ95SJj19PN2jdvJjyzpSZgxkyyxNnBNnY" //nolint:lll
+		didP256    = "did:key:zDnaerDaTF5BXEavCrfRZEk316dpbLsfPDZ3WJ5hRTPFU2169"
+		didX25519  = "did:key:z6LSeu9HkTHSfLLeUs2nnzUSNedgDUevfNQgQjQC23ZCit6F"
+		didK256    = "did:key:zQ3shokFTS3brHcDQrn82RUDfCZESWL1ZdCEJwekUDPQiYBme"
+	)
+
+	t.Run("JsonWebKey2020", func(t *testing.T) {
+		docResolution, err := New().Read(didEd25519, vdrapi.WithOption(PublicKeyFormat, jsonWebKey2020))
+		require.NoError(t, err)
+
+		doc := docResolution.DIDDocument
+		require.Equal(t, []string{schemaDIDV1, "https://w3id.org/security/suites/jws-2020/v1"}, doc.Context)
+		require.Equal(t, jsonWebKey2020, doc.VerificationMethod[0].Type)
+		require.Equal(t, "Ed25519", doc.VerificationMethod[0].JSONWebKey().Crv)
+		require.Equal(t, jsonWebKey2020, doc.KeyAgreement[0].VerificationMethod.Type)
+		require.Equal(t, "X25519", doc.KeyAgreement[0].VerificationMethod.JSONWebKey().Crv)
+
+		docResolution, err = New().Read(didX25519, vdrapi.WithOption(PublicKeyFormat, jsonWebKey2020))
+		require.NoError(t, err)
+		require.Equal(t, "X25519", docResolution.DIDDocument.VerificationMethod[0].JSONWebKey().Crv)
+
+		_, err = New().Read(didBLS, vdrapi.WithOption(PublicKeyFormat, jsonWebKey2020))
+		require.ErrorIs(t, err, vdrapi.ErrInvalidPublicKeyType)
+	})
+
+	t.Run("experimental public key types", func(t *testing.T) {
+		for didKey, format := range map[string]string{
+			didEd25519: ed25519VerificationKey2018,
+			didX25519:  x25519KeyAgreementKey2020,
+			didBLS:     bls12381G2Key2020,
+			didK256:    ecdsaSecp256k1VerificationKey2019,
+		} {
+			_, err := New().Read(didKey, vdrapi.WithOption(PublicKeyFormat, format))
+			require.ErrorIs(t, err, vdrapi.ErrInvalidPublicKeyType)
+			require.ErrorContains(t, err, "requires the enableExperimentalPublicKeyTypes option")
+
+			docResolution, err := New().Read(didKey, vdrapi.WithOption(PublicKeyFormat, format),
+				vdrapi.WithOption(EnableExperimentalPublicKeyTypes, true))
+			require.NoError(t, err)
+			require.Equal(t, format, docResolution.DIDDocument.VerificationMethod[0].Type)
+
+			if didKey != didEd25519 {
+				require.Len(t, docResolution.DIDDocument.Context, 2)
+			}
+		}
+
+		docResolution, err := New().Read(didEd25519, vdrapi.WithOption(PublicKeyFormat, ed25519VerificationKey2018),
+			vdrapi.WithOption(EnableExperimentalPublicKeyTypes, true))
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			schemaDIDV1,
+			"https://w3id.org/security/suites/ed25519-2018/v1",
+			"https://w3id.org/security/suites/x25519-2019/v1",
+		}, docResolution.DIDDocument.Context)
+		assertEd25519Doc(t, docResolution.DIDDocument, ed25519VerificationKey2018, x25519KeyAgreementKey2019)
+	})
+
+	t.Run("public key format not matching the key", func(t *testing.T) {
+		for didKey, format := range map[string]string{
+			didP256:    ed25519VerificationKey2020,
+			didEd25519: bls12381G2Key2020,
+			didK256:    x25519KeyAgreementKey2019,
+		} {
+			_, err := New().Read(didKey, vdrapi.WithOption(PublicKeyFormat, format),
+				vdrapi.WithOption(EnableExperimentalPublicKeyTypes, true))
+			require.ErrorIs(t, err, vdrapi.ErrInvalidPublicKeyType)
+			require.ErrorContains(t, err, "does not apply to key multicodec code")
+		}
+
+		_, err := New().Read(didEd25519, vdrapi.WithOption(PublicKeyFormat, "PublicKeyPem"))
+		require.ErrorIs(t, err, vdrapi.ErrInvalidPublicKeyType)
+		require.Equal(t, did.ResolutionErrorInvalidPublicKeyType, vdrapi.ErrorCode(err))
+	})
+
+	t.Run("default context", func(t *testing.T) {
+		docResolution, err := New().Read(didEd25519, vdrapi.WithOption(DefaultContext, did.ContextV1))
+		require.NoError(t, err)
+		require.Equal(t, []string{did.ContextV1}, docResolution.DIDDocument.Context)
+
+		docResolution, err = New().Read(didEd25519, vdrapi.WithOption(DefaultContext, did.ContextV1),
+			vdrapi.WithOption(PublicKeyFormat, ed25519VerificationKey2020))
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			did.ContextV1,
+			"https://w3id.org/security/suites/ed25519-2020/v1",
+			"https://w3id.org/security/suites/x25519-2020/v1",
+		}, docResolution.DIDDocument.Context)
+	})
+
+	t.Run("encryption key derivation", func(t *testing.T) {
+		docResolution, err := New().Read(didEd25519, vdrapi.WithOption(EnableEncryptionKeyDerivation, false))
+		require.NoError(t, err)
+		require.Empty(t, docResolution.DIDDocument.KeyAgreement)
+		require.Len(t, docResolution.DIDDocument.VerificationMethod, 1)
+
+		docResolution, err = New().Read(didEd25519, vdrapi.WithOption(EnableEncryptionKeyDerivation, false),
+			vdrapi.WithOption(PublicKeyFormat, multikey))
+		require.NoError(t, err)
+		require.Equal(t, []string{schemaDIDV1, schemaMultikeyV1}, docResolution.DIDDocument.Context)
+		require.Empty(t, docResolution.DIDDocument.KeyAgreement)
+
+		encryptionKey := did.NewVerificationMethodFromBytes(didEd25519+"#enc", x25519KeyAgreementKey2019, didEd25519,
+			[]byte{1, 2, 3})
+
+		docResolution, err = New().Read(didEd25519, vdrapi.WithOption(EncryptionKey, encryptionKey))
+		require.NoError(t, err)
+		require.Equal(t, didEd25519+"#enc", docResolution.DIDDocument.KeyAgreement[0].VerificationMethod.ID)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		for name, value := range map[string]interface{}{
+			EnableExperimentalPublicKeyTypes: "true",
+			DefaultContext:                   []string{did.ContextV1},
+			EnableEncryptionKeyDerivation:    1,
+			EncryptionKey:                    "key",
+		} {
+			_, err := New().Read(didEd25519, vdrapi.WithOption(name, value))
+			require.ErrorContains(t, err, name, name)
+			require.ErrorIs(t, err, vdrapi.ErrInvalidDID, name)
+			require.Equal(t, did.ResolutionErrorInvalidDID, vdrapi.ErrorCode(err), name)
+		}
+	})
+}
+
 func TestCreateJsonWeKey(t *testing.T) {
 	t.Run("test invalid code", func(t *testing.T) {
-		_, err := createJSONWebKey2020DIDDoc("123", 0, []byte{})
+		_, err := jwkFromBytes(0, []byte{})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "unsupported key multicodec code for JsonWebKey2020")
 	})
 
 	t.Run("test invalid key bytes", func(t *testing.T) {
-		_, err := createJSONWebKey2020DIDDoc("123", fingerprint.P256PubKeyMultiCodec, []byte{0x01})
-		require.Error(t, err)
-		require.Contains(t, err.Error(), "error unmarshalling key bytes")
+		for _, code := range []uint64{
+			fingerprint.P256PubKeyMultiCodec, secp256k1PubKeyMultiCodec, fingerprint.RSAPubKeyMultiCodec,
+		} {
+			_, err := jwkFromBytes(code, []byte{0x01})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "error unmarshalling key bytes")
+		}
 	})
 }
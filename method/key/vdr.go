@@ -20,6 +20,22 @@ const (
 	EncryptionKey = "encryptionKey"
 	// KeyType option to create a new kms key for DIDDocs with empty VerificationMethod.
 	KeyType = "keyType"
+	// PublicKeyFormat option to choose the verification method type on Read: "Multikey" for every key type,
+	// "JsonWebKey2020" for every key type but BLS12-381 keys, or "Ed25519VerificationKey2020" for Ed25519 and X25519
+	// keys instead of the 2018 and 2019 types. Other types require the EnableExperimentalPublicKeyTypes option.
+	PublicKeyFormat = "publicKeyFormat"
+	// EnableExperimentalPublicKeyTypes option allows the PublicKeyFormat option to request the
+	// Ed25519VerificationKey2018, X25519KeyAgreementKey2019, X25519KeyAgreementKey2020, Bls12381G1Key2020,
+	// Bls12381G2Key2020 and EcdsaSecp256k1VerificationKey2019 types on Read.
+	EnableExperimentalPublicKeyTypes = "enableExperimentalPublicKeyTypes"
+	// DefaultContext option overrides the first @context of the DID document on Read.
+	DefaultContext = "defaultContext"
+	// EnableEncryptionKeyDerivation option, true by default, derives the X25519 key agreement key of an Ed25519 key
+	// on Read.
+	EnableEncryptionKeyDerivation = "enableEncryptionKeyDerivation"
+	// JWKJCSPub option, when true, creates the did:key of a JsonWebKey2020 verification method with the jwk_jcs-pub
+	// multicodec, whose key is the JCS canonical form of the public JWK, as in EBSI natural person wallets.
+	JWKJCSPub = "jwkJCSPub"
 )
 
 // VDR implements did:key method support.
@@ -0,0 +1,258 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/trustbloc/kms-go/doc/util/fingerprint"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/doc/did/endpoint"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	ed25519VerificationKey2018 = "Ed25519VerificationKey2018"
+	x25519KeyAgreementKey2019  = "X25519KeyAgreementKey2019"
+)
+
+// Create creates a did:peer for didDoc. The numalgo is selected with NumAlgoOpt, NumAlgo2 is used by default.
+//
+// NumAlgo0 uses the first verification method of didDoc as inception key, NumAlgo2 encodes the verification
+// relationships and services of didDoc and NumAlgo4 encodes the whole didDoc. The returned document is the
+// resolution of the created DID, which is the long form for NumAlgo4.
+func (v *VDR) Create(didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	createOpts := &vdrapi.DIDMethodOpts{Values: make(map[string]interface{})}
+	// Apply options
+	for _, opt := range opts {
+		opt(createOpts)
+	}
+
+	numAlgo := NumAlgo2
+
+	if n, ok := createOpts.Values[NumAlgoOpt]; ok {
+		if numAlgo, ok = n.(int); !ok {
+			return nil, errors.New("numalgo option must be an int")
+		}
+	}
+
+	var (
+		didPeer string
+		err     error
+	)
+
+	switch numAlgo {
+	case NumAlgo0:
+		didPeer, err = createNumAlgo0(didDoc)
+	case NumAlgo2:
+		didPeer, err = createNumAlgo2(didDoc)
+	case NumAlgo4:
+		didPeer, err = createNumAlgo4(didDoc)
+	default:
+		return nil, fmt.Errorf("unsupported numalgo: %d", numAlgo)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("peer-vdr create: %w", err)
+	}
+
+	return v.Read(didPeer)
+}
+
+func createNumAlgo0(didDoc *did.Doc) (string, error) {
+	if len(didDoc.VerificationMethod) == 0 {
+		return "", errors.New("verification method is empty")
+	}
+
+	fp, err := keyFingerprint(&didDoc.VerificationMethod[0])
+	if err != nil {
+		return "", err
+	}
+
+	return "did:peer:0" + fp, nil
+}
+
+func createNumAlgo2(didDoc *did.Doc) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("did:peer:2")
+
+	relationships := []struct {
+		purpose       byte
+		verifications []did.Verification
+	}{
+		{purposeAssertion, didDoc.AssertionMethod},
+		{purposeEncryption, didDoc.KeyAgreement},
+		{purposeVerification, didDoc.Authentication},
+		{purposeCapabilityInvocation, didDoc.CapabilityInvocation},
+		{purposeCapabilityDelegation, didDoc.CapabilityDelegation},
+	}
+
+	keys := 0
+
+	for _, r := range relationships {
+		for i := range r.verifications {
+			fp, err := keyFingerprint(&r.verifications[i].VerificationMethod)
+			if err != nil {
+				return "", err
+			}
+
+			sb.WriteString("." + string(r.purpose) + fp)
+
+			keys++
+		}
+	}
+
+	if keys == 0 {
+		return "", errors.New("verification relationships are empty")
+	}
+
+	for i := range didDoc.Service {
+		encoded, err := encodeService(didDoc.ID, i, &didDoc.Service[i])
+		if err != nil {
+			return "", err
+		}
+
+		sb.WriteString("." + string(purposeService) + encoded)
+	}
+
+	return sb.String(), nil
+}
+
+func createNumAlgo4(didDoc *did.Doc) (string, error) {
+	docBytes, err := didDoc.JSONBytes()
+	if err != nil {
+		return "", fmt.Errorf("marshal input document: %w", err)
+	}
+
+	var rawDoc map[string]interface{}
+
+	if err = json.Unmarshal(docBytes, &rawDoc); err != nil {
+		return "", fmt.Errorf("unmarshal input document: %w", err)
+	}
+
+	delete(rawDoc, "id")
+
+	if didDoc.ID != "" {
+		rawDoc = relativize(rawDoc, didDoc.ID).(map[string]interface{}) //nolint:forcetypeassert
+	}
+
+	inputBytes, err := json.Marshal(rawDoc)
+	if err != nil {
+		return "", fmt.Errorf("marshal input document: %w", err)
+	}
+
+	encodedDoc := "z" + base58.Encode(append(binary.AppendUvarint(nil, jsonMultiCodec), inputBytes...))
+
+	return "did:peer:4" + numAlgo4Hash(encodedDoc) + ":" + encodedDoc, nil
+}
+
+// relativize turns DID URLs of the input document into relative references and drops the controllers set to the
+// input document DID, as the DID is not known before the did:peer is created.
+func relativize(value interface{}, didID string) interface{} {
+	switch val := value.(type) {
+	case map[string]interface{}:
+		if val["controller"] == didID {
+			delete(val, "controller")
+		}
+
+		for k, entry := range val {
+			val[k] = relativize(entry, didID)
+		}
+
+		return val
+	case []interface{}:
+		for i, entry := range val {
+			val[i] = relativize(entry, didID)
+		}
+
+		return val
+	case string:
+		if strings.HasPrefix(val, didID+"#") {
+			return strings.TrimPrefix(val, didID)
+		}
+
+		return val
+	default:
+		return val
+	}
+}
+
+func encodeService(didID string, index int, service *did.Service) (string, error) {
+	raw := map[string]interface{}{}
+
+	defaultID := "#service"
+	if index > 0 {
+		defaultID = fmt.Sprintf("#service-%d", index)
+	}
+
+	if id := strings.TrimPrefix(service.ID, didID); id != "" && id != defaultID {
+		raw["id"] = id
+	}
+
+	raw["t"] = service.Type
+	if service.Type == didCommV2Type {
+		raw["t"] = didCommV2TypeAbr
+	}
+
+	if service.ServiceEndpoint.Type() == endpoint.DIDCommV2 {
+		uri, err := service.ServiceEndpoint.URI()
+		if err != nil {
+			return "", fmt.Errorf("service endpoint: %w", err)
+		}
+
+		ep := map[string]interface{}{"uri": uri}
+
+		if accept, e := service.ServiceEndpoint.Accept(); e == nil && len(accept) > 0 {
+			ep["a"] = accept
+		}
+
+		if routingKeys, e := service.ServiceEndpoint.RoutingKeys(); e == nil && len(routingKeys) > 0 {
+			ep["r"] = routingKeys
+		}
+
+		raw["s"] = ep
+	} else {
+		raw["s"] = &service.ServiceEndpoint
+
+		if len(service.RoutingKeys) > 0 {
+			raw["r"] = service.RoutingKeys
+		}
+	}
+
+	serviceBytes, err := json.Marshal(raw)
+	if err != nil {
+		return "", fmt.Errorf("marshal service: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(serviceBytes), nil
+}
+
+func keyFingerprint(vm *did.VerificationMethod) (string, error) {
+	switch vm.Type {
+	case jsonWebKey2020:
+		didKey, _, err := fingerprint.CreateDIDKeyByJwk(vm.JSONWebKey())
+		if err != nil {
+			return "", fmt.Errorf("key fingerprint: %w", err)
+		}
+
+		return strings.TrimPrefix(didKey, "did:key:"), nil
+	case ed25519VerificationKey2018, ed25519VerificationKey2020:
+		return fingerprint.KeyFingerprint(fingerprint.ED25519PubKeyMultiCodec, vm.Value), nil
+	case x25519KeyAgreementKey2019, x25519KeyAgreementKey2020:
+		return fingerprint.KeyFingerprint(fingerprint.X25519PubKeyMultiCodec, vm.Value), nil
+	default:
+		return "", fmt.Errorf("not supported public key type: %s", vm.Type)
+	}
+}
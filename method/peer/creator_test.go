@@ -0,0 +1,178 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/doc/did/endpoint"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+func ed25519VM(t *testing.T, id string) *did.VerificationMethod {
+	t.Helper()
+
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return did.NewVerificationMethodFromBytes(id, ed25519VerificationKey2018, "", pubKey)
+}
+
+func TestCreate(t *testing.T) {
+	t.Run("test numalgo 0", func(t *testing.T) {
+		v := New()
+
+		vm := did.NewVerificationMethodFromBytes("#key-1", ed25519VerificationKey2018, "",
+			base58.Decode("B12NYF8RrR3h41TDCTJojY59usg3mbtbjnFs7Eud1Y6u"))
+
+		docResolution, err := v.Create(&did.Doc{VerificationMethod: []did.VerificationMethod{*vm}},
+			vdrapi.WithOption(NumAlgoOpt, NumAlgo0))
+		require.NoError(t, err)
+		require.Equal(t, "did:peer:0z6MkpTHR8VNsBxYAAWHut2Geadd9jSwuBV8xRoAnwWsdvktH", docResolution.DIDDocument.ID)
+	})
+
+	t.Run("test numalgo 0 jwk", func(t *testing.T) {
+		v := New()
+
+		key, err := ecdsaJWK(t)
+		require.NoError(t, err)
+
+		vm, err := did.NewVerificationMethodFromJWK("#key-1", jsonWebKey2020, "", key)
+		require.NoError(t, err)
+
+		docResolution, err := v.Create(&did.Doc{VerificationMethod: []did.VerificationMethod{*vm}},
+			vdrapi.WithOption(NumAlgoOpt, NumAlgo0))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(docResolution.DIDDocument.ID, "did:peer:0zDn"))
+	})
+
+	t.Run("test numalgo 2 round trip", func(t *testing.T) {
+		v := New()
+
+		auth := ed25519VM(t, "#key-1")
+		keyAgreement := did.NewVerificationMethodFromBytes("#key-2", x25519KeyAgreementKey2019, "",
+			base58.Decode("JhNWeSVLMYccCk7iopQW4guaSJTojqpMEELgSLhKwRr"))
+
+		docResolution, err := v.Create(&did.Doc{
+			Authentication: []did.Verification{*did.NewEmbeddedVerification(auth, did.Authentication)},
+			KeyAgreement:   []did.Verification{*did.NewEmbeddedVerification(keyAgreement, did.KeyAgreement)},
+			Service: []did.Service{
+				{
+					ID:   "#didcomm",
+					Type: didCommV2Type,
+					ServiceEndpoint: endpoint.NewDIDCommV2Endpoint([]endpoint.DIDCommV2Endpoint{{
+						URI:         "https://example.com/didcomm",
+						Accept:      []string{"didcomm/v2"},
+						RoutingKeys: []string{"did:example:mediator#key-1"},
+					}}),
+				},
+				{
+					Type:            "LinkedDomains",
+					ServiceEndpoint: endpoint.NewDIDCommV1Endpoint("https://example.com"),
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		doc := docResolution.DIDDocument
+		require.True(t, strings.HasPrefix(doc.ID, "did:peer:2.E"))
+		require.Equal(t, auth.Value, doc.Authentication[0].VerificationMethod.Value)
+		require.Equal(t, keyAgreement.Value, doc.KeyAgreement[0].VerificationMethod.Value)
+		require.Len(t, doc.Service, 2)
+		require.Equal(t, doc.ID+"#didcomm", doc.Service[0].ID)
+		require.Equal(t, doc.ID+"#service-1", doc.Service[1].ID)
+
+		routingKeys, err := doc.Service[0].ServiceEndpoint.RoutingKeys()
+		require.NoError(t, err)
+		require.Equal(t, []string{"did:example:mediator#key-1"}, routingKeys)
+
+		uri, err := doc.Service[1].ServiceEndpoint.URI()
+		require.NoError(t, err)
+		require.Equal(t, "https://example.com", uri)
+	})
+
+	t.Run("test numalgo 4 long and short form", func(t *testing.T) {
+		v := New()
+
+		vm := ed25519VM(t, "did:example:123#key-1")
+		vm.Controller = "did:example:123"
+
+		docResolution, err := v.Create(&did.Doc{
+			ID:                 "did:example:123",
+			Context:            []string{did.ContextV1},
+			VerificationMethod: []did.VerificationMethod{*vm},
+			Authentication:     []did.Verification{*did.NewReferencedVerification(vm, did.Authentication)},
+		}, vdrapi.WithOption(NumAlgoOpt, NumAlgo4))
+		require.NoError(t, err)
+
+		longForm := docResolution.DIDDocument.ID
+		require.True(t, strings.HasPrefix(longForm, "did:peer:4z"))
+
+		shortForm := longForm[:strings.LastIndex(longForm, ":")]
+		require.Equal(t, []string{shortForm}, docResolution.DIDDocument.AlsoKnownAs)
+		require.Equal(t, longForm+"#key-1", docResolution.DIDDocument.VerificationMethod[0].ID)
+		require.Equal(t, longForm, docResolution.DIDDocument.VerificationMethod[0].Controller)
+		require.Equal(t, vm.Value, docResolution.DIDDocument.VerificationMethod[0].Value)
+
+		docResolution, err = v.Read(shortForm)
+		require.NoError(t, err)
+		require.Equal(t, shortForm, docResolution.DIDDocument.ID)
+		require.Equal(t, []string{longForm}, docResolution.DIDDocument.AlsoKnownAs)
+		require.Equal(t, shortForm+"#key-1", docResolution.DIDDocument.Authentication[0].VerificationMethod.ID)
+
+		// short form is unknown to a VDR that has not seen the long form.
+		_, err = New().Read(shortForm)
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+
+		docResolution, err = New().Read(longForm)
+		require.NoError(t, err)
+		require.Equal(t, longForm, docResolution.DIDDocument.ID)
+	})
+
+	t.Run("test errors", func(t *testing.T) {
+		v := New()
+
+		_, err := v.Create(&did.Doc{}, vdrapi.WithOption(NumAlgoOpt, "2"))
+		require.EqualError(t, err, "numalgo option must be an int")
+
+		_, err = v.Create(&did.Doc{}, vdrapi.WithOption(NumAlgoOpt, 1))
+		require.EqualError(t, err, "unsupported numalgo: 1")
+
+		_, err = v.Create(&did.Doc{}, vdrapi.WithOption(NumAlgoOpt, NumAlgo0))
+		require.EqualError(t, err, "peer-vdr create: verification method is empty")
+
+		_, err = v.Create(&did.Doc{})
+		require.EqualError(t, err, "peer-vdr create: verification relationships are empty")
+
+		vm := did.NewVerificationMethodFromBytes("#key-1", "Bls12381G2Key2020", "", []byte("key"))
+
+		_, err = v.Create(&did.Doc{Authentication: []did.Verification{
+			*did.NewEmbeddedVerification(vm, did.Authentication),
+		}})
+		require.EqualError(t, err, "peer-vdr create: not supported public key type: Bls12381G2Key2020")
+	})
+}
+
+func ecdsaJWK(t *testing.T) (*jwk.JWK, error) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return jwksupport.JWKFromKey(&key.PublicKey)
+}
@@ -0,0 +1,508 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+	"github.com/trustbloc/kms-go/doc/util/fingerprint"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/doc/did/endpoint"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	schemaResV1      = "https://w3id.org/did-resolution/v1"
+	ed25519SuiteV1   = "https://w3id.org/security/suites/ed25519-2020/v1"
+	x25519SuiteV1    = "https://w3id.org/security/suites/x25519-2020/v1"
+	didCommV2Type    = "DIDCommMessaging"
+	didCommV2TypeAbr = "dm"
+
+	ed25519VerificationKey2020 = "Ed25519VerificationKey2020"
+	x25519KeyAgreementKey2020  = "X25519KeyAgreementKey2020"
+	jsonWebKey2020             = "JsonWebKey2020"
+
+	// multicodec json and multihash sha2-256 codes used by numalgo 4.
+	jsonMultiCodec   = 0x0200
+	sha256MultiHash  = 0x12
+	sha256DigestSize = 32
+)
+
+// numalgo 2 purpose codes.
+const (
+	purposeAssertion            = 'A'
+	purposeEncryption           = 'E'
+	purposeVerification         = 'V'
+	purposeCapabilityInvocation = 'I'
+	purposeCapabilityDelegation = 'D'
+	purposeService              = 'S'
+)
+
+// serviceAbbreviations maps numalgo 2 service abbreviations to their expanded form.
+var serviceAbbreviations = map[string]string{ //nolint:gochecknoglobals
+	"t": "type",
+	"s": "serviceEndpoint",
+	"r": "routingKeys",
+	"a": "accept",
+}
+
+// Read expands did:peer value to a DID document.
+func (v *VDR) Read(didPeer string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	return v.ReadContext(context.Background(), didPeer, opts...)
+}
+
+// ReadContext expands did:peer value to a DID document. Resolution is done offline, so the context
+// is only checked for cancellation.
+func (v *VDR) ReadContext(ctx context.Context, didPeer string,
+	_ ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	parsed, err := did.Parse(didPeer)
+	if err != nil {
+		return nil, fmt.Errorf("peer-vdr read: failed to parse DID: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	if parsed.Method != DIDMethod {
+		return nil, fmt.Errorf("peer-vdr read: invalid method: %s: %w", parsed.Method, vdrapi.ErrInvalidDID)
+	}
+
+	var doc *did.Doc
+
+	switch parsed.MethodSpecificID[0] {
+	case '0':
+		doc, err = resolveNumAlgo0(didPeer, parsed.MethodSpecificID[1:])
+	case '2':
+		doc, err = resolveNumAlgo2(didPeer, parsed.MethodSpecificID[1:])
+	case '4':
+		doc, err = v.resolveNumAlgo4(didPeer, parsed.MethodSpecificID[1:])
+	default:
+		return nil, fmt.Errorf("peer-vdr read: unsupported numalgo %c: %w", parsed.MethodSpecificID[0],
+			vdrapi.ErrInvalidDID)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("peer-vdr read: %w", err)
+	}
+
+	return &did.DocResolution{
+		Context:            []string{schemaResV1},
+		DIDDocument:        doc,
+		ResolutionMetadata: &did.ResolutionMetadata{ContentType: did.ContentTypeDIDLDJSON},
+	}, nil
+}
+
+func resolveNumAlgo0(didPeer, fp string) (*did.Doc, error) {
+	vm, code, err := verificationMethodFromFingerprint(didPeer+"#"+fp, didPeer, fp)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &did.Doc{
+		Context:            []string{did.ContextV1, ed25519SuiteV1, x25519SuiteV1},
+		ID:                 didPeer,
+		VerificationMethod: []did.VerificationMethod{*vm},
+	}
+
+	if code == fingerprint.X25519PubKeyMultiCodec {
+		doc.KeyAgreement = []did.Verification{*did.NewReferencedVerification(vm, did.KeyAgreement)}
+
+		return doc, nil
+	}
+
+	doc.Authentication = []did.Verification{*did.NewReferencedVerification(vm, did.Authentication)}
+	doc.AssertionMethod = []did.Verification{*did.NewReferencedVerification(vm, did.AssertionMethod)}
+	doc.CapabilityDelegation = []did.Verification{*did.NewReferencedVerification(vm, did.CapabilityDelegation)}
+	doc.CapabilityInvocation = []did.Verification{*did.NewReferencedVerification(vm, did.CapabilityInvocation)}
+
+	return doc, nil
+}
+
+func resolveNumAlgo2(didPeer, elements string) (*did.Doc, error) { //nolint:gocyclo
+	if !strings.HasPrefix(elements, ".") {
+		return nil, fmt.Errorf("invalid numalgo 2 did: %w", vdrapi.ErrInvalidDID)
+	}
+
+	doc := &did.Doc{
+		Context: []string{did.ContextV1, ed25519SuiteV1, x25519SuiteV1},
+		ID:      didPeer,
+	}
+
+	var rawServices []map[string]interface{}
+
+	for _, element := range strings.Split(elements[1:], ".") {
+		if len(element) < 2 { //nolint:mnd
+			return nil, fmt.Errorf("invalid numalgo 2 element [%s]: %w", element, vdrapi.ErrInvalidDID)
+		}
+
+		purpose, value := element[0], element[1:]
+
+		if purpose == purposeService {
+			services, err := decodeServices(value)
+			if err != nil {
+				return nil, err
+			}
+
+			rawServices = append(rawServices, services...)
+
+			continue
+		}
+
+		id := fmt.Sprintf("%s#key-%d", didPeer, len(doc.VerificationMethod)+1)
+
+		vm, _, err := verificationMethodFromFingerprint(id, didPeer, value)
+		if err != nil {
+			return nil, err
+		}
+
+		switch purpose {
+		case purposeAssertion:
+			doc.AssertionMethod = append(doc.AssertionMethod, *did.NewReferencedVerification(vm, did.AssertionMethod))
+		case purposeEncryption:
+			doc.KeyAgreement = append(doc.KeyAgreement, *did.NewReferencedVerification(vm, did.KeyAgreement))
+		case purposeVerification:
+			doc.Authentication = append(doc.Authentication, *did.NewReferencedVerification(vm, did.Authentication))
+		case purposeCapabilityInvocation:
+			doc.CapabilityInvocation = append(doc.CapabilityInvocation,
+				*did.NewReferencedVerification(vm, did.CapabilityInvocation))
+		case purposeCapabilityDelegation:
+			doc.CapabilityDelegation = append(doc.CapabilityDelegation,
+				*did.NewReferencedVerification(vm, did.CapabilityDelegation))
+		default:
+			return nil, fmt.Errorf("unsupported numalgo 2 purpose code [%c]: %w", purpose, vdrapi.ErrInvalidDID)
+		}
+
+		doc.VerificationMethod = append(doc.VerificationMethod, *vm)
+	}
+
+	for i, rawService := range rawServices {
+		service, err := serviceFromRaw(didPeer, i, rawService)
+		if err != nil {
+			return nil, err
+		}
+
+		doc.Service = append(doc.Service, *service)
+	}
+
+	return doc, nil
+}
+
+func (v *VDR) resolveNumAlgo4(didPeer, methodID string) (*did.Doc, error) {
+	hash, encodedDoc, isLongForm := strings.Cut(methodID, ":")
+
+	shortForm := "did:peer:4" + hash
+
+	if !isLongForm {
+		longForm, ok := v.longForm(shortForm)
+		if !ok {
+			return nil, fmt.Errorf("numalgo 4 short form did has not been seen in long form: %w", vdrapi.ErrNotFound)
+		}
+
+		_, encodedDoc, _ = strings.Cut(strings.TrimPrefix(longForm, "did:peer:4"), ":")
+
+		return numAlgo4Doc(shortForm, longForm, encodedDoc)
+	}
+
+	if hash != numAlgo4Hash(encodedDoc) {
+		return nil, fmt.Errorf("numalgo 4 hash does not match encoded document: %w", vdrapi.ErrInvalidDID)
+	}
+
+	doc, err := numAlgo4Doc(didPeer, shortForm, encodedDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	v.storeLongForm(shortForm, didPeer)
+
+	return doc, nil
+}
+
+// numAlgo4Doc decodes the numalgo 4 input document and contextualizes it with the given DID.
+func numAlgo4Doc(didPeer, alsoKnownAs, encodedDoc string) (*did.Doc, error) {
+	if len(encodedDoc) < 2 || encodedDoc[0] != 'z' {
+		return nil, fmt.Errorf("numalgo 4 document is not base58btc multibase encoded: %w", vdrapi.ErrInvalidDID)
+	}
+
+	decoded := base58.Decode(encodedDoc[1:])
+
+	code, n := binary.Uvarint(decoded)
+	if n <= 0 || code != jsonMultiCodec {
+		return nil, fmt.Errorf("numalgo 4 document is not json multicodec encoded: %w", vdrapi.ErrInvalidDID)
+	}
+
+	var rawDoc map[string]interface{}
+
+	if err := json.Unmarshal(decoded[n:], &rawDoc); err != nil {
+		return nil, fmt.Errorf("numalgo 4 document: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	if _, ok := rawDoc["id"]; ok {
+		return nil, fmt.Errorf("numalgo 4 input document must not contain id: %w", vdrapi.ErrInvalidDID)
+	}
+
+	contextualize(rawDoc, didPeer, alsoKnownAs)
+
+	docBytes, err := json.Marshal(rawDoc)
+	if err != nil {
+		return nil, fmt.Errorf("marshal numalgo 4 document: %w", err)
+	}
+
+	doc, err := did.ParseDocument(docBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse numalgo 4 document: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	return doc, nil
+}
+
+func contextualize(rawDoc map[string]interface{}, didPeer, alsoKnownAs string) {
+	rawDoc["id"] = didPeer
+
+	if _, ok := rawDoc["@context"]; !ok {
+		rawDoc["@context"] = []interface{}{did.ContextV1}
+	}
+
+	aka, _ := rawDoc["alsoKnownAs"].([]interface{}) //nolint:errcheck
+	rawDoc["alsoKnownAs"] = append(aka, alsoKnownAs)
+
+	setController := func(rawVM interface{}) {
+		if vm, ok := rawVM.(map[string]interface{}); ok {
+			if _, ok = vm["controller"]; !ok {
+				vm["controller"] = didPeer
+			}
+		}
+	}
+
+	for _, key := range []string{
+		"verificationMethod", "authentication", "assertionMethod", "keyAgreement",
+		"capabilityInvocation", "capabilityDelegation",
+	} {
+		entries, _ := rawDoc[key].([]interface{}) //nolint:errcheck
+		for _, entry := range entries {
+			setController(entry)
+		}
+	}
+
+	services, _ := rawDoc["service"].([]interface{}) //nolint:errcheck
+	for _, s := range services {
+		if rawService, ok := s.(map[string]interface{}); ok {
+			normalizeDIDCommV2Endpoint(rawService)
+		}
+	}
+}
+
+// numAlgo4Hash returns the multibase encoded sha2-256 multihash of the numalgo 4 encoded document.
+func numAlgo4Hash(encodedDoc string) string {
+	digest := sha256.Sum256([]byte(encodedDoc))
+
+	return "z" + base58.Encode(append([]byte{sha256MultiHash, sha256DigestSize}, digest[:]...))
+}
+
+func decodeServices(value string) ([]map[string]interface{}, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, fmt.Errorf("decode numalgo 2 service: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	var raw interface{}
+
+	if err = json.Unmarshal(decoded, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal numalgo 2 service: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	var services []map[string]interface{}
+
+	switch s := raw.(type) {
+	case map[string]interface{}:
+		services = append(services, s)
+	case []interface{}:
+		for _, entry := range s {
+			m, ok := entry.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid numalgo 2 service entry: %w", vdrapi.ErrInvalidDID)
+			}
+
+			services = append(services, m)
+		}
+	default:
+		return nil, fmt.Errorf("invalid numalgo 2 service: %w", vdrapi.ErrInvalidDID)
+	}
+
+	for i := range services {
+		services[i] = expandAbbreviations(services[i])
+	}
+
+	return services, nil
+}
+
+func expandAbbreviations(raw map[string]interface{}) map[string]interface{} {
+	expanded := make(map[string]interface{}, len(raw))
+
+	for k, val := range raw {
+		if full, ok := serviceAbbreviations[k]; ok {
+			k = full
+		}
+
+		if m, ok := val.(map[string]interface{}); ok {
+			val = expandAbbreviations(m)
+		}
+
+		expanded[k] = val
+	}
+
+	if expanded["type"] == didCommV2TypeAbr {
+		expanded["type"] = didCommV2Type
+	}
+
+	return expanded
+}
+
+// normalizeDIDCommV2Endpoint converts DIDCommMessaging service endpoints given either as object or as legacy string
+// with routingKeys/accept at service level into the list form parsed as DIDComm V2 endpoint.
+func normalizeDIDCommV2Endpoint(rawService map[string]interface{}) {
+	if rawService["type"] != didCommV2Type {
+		return
+	}
+
+	switch ep := rawService["serviceEndpoint"].(type) {
+	case map[string]interface{}:
+		rawService["serviceEndpoint"] = []interface{}{ep}
+	case string:
+		entry := map[string]interface{}{"uri": ep}
+
+		for _, key := range []string{"routingKeys", "accept"} {
+			if val, ok := rawService[key]; ok {
+				entry[key] = val
+				delete(rawService, key)
+			}
+		}
+
+		rawService["serviceEndpoint"] = []interface{}{entry}
+	}
+}
+
+func serviceFromRaw(didPeer string, index int, rawService map[string]interface{}) (*did.Service, error) {
+	id := "#service"
+	if index > 0 {
+		id = fmt.Sprintf("#service-%d", index)
+	}
+
+	if rawID, ok := rawService["id"].(string); ok && rawID != "" {
+		id = rawID
+	}
+
+	if strings.HasPrefix(id, "#") {
+		id = didPeer + id
+	}
+
+	normalizeDIDCommV2Endpoint(rawService)
+
+	service := &did.Service{ID: id, Type: rawService["type"]}
+
+	switch ep := rawService["serviceEndpoint"].(type) {
+	case []interface{}:
+		var endpoints []endpoint.DIDCommV2Endpoint
+
+		for _, e := range ep {
+			epBytes, err := json.Marshal(e)
+			if err != nil {
+				return nil, fmt.Errorf("marshal numalgo 2 service endpoint: %w", err)
+			}
+
+			var didCommEndpoint endpoint.DIDCommV2Endpoint
+
+			if err = json.Unmarshal(epBytes, &didCommEndpoint); err != nil {
+				return nil, fmt.Errorf("invalid numalgo 2 service endpoint: %w: %w", err, vdrapi.ErrInvalidDID)
+			}
+
+			endpoints = append(endpoints, didCommEndpoint)
+		}
+
+		service.ServiceEndpoint = endpoint.NewDIDCommV2Endpoint(endpoints)
+	case string:
+		service.ServiceEndpoint = endpoint.NewDIDCommV1Endpoint(ep)
+	case nil:
+		return nil, fmt.Errorf("numalgo 2 service without serviceEndpoint: %w", vdrapi.ErrInvalidDID)
+	default:
+		service.ServiceEndpoint = endpoint.NewDIDCoreEndpoint(ep)
+	}
+
+	if keys, ok := rawService["routingKeys"].([]interface{}); ok {
+		for _, k := range keys {
+			if s, isString := k.(string); isString {
+				service.RoutingKeys = append(service.RoutingKeys, s)
+			}
+		}
+	}
+
+	for _, key := range []string{"id", "type", "serviceEndpoint", "routingKeys"} {
+		delete(rawService, key)
+	}
+
+	if len(rawService) > 0 {
+		service.Properties = rawService
+	}
+
+	return service, nil
+}
+
+func verificationMethodFromFingerprint(id, controller, fp string) (*did.VerificationMethod, uint64, error) {
+	pubKey, code, err := fingerprint.PubKeyFromFingerprint(fp)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid key [%s]: %w: %w", fp, err, vdrapi.ErrInvalidDID)
+	}
+
+	switch code {
+	case fingerprint.ED25519PubKeyMultiCodec:
+		return did.NewVerificationMethodFromBytes(id, ed25519VerificationKey2020, controller, pubKey), code, nil
+	case fingerprint.X25519PubKeyMultiCodec:
+		return did.NewVerificationMethodFromBytes(id, x25519KeyAgreementKey2020, controller, pubKey), code, nil
+	case fingerprint.P256PubKeyMultiCodec, fingerprint.P384PubKeyMultiCodec, fingerprint.P521PubKeyMultiCodec:
+		vm, e := ecVerificationMethod(id, controller, code, pubKey)
+
+		return vm, code, e
+	}
+
+	return nil, 0, fmt.Errorf("unsupported key multicodec code [0x%x]: %w", code, vdrapi.ErrInvalidDID)
+}
+
+func ecVerificationMethod(id, controller string, code uint64, pubKey []byte) (*did.VerificationMethod, error) {
+	var curve elliptic.Curve
+
+	switch code {
+	case fingerprint.P256PubKeyMultiCodec:
+		curve = elliptic.P256()
+	case fingerprint.P384PubKeyMultiCodec:
+		curve = elliptic.P384()
+	default:
+		curve = elliptic.P521()
+	}
+
+	x, y := elliptic.UnmarshalCompressed(curve, pubKey)
+	if x == nil {
+		return nil, fmt.Errorf("error unmarshalling key bytes: %w", vdrapi.ErrInvalidDID)
+	}
+
+	j, err := jwksupport.JWKFromKey(&ecdsa.PublicKey{Curve: curve, X: x, Y: y})
+	if err != nil {
+		return nil, fmt.Errorf("error creating JWK: %w", err)
+	}
+
+	return did.NewVerificationMethodFromJWK(id, jsonWebKey2020, controller, j)
+}
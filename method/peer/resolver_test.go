@@ -0,0 +1,289 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/doc/did/endpoint"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	numAlgo0DID = "did:peer:0z6MkpTHR8VNsBxYAAWHut2Geadd9jSwuBV8xRoAnwWsdvktH"
+	// numalgo 4 example of the peer did method spec.
+	numAlgo4ShortForm = "did:peer:4zQmd8CpeFPci817KDsbSAKWcXAE2mjvCQSasRewvbSF54Bd"
+	numAlgo4LongForm  = numAlgo4ShortForm + ":z2M1k7h4psgp4CmJcnQn2Ljp7Pz7ktsd7oBhMU3dWY5s4fhFNj17qcRTQ427C7QHNT6cQ7T3Xf" +
+		"Rh35Q2GhaNFZmWHVFq4vL7F8nm36PA9Y96DvdrUiRUaiCuXnBFrn1o7mxFZAx14JL4t8vUWpuDPwQuddVo1T8myRiVH7wdxuoYbsva5x6" +
+		"idEpCQydJdFjiHGCpNc2UtjzPQ8awSXkctGCnBmgkhrj5gto3D4i3EREXYq4Z8r2cWGBr2UzbSmnxW2BuYddFo9Yfm6mKjtJyLpF74ytq" +
+		"rF5xtf84MnGFg1hMBmh1xVx1JwjZ2BeMJs7mNS8DTZhKC7KH38EgqDtUZzfjhpjmmUfkXg2KFEA3EGbbVm1DPqQXayPYKAsYPS9AyKkcQ" +
+		"3fzWafLPP93UfNhtUPL8JW5pMcSV3P8v6j3vPXqnnGknNyBprD6YGUVtgLiAqDBDUF3LSxFQJCVYYtghMTv8WuSw9h1a1SRFrDQLGHE4U" +
+		"rkgoRvwaGWr64aM87T1eVGkP5Dt4L1AbboeK2ceLArPScrdYGTpi3BpTkLwZCdjdiFSfTy9okL1YNRARqUf2wm8DvkVGUU7u5nQA3ZMaX" +
+		"WJAewk6k1YUxKd7LvofGUK4YEDtoxN5vb6r1Q2godrGqaPkjfL3RoYPpDYymf9XhcgG8Kx3DZaA6cyTs24t45KxYAfeCw4wqUpCH9HbpD" +
+		"78TbEUr9PPAsJgXBvBj2VVsxnr7FKbK4KykGcg1W8M1JPz21Z4Y72LWgGQCmixovrkHktcTX1uNHjAvKBqVD5C7XmVfHgXCHj7djCh3vz" +
+		"LNuVLtEED8J1hhqsB1oCBGiuh3xXr7fZ9wUjJCQ1HYHqxLJKdYKtoCiPmgKM7etVftXkmTFETZmpM19aRyih3bao76LdpQtbw636r7a3q" +
+		"t8v4WfxsXJetSL8c7t24SqQBcAY89FBsbEnFNrQCMK3JEseKHVaU388ctvRD45uQfe5GndFxthj4iSDomk4uRFd1uRbywoP1tRuabHTDX" +
+		"42UxPjz"
+	numAlgo2DID = "did:peer:2" +
+		".Vz6Mkj3PUd1WjvaDhNZhhhXQdz5UnZXmS7ehtx8bsPpD47kKc" +
+		".Ez6LSg8zQom395jKLrGiBNruB9MM6V8PWuf2FpEy4uRFiqQBR" +
+		".SeyJ0IjoiZG0iLCJzIjp7InVyaSI6Imh0dHA6Ly9leGFtcGxlLmNvbS9kaWRjb21tIiwiYSI6WyJkaWRjb21tL3YyIl0sInIiOlsiZGlk" +
+		"OmV4YW1wbGU6MTIzNDU2Nzg5YWJjZGVmZ2hpI2tleS0xIl19fQ" +
+		".SeyJ0IjoiZG0iLCJzIjp7InVyaSI6Imh0dHA6Ly9leGFtcGxlLmNvbS9hbm90aGVyIiwiYSI6WyJkaWRjb21tL3YyIl0sInIiOlsiZGlk" +
+		"OmV4YW1wbGU6MTIzNDU2Nzg5YWJjZGVmZ2hpI2tleS0yIl19fQ"
+)
+
+func TestReadNumAlgo0(t *testing.T) {
+	t.Run("test ed25519 inception key", func(t *testing.T) {
+		v := New()
+
+		docResolution, err := v.Read(numAlgo0DID)
+		require.NoError(t, err)
+		require.Equal(t, did.ContentTypeDIDLDJSON, docResolution.ResolutionMetadata.ContentType)
+
+		doc := docResolution.DIDDocument
+		require.Equal(t, numAlgo0DID, doc.ID)
+		require.Len(t, doc.VerificationMethod, 1)
+		require.Equal(t, numAlgo0DID+"#z6MkpTHR8VNsBxYAAWHut2Geadd9jSwuBV8xRoAnwWsdvktH", doc.VerificationMethod[0].ID)
+		require.Equal(t, ed25519VerificationKey2020, doc.VerificationMethod[0].Type)
+		require.Len(t, doc.Authentication, 1)
+		require.Len(t, doc.AssertionMethod, 1)
+		require.Empty(t, doc.KeyAgreement)
+
+		_, err = doc.JSONBytes()
+		require.NoError(t, err)
+	})
+
+	t.Run("test x25519 inception key", func(t *testing.T) {
+		v := New()
+
+		docResolution, err := v.Read("did:peer:0z6LSg8zQom395jKLrGiBNruB9MM6V8PWuf2FpEy4uRFiqQBR")
+		require.NoError(t, err)
+		require.Len(t, docResolution.DIDDocument.KeyAgreement, 1)
+		require.Equal(t, x25519KeyAgreementKey2020, docResolution.DIDDocument.KeyAgreement[0].VerificationMethod.Type)
+		require.Empty(t, docResolution.DIDDocument.Authentication)
+	})
+
+	t.Run("test p-256 inception key", func(t *testing.T) {
+		v := New()
+
+		docResolution, err := v.Read("did:peer:0zDnaerDaTF5BXEavCrfRZEk316dpbLsfPDZ3WJ5hRTPFU2169")
+		require.NoError(t, err)
+		require.Equal(t, jsonWebKey2020, docResolution.DIDDocument.VerificationMethod[0].Type)
+		require.Equal(t, "P-256", docResolution.DIDDocument.VerificationMethod[0].JSONWebKey().Crv)
+	})
+
+	t.Run("test invalid key", func(t *testing.T) {
+		v := New()
+
+		_, err := v.Read("did:peer:0zinvalid")
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+	})
+}
+
+func TestReadNumAlgo2(t *testing.T) {
+	t.Run("test spec example", func(t *testing.T) {
+		v := New()
+
+		docResolution, err := v.Read(numAlgo2DID)
+		require.NoError(t, err)
+
+		doc := docResolution.DIDDocument
+		require.Len(t, doc.VerificationMethod, 2)
+		require.Equal(t, numAlgo2DID+"#key-1", doc.VerificationMethod[0].ID)
+		require.Equal(t, numAlgo2DID+"#key-2", doc.VerificationMethod[1].ID)
+		require.Len(t, doc.Authentication, 1)
+		require.Equal(t, numAlgo2DID+"#key-1", doc.Authentication[0].VerificationMethod.ID)
+		require.Len(t, doc.KeyAgreement, 1)
+		require.Equal(t, numAlgo2DID+"#key-2", doc.KeyAgreement[0].VerificationMethod.ID)
+
+		require.Len(t, doc.Service, 2)
+		require.Equal(t, numAlgo2DID+"#service", doc.Service[0].ID)
+		require.Equal(t, numAlgo2DID+"#service-1", doc.Service[1].ID)
+		require.Equal(t, didCommV2Type, doc.Service[0].Type)
+		require.Equal(t, endpoint.DIDCommV2, doc.Service[0].ServiceEndpoint.Type())
+
+		uri, err := doc.Service[0].ServiceEndpoint.URI()
+		require.NoError(t, err)
+		require.Equal(t, "http://example.com/didcomm", uri)
+
+		routingKeys, err := doc.Service[0].ServiceEndpoint.RoutingKeys()
+		require.NoError(t, err)
+		require.Equal(t, []string{"did:example:123456789abcdefghi#key-1"}, routingKeys)
+
+		accept, err := doc.Service[1].ServiceEndpoint.Accept()
+		require.NoError(t, err)
+		require.Equal(t, []string{"didcomm/v2"}, accept)
+
+		_, err = doc.JSONBytes()
+		require.NoError(t, err)
+	})
+
+	t.Run("test legacy service encoding", func(t *testing.T) {
+		v := New()
+
+		service := base64.RawURLEncoding.EncodeToString(
+			[]byte(`{"t":"dm","s":"https://example.com/endpoint","r":["did:example:somemediator#somekey"],"a":["didcomm/v2"]}`))
+
+		docResolution, err := v.Read("did:peer:2.Vz6Mkj3PUd1WjvaDhNZhhhXQdz5UnZXmS7ehtx8bsPpD47kKc.S" + service)
+		require.NoError(t, err)
+		require.Len(t, docResolution.DIDDocument.Service, 1)
+
+		s := docResolution.DIDDocument.Service[0]
+		require.Equal(t, endpoint.DIDCommV2, s.ServiceEndpoint.Type())
+
+		uri, err := s.ServiceEndpoint.URI()
+		require.NoError(t, err)
+		require.Equal(t, "https://example.com/endpoint", uri)
+
+		routingKeys, err := s.ServiceEndpoint.RoutingKeys()
+		require.NoError(t, err)
+		require.Equal(t, []string{"did:example:somemediator#somekey"}, routingKeys)
+	})
+
+	t.Run("test other service", func(t *testing.T) {
+		v := New()
+
+		service := base64.RawURLEncoding.EncodeToString(
+			[]byte(`{"id":"#domains","t":"LinkedDomains","s":"https://example.com"}`))
+
+		docResolution, err := v.Read("did:peer:2.Vz6Mkj3PUd1WjvaDhNZhhhXQdz5UnZXmS7ehtx8bsPpD47kKc.S" + service)
+		require.NoError(t, err)
+		require.Len(t, docResolution.DIDDocument.Service, 1)
+		require.Equal(t, "LinkedDomains", docResolution.DIDDocument.Service[0].Type)
+		require.True(t, len(docResolution.DIDDocument.Service[0].ID) > len("#domains"))
+		require.Contains(t, docResolution.DIDDocument.Service[0].ID, "#domains")
+	})
+
+	t.Run("test invalid elements", func(t *testing.T) {
+		v := New()
+
+		for _, didPeer := range []string{
+			"did:peer:2Vz6Mkj3PUd1WjvaDhNZhhhXQdz5UnZXmS7ehtx8bsPpD47kKc",
+			"did:peer:2.Xz6Mkj3PUd1WjvaDhNZhhhXQdz5UnZXmS7ehtx8bsPpD47kKc",
+			"did:peer:2.Vz6Mkinvalid",
+			"did:peer:2.Vz6Mkj3PUd1WjvaDhNZhhhXQdz5UnZXmS7ehtx8bsPpD47kKc.S",
+			"did:peer:2.Vz6Mkj3PUd1WjvaDhNZhhhXQdz5UnZXmS7ehtx8bsPpD47kKc.Sinvalid",
+		} {
+			_, err := v.Read(didPeer)
+			require.ErrorIs(t, err, vdrapi.ErrInvalidDID, didPeer)
+		}
+	})
+}
+
+func TestReadNumAlgo4(t *testing.T) {
+	t.Run("test spec example", func(t *testing.T) {
+		v := New()
+
+		docResolution, err := v.Read(numAlgo4LongForm)
+		require.NoError(t, err)
+
+		doc := docResolution.DIDDocument
+		require.Equal(t, numAlgo4LongForm, doc.ID)
+		require.Equal(t, []string{numAlgo4ShortForm}, doc.AlsoKnownAs)
+		require.Len(t, doc.VerificationMethod, 2)
+		require.Equal(t, numAlgo4LongForm+"#6LSqPZfn", doc.VerificationMethod[0].ID)
+		require.Equal(t, "X25519KeyAgreementKey2020", doc.VerificationMethod[0].Type)
+		require.Equal(t, numAlgo4LongForm, doc.VerificationMethod[0].Controller)
+		require.Equal(t, numAlgo4LongForm+"#6MkrCD1c", doc.VerificationMethod[1].ID)
+		require.Len(t, doc.Authentication, 1)
+		require.Len(t, doc.KeyAgreement, 1)
+		require.Len(t, doc.Service, 1)
+		require.Equal(t, "DIDCommMessaging", doc.Service[0].Type)
+
+		uri, err := doc.Service[0].ServiceEndpoint.URI()
+		require.NoError(t, err)
+		require.Equal(t, "didcomm:transport/queue", uri)
+
+		docResolution, err = v.Read(numAlgo4ShortForm)
+		require.NoError(t, err)
+		require.Equal(t, numAlgo4ShortForm, docResolution.DIDDocument.ID)
+		require.Equal(t, []string{numAlgo4LongForm}, docResolution.DIDDocument.AlsoKnownAs)
+	})
+
+	t.Run("test long forms bounded", func(t *testing.T) {
+		v := New(WithMaxLongForms(1))
+
+		_, err := v.Read(numAlgo4LongForm)
+		require.NoError(t, err)
+
+		docResolution, err := v.Create(&did.Doc{
+			VerificationMethod: []did.VerificationMethod{*ed25519VM(t, "#key-1")},
+		}, vdrapi.WithOption(NumAlgoOpt, NumAlgo4))
+		require.NoError(t, err)
+
+		_, err = v.Read(numAlgo4ShortForm)
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+
+		_, err = v.Read(docResolution.DIDDocument.AlsoKnownAs[0])
+		require.NoError(t, err)
+
+		_, err = New(WithMaxLongForms(0)).Read(numAlgo4LongForm)
+		require.NoError(t, err)
+	})
+
+	t.Run("test hash mismatch", func(t *testing.T) {
+		v := New()
+
+		docResolution, err := v.Create(&did.Doc{
+			VerificationMethod: []did.VerificationMethod{*ed25519VM(t, "#key-1")},
+		}, vdrapi.WithOption(NumAlgoOpt, NumAlgo4))
+		require.NoError(t, err)
+
+		longForm := docResolution.DIDDocument.ID
+
+		_, err = v.Read(longForm[:len(longForm)-1] + "a")
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+	})
+
+	t.Run("test short form not seen", func(t *testing.T) {
+		v := New()
+
+		_, err := v.Read(numAlgo4ShortForm)
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+	})
+
+	t.Run("test invalid encoded document", func(t *testing.T) {
+		v := New()
+
+		for _, encoded := range []string{"invalid", "z3yQ", "z" + "2Ub4"} {
+			_, err := v.Read("did:peer:4" + numAlgo4Hash(encoded) + ":" + encoded)
+			require.ErrorIs(t, err, vdrapi.ErrInvalidDID, encoded)
+		}
+	})
+}
+
+func TestReadInvalid(t *testing.T) {
+	t.Run("test invalid did", func(t *testing.T) {
+		_, err := New().Read("invalid")
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+	})
+
+	t.Run("test other method", func(t *testing.T) {
+		_, err := New().Read("did:key:z6MkpTHR8VNsBxYAAWHut2Geadd9jSwuBV8xRoAnwWsdvktH")
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+	})
+
+	t.Run("test unsupported numalgo", func(t *testing.T) {
+		_, err := New().Read("did:peer:1zQmZMygzYqNwU6Uhmewx5Xepf2VLp5S4HLSwwgf2aiKZuwa")
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+		require.Contains(t, err.Error(), "unsupported numalgo 1")
+	})
+
+	t.Run("test context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := New().ReadContext(ctx, numAlgo0DID)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
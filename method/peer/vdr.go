@@ -0,0 +1,132 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package peer implements did:peer method (https://identity.foundation/peer-did-method-spec/)
+// for numalgo 0, 2 and 4.
+package peer
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+
+	diddoc "github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	// DIDMethod did method.
+	DIDMethod = "peer"
+	// NumAlgoOpt option to select the numalgo of a created did:peer, one of NumAlgo0, NumAlgo2 or NumAlgo4.
+	NumAlgoOpt = "numalgo"
+
+	// NumAlgo0 inception key without doc.
+	NumAlgo0 = 0
+	// NumAlgo2 multiple inception keys and services.
+	NumAlgo2 = 2
+	// NumAlgo4 short form and long form.
+	NumAlgo4 = 4
+
+	defaultMaxLongForms = 1000
+)
+
+// VDR implements did:peer method support.
+type VDR struct {
+	mutex sync.Mutex
+	// longForms maps did:peer:4 short forms to their long forms seen on Read and Create, least recently used
+	// entries are evicted once maxLongForms is reached.
+	longForms    map[string]*list.Element
+	lru          *list.List
+	maxLongForms int
+}
+
+type longFormEntry struct {
+	shortForm string
+	longForm  string
+}
+
+// Option configures the peer vdr.
+type Option func(opts *VDR)
+
+// New returns new instance of VDR that works with did:peer method.
+func New(opts ...Option) *VDR {
+	v := &VDR{
+		longForms:    make(map[string]*list.Element),
+		lru:          list.New(),
+		maxLongForms: defaultMaxLongForms,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// WithMaxLongForms sets how many did:peer:4 long forms are kept to resolve their short forms, 1000 by default.
+// A zero size disables short form resolution.
+func WithMaxLongForms(size int) Option {
+	return func(opts *VDR) {
+		opts.maxLongForms = size
+	}
+}
+
+// Accept accepts did:peer method.
+func (v *VDR) Accept(method string, opts ...vdrapi.DIDMethodOption) bool {
+	return method == DIDMethod
+}
+
+// Close frees resources being maintained by VDR.
+func (v *VDR) Close() error {
+	return nil
+}
+
+// Update did doc.
+func (v *VDR) Update(didDoc *diddoc.Doc, opts ...vdrapi.DIDMethodOption) error {
+	return errors.New("not supported")
+}
+
+// Deactivate did doc.
+func (v *VDR) Deactivate(didID string, opts ...vdrapi.DIDMethodOption) error {
+	return errors.New("not supported")
+}
+
+func (v *VDR) storeLongForm(shortForm, longForm string) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if elem, ok := v.longForms[shortForm]; ok {
+		v.lru.MoveToFront(elem)
+
+		return
+	}
+
+	if v.maxLongForms <= 0 {
+		return
+	}
+
+	v.longForms[shortForm] = v.lru.PushFront(&longFormEntry{shortForm: shortForm, longForm: longForm})
+
+	for v.lru.Len() > v.maxLongForms {
+		e := v.lru.Remove(v.lru.Back()).(*longFormEntry) //nolint:errcheck,forcetypeassert
+
+		delete(v.longForms, e.shortForm)
+	}
+}
+
+func (v *VDR) longForm(shortForm string) (string, bool) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	elem, ok := v.longForms[shortForm]
+	if !ok {
+		return "", false
+	}
+
+	v.lru.MoveToFront(elem)
+
+	return elem.Value.(*longFormEntry).longForm, true //nolint:forcetypeassert
+}
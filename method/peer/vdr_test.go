@@ -0,0 +1,59 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/did-go/vdr/api"
+)
+
+var _ api.VDR = (*VDR)(nil) // verify interface compliance
+
+func TestAccept(t *testing.T) {
+	t.Run("peer method", func(t *testing.T) {
+		v := New()
+		require.NotNil(t, v)
+
+		require.True(t, v.Accept("peer"))
+	})
+
+	t.Run("other method", func(t *testing.T) {
+		v := New()
+		require.NotNil(t, v)
+
+		require.False(t, v.Accept("other"))
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("test update", func(t *testing.T) {
+		v := New()
+		err := v.Update(nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not supported")
+	})
+}
+
+func TestDeactivate(t *testing.T) {
+	t.Run("test deactivate", func(t *testing.T) {
+		v := New()
+		err := v.Deactivate("")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not supported")
+	})
+}
+
+func TestClose(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		v := New()
+		require.NotNil(t, v)
+		require.NoError(t, v.Close())
+	})
+}
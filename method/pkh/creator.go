@@ -0,0 +1,24 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkh
+
+import (
+	"errors"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+// Create returns the did:pkh DID document of the CAIP-10 blockchain account ID held by the first verification
+// method of didDoc.
+func (v *VDR) Create(didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	if len(didDoc.VerificationMethod) == 0 || didDoc.VerificationMethod[0].BlockchainAccountID == "" {
+		return nil, errors.New("verification method with blockchain account ID is required")
+	}
+
+	return v.Read("did:pkh:"+didDoc.VerificationMethod[0].BlockchainAccountID, opts...)
+}
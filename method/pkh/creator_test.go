@@ -0,0 +1,31 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkh
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/did-go/doc/did"
+)
+
+func TestCreate(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		vm := did.NewVerificationMethodFromBlockchainAccountID("#key-1", "EcdsaSecp256k1RecoveryMethod2020", "",
+			"eip155:1:0xb9c5714089478a327f09197987f16f9e5d936e8a")
+
+		docResolution, err := New().Create(&did.Doc{VerificationMethod: []did.VerificationMethod{*vm}})
+		require.NoError(t, err)
+		require.Equal(t, "did:pkh:eip155:1:0xb9c5714089478a327f09197987f16f9e5d936e8a", docResolution.DIDDocument.ID)
+	})
+
+	t.Run("test missing blockchain account ID", func(t *testing.T) {
+		_, err := New().Create(&did.Doc{})
+		require.EqualError(t, err, "verification method with blockchain account ID is required")
+	})
+}
@@ -0,0 +1,192 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkh
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	schemaResV1 = "https://w3id.org/did-resolution/v1"
+
+	// EIP155 namespace of Ethereum and EVM compatible chains.
+	EIP155 = "eip155"
+	// BIP122 namespace of Bitcoin and forks.
+	BIP122 = "bip122"
+	// Solana namespace.
+	Solana = "solana"
+	// Tezos namespace.
+	Tezos = "tezos"
+
+	ecdsaSecp256k1RecoveryMethod2020 = "EcdsaSecp256k1RecoveryMethod2020"
+	ed25519VerificationKey2018       = "Ed25519VerificationKey2018"
+	tezosEd25519Method               = "Ed25519PublicKeyBLAKE2BDigestSize20Base58CheckEncoded2021"
+	tezosP256Method                  = "P256PublicKeyBLAKE2BDigestSize20Base58CheckEncoded2021"
+
+	blockchainAccountIDFragment = "#blockchainAccountId"
+	controllerFragment          = "#controller"
+
+	ed25519PublicKeySize = 32
+)
+
+// vmTypeContexts maps verification method types to their JSON-LD term definitions.
+var vmTypeContexts = map[string]string{ //nolint:gochecknoglobals
+	ecdsaSecp256k1RecoveryMethod2020: "https://identity.foundation/EcdsaSecp256k1RecoverySignature2020#" +
+		ecdsaSecp256k1RecoveryMethod2020,
+	ed25519VerificationKey2018: "https://w3id.org/security#" + ed25519VerificationKey2018,
+	tezosEd25519Method:         "https://w3id.org/security#" + tezosEd25519Method,
+	tezosP256Method:            "https://w3id.org/security#" + tezosP256Method,
+}
+
+// caip10 is the CAIP-10 account ID syntax, the namespaces below restrict reference and address further.
+var caip10 = regexp.MustCompile( //nolint:gochecknoglobals
+	`^([-a-z0-9]{3,8}):([-_a-zA-Z0-9]{1,32}):([-.%a-zA-Z0-9]{1,128})$`)
+
+type namespace struct {
+	reference *regexp.Regexp
+	address   *regexp.Regexp
+	// verificationMethod returns the verification method of the account address.
+	verificationMethod func(didPKH, accountID, address string) (*did.VerificationMethod, error)
+}
+
+var namespaces = map[string]namespace{ //nolint:gochecknoglobals
+	EIP155: {
+		reference:          regexp.MustCompile(`^[0-9]{1,32}$`),
+		address:            regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`),
+		verificationMethod: recoveryMethod,
+	},
+	BIP122: {
+		reference:          regexp.MustCompile(`^[0-9a-f]{32}$`),
+		address:            regexp.MustCompile(`^([1-9A-HJ-NP-Za-km-z]{25,35}|bc1[ac-hj-np-z02-9]{11,71})$`),
+		verificationMethod: recoveryMethod,
+	},
+	Solana: {
+		reference:          regexp.MustCompile(`^[1-9A-HJ-NP-Za-km-z]{32}$`),
+		address:            regexp.MustCompile(`^[1-9A-HJ-NP-Za-km-z]{32,44}$`),
+		verificationMethod: solanaMethod,
+	},
+	Tezos: {
+		reference:          regexp.MustCompile(`^[1-9A-HJ-NP-Za-km-z]{1,32}$`),
+		address:            regexp.MustCompile(`^tz[123][1-9A-HJ-NP-Za-km-z]{33}$`),
+		verificationMethod: tezosMethod,
+	},
+}
+
+// Read expands did:pkh value to a DID document.
+func (v *VDR) Read(didPKH string, _ ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	parsed, err := did.Parse(didPKH)
+	if err != nil {
+		return nil, fmt.Errorf("pkh-vdr read: failed to parse DID: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	if parsed.Method != DIDMethod {
+		return nil, fmt.Errorf("pkh-vdr read: invalid method: %s: %w", parsed.Method, vdrapi.ErrInvalidDID)
+	}
+
+	accountID := parsed.MethodSpecificID
+
+	parts := caip10.FindStringSubmatch(accountID)
+	if parts == nil {
+		return nil, fmt.Errorf("pkh-vdr read: invalid CAIP-10 account ID: %s: %w", accountID, vdrapi.ErrInvalidDID)
+	}
+
+	ns, ok := namespaces[parts[1]]
+	if !ok {
+		return nil, fmt.Errorf("pkh-vdr read: unsupported namespace: %s: %w", parts[1], vdrapi.ErrMethodNotSupported)
+	}
+
+	if !ns.reference.MatchString(parts[2]) {
+		return nil, fmt.Errorf("pkh-vdr read: invalid %s reference: %s: %w", parts[1], parts[2], vdrapi.ErrInvalidDID)
+	}
+
+	if !ns.address.MatchString(parts[3]) {
+		return nil, fmt.Errorf("pkh-vdr read: invalid %s address: %s: %w", parts[1], parts[3], vdrapi.ErrInvalidDID)
+	}
+
+	vm, err := ns.verificationMethod(didPKH, accountID, parts[3])
+	if err != nil {
+		return nil, fmt.Errorf("pkh-vdr read: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	return &did.DocResolution{
+		Context:            []string{schemaResV1},
+		DIDDocument:        createDoc(vm, didPKH),
+		ResolutionMetadata: &did.ResolutionMetadata{ContentType: did.ContentTypeDIDLDJSON},
+	}, nil
+}
+
+// ReadContext expands did:pkh value to a DID document. Resolution is done offline, so the context
+// is only checked for cancellation.
+func (v *VDR) ReadContext(ctx context.Context, didPKH string,
+	opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return v.Read(didPKH, opts...)
+}
+
+func recoveryMethod(didPKH, accountID, _ string) (*did.VerificationMethod, error) {
+	return did.NewVerificationMethodFromBlockchainAccountID(didPKH+blockchainAccountIDFragment,
+		ecdsaSecp256k1RecoveryMethod2020, didPKH, accountID), nil
+}
+
+func solanaMethod(didPKH, accountID, address string) (*did.VerificationMethod, error) {
+	// solana addresses are base58 encoded ed25519 public keys.
+	pubKey := base58.Decode(address)
+	if len(pubKey) != ed25519PublicKeySize {
+		return nil, fmt.Errorf("invalid solana address: %s", address)
+	}
+
+	vm := did.NewVerificationMethodFromBytes(didPKH+controllerFragment, ed25519VerificationKey2018, didPKH, pubKey)
+	vm.BlockchainAccountID = accountID
+
+	return vm, nil
+}
+
+func tezosMethod(didPKH, accountID, address string) (*did.VerificationMethod, error) {
+	var vmType string
+
+	switch {
+	case strings.HasPrefix(address, "tz1"):
+		vmType = tezosEd25519Method
+	case strings.HasPrefix(address, "tz2"):
+		vmType = ecdsaSecp256k1RecoveryMethod2020
+	default:
+		vmType = tezosP256Method
+	}
+
+	return did.NewVerificationMethodFromBlockchainAccountID(didPKH+blockchainAccountIDFragment, vmType, didPKH,
+		accountID), nil
+}
+
+func createDoc(vm *did.VerificationMethod, didPKH string) *did.Doc {
+	termContext := map[string]interface{}{
+		"blockchainAccountId": "https://w3id.org/security#blockchainAccountId",
+		vm.Type:               vmTypeContexts[vm.Type],
+	}
+
+	if vm.Value != nil {
+		termContext["publicKeyBase58"] = "https://w3id.org/security#publicKeyBase58"
+	}
+
+	return &did.Doc{
+		Context:            []interface{}{did.ContextV1, termContext},
+		ID:                 didPKH,
+		VerificationMethod: []did.VerificationMethod{*vm},
+		Authentication:     []did.Verification{*did.NewReferencedVerification(vm, did.Authentication)},
+		AssertionMethod:    []did.Verification{*did.NewReferencedVerification(vm, did.AssertionMethod)},
+	}
+}
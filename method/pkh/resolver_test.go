@@ -0,0 +1,125 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkh
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		did     string
+		vmID    string
+		vmType  string
+		withKey bool
+	}{
+		{
+			name:   "eip155",
+			did:    "did:pkh:eip155:1:0xb9c5714089478a327f09197987f16f9e5d936e8a",
+			vmID:   "#blockchainAccountId",
+			vmType: "EcdsaSecp256k1RecoveryMethod2020",
+		},
+		{
+			name:   "bip122",
+			did:    "did:pkh:bip122:000000000019d6689c085ae165831e93:128Lkh3S7CkDTBZ8W7BbpsN3YYizJMp8p6",
+			vmID:   "#blockchainAccountId",
+			vmType: "EcdsaSecp256k1RecoveryMethod2020",
+		},
+		{
+			name:    "solana",
+			did:     "did:pkh:solana:4sGjMW1sUnHzSxGspuhpqLDx6wiyjNtZ:CKg5d12Jhpej1JqtmxLJgaFqqeYjxgPqToJ4LBdvG9Ev",
+			vmID:    "#controller",
+			vmType:  "Ed25519VerificationKey2018",
+			withKey: true,
+		},
+		{
+			name:   "tezos tz1",
+			did:    "did:pkh:tezos:NetXdQprcVkpaWU:tz1TzrmTBSuiVHV2VfMnGRMYvTEPCP42oSM8",
+			vmID:   "#blockchainAccountId",
+			vmType: "Ed25519PublicKeyBLAKE2BDigestSize20Base58CheckEncoded2021",
+		},
+		{
+			name:   "tezos tz2",
+			did:    "did:pkh:tezos:NetXdQprcVkpaWU:tz2BFTyPeYRzxd5aiBchbXN3WCZhx7BqbMBq",
+			vmID:   "#blockchainAccountId",
+			vmType: "EcdsaSecp256k1RecoveryMethod2020",
+		},
+		{
+			name:   "tezos tz3",
+			did:    "did:pkh:tezos:NetXdQprcVkpaWU:tz3agP9LGe2cXmKQyYn6T68BHKjjktDbbSWX",
+			vmID:   "#blockchainAccountId",
+			vmType: "P256PublicKeyBLAKE2BDigestSize20Base58CheckEncoded2021",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run("test "+tc.name, func(t *testing.T) {
+			docResolution, err := New().Read(tc.did)
+			require.NoError(t, err)
+			require.Equal(t, did.ContentTypeDIDLDJSON, docResolution.ResolutionMetadata.ContentType)
+
+			doc := docResolution.DIDDocument
+			require.Equal(t, tc.did, doc.ID)
+			require.Len(t, doc.VerificationMethod, 1)
+			require.Equal(t, tc.did+tc.vmID, doc.VerificationMethod[0].ID)
+			require.Equal(t, tc.vmType, doc.VerificationMethod[0].Type)
+			require.Equal(t, tc.did[len("did:pkh:"):], doc.VerificationMethod[0].BlockchainAccountID)
+			require.Equal(t, tc.withKey, len(doc.VerificationMethod[0].Value) > 0)
+			require.Len(t, doc.Authentication, 1)
+			require.Len(t, doc.AssertionMethod, 1)
+
+			docBytes, err := doc.JSONBytes()
+			require.NoError(t, err)
+
+			require.Contains(t, string(docBytes), `"blockchainAccountId":"`+tc.did[len("did:pkh:"):]+`"`)
+
+			parsed, err := did.ParseDocument(docBytes)
+			require.NoError(t, err)
+			require.Equal(t, doc.VerificationMethod[0].BlockchainAccountID,
+				parsed.VerificationMethod[0].BlockchainAccountID)
+		})
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	t.Run("test invalid did", func(t *testing.T) {
+		for _, didPKH := range []string{
+			"invalid",
+			"did:key:z6MkpTHR8VNsBxYAAWHut2Geadd9jSwuBV8xRoAnwWsdvktH",
+			"did:pkh:0xb9c5714089478a327f09197987f16f9e5d936e8a",
+			"did:pkh:eip155:one:0xb9c5714089478a327f09197987f16f9e5d936e8a",
+			"did:pkh:eip155:1:0xb9c5714089478a327f09197987f16f9e5d936e8",
+			"did:pkh:bip122:000000000019d6689c085ae165831e93:0OIl",
+			"did:pkh:solana:4sGjMW1sUnHzSxGspuhpqLDx6wiyjNtZ:4sGjMW1sUnHzSxGspuhpqLDx6wiyjNtZ",
+			"did:pkh:tezos:NetXdQprcVkpaWU:tz4TzrmTBSuiVHV2VfMnGRMYvTEPCP42oSM8",
+		} {
+			_, err := New().Read(didPKH)
+			require.ErrorIs(t, err, vdrapi.ErrInvalidDID, didPKH)
+		}
+	})
+
+	t.Run("test unsupported namespace", func(t *testing.T) {
+		_, err := New().Read("did:pkh:cosmos:cosmoshub-4:cosmos1s6j3aclm4yutz3h7nqtvv8k0p5jv0kqfsw6a6p")
+		require.ErrorIs(t, err, vdrapi.ErrMethodNotSupported)
+		require.Contains(t, err.Error(), "unsupported namespace: cosmos")
+	})
+
+	t.Run("test context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := New().ReadContext(ctx, "did:pkh:eip155:1:0xb9c5714089478a327f09197987f16f9e5d936e8a")
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,101 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package plc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+const (
+	cborText   = 3
+	cborArray  = 4
+	cborMap    = 5
+	cborFalse  = 0xf4
+	cborTrue   = 0xf5
+	cborNull   = 0xf6
+	cborInline = 24
+)
+
+// marshalDAGCBOR encodes a JSON value, as unmarshalled into interface{}, in the deterministic DAG-CBOR form the PLC
+// operations are hashed and signed in: map keys are sorted by length, then bytewise.
+func marshalDAGCBOR(value interface{}) ([]byte, error) {
+	return appendDAGCBOR(nil, value)
+}
+
+func appendDAGCBOR(buf []byte, value interface{}) ([]byte, error) {
+	var err error
+
+	switch v := value.(type) {
+	case nil:
+		return append(buf, cborNull), nil
+	case bool:
+		if v {
+			return append(buf, cborTrue), nil
+		}
+
+		return append(buf, cborFalse), nil
+	case string:
+		return append(appendHeader(buf, cborText, uint64(len(v))), v...), nil
+	case []interface{}:
+		buf = appendHeader(buf, cborArray, uint64(len(v)))
+
+		for _, item := range v {
+			if buf, err = appendDAGCBOR(buf, item); err != nil {
+				return nil, err
+			}
+		}
+
+		return buf, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+
+		sort.Slice(keys, func(i, j int) bool {
+			if len(keys[i]) != len(keys[j]) {
+				return len(keys[i]) < len(keys[j])
+			}
+
+			return keys[i] < keys[j]
+		})
+
+		buf = appendHeader(buf, cborMap, uint64(len(v)))
+
+		for _, key := range keys {
+			buf = append(appendHeader(buf, cborText, uint64(len(key))), key...)
+
+			if buf, err = appendDAGCBOR(buf, v[key]); err != nil {
+				return nil, err
+			}
+		}
+
+		return buf, nil
+	default:
+		return nil, fmt.Errorf("unsupported dag-cbor value of type %T", value)
+	}
+}
+
+// appendHeader appends the header of a data item of the major type, with the shortest encoding of n.
+func appendHeader(buf []byte, majorType byte, n uint64) []byte {
+	major := majorType << 5 //nolint:mnd
+
+	switch {
+	case n < cborInline:
+		return append(buf, major|byte(n))
+	case n <= 0xff:
+		return append(buf, major|cborInline, byte(n))
+	case n <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(buf, major|(cborInline+1)), uint16(n))
+	case n <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(buf, major|(cborInline+2)), uint32(n)) //nolint:mnd
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, major|(cborInline+3)), n) //nolint:mnd
+	}
+}
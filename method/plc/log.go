@@ -0,0 +1,169 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package plc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// nullificationWindow is how long after an operation a higher authority rotation key can nullify it.
+const nullificationWindow = 72 * time.Hour
+
+// ErrInvalidLog is returned when the operation log of a DID fails verification.
+var ErrInvalidLog = errors.New("invalid did:plc operation log")
+
+// LogEntry is an entry of the audit log of a DID, as served by the PLC directory.
+type LogEntry struct {
+	DID       string          `json:"did"`
+	Operation json.RawMessage `json:"operation"`
+	CID       string          `json:"cid"`
+	Nullified bool            `json:"nullified"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// logOperation is a valid operation of the log.
+type logOperation struct {
+	op        *Operation
+	cid       string
+	createdAt time.Time
+	// keyIndex is the index of the rotation key that signed the operation in the rotation keys of the previous
+	// operation.
+	keyIndex int
+}
+
+// verifyLog verifies the audit log of didID and returns the operations in effect, the last one being the current
+// state of the DID.
+//
+// The genesis operation must hash to the DID, every operation must be signed by a rotation key of the operation
+// it follows and refer to it by its CID. An operation following an operation other than the latest one nullifies
+// the operations after it, which requires a rotation key of higher authority than the key of the first nullified
+// operation, within 72 hours of it. The nullified flags of the entries must match the nullified operations.
+func verifyLog(didID string, entries []*LogEntry) ([]*logOperation, error) { //nolint:gocyclo,funlen
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: empty log", ErrInvalidLog)
+	}
+
+	var (
+		chain     []*logOperation
+		nullified = make(map[string]bool)
+	)
+
+	for i, entry := range entries {
+		if entry.DID != didID {
+			return nil, fmt.Errorf("%w: entry %d: did [%s] does not match", ErrInvalidLog, i+1, entry.DID)
+		}
+
+		op, err := parseOperation(entry.Operation)
+		if err != nil {
+			return nil, fmt.Errorf("%w: entry %d: %w", ErrInvalidLog, i+1, err)
+		}
+
+		cid, err := op.CID()
+		if err != nil {
+			return nil, fmt.Errorf("%w: entry %d: %w", ErrInvalidLog, i+1, err)
+		}
+
+		if cid != entry.CID {
+			return nil, fmt.Errorf("%w: entry %d: cid [%s] does not match the operation", ErrInvalidLog, i+1,
+				entry.CID)
+		}
+
+		if i == 0 {
+			if err = verifyGenesis(didID, op); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidLog, err)
+			}
+
+			chain = append(chain, &logOperation{op: op, cid: cid, createdAt: entry.CreatedAt})
+
+			continue
+		}
+
+		if op.Prev == nil {
+			return nil, fmt.Errorf("%w: entry %d: missing prev operation", ErrInvalidLog, i+1)
+		}
+
+		p := indexOf(chain, *op.Prev)
+		if p < 0 {
+			return nil, fmt.Errorf("%w: entry %d: unknown prev operation [%s]", ErrInvalidLog, i+1, *op.Prev)
+		}
+
+		prev := chain[p].op
+		if prev.Type == TombstoneType {
+			return nil, fmt.Errorf("%w: entry %d: follows a tombstone", ErrInvalidLog, i+1)
+		}
+
+		keyIndex, err := op.verify(prev.RotationKeys)
+		if err != nil {
+			return nil, fmt.Errorf("%w: entry %d: %w", ErrInvalidLog, i+1, err)
+		}
+
+		if p < len(chain)-1 {
+			first := chain[p+1]
+
+			if keyIndex >= first.keyIndex {
+				return nil, fmt.Errorf("%w: entry %d: rotation key has no authority to nullify [%s]", ErrInvalidLog,
+					i+1, first.cid)
+			}
+
+			if entry.CreatedAt.Sub(first.createdAt) > nullificationWindow {
+				return nil, fmt.Errorf("%w: entry %d: nullification window of [%s] has passed", ErrInvalidLog, i+1,
+					first.cid)
+			}
+
+			for _, n := range chain[p+1:] {
+				nullified[n.cid] = true
+			}
+
+			chain = chain[:p+1]
+		}
+
+		chain = append(chain, &logOperation{op: op, cid: cid, createdAt: entry.CreatedAt, keyIndex: keyIndex})
+	}
+
+	for i, entry := range entries {
+		if entry.Nullified != nullified[entry.CID] {
+			return nil, fmt.Errorf("%w: entry %d: nullified flag does not match the log", ErrInvalidLog, i+1)
+		}
+	}
+
+	return chain, nil
+}
+
+// verifyGenesis checks the genesis operation is self-signed and hashes to the DID.
+func verifyGenesis(didID string, op *Operation) error {
+	if op.Type == TombstoneType || op.Prev != nil {
+		return errors.New("invalid genesis operation")
+	}
+
+	if _, err := op.verify(op.RotationKeys); err != nil {
+		return fmt.Errorf("genesis operation: %w", err)
+	}
+
+	genesisDID, err := op.DID()
+	if err != nil {
+		return err
+	}
+
+	if genesisDID != didID {
+		return fmt.Errorf("genesis operation hash does not match the did [%s]", didID)
+	}
+
+	return nil
+}
+
+func indexOf(chain []*logOperation, cid string) int {
+	for i, op := range chain {
+		if op.cid == cid {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,348 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package plc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	btcecdsa "github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/kms-go/doc/util/fingerprint"
+)
+
+type testKey struct {
+	didKey string
+	sign   func(digest []byte) []byte
+}
+
+func newK256Key(t *testing.T) *testKey {
+	t.Helper()
+
+	privateKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	return &testKey{
+		didKey: "did:key:" + fingerprint.KeyFingerprint(secp256k1PubKeyMultiCodec,
+			privateKey.PubKey().SerializeCompressed()),
+		sign: func(digest []byte) []byte {
+			return btcecdsa.SignCompact(privateKey, digest, true)[1:]
+		},
+	}
+}
+
+func newP256Key(t *testing.T) *testKey {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pub := elliptic.MarshalCompressed(elliptic.P256(), privateKey.X, privateKey.Y)
+
+	return &testKey{
+		didKey: "did:key:" + fingerprint.KeyFingerprint(fingerprint.P256PubKeyMultiCodec, pub),
+		sign: func(digest []byte) []byte {
+			r, s, err := ecdsa.Sign(rand.Reader, privateKey, digest)
+			require.NoError(t, err)
+
+			n := elliptic.P256().Params().N
+			if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+				s.Sub(n, s)
+			}
+
+			return append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+		},
+	}
+}
+
+// signedOp signs the operation with the key and returns its JSON form.
+func signedOp(t *testing.T, op map[string]interface{}, key *testKey) json.RawMessage {
+	t.Helper()
+
+	data, err := json.Marshal(op)
+	require.NoError(t, err)
+
+	var unsigned map[string]interface{}
+
+	require.NoError(t, json.Unmarshal(data, &unsigned))
+
+	block, err := marshalDAGCBOR(unsigned)
+	require.NoError(t, err)
+
+	digest := sha256.Sum256(block)
+	unsigned["sig"] = base64.RawURLEncoding.EncodeToString(key.sign(digest[:]))
+
+	data, err = json.Marshal(unsigned)
+	require.NoError(t, err)
+
+	return data
+}
+
+func newOp(rotationKeys []*testKey, signingKey *testKey, handle string, prev interface{}) map[string]interface{} {
+	keys := make([]string, len(rotationKeys))
+	for i, key := range rotationKeys {
+		keys[i] = key.didKey
+	}
+
+	return map[string]interface{}{
+		"type":                OperationType,
+		"rotationKeys":        keys,
+		"verificationMethods": map[string]string{"atproto": signingKey.didKey},
+		"alsoKnownAs":         []string{"at://" + handle},
+		"services": map[string]interface{}{
+			"atproto_pds": map[string]string{"type": PDSServiceType, "endpoint": "https://pds.example.com"},
+		},
+		"prev": prev,
+	}
+}
+
+// testLog builds an audit log from the signed operations.
+type testLog struct {
+	did     string
+	entries []*LogEntry
+	time    time.Time
+}
+
+func newTestLog(t *testing.T, genesis json.RawMessage) *testLog {
+	t.Helper()
+
+	op, err := parseOperation(genesis)
+	require.NoError(t, err)
+
+	didID, err := op.DID()
+	require.NoError(t, err)
+
+	l := &testLog{did: didID, time: time.Now().Add(-240 * time.Hour).UTC()}
+	l.add(t, genesis, 0)
+
+	return l
+}
+
+// add appends the operation, created after the given duration since the previous one, and returns its CID.
+func (l *testLog) add(t *testing.T, data json.RawMessage, after time.Duration) string {
+	t.Helper()
+
+	op, err := parseOperation(data)
+	require.NoError(t, err)
+
+	cid, err := op.CID()
+	require.NoError(t, err)
+
+	l.time = l.time.Add(after)
+	l.entries = append(l.entries, &LogEntry{DID: l.did, Operation: data, CID: cid, CreatedAt: l.time})
+
+	return cid
+}
+
+func (l *testLog) cid(i int) string {
+	return l.entries[i].CID
+}
+
+func TestVerifyLog(t *testing.T) {
+	t.Run("test updates", func(t *testing.T) {
+		rotation, signing := newK256Key(t), newP256Key(t)
+
+		l := newTestLog(t, signedOp(t, newOp([]*testKey{rotation}, signing, "alice.test", nil), rotation))
+		l.add(t, signedOp(t, newOp([]*testKey{rotation}, signing, "bob.test", l.cid(0)), rotation), time.Hour)
+
+		chain, err := verifyLog(l.did, l.entries)
+		require.NoError(t, err)
+		require.Len(t, chain, 2)
+		require.Equal(t, []string{"at://bob.test"}, chain[1].op.AlsoKnownAs)
+	})
+
+	t.Run("test p-256 rotation key", func(t *testing.T) {
+		rotation := newP256Key(t)
+
+		l := newTestLog(t, signedOp(t, newOp([]*testKey{rotation}, rotation, "alice.test", nil), rotation))
+		l.add(t, signedOp(t, newOp([]*testKey{rotation}, rotation, "bob.test", l.cid(0)), rotation), time.Hour)
+
+		_, err := verifyLog(l.did, l.entries)
+		require.NoError(t, err)
+	})
+
+	t.Run("test legacy create operation", func(t *testing.T) {
+		signing, recovery := newK256Key(t), newK256Key(t)
+
+		l := newTestLog(t, signedOp(t, map[string]interface{}{
+			"type":        LegacyCreateType,
+			"signingKey":  signing.didKey,
+			"recoveryKey": recovery.didKey,
+			"handle":      "alice.test",
+			"service":     "https://pds.example.com",
+			"prev":        nil,
+		}, signing))
+
+		chain, err := verifyLog(l.did, l.entries)
+		require.NoError(t, err)
+		require.Equal(t, []string{recovery.didKey, signing.didKey}, chain[0].op.RotationKeys)
+		require.Equal(t, "https://pds.example.com", chain[0].op.Services[pdsServiceID].Endpoint)
+	})
+
+	t.Run("test tombstone", func(t *testing.T) {
+		rotation := newK256Key(t)
+
+		l := newTestLog(t, signedOp(t, newOp([]*testKey{rotation}, rotation, "alice.test", nil), rotation))
+		tombstone := l.add(t, signedOp(t, map[string]interface{}{"type": TombstoneType, "prev": l.cid(0)}, rotation),
+			time.Hour)
+
+		chain, err := verifyLog(l.did, l.entries)
+		require.NoError(t, err)
+		require.Equal(t, TombstoneType, chain[1].op.Type)
+
+		l.add(t, signedOp(t, newOp([]*testKey{rotation}, rotation, "bob.test", tombstone), rotation), time.Hour)
+
+		_, err = verifyLog(l.did, l.entries)
+		require.ErrorContains(t, err, "follows a tombstone")
+	})
+
+	t.Run("test nullification", func(t *testing.T) {
+		recovery, rotation := newK256Key(t), newK256Key(t)
+		keys := []*testKey{recovery, rotation}
+
+		genesis := signedOp(t, newOp(keys, rotation, "alice.test", nil), rotation)
+
+		newLog := func(recoverAfter time.Duration, recoveryKey *testKey) *testLog {
+			l := newTestLog(t, genesis)
+			l.add(t, signedOp(t, newOp(keys, rotation, "mallory.test", l.cid(0)), rotation), time.Hour)
+			l.add(t, signedOp(t, newOp(keys, rotation, "alice.test", l.cid(0)), recoveryKey), recoverAfter)
+
+			return l
+		}
+
+		l := newLog(71*time.Hour, recovery)
+		l.entries[1].Nullified = true
+
+		chain, err := verifyLog(l.did, l.entries)
+		require.NoError(t, err)
+		require.Len(t, chain, 2)
+		require.Equal(t, []string{"at://alice.test"}, chain[1].op.AlsoKnownAs)
+
+		l.entries[1].Nullified = false
+
+		_, err = verifyLog(l.did, l.entries)
+		require.ErrorContains(t, err, "nullified flag does not match")
+
+		l = newLog(73*time.Hour, recovery)
+		l.entries[1].Nullified = true
+
+		_, err = verifyLog(l.did, l.entries)
+		require.ErrorContains(t, err, "nullification window")
+
+		l = newLog(time.Hour, rotation)
+		l.entries[1].Nullified = true
+
+		_, err = verifyLog(l.did, l.entries)
+		require.ErrorContains(t, err, "no authority to nullify")
+	})
+
+	t.Run("test invalid logs", func(t *testing.T) {
+		rotation, other := newK256Key(t), newK256Key(t)
+
+		genesis := signedOp(t, newOp([]*testKey{rotation}, rotation, "alice.test", nil), rotation)
+
+		_, err := verifyLog("did:plc:aaaaaaaaaaaaaaaaaaaaaaaa", nil)
+		require.ErrorContains(t, err, "empty log")
+
+		l := newTestLog(t, genesis)
+		l.add(t, signedOp(t, newOp([]*testKey{rotation}, rotation, "bob.test", l.cid(0)), other), time.Hour)
+
+		_, err = verifyLog(l.did, l.entries)
+		require.ErrorIs(t, err, ErrInvalidLog)
+		require.ErrorContains(t, err, "not signed by a rotation key")
+
+		l = newTestLog(t, genesis)
+		l.add(t, signedOp(t, newOp([]*testKey{rotation}, rotation, "bob.test", "bafyreiunknown"), rotation),
+			time.Hour)
+
+		_, err = verifyLog(l.did, l.entries)
+		require.ErrorContains(t, err, "unknown prev operation")
+
+		l = newTestLog(t, genesis)
+		l.add(t, signedOp(t, newOp([]*testKey{rotation}, rotation, "bob.test", nil), rotation), time.Hour)
+
+		_, err = verifyLog(l.did, l.entries)
+		require.ErrorContains(t, err, "missing prev operation")
+
+		l = newTestLog(t, genesis)
+		l.entries[0].CID = "bafyreiother"
+
+		_, err = verifyLog(l.did, l.entries)
+		require.ErrorContains(t, err, "does not match the operation")
+
+		l = newTestLog(t, genesis)
+
+		_, err = verifyLog("did:plc:aaaaaaaaaaaaaaaaaaaaaaaa", l.entries)
+		require.ErrorContains(t, err, "does not match")
+
+		l.entries[0].DID = "did:plc:aaaaaaaaaaaaaaaaaaaaaaaa"
+
+		_, err = verifyLog(l.entries[0].DID, l.entries)
+		require.ErrorContains(t, err, "genesis operation hash does not match the did")
+
+		l = newTestLog(t, signedOp(t, newOp([]*testKey{rotation}, rotation, "alice.test", nil), other))
+
+		_, err = verifyLog(l.did, l.entries)
+		require.ErrorContains(t, err, "genesis operation: operation is not signed by a rotation key")
+
+		l = newTestLog(t, genesis)
+		l.entries[0].Operation = json.RawMessage(`{"type":"unknown"}`)
+
+		_, err = verifyLog(l.did, l.entries)
+		require.ErrorContains(t, err, "unsupported operation type")
+	})
+}
+
+func TestVerifySignature(t *testing.T) {
+	key := newK256Key(t)
+	digest := sha256.Sum256([]byte("operation"))
+
+	sig := key.sign(digest[:])
+	require.True(t, verifySignature(key.didKey, digest[:], sig))
+
+	// The high-S form of the signature is rejected.
+	var s btcec.ModNScalar
+
+	s.SetByteSlice(sig[32:])
+	s.Negate()
+
+	highS := s.Bytes()
+	require.False(t, verifySignature(key.didKey, digest[:], append(sig[:32:32], highS[:]...)))
+
+	require.False(t, verifySignature("did:web:example.com", digest[:], sig))
+	require.False(t, verifySignature("did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK", digest[:], sig))
+}
+
+func TestMarshalDAGCBOR(t *testing.T) {
+	b, err := marshalDAGCBOR(map[string]interface{}{
+		"bb": []interface{}{true, false},
+		"c":  nil,
+		"a":  "x",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []byte{
+		0xa3,
+		0x61, 'a', 0x61, 'x',
+		0x61, 'c', 0xf6,
+		0x62, 'b', 'b', 0x82, 0xf5, 0xf4,
+	}, b)
+
+	b, err = marshalDAGCBOR(string(make([]byte, 300)))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x79, 0x01, 0x2c}, b[:3])
+
+	_, err = marshalDAGCBOR(1.5)
+	require.ErrorContains(t, err, "unsupported dag-cbor value")
+}
@@ -0,0 +1,209 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package plc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	btcecdsa "github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/trustbloc/kms-go/doc/util/fingerprint"
+)
+
+const (
+	// OperationType is the type of the operations creating and updating a DID.
+	OperationType = "plc_operation"
+	// TombstoneType is the type of the operation deactivating a DID.
+	TombstoneType = "plc_tombstone"
+	// LegacyCreateType is the type of the legacy genesis operations.
+	LegacyCreateType = "create"
+
+	// PDSServiceType is the type of the service of the AT Protocol Personal Data Server of a DID.
+	PDSServiceType = "AtprotoPersonalDataServer"
+
+	pdsServiceID = "atproto_pds"
+	atprotoKeyID = "atproto"
+
+	secp256k1PubKeyMultiCodec = 0xe7
+	signatureSize             = 64
+
+	// CIDv1 of a dag-cbor block with a sha2-256 multihash.
+	cidVersion      = 0x01
+	dagCBORCodec    = 0x71
+	sha256Multihash = 0x12
+
+	didSuffixSize = 24
+)
+
+var base32Lower = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// Service is a service of a PLC operation.
+type Service struct {
+	Type     string `json:"type"`
+	Endpoint string `json:"endpoint"`
+}
+
+// Operation is a signed PLC operation.
+type Operation struct {
+	Type                string             `json:"type"`
+	RotationKeys        []string           `json:"rotationKeys,omitempty"`
+	VerificationMethods map[string]string  `json:"verificationMethods,omitempty"`
+	AlsoKnownAs         []string           `json:"alsoKnownAs,omitempty"`
+	Services            map[string]Service `json:"services,omitempty"`
+	Prev                *string            `json:"prev"`
+	Sig                 string             `json:"sig"`
+
+	// Fields of the legacy create operations.
+	SigningKey  string `json:"signingKey,omitempty"`
+	RecoveryKey string `json:"recoveryKey,omitempty"`
+	Handle      string `json:"handle,omitempty"`
+	Service     string `json:"service,omitempty"`
+
+	raw map[string]interface{}
+}
+
+// parseOperation parses a signed operation, keeping its JSON form to hash and verify it.
+func parseOperation(data []byte) (*Operation, error) {
+	op := &Operation{}
+
+	if err := json.Unmarshal(data, op); err != nil {
+		return nil, fmt.Errorf("unmarshal operation: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &op.raw); err != nil {
+		return nil, fmt.Errorf("unmarshal operation: %w", err)
+	}
+
+	switch op.Type {
+	case OperationType, TombstoneType:
+	case LegacyCreateType:
+		if op.Prev != nil {
+			return nil, errors.New("legacy create operation with a prev operation")
+		}
+
+		op.normalize()
+	default:
+		return nil, fmt.Errorf("unsupported operation type [%s]", op.Type)
+	}
+
+	return op, nil
+}
+
+// normalize maps the fields of a legacy create operation to the fields of a plc_operation.
+func (op *Operation) normalize() {
+	op.RotationKeys = []string{op.RecoveryKey, op.SigningKey}
+	op.VerificationMethods = map[string]string{atprotoKeyID: op.SigningKey}
+	op.AlsoKnownAs = []string{"at://" + op.Handle}
+	op.Services = map[string]Service{pdsServiceID: {Type: PDSServiceType, Endpoint: op.Service}}
+}
+
+// CID returns the CID of the signed operation, which the prev field of the next operation refers to.
+func (op *Operation) CID() (string, error) {
+	block, err := marshalDAGCBOR(op.raw)
+	if err != nil {
+		return "", err
+	}
+
+	digest := sha256.Sum256(block)
+
+	cid := append([]byte{cidVersion, dagCBORCodec, sha256Multihash, byte(len(digest))}, digest[:]...)
+
+	return "b" + base32Lower.EncodeToString(cid), nil
+}
+
+// DID returns the DID created by the operation when it is the genesis operation.
+func (op *Operation) DID() (string, error) {
+	block, err := marshalDAGCBOR(op.raw)
+	if err != nil {
+		return "", err
+	}
+
+	digest := sha256.Sum256(block)
+
+	return "did:plc:" + base32Lower.EncodeToString(digest[:])[:didSuffixSize], nil
+}
+
+// verify checks the operation is signed by one of the rotation keys and returns the index of the key, the lower
+// the index the higher the authority of the key.
+func (op *Operation) verify(rotationKeys []string) (int, error) {
+	unsigned := make(map[string]interface{}, len(op.raw))
+
+	for key, value := range op.raw {
+		if key != "sig" {
+			unsigned[key] = value
+		}
+	}
+
+	block, err := marshalDAGCBOR(unsigned)
+	if err != nil {
+		return 0, err
+	}
+
+	digest := sha256.Sum256(block)
+
+	sig, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(op.Sig, "="))
+	if err != nil || len(sig) != signatureSize {
+		return 0, errors.New("invalid operation signature encoding")
+	}
+
+	for i, key := range rotationKeys {
+		if verifySignature(key, digest[:], sig) {
+			return i, nil
+		}
+	}
+
+	return 0, errors.New("operation is not signed by a rotation key")
+}
+
+// verifySignature verifies the low-S compact ECDSA signature of digest by a secp256k1 or P-256 did:key.
+func verifySignature(didKey string, digest, sig []byte) bool {
+	pub, code, err := fingerprint.PubKeyFromFingerprint(strings.TrimPrefix(didKey, "did:key:"))
+	if err != nil || !strings.HasPrefix(didKey, "did:key:") {
+		return false
+	}
+
+	switch code {
+	case secp256k1PubKeyMultiCodec:
+		pubKey, err := btcec.ParsePubKey(pub)
+		if err != nil {
+			return false
+		}
+
+		var r, s btcec.ModNScalar
+
+		if r.SetByteSlice(sig[:32]) || s.SetByteSlice(sig[32:]) || s.IsOverHalfOrder() { //nolint:mnd
+			return false
+		}
+
+		return btcecdsa.NewSignature(&r, &s).Verify(digest, pubKey)
+	case fingerprint.P256PubKeyMultiCodec:
+		x, y := elliptic.UnmarshalCompressed(elliptic.P256(), pub)
+		if x == nil {
+			return false
+		}
+
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:]) //nolint:mnd
+
+		halfOrder := new(big.Int).Rsh(elliptic.P256().Params().N, 1)
+		if s.Cmp(halfOrder) > 0 {
+			return false
+		}
+
+		return ecdsa.Verify(&ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, digest, r, s)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,196 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package plc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/multiformats/go-multibase"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/doc/did/endpoint"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	// ContextMultikeyV1 is the JSON-LD context of the Multikey verification methods.
+	ContextMultikeyV1 = "https://w3id.org/security/multikey/v1"
+
+	multikeyType = "Multikey"
+	didPrefix    = "did:" + DIDMethod + ":"
+)
+
+// Read resolves a did:plc DID.
+func (v *VDR) Read(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	return v.ReadContext(context.Background(), didID, opts...)
+}
+
+// ReadContext resolves a did:plc DID, the request to the PLC directory is bound to the given context.
+//
+// The audit log of the DID is fetched from the directory and verified, the DID document is built from the current
+// operation of the log: its verification methods in the Multikey form, its alsoKnownAs handles and its services,
+// such as the AtprotoPersonalDataServer service.
+func (v *VDR) ReadContext(ctx context.Context, didID string,
+	_ ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	if !validDID(didID) {
+		return nil, fmt.Errorf("error resolving did:plc did --> invalid did [%s]: %w", didID, vdrapi.ErrInvalidDID)
+	}
+
+	entries, err := v.fetchLog(ctx, didID)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving did:plc did --> %w", err)
+	}
+
+	chain, err := verifyLog(didID, entries)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving did:plc did --> %w", err)
+	}
+
+	current := chain[len(chain)-1]
+
+	metadata := &did.DocumentMetadata{
+		VersionID: current.cid,
+		Created:   chain[0].createdAt.UTC().Format(time.RFC3339Nano),
+		Updated:   current.createdAt.UTC().Format(time.RFC3339Nano),
+	}
+
+	if current.op.Type == TombstoneType {
+		metadata.Deactivated = true
+
+		return &did.DocResolution{
+			DIDDocument:        &did.Doc{Context: []string{did.ContextV1}, ID: didID},
+			DocumentMetadata:   metadata,
+			ResolutionMetadata: &did.ResolutionMetadata{ContentType: did.ContentTypeDIDJSON},
+		}, nil
+	}
+
+	doc, err := buildDoc(didID, current.op)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving did:plc did --> %w", err)
+	}
+
+	return &did.DocResolution{
+		DIDDocument:        doc,
+		DocumentMetadata:   metadata,
+		ResolutionMetadata: &did.ResolutionMetadata{ContentType: did.ContentTypeDIDJSON},
+	}, nil
+}
+
+// buildDoc builds the DID document of the operation.
+func buildDoc(didID string, op *Operation) (*did.Doc, error) {
+	doc := &did.Doc{
+		Context:     []string{did.ContextV1, ContextMultikeyV1},
+		ID:          didID,
+		AlsoKnownAs: op.AlsoKnownAs,
+	}
+
+	for _, name := range sortedKeys(op.VerificationMethods) {
+		didKey := op.VerificationMethods[name]
+
+		if !strings.HasPrefix(didKey, "did:key:z") {
+			return nil, fmt.Errorf("verification method %s is not a did:key [%s]", name, didKey)
+		}
+
+		// The Multikey value is the multicodec key of the did:key.
+		value := base58.Decode(strings.TrimPrefix(didKey, "did:key:z"))
+
+		doc.VerificationMethod = append(doc.VerificationMethod, *did.NewVerificationMethodFromBytesWithMultibase(
+			didID+"#"+name, multikeyType, didID, value, multibase.Base58BTC))
+	}
+
+	for _, name := range sortedKeys(op.Services) {
+		service := op.Services[name]
+
+		doc.Service = append(doc.Service, did.Service{
+			ID:              "#" + name,
+			Type:            service.Type,
+			ServiceEndpoint: endpoint.NewDIDCommV1Endpoint(service.Endpoint),
+		})
+	}
+
+	return doc, nil
+}
+
+// fetchLog fetches the audit log of the DID from the PLC directory.
+func (v *VDR) fetchLog(ctx context.Context, didID string) ([]*LogEntry, error) {
+	address := v.directoryURL + "/" + didID + "/log/audit"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http request failed: %w", err)
+	}
+
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			errorLogger.Printf("Failed to close response body: %v", closeErr)
+		}
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("audit log %s: %w", address, vdrapi.ErrNotFound)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("http request for %s returned status %d", address, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, v.maxLogSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read audit log: %w", err)
+	}
+
+	if int64(len(body)) > v.maxLogSize {
+		return nil, fmt.Errorf("audit log %s exceeds %d bytes", address, v.maxLogSize)
+	}
+
+	var entries []*LogEntry
+
+	if err = json.Unmarshal(body, &entries); err != nil {
+		return nil, fmt.Errorf("unmarshal audit log: %w", err)
+	}
+
+	return entries, nil
+}
+
+// validDID checks the DID is did:plc: followed by 24 base32 characters.
+func validDID(didID string) bool {
+	suffix, ok := strings.CutPrefix(didID, didPrefix)
+	if !ok || len(suffix) != didSuffixSize {
+		return false
+	}
+
+	for _, c := range suffix {
+		if (c < 'a' || c > 'z') && (c < '2' || c > '7') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
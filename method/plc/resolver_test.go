@@ -0,0 +1,147 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package plc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+// newDirectory starts a PLC directory stand-in serving the audit logs.
+func newDirectory(t *testing.T, logs ...*testLog) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, l := range logs {
+			if r.URL.Path == "/"+l.did+"/log/audit" {
+				w.Header().Set("Content-Type", "application/json")
+				require.NoError(t, json.NewEncoder(w).Encode(l.entries))
+
+				return
+			}
+		}
+
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestRead(t *testing.T) {
+	t.Run("test resolve did", func(t *testing.T) {
+		rotation, signing := newK256Key(t), newK256Key(t)
+
+		l := newTestLog(t, signedOp(t, newOp([]*testKey{rotation}, signing, "alice.test", nil), rotation))
+		l.add(t, signedOp(t, newOp([]*testKey{rotation}, signing, "alice.example.com", l.cid(0)), rotation),
+			time.Hour)
+
+		directory := newDirectory(t, l)
+
+		docResolution, err := New(WithDirectoryURL(directory.URL + "/")).Read(l.did)
+		require.NoError(t, err)
+
+		doc := docResolution.DIDDocument
+		require.Equal(t, l.did, doc.ID)
+		require.Equal(t, []string{did.ContextV1, ContextMultikeyV1}, doc.Context)
+		require.Equal(t, []string{"at://alice.example.com"}, doc.AlsoKnownAs)
+
+		require.Len(t, doc.VerificationMethod, 1)
+		require.Equal(t, l.did+"#atproto", doc.VerificationMethod[0].ID)
+		require.Equal(t, "Multikey", doc.VerificationMethod[0].Type)
+
+		require.Len(t, doc.Service, 1)
+		require.Equal(t, "#atproto_pds", doc.Service[0].ID)
+		require.Equal(t, PDSServiceType, doc.Service[0].Type)
+
+		uri, err := doc.Service[0].ServiceEndpoint.URI()
+		require.NoError(t, err)
+		require.Equal(t, "https://pds.example.com", uri)
+
+		docBytes, err := doc.JSONBytes()
+		require.NoError(t, err)
+		require.Contains(t, string(docBytes),
+			`"publicKeyMultibase":"`+strings.TrimPrefix(signing.didKey, "did:key:")+`"`)
+
+		require.Equal(t, l.cid(1), docResolution.DocumentMetadata.VersionID)
+		require.Equal(t, l.entries[0].CreatedAt.Format(time.RFC3339Nano), docResolution.DocumentMetadata.Created)
+		require.Equal(t, l.entries[1].CreatedAt.Format(time.RFC3339Nano), docResolution.DocumentMetadata.Updated)
+		require.False(t, docResolution.DocumentMetadata.Deactivated)
+	})
+
+	t.Run("test resolve deactivated did", func(t *testing.T) {
+		rotation := newK256Key(t)
+
+		l := newTestLog(t, signedOp(t, newOp([]*testKey{rotation}, rotation, "alice.test", nil), rotation))
+		l.add(t, signedOp(t, map[string]interface{}{"type": TombstoneType, "prev": l.cid(0)}, rotation), time.Hour)
+
+		docResolution, err := New(WithDirectoryURL(newDirectory(t, l).URL)).Read(l.did)
+		require.NoError(t, err)
+		require.True(t, docResolution.DocumentMetadata.Deactivated)
+		require.Empty(t, docResolution.DIDDocument.VerificationMethod)
+	})
+
+	t.Run("test resolution errors", func(t *testing.T) {
+		rotation := newK256Key(t)
+
+		l := newTestLog(t, signedOp(t, newOp([]*testKey{rotation}, rotation, "alice.test", nil), rotation))
+		directory := newDirectory(t, l)
+
+		for _, didID := range []string{"did:plc:short", "did:web:example.com", "did:plc:AAAAAAAAAAAAAAAAAAAAAAAA"} {
+			_, err := New(WithDirectoryURL(directory.URL)).Read(didID)
+			require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+		}
+
+		_, err := New(WithDirectoryURL(directory.URL)).Read("did:plc:aaaaaaaaaaaaaaaaaaaaaaaa")
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+
+		_, err = New(WithDirectoryURL(directory.URL), WithMaxLogSize(10)).Read(l.did)
+		require.ErrorContains(t, err, "exceeds 10 bytes")
+
+		l.entries[0].Nullified = true
+
+		_, err = New(WithDirectoryURL(directory.URL), WithHTTPClient(directory.Client())).Read(l.did)
+		require.ErrorIs(t, err, ErrInvalidLog)
+
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer failing.Close()
+
+		_, err = New(WithDirectoryURL(failing.URL)).Read(l.did)
+		require.ErrorContains(t, err, "returned status 500")
+
+		invalid := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("{"))
+		}))
+		defer invalid.Close()
+
+		_, err = New(WithDirectoryURL(invalid.URL)).Read(l.did)
+		require.ErrorContains(t, err, "unmarshal audit log")
+	})
+}
+
+func TestVDR(t *testing.T) {
+	v := New()
+	require.True(t, v.Accept(DIDMethod))
+	require.False(t, v.Accept("web"))
+	require.NoError(t, v.Close())
+
+	_, err := v.Create(&did.Doc{})
+	require.ErrorContains(t, err, "not supported")
+	require.ErrorContains(t, v.Update(&did.Doc{}), "not supported")
+	require.ErrorContains(t, v.Deactivate("did:plc:aaaaaaaaaaaaaaaaaaaaaaaa"), "not supported")
+}
@@ -0,0 +1,105 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package plc implements the did:plc method of the AT Protocol, resolving DIDs from a PLC directory and verifying
+// their operation log.
+package plc
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	diddoc "github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	// DIDMethod did method.
+	DIDMethod = "plc"
+
+	// DefaultDirectoryURL is the URL of the public PLC directory.
+	DefaultDirectoryURL = "https://plc.directory"
+
+	defaultMaxLogSize = 1 << 20
+	defaultTimeout    = 10 * time.Second
+)
+
+var errorLogger = log.New(os.Stderr, " [did-go/vdr/plc] ", log.Ldate|log.Ltime|log.LUTC)
+
+// VDR implements did:plc method support.
+type VDR struct {
+	directoryURL string
+	httpClient   *http.Client
+	maxLogSize   int64
+}
+
+// Option configures the did:plc vdr.
+type Option func(opts *VDR)
+
+// WithDirectoryURL sets the URL of the PLC directory the DIDs are resolved from, DefaultDirectoryURL by default.
+func WithDirectoryURL(directoryURL string) Option {
+	return func(opts *VDR) {
+		opts.directoryURL = strings.TrimSuffix(directoryURL, "/")
+	}
+}
+
+// WithHTTPClient sets the http client the PLC directory is requested with.
+func WithHTTPClient(client *http.Client) Option {
+	return func(opts *VDR) {
+		opts.httpClient = client
+	}
+}
+
+// WithMaxLogSize limits the size of the operation logs downloaded from the PLC directory, 1 MiB by default.
+func WithMaxLogSize(size int64) Option {
+	return func(opts *VDR) {
+		opts.maxLogSize = size
+	}
+}
+
+// New returns new instance of VDR that works with did:plc method.
+func New(opts ...Option) *VDR {
+	v := &VDR{
+		directoryURL: DefaultDirectoryURL,
+		httpClient:   &http.Client{Timeout: defaultTimeout},
+		maxLogSize:   defaultMaxLogSize,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// Accept accepts did:plc method.
+func (v *VDR) Accept(method string, opts ...vdrapi.DIDMethodOption) bool {
+	return method == DIDMethod
+}
+
+// Close frees resources being maintained by VDR.
+func (v *VDR) Close() error {
+	return nil
+}
+
+// Create did doc.
+func (v *VDR) Create(didDoc *diddoc.Doc, opts ...vdrapi.DIDMethodOption) (*diddoc.DocResolution, error) {
+	return nil, errors.New("not supported")
+}
+
+// Update did doc.
+func (v *VDR) Update(didDoc *diddoc.Doc, opts ...vdrapi.DIDMethodOption) error {
+	return errors.New("not supported")
+}
+
+// Deactivate did doc.
+func (v *VDR) Deactivate(didID string, opts ...vdrapi.DIDMethodOption) error {
+	return errors.New("not supported")
+}
@@ -0,0 +1,103 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sidetree
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+var errNoEndpoint = errors.New("sidetree endpoint is not configured")
+
+// sendRequest sends the operation request to the Sidetree node and returns the response body.
+func (v *VDR) sendRequest(ctx context.Context, request []byte) ([]byte, error) {
+	if v.endpointURL == "" {
+		return nil, errNoEndpoint
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimSuffix(v.endpointURL, "/")+"/operations", bytes.NewReader(request))
+	if err != nil {
+		return nil, fmt.Errorf("HTTP create post request failed: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP post request failed: %w", err)
+	}
+
+	defer closeResponseBody(resp.Body)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("sidetree operation failed [%d]: %s", resp.StatusCode, body)
+	}
+
+	return body, nil
+}
+
+// resolveDID resolves the DID with the Sidetree node.
+func (v *VDR) resolveDID(ctx context.Context, didID string) (*did.DocResolution, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		strings.TrimSuffix(v.endpointURL, "/")+"/identifiers/"+didID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("sidetree-vdr read: HTTP create get request failed: %w", err)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sidetree-vdr read: HTTP get request failed: %w", err)
+	}
+
+	defer closeResponseBody(resp.Body)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("sidetree-vdr read: reading response body failed: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("sidetree-vdr read: %s: %w", didID, vdrapi.ErrNotFound)
+	default:
+		return nil, fmt.Errorf("sidetree-vdr read: unsupported response from sidetree node [%d]: %s",
+			resp.StatusCode, body)
+	}
+
+	docResolution, err := did.ParseDocumentResolution(body)
+	if err != nil {
+		return nil, fmt.Errorf("sidetree-vdr read: parse document resolution: %w", err)
+	}
+
+	if docResolution.ResolutionMetadata == nil {
+		docResolution.ResolutionMetadata = &did.ResolutionMetadata{ContentType: did.ContentTypeDIDLDJSON}
+	}
+
+	return docResolution, nil
+}
+
+func closeResponseBody(respBody io.Closer) {
+	e := respBody.Close()
+	if e != nil {
+		errLogger.Printf("Failed to close response body: %v", e)
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sidetree
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/multiformats/go-multibase"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/method/sidetree/operation"
+)
+
+// sidetreeDocument converts the DID document to the Sidetree document of create and recover operations.
+func sidetreeDocument(didDoc *did.Doc) (*operation.Document, error) {
+	doc := &operation.Document{}
+	keys := make(map[string]*operation.PublicKey)
+
+	addKey := func(vm *did.VerificationMethod) (*operation.PublicKey, error) {
+		id := fragment(vm.ID)
+
+		if key, ok := keys[id]; ok {
+			return key, nil
+		}
+
+		key, err := publicKey(id, vm)
+		if err != nil {
+			return nil, err
+		}
+
+		keys[id] = key
+		doc.PublicKeys = append(doc.PublicKeys, key)
+
+		return key, nil
+	}
+
+	for i := range didDoc.VerificationMethod {
+		if _, err := addKey(&didDoc.VerificationMethod[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	relationships := []struct {
+		purpose       string
+		verifications []did.Verification
+	}{
+		{operation.PurposeAuthentication, didDoc.Authentication},
+		{operation.PurposeAssertionMethod, didDoc.AssertionMethod},
+		{operation.PurposeCapabilityInvocation, didDoc.CapabilityInvocation},
+		{operation.PurposeCapabilityDelegation, didDoc.CapabilityDelegation},
+		{operation.PurposeKeyAgreement, didDoc.KeyAgreement},
+	}
+
+	for _, r := range relationships {
+		for i := range r.verifications {
+			key, err := addKey(&r.verifications[i].VerificationMethod)
+			if err != nil {
+				return nil, err
+			}
+
+			key.Purposes = append(key.Purposes, r.purpose)
+		}
+	}
+
+	for i := range didDoc.Service {
+		s, err := service(&didDoc.Service[i])
+		if err != nil {
+			return nil, err
+		}
+
+		doc.Services = append(doc.Services, s)
+	}
+
+	return doc, nil
+}
+
+func publicKey(id string, vm *did.VerificationMethod) (*operation.PublicKey, error) {
+	key := &operation.PublicKey{ID: id, Type: vm.Type}
+
+	if vm.JSONWebKey() != nil {
+		jwkBytes, err := vm.JSONWebKey().MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("marshal public key '%s': %w", id, err)
+		}
+
+		if err = json.Unmarshal(jwkBytes, &key.PublicKeyJwk); err != nil {
+			return nil, fmt.Errorf("unmarshal public key '%s': %w", id, err)
+		}
+
+		return key, nil
+	}
+
+	if len(vm.Value) == 0 {
+		return nil, fmt.Errorf("public key '%s' has no value", id)
+	}
+
+	encoded, err := multibase.Encode(multibase.Base58BTC, vm.Value)
+	if err != nil {
+		return nil, fmt.Errorf("encode public key '%s': %w", id, err)
+	}
+
+	key.PublicKeyMultibase = encoded
+
+	return key, nil
+}
+
+func service(s *did.Service) (*operation.Service, error) {
+	serviceType, ok := s.Type.(string)
+	if !ok {
+		return nil, fmt.Errorf("service '%s' type must be a string", s.ID)
+	}
+
+	endpointBytes, err := json.Marshal(&s.ServiceEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("marshal service '%s' endpoint: %w", s.ID, err)
+	}
+
+	var endpoint interface{}
+
+	if err = json.Unmarshal(endpointBytes, &endpoint); err != nil {
+		return nil, fmt.Errorf("unmarshal service '%s' endpoint: %w", s.ID, err)
+	}
+
+	return &operation.Service{ID: fragment(s.ID), Type: serviceType, ServiceEndpoint: endpoint}, nil
+}
+
+// fragment returns the fragment of a DID URL or relative reference.
+func fragment(id string) string {
+	if i := strings.LastIndex(id, "#"); i >= 0 {
+		return id[i+1:]
+	}
+
+	return id
+}
@@ -0,0 +1,109 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+
+	"github.com/trustbloc/did-go/pkg/canonicalizer"
+)
+
+// sha2-256 multihash code, the only hash algorithm of the Sidetree default protocol parameters.
+const sha256MultihashCode = 0x12
+
+// CanonicalHash returns the encoded multihash of the JCS canonicalized value.
+func CanonicalHash(value interface{}) (string, error) {
+	canonicalBytes, err := canonicalizer.MarshalCanonical(value)
+	if err != nil {
+		return "", fmt.Errorf("marshal canonical: %w", err)
+	}
+
+	return EncodedMultihash(canonicalBytes), nil
+}
+
+// EncodedMultihash returns the base64url encoded sha2-256 multihash of data.
+func EncodedMultihash(data []byte) string {
+	digest := sha256.Sum256(data)
+
+	mh := binary.AppendUvarint(nil, sha256MultihashCode)
+	mh = binary.AppendUvarint(mh, uint64(len(digest)))
+	mh = append(mh, digest[:]...)
+
+	return base64.RawURLEncoding.EncodeToString(mh)
+}
+
+// ValidateEncodedMultihash checks that value is a base64url encoded sha2-256 multihash.
+func ValidateEncodedMultihash(value string) error {
+	mh, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return fmt.Errorf("decode multihash: %w", err)
+	}
+
+	code, n := binary.Uvarint(mh)
+	if n <= 0 {
+		return errors.New("invalid multihash code")
+	}
+
+	if code != sha256MultihashCode {
+		return fmt.Errorf("multihash code %d not supported", code)
+	}
+
+	length, m := binary.Uvarint(mh[n:])
+	if m <= 0 || length != sha256.Size || len(mh[n+m:]) != sha256.Size {
+		return errors.New("invalid multihash length")
+	}
+
+	return nil
+}
+
+// RevealValue returns the reveal value of the public key, which is the encoded multihash of the JCS canonicalized
+// public key.
+func RevealValue(key *jwk.JWK) (string, error) {
+	canonicalBytes, err := canonicalKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return EncodedMultihash(canonicalBytes), nil
+}
+
+// Commitment returns the commitment of the public key, which is the encoded multihash of the hash of the JCS
+// canonicalized public key.
+func Commitment(key *jwk.JWK) (string, error) {
+	canonicalBytes, err := canonicalKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	digest := sha256.Sum256(canonicalBytes)
+
+	return EncodedMultihash(digest[:]), nil
+}
+
+func canonicalKey(key *jwk.JWK) ([]byte, error) {
+	if key == nil {
+		return nil, errors.New("missing public key")
+	}
+
+	keyBytes, err := key.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("marshal key: %w", err)
+	}
+
+	canonicalBytes, err := canonicalizer.MarshalCanonical(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("marshal canonical: %w", err)
+	}
+
+	return canonicalBytes, nil
+}
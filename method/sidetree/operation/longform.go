@@ -0,0 +1,100 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/did-go/pkg/canonicalizer"
+)
+
+// LongFormDID returns the long-form DID of a create operation for the given method, which may include
+// a network segment (e.g. "ion:test").
+func LongFormDID(method string, suffixData *SuffixData, delta *Delta) (string, error) {
+	suffix, err := CanonicalHash(suffixData)
+	if err != nil {
+		return "", fmt.Errorf("did suffix: %w", err)
+	}
+
+	stateBytes, err := canonicalizer.MarshalCanonical(&InitialState{SuffixData: suffixData, Delta: delta})
+	if err != nil {
+		return "", fmt.Errorf("marshal canonical initial state: %w", err)
+	}
+
+	return "did:" + method + ":" + suffix + ":" + base64.RawURLEncoding.EncodeToString(stateBytes), nil
+}
+
+// ParseLongForm decodes the initial state of a long-form DID and validates it against the DID suffix.
+func ParseLongForm(suffix, encoded string) (*InitialState, error) {
+	stateBytes, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode long-form initial state: %w", err)
+	}
+
+	canonicalBytes, err := canonicalizer.MarshalCanonical(stateBytes)
+	if err != nil {
+		return nil, fmt.Errorf("canonicalize long-form initial state: %w", err)
+	}
+
+	if !bytes.Equal(stateBytes, canonicalBytes) {
+		return nil, errors.New("long-form initial state is not JCS canonicalized")
+	}
+
+	raw := struct {
+		SuffixData json.RawMessage `json:"suffixData"`
+		Delta      json.RawMessage `json:"delta"`
+	}{}
+
+	if err = json.Unmarshal(stateBytes, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal long-form initial state: %w", err)
+	}
+
+	if len(raw.SuffixData) == 0 || len(raw.Delta) == 0 {
+		return nil, errors.New("long-form initial state must contain suffix data and delta")
+	}
+
+	initialState := &InitialState{}
+
+	if err = json.Unmarshal(stateBytes, initialState); err != nil {
+		return nil, fmt.Errorf("unmarshal long-form initial state: %w", err)
+	}
+
+	if err = verifyHash(raw.SuffixData, suffix); err != nil {
+		return nil, fmt.Errorf("did suffix does not match suffix data: %w", err)
+	}
+
+	if err = verifyHash(raw.Delta, initialState.SuffixData.DeltaHash); err != nil {
+		return nil, fmt.Errorf("delta hash does not match delta: %w", err)
+	}
+
+	if err = ValidateEncodedMultihash(initialState.SuffixData.RecoveryCommitment); err != nil {
+		return nil, fmt.Errorf("invalid recovery commitment: %w", err)
+	}
+
+	if err = ValidateEncodedMultihash(initialState.Delta.UpdateCommitment); err != nil {
+		return nil, fmt.Errorf("invalid update commitment: %w", err)
+	}
+
+	return initialState, nil
+}
+
+func verifyHash(value json.RawMessage, expected string) error {
+	hash, err := CanonicalHash([]byte(value))
+	if err != nil {
+		return err
+	}
+
+	if hash != expected {
+		return fmt.Errorf("expected %s, got %s", expected, hash)
+	}
+
+	return nil
+}
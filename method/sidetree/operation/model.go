@@ -0,0 +1,154 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import "github.com/trustbloc/kms-go/doc/jose/jwk"
+
+// Patch actions.
+const (
+	// PatchAddPublicKeys adds public keys to the document.
+	PatchAddPublicKeys = "add-public-keys"
+	// PatchRemovePublicKeys removes public keys from the document.
+	PatchRemovePublicKeys = "remove-public-keys"
+	// PatchAddServices adds services to the document.
+	PatchAddServices = "add-services"
+	// PatchRemoveServices removes services from the document.
+	PatchRemoveServices = "remove-services"
+	// PatchReplace replaces the whole document.
+	PatchReplace = "replace"
+)
+
+// Public key purposes.
+const (
+	// PurposeAuthentication is the authentication verification relationship.
+	PurposeAuthentication = "authentication"
+	// PurposeAssertionMethod is the assertionMethod verification relationship.
+	PurposeAssertionMethod = "assertionMethod"
+	// PurposeCapabilityInvocation is the capabilityInvocation verification relationship.
+	PurposeCapabilityInvocation = "capabilityInvocation"
+	// PurposeCapabilityDelegation is the capabilityDelegation verification relationship.
+	PurposeCapabilityDelegation = "capabilityDelegation"
+	// PurposeKeyAgreement is the keyAgreement verification relationship.
+	PurposeKeyAgreement = "keyAgreement"
+)
+
+// InitialState is the create operation data encoded in a long-form DID.
+type InitialState struct {
+	SuffixData *SuffixData `json:"suffixData"`
+	Delta      *Delta      `json:"delta"`
+}
+
+// SuffixData is the create operation data hashed into the DID suffix.
+type SuffixData struct {
+	DeltaHash          string `json:"deltaHash"`
+	RecoveryCommitment string `json:"recoveryCommitment"`
+	Type               string `json:"type,omitempty"`
+	AnchorOrigin       string `json:"anchorOrigin,omitempty"`
+}
+
+// Delta holds the patches of an operation and the commitment for the next update.
+type Delta struct {
+	Patches          []*Patch `json:"patches"`
+	UpdateCommitment string   `json:"updateCommitment"`
+}
+
+// Patch is a document patch of an operation delta.
+type Patch struct {
+	Action     string       `json:"action"`
+	PublicKeys []*PublicKey `json:"publicKeys,omitempty"`
+	Services   []*Service   `json:"services,omitempty"`
+	IDs        []string     `json:"ids,omitempty"`
+	Document   *Document    `json:"document,omitempty"`
+}
+
+// Document is the document of a replace patch.
+type Document struct {
+	PublicKeys []*PublicKey `json:"publicKeys,omitempty"`
+	Services   []*Service   `json:"services,omitempty"`
+}
+
+// PublicKey is a Sidetree document public key.
+type PublicKey struct {
+	ID                 string                 `json:"id"`
+	Type               string                 `json:"type"`
+	Controller         string                 `json:"controller,omitempty"`
+	PublicKeyJwk       map[string]interface{} `json:"publicKeyJwk,omitempty"`
+	PublicKeyMultibase string                 `json:"publicKeyMultibase,omitempty"`
+	Purposes           []string               `json:"purposes,omitempty"`
+}
+
+// Service is a Sidetree document service.
+type Service struct {
+	ID              string      `json:"id"`
+	Type            string      `json:"type"`
+	ServiceEndpoint interface{} `json:"serviceEndpoint"`
+}
+
+// Operation types.
+const (
+	// TypeCreate is the create operation type.
+	TypeCreate = "create"
+	// TypeUpdate is the update operation type.
+	TypeUpdate = "update"
+	// TypeRecover is the recover operation type.
+	TypeRecover = "recover"
+	// TypeDeactivate is the deactivate operation type.
+	TypeDeactivate = "deactivate"
+)
+
+// CreateRequest is the create operation request.
+type CreateRequest struct {
+	Type       string      `json:"type"`
+	SuffixData *SuffixData `json:"suffixData"`
+	Delta      *Delta      `json:"delta"`
+}
+
+// UpdateRequest is the update operation request.
+type UpdateRequest struct {
+	Type        string `json:"type"`
+	DIDSuffix   string `json:"didSuffix"`
+	RevealValue string `json:"revealValue"`
+	Delta       *Delta `json:"delta"`
+	SignedData  string `json:"signedData"`
+}
+
+// RecoverRequest is the recover operation request.
+type RecoverRequest struct {
+	Type        string `json:"type"`
+	DIDSuffix   string `json:"didSuffix"`
+	RevealValue string `json:"revealValue"`
+	Delta       *Delta `json:"delta"`
+	SignedData  string `json:"signedData"`
+}
+
+// DeactivateRequest is the deactivate operation request.
+type DeactivateRequest struct {
+	Type        string `json:"type"`
+	DIDSuffix   string `json:"didSuffix"`
+	RevealValue string `json:"revealValue"`
+	SignedData  string `json:"signedData"`
+}
+
+// UpdateSignedData is the payload of the update operation signed data.
+type UpdateSignedData struct {
+	UpdateKey *jwk.JWK `json:"updateKey"`
+	DeltaHash string   `json:"deltaHash"`
+}
+
+// RecoverSignedData is the payload of the recover operation signed data.
+type RecoverSignedData struct {
+	RecoveryCommitment string   `json:"recoveryCommitment"`
+	RecoveryKey        *jwk.JWK `json:"recoveryKey"`
+	DeltaHash          string   `json:"deltaHash"`
+	AnchorOrigin       string   `json:"anchorOrigin,omitempty"`
+}
+
+// DeactivateSignedData is the payload of the deactivate operation signed data.
+type DeactivateSignedData struct {
+	DIDSuffix   string   `json:"didSuffix"`
+	RecoveryKey *jwk.JWK `json:"recoveryKey"`
+}
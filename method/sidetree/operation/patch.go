@@ -0,0 +1,224 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+const maxServiceTypeLength = 30
+
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,50}$`) //nolint:gochecknoglobals
+
+var purposes = map[string]bool{ //nolint:gochecknoglobals
+	PurposeAuthentication:       true,
+	PurposeAssertionMethod:      true,
+	PurposeCapabilityInvocation: true,
+	PurposeCapabilityDelegation: true,
+	PurposeKeyAgreement:         true,
+}
+
+// ApplyPatches applies the patches to a copy of the document and returns the patched document.
+func ApplyPatches(doc *Document, patches []*Patch) (*Document, error) {
+	patched := &Document{
+		PublicKeys: append([]*PublicKey(nil), doc.PublicKeys...),
+		Services:   append([]*Service(nil), doc.Services...),
+	}
+
+	for _, patch := range patches {
+		if patch == nil {
+			return nil, errors.New("missing patch")
+		}
+
+		if err := applyPatch(patched, patch); err != nil {
+			return nil, fmt.Errorf("apply %s patch: %w", patch.Action, err)
+		}
+	}
+
+	return patched, nil
+}
+
+func applyPatch(doc *Document, patch *Patch) error {
+	switch patch.Action {
+	case PatchAddPublicKeys:
+		if err := validatePublicKeys(patch.PublicKeys); err != nil {
+			return err
+		}
+
+		for _, key := range patch.PublicKeys {
+			doc.PublicKeys = append(removePublicKey(doc.PublicKeys, key.ID), key)
+		}
+	case PatchRemovePublicKeys:
+		if err := validateIDs(patch.IDs); err != nil {
+			return err
+		}
+
+		for _, id := range patch.IDs {
+			doc.PublicKeys = removePublicKey(doc.PublicKeys, id)
+		}
+	case PatchAddServices:
+		if err := validateServices(patch.Services); err != nil {
+			return err
+		}
+
+		for _, service := range patch.Services {
+			doc.Services = append(removeService(doc.Services, service.ID), service)
+		}
+	case PatchRemoveServices:
+		if err := validateIDs(patch.IDs); err != nil {
+			return err
+		}
+
+		for _, id := range patch.IDs {
+			doc.Services = removeService(doc.Services, id)
+		}
+	case PatchReplace:
+		if patch.Document == nil {
+			return errors.New("missing document")
+		}
+
+		if err := validatePublicKeys(patch.Document.PublicKeys); err != nil {
+			return err
+		}
+
+		if err := validateServices(patch.Document.Services); err != nil {
+			return err
+		}
+
+		doc.PublicKeys = append([]*PublicKey(nil), patch.Document.PublicKeys...)
+		doc.Services = append([]*Service(nil), patch.Document.Services...)
+	default:
+		return fmt.Errorf("action '%s' is not supported", patch.Action)
+	}
+
+	return nil
+}
+
+func validatePublicKeys(keys []*PublicKey) error {
+	ids := make(map[string]bool)
+
+	for _, key := range keys {
+		if key == nil {
+			return errors.New("missing public key")
+		}
+
+		if err := validateID(key.ID, ids); err != nil {
+			return fmt.Errorf("public key: %w", err)
+		}
+
+		if key.Type == "" {
+			return fmt.Errorf("public key '%s': missing type", key.ID)
+		}
+
+		if (key.PublicKeyJwk == nil) == (key.PublicKeyMultibase == "") {
+			return fmt.Errorf("public key '%s': exactly one of publicKeyJwk or publicKeyMultibase is required", key.ID)
+		}
+
+		if _, ok := key.PublicKeyJwk["d"]; ok {
+			return fmt.Errorf("public key '%s': publicKeyJwk must not contain a private key", key.ID)
+		}
+
+		seen := make(map[string]bool)
+
+		for _, purpose := range key.Purposes {
+			if !purposes[purpose] || seen[purpose] {
+				return fmt.Errorf("public key '%s': invalid purpose '%s'", key.ID, purpose)
+			}
+
+			seen[purpose] = true
+		}
+	}
+
+	return nil
+}
+
+func validateServices(services []*Service) error {
+	ids := make(map[string]bool)
+
+	for _, service := range services {
+		if service == nil {
+			return errors.New("missing service")
+		}
+
+		if err := validateID(service.ID, ids); err != nil {
+			return fmt.Errorf("service: %w", err)
+		}
+
+		if service.Type == "" || len(service.Type) > maxServiceTypeLength {
+			return fmt.Errorf("service '%s': service type must be 1 to %d characters", service.ID,
+				maxServiceTypeLength)
+		}
+
+		switch endpoint := service.ServiceEndpoint.(type) {
+		case string:
+			if _, err := url.ParseRequestURI(endpoint); err != nil {
+				return fmt.Errorf("service '%s': invalid service endpoint: %w", service.ID, err)
+			}
+		case map[string]interface{}:
+			if len(endpoint) == 0 {
+				return fmt.Errorf("service '%s': empty service endpoint", service.ID)
+			}
+		default:
+			return fmt.Errorf("service '%s': service endpoint must be a URI or an object", service.ID)
+		}
+	}
+
+	return nil
+}
+
+func validateIDs(ids []string) error {
+	seen := make(map[string]bool)
+
+	for _, id := range ids {
+		if err := validateID(id, seen); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateID(id string, seen map[string]bool) error {
+	if !idPattern.MatchString(id) {
+		return fmt.Errorf("id '%s' must be 1 to 50 base64url characters", id)
+	}
+
+	if seen[id] {
+		return fmt.Errorf("duplicate id '%s'", id)
+	}
+
+	seen[id] = true
+
+	return nil
+}
+
+func removePublicKey(keys []*PublicKey, id string) []*PublicKey {
+	var result []*PublicKey
+
+	for _, key := range keys {
+		if key.ID != id {
+			result = append(result, key)
+		}
+	}
+
+	return result
+}
+
+func removeService(services []*Service, id string) []*Service {
+	var result []*Service
+
+	for _, service := range services {
+		if service.ID != id {
+			result = append(result, service)
+		}
+	}
+
+	return result
+}
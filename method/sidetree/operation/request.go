@@ -0,0 +1,274 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+
+	"github.com/trustbloc/did-go/pkg/canonicalizer"
+)
+
+// Signer signs the signed data of update, recover and deactivate operations.
+type Signer interface {
+	// Sign signs the JWS signing input.
+	Sign(data []byte) ([]byte, error)
+	// Algorithm returns the JWS "alg" of the signature, e.g. ES256K.
+	Algorithm() string
+}
+
+// CreateRequestInfo contains data for creating a create request.
+type CreateRequestInfo struct {
+	// Patches of the initial document, usually a single replace patch.
+	Patches []*Patch
+	// RecoveryCommitment is the commitment of the key for the next recover or deactivate operation.
+	RecoveryCommitment string
+	// UpdateCommitment is the commitment of the key for the next update operation.
+	UpdateCommitment string
+	// AnchorOrigin is optional anchor origin.
+	AnchorOrigin string
+	// Type is optional type of the DID.
+	Type string
+}
+
+// UpdateRequestInfo contains data for creating an update request.
+type UpdateRequestInfo struct {
+	// DIDSuffix is the suffix of the updated DID.
+	DIDSuffix string
+	// Patches to apply to the document.
+	Patches []*Patch
+	// UpdateKey is the public key matching the current update commitment, it is revealed by the request.
+	UpdateKey *jwk.JWK
+	// UpdateCommitment is the commitment of the key for the next update operation.
+	UpdateCommitment string
+	// Signer signs with the private key of UpdateKey.
+	Signer Signer
+}
+
+// RecoverRequestInfo contains data for creating a recover request.
+type RecoverRequestInfo struct {
+	// DIDSuffix is the suffix of the recovered DID.
+	DIDSuffix string
+	// Patches of the recovered document, usually a single replace patch.
+	Patches []*Patch
+	// RecoveryKey is the public key matching the current recovery commitment, it is revealed by the request.
+	RecoveryKey *jwk.JWK
+	// RecoveryCommitment is the commitment of the key for the next recover or deactivate operation.
+	RecoveryCommitment string
+	// UpdateCommitment is the commitment of the key for the next update operation.
+	UpdateCommitment string
+	// AnchorOrigin is optional anchor origin.
+	AnchorOrigin string
+	// Signer signs with the private key of RecoveryKey.
+	Signer Signer
+}
+
+// DeactivateRequestInfo contains data for creating a deactivate request.
+type DeactivateRequestInfo struct {
+	// DIDSuffix is the suffix of the deactivated DID.
+	DIDSuffix string
+	// RecoveryKey is the public key matching the current recovery commitment, it is revealed by the request.
+	RecoveryKey *jwk.JWK
+	// Signer signs with the private key of RecoveryKey.
+	Signer Signer
+}
+
+// NewCreateRequest returns the create request JSON.
+func NewCreateRequest(info *CreateRequestInfo) ([]byte, error) {
+	request, err := newCreateRequest(info)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(request)
+}
+
+// NewCreateLongFormDID returns the long-form DID of the create request built from info.
+func NewCreateLongFormDID(method string, info *CreateRequestInfo) (string, error) {
+	request, err := newCreateRequest(info)
+	if err != nil {
+		return "", err
+	}
+
+	return LongFormDID(method, request.SuffixData, request.Delta)
+}
+
+func newCreateRequest(info *CreateRequestInfo) (*CreateRequest, error) {
+	if err := ValidateEncodedMultihash(info.RecoveryCommitment); err != nil {
+		return nil, fmt.Errorf("invalid recovery commitment: %w", err)
+	}
+
+	delta, deltaHash, err := newDelta(info.Patches, info.UpdateCommitment)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateRequest{
+		Type: TypeCreate,
+		SuffixData: &SuffixData{
+			DeltaHash:          deltaHash,
+			RecoveryCommitment: info.RecoveryCommitment,
+			Type:               info.Type,
+			AnchorOrigin:       info.AnchorOrigin,
+		},
+		Delta: delta,
+	}, nil
+}
+
+// NewUpdateRequest returns the signed update request JSON.
+func NewUpdateRequest(info *UpdateRequestInfo) ([]byte, error) {
+	if info.DIDSuffix == "" {
+		return nil, errors.New("missing did suffix")
+	}
+
+	if info.Signer == nil {
+		return nil, errors.New("missing signer")
+	}
+
+	revealValue, err := RevealValue(info.UpdateKey)
+	if err != nil {
+		return nil, fmt.Errorf("update key: %w", err)
+	}
+
+	delta, deltaHash, err := newDelta(info.Patches, info.UpdateCommitment)
+	if err != nil {
+		return nil, err
+	}
+
+	signedData, err := signData(&UpdateSignedData{UpdateKey: info.UpdateKey, DeltaHash: deltaHash}, info.Signer)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&UpdateRequest{
+		Type:        TypeUpdate,
+		DIDSuffix:   info.DIDSuffix,
+		RevealValue: revealValue,
+		Delta:       delta,
+		SignedData:  signedData,
+	})
+}
+
+// NewRecoverRequest returns the signed recover request JSON.
+func NewRecoverRequest(info *RecoverRequestInfo) ([]byte, error) {
+	if info.DIDSuffix == "" {
+		return nil, errors.New("missing did suffix")
+	}
+
+	if info.Signer == nil {
+		return nil, errors.New("missing signer")
+	}
+
+	if err := ValidateEncodedMultihash(info.RecoveryCommitment); err != nil {
+		return nil, fmt.Errorf("invalid recovery commitment: %w", err)
+	}
+
+	revealValue, err := RevealValue(info.RecoveryKey)
+	if err != nil {
+		return nil, fmt.Errorf("recovery key: %w", err)
+	}
+
+	delta, deltaHash, err := newDelta(info.Patches, info.UpdateCommitment)
+	if err != nil {
+		return nil, err
+	}
+
+	signedData, err := signData(&RecoverSignedData{
+		RecoveryCommitment: info.RecoveryCommitment,
+		RecoveryKey:        info.RecoveryKey,
+		DeltaHash:          deltaHash,
+		AnchorOrigin:       info.AnchorOrigin,
+	}, info.Signer)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&RecoverRequest{
+		Type:        TypeRecover,
+		DIDSuffix:   info.DIDSuffix,
+		RevealValue: revealValue,
+		Delta:       delta,
+		SignedData:  signedData,
+	})
+}
+
+// NewDeactivateRequest returns the signed deactivate request JSON.
+func NewDeactivateRequest(info *DeactivateRequestInfo) ([]byte, error) {
+	if info.DIDSuffix == "" {
+		return nil, errors.New("missing did suffix")
+	}
+
+	if info.Signer == nil {
+		return nil, errors.New("missing signer")
+	}
+
+	revealValue, err := RevealValue(info.RecoveryKey)
+	if err != nil {
+		return nil, fmt.Errorf("recovery key: %w", err)
+	}
+
+	signedData, err := signData(&DeactivateSignedData{
+		DIDSuffix:   info.DIDSuffix,
+		RecoveryKey: info.RecoveryKey,
+	}, info.Signer)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&DeactivateRequest{
+		Type:        TypeDeactivate,
+		DIDSuffix:   info.DIDSuffix,
+		RevealValue: revealValue,
+		SignedData:  signedData,
+	})
+}
+
+// newDelta validates the patches and returns the delta with its hash.
+func newDelta(patches []*Patch, updateCommitment string) (*Delta, string, error) {
+	if err := ValidateEncodedMultihash(updateCommitment); err != nil {
+		return nil, "", fmt.Errorf("invalid update commitment: %w", err)
+	}
+
+	if _, err := ApplyPatches(&Document{}, patches); err != nil {
+		return nil, "", err
+	}
+
+	delta := &Delta{Patches: patches, UpdateCommitment: updateCommitment}
+
+	deltaHash, err := CanonicalHash(delta)
+	if err != nil {
+		return nil, "", fmt.Errorf("delta hash: %w", err)
+	}
+
+	return delta, deltaHash, nil
+}
+
+// signData returns the compact JWS of the JCS canonicalized signed data.
+func signData(signedData interface{}, signer Signer) (string, error) {
+	payload, err := canonicalizer.MarshalCanonical(signedData)
+	if err != nil {
+		return "", fmt.Errorf("marshal canonical signed data: %w", err)
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": signer.Algorithm()})
+	if err != nil {
+		return "", fmt.Errorf("marshal jws header: %w", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	signature, err := signer.Sign([]byte(signingInput))
+	if err != nil {
+		return "", fmt.Errorf("sign data: %w", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
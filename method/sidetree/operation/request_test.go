@@ -0,0 +1,310 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+)
+
+type mockSigner struct {
+	err error
+}
+
+func (s *mockSigner) Sign(data []byte) ([]byte, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return []byte("signature"), nil
+}
+
+func (s *mockSigner) Algorithm() string {
+	return "ES256"
+}
+
+func newKey(t *testing.T) *jwk.JWK {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	key, err := jwksupport.JWKFromKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+
+	return key
+}
+
+func newCommitment(t *testing.T) string {
+	t.Helper()
+
+	commitment, err := Commitment(newKey(t))
+	require.NoError(t, err)
+
+	return commitment
+}
+
+func replacePatch() []*Patch {
+	return []*Patch{{
+		Action: PatchReplace,
+		Document: &Document{
+			PublicKeys: []*PublicKey{{
+				ID:                 "key-1",
+				Type:               "Ed25519VerificationKey2020",
+				PublicKeyMultibase: "z6MkpTHR8VNsBxYAAWHut2Geadd9jSwuBV8xRoAnwWsdvktH",
+				Purposes:           []string{PurposeAuthentication},
+			}},
+		},
+	}}
+}
+
+// parseSignedData checks the compact JWS and returns its payload.
+func parseSignedData(t *testing.T, signedData string, payload interface{}) {
+	t.Helper()
+
+	parts := strings.Split(signedData, ".")
+	require.Len(t, parts, 3)
+
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"alg":"ES256"}`, string(header))
+
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(payloadBytes, payload))
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	require.Equal(t, "signature", string(signature))
+}
+
+func TestCommitment(t *testing.T) {
+	key := newKey(t)
+
+	revealValue, err := RevealValue(key)
+	require.NoError(t, err)
+	require.NoError(t, ValidateEncodedMultihash(revealValue))
+
+	commitment, err := Commitment(key)
+	require.NoError(t, err)
+	require.NoError(t, ValidateEncodedMultihash(commitment))
+
+	// the commitment is the multihash of the digest of the reveal value.
+	mh, err := base64.RawURLEncoding.DecodeString(revealValue)
+	require.NoError(t, err)
+	require.Equal(t, commitment, EncodedMultihash(mh[2:]))
+
+	_, err = Commitment(nil)
+	require.EqualError(t, err, "missing public key")
+
+	_, err = RevealValue(nil)
+	require.EqualError(t, err, "missing public key")
+}
+
+func TestNewCreateRequest(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		info := &CreateRequestInfo{
+			Patches:            replacePatch(),
+			RecoveryCommitment: newCommitment(t),
+			UpdateCommitment:   newCommitment(t),
+			AnchorOrigin:       "https://example.com",
+		}
+
+		requestBytes, err := NewCreateRequest(info)
+		require.NoError(t, err)
+
+		request := &CreateRequest{}
+		require.NoError(t, json.Unmarshal(requestBytes, request))
+		require.Equal(t, TypeCreate, request.Type)
+		require.Equal(t, info.RecoveryCommitment, request.SuffixData.RecoveryCommitment)
+		require.Equal(t, info.UpdateCommitment, request.Delta.UpdateCommitment)
+
+		deltaHash, err := CanonicalHash(request.Delta)
+		require.NoError(t, err)
+		require.Equal(t, deltaHash, request.SuffixData.DeltaHash)
+
+		longForm, err := NewCreateLongFormDID("ion", info)
+		require.NoError(t, err)
+
+		suffix, err := CanonicalHash(request.SuffixData)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(longForm, "did:ion:"+suffix+":"))
+
+		initialState, err := ParseLongForm(suffix, strings.TrimPrefix(longForm, "did:ion:"+suffix+":"))
+		require.NoError(t, err)
+		require.Equal(t, request.SuffixData, initialState.SuffixData)
+	})
+
+	t.Run("test invalid commitments and patches", func(t *testing.T) {
+		_, err := NewCreateRequest(&CreateRequestInfo{
+			Patches:            replacePatch(),
+			RecoveryCommitment: "invalid",
+			UpdateCommitment:   newCommitment(t),
+		})
+		require.ErrorContains(t, err, "invalid recovery commitment")
+
+		_, err = NewCreateLongFormDID("ion", &CreateRequestInfo{
+			Patches:            replacePatch(),
+			RecoveryCommitment: newCommitment(t),
+		})
+		require.ErrorContains(t, err, "invalid update commitment")
+
+		_, err = NewCreateRequest(&CreateRequestInfo{
+			Patches:            []*Patch{{Action: "unknown"}},
+			RecoveryCommitment: newCommitment(t),
+			UpdateCommitment:   newCommitment(t),
+		})
+		require.ErrorContains(t, err, "action 'unknown' is not supported")
+	})
+}
+
+func TestNewUpdateRequest(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		updateKey := newKey(t)
+
+		requestBytes, err := NewUpdateRequest(&UpdateRequestInfo{
+			DIDSuffix:        "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A",
+			Patches:          []*Patch{{Action: PatchRemovePublicKeys, IDs: []string{"key-1"}}},
+			UpdateKey:        updateKey,
+			UpdateCommitment: newCommitment(t),
+			Signer:           &mockSigner{},
+		})
+		require.NoError(t, err)
+
+		request := &UpdateRequest{}
+		require.NoError(t, json.Unmarshal(requestBytes, request))
+		require.Equal(t, TypeUpdate, request.Type)
+		require.Equal(t, "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A", request.DIDSuffix)
+
+		revealValue, err := RevealValue(updateKey)
+		require.NoError(t, err)
+		require.Equal(t, revealValue, request.RevealValue)
+
+		signedData := &UpdateSignedData{}
+		parseSignedData(t, request.SignedData, signedData)
+
+		deltaHash, err := CanonicalHash(request.Delta)
+		require.NoError(t, err)
+		require.Equal(t, deltaHash, signedData.DeltaHash)
+		signedRevealValue, err := RevealValue(signedData.UpdateKey)
+		require.NoError(t, err)
+		require.Equal(t, revealValue, signedRevealValue)
+	})
+
+	t.Run("test errors", func(t *testing.T) {
+		_, err := NewUpdateRequest(&UpdateRequestInfo{})
+		require.EqualError(t, err, "missing did suffix")
+
+		_, err = NewUpdateRequest(&UpdateRequestInfo{DIDSuffix: "suffix"})
+		require.EqualError(t, err, "missing signer")
+
+		_, err = NewUpdateRequest(&UpdateRequestInfo{DIDSuffix: "suffix", Signer: &mockSigner{}})
+		require.EqualError(t, err, "update key: missing public key")
+
+		_, err = NewUpdateRequest(&UpdateRequestInfo{
+			DIDSuffix:        "suffix",
+			UpdateKey:        newKey(t),
+			UpdateCommitment: newCommitment(t),
+			Signer:           &mockSigner{err: errors.New("sign error")},
+		})
+		require.EqualError(t, err, "sign data: sign error")
+	})
+}
+
+func TestNewRecoverRequest(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		recoveryKey := newKey(t)
+		nextRecoveryCommitment := newCommitment(t)
+
+		requestBytes, err := NewRecoverRequest(&RecoverRequestInfo{
+			DIDSuffix:          "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A",
+			Patches:            replacePatch(),
+			RecoveryKey:        recoveryKey,
+			RecoveryCommitment: nextRecoveryCommitment,
+			UpdateCommitment:   newCommitment(t),
+			AnchorOrigin:       "https://example.com",
+			Signer:             &mockSigner{},
+		})
+		require.NoError(t, err)
+
+		request := &RecoverRequest{}
+		require.NoError(t, json.Unmarshal(requestBytes, request))
+		require.Equal(t, TypeRecover, request.Type)
+
+		signedData := &RecoverSignedData{}
+		parseSignedData(t, request.SignedData, signedData)
+		require.Equal(t, nextRecoveryCommitment, signedData.RecoveryCommitment)
+		require.Equal(t, "https://example.com", signedData.AnchorOrigin)
+		revealValue, err := RevealValue(recoveryKey)
+		require.NoError(t, err)
+		require.Equal(t, revealValue, request.RevealValue)
+
+		signedRevealValue, err := RevealValue(signedData.RecoveryKey)
+		require.NoError(t, err)
+		require.Equal(t, revealValue, signedRevealValue)
+	})
+
+	t.Run("test errors", func(t *testing.T) {
+		_, err := NewRecoverRequest(&RecoverRequestInfo{})
+		require.EqualError(t, err, "missing did suffix")
+
+		_, err = NewRecoverRequest(&RecoverRequestInfo{DIDSuffix: "suffix"})
+		require.EqualError(t, err, "missing signer")
+
+		_, err = NewRecoverRequest(&RecoverRequestInfo{DIDSuffix: "suffix", Signer: &mockSigner{}})
+		require.ErrorContains(t, err, "invalid recovery commitment")
+
+		_, err = NewRecoverRequest(&RecoverRequestInfo{
+			DIDSuffix:          "suffix",
+			RecoveryCommitment: newCommitment(t),
+			Signer:             &mockSigner{},
+		})
+		require.EqualError(t, err, "recovery key: missing public key")
+	})
+}
+
+func TestNewDeactivateRequest(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		recoveryKey := newKey(t)
+
+		requestBytes, err := NewDeactivateRequest(&DeactivateRequestInfo{
+			DIDSuffix:   "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A",
+			RecoveryKey: recoveryKey,
+			Signer:      &mockSigner{},
+		})
+		require.NoError(t, err)
+
+		request := &DeactivateRequest{}
+		require.NoError(t, json.Unmarshal(requestBytes, request))
+		require.Equal(t, TypeDeactivate, request.Type)
+
+		signedData := &DeactivateSignedData{}
+		parseSignedData(t, request.SignedData, signedData)
+		require.Equal(t, "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A", signedData.DIDSuffix)
+	})
+
+	t.Run("test errors", func(t *testing.T) {
+		_, err := NewDeactivateRequest(&DeactivateRequestInfo{})
+		require.EqualError(t, err, "missing did suffix")
+
+		_, err = NewDeactivateRequest(&DeactivateRequestInfo{DIDSuffix: "suffix"})
+		require.EqualError(t, err, "missing signer")
+
+		_, err = NewDeactivateRequest(&DeactivateRequestInfo{DIDSuffix: "suffix", Signer: &mockSigner{}})
+		require.EqualError(t, err, "recovery key: missing public key")
+	})
+}
@@ -0,0 +1,345 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sidetree
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/method/sidetree/operation"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+// Create creates a Sidetree DID for didDoc. RecoveryPublicKeyOpt and UpdatePublicKeyOpt are required.
+// The create operation is sent to the Sidetree node set with WithEndpoint, without endpoint the resolution
+// of the unpublished long-form DID is returned.
+func (v *VDR) Create(didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	didMethodOpts := applyOpts(opts)
+
+	recoveryKey, err := keyOpt(didMethodOpts, RecoveryPublicKeyOpt)
+	if err != nil {
+		return nil, fmt.Errorf("sidetree-vdr create: %w", err)
+	}
+
+	updateKey, err := keyOpt(didMethodOpts, UpdatePublicKeyOpt)
+	if err != nil {
+		return nil, fmt.Errorf("sidetree-vdr create: %w", err)
+	}
+
+	doc, err := sidetreeDocument(didDoc)
+	if err != nil {
+		return nil, fmt.Errorf("sidetree-vdr create: %w", err)
+	}
+
+	info := &operation.CreateRequestInfo{
+		Patches:      []*operation.Patch{{Action: operation.PatchReplace, Document: doc}},
+		AnchorOrigin: stringOpt(didMethodOpts, AnchorOriginOpt),
+	}
+
+	if info.RecoveryCommitment, err = operation.Commitment(recoveryKey); err != nil {
+		return nil, fmt.Errorf("sidetree-vdr create: recovery commitment: %w", err)
+	}
+
+	if info.UpdateCommitment, err = operation.Commitment(updateKey); err != nil {
+		return nil, fmt.Errorf("sidetree-vdr create: update commitment: %w", err)
+	}
+
+	longForm, err := operation.NewCreateLongFormDID(v.method, info)
+	if err != nil {
+		return nil, fmt.Errorf("sidetree-vdr create: %w", err)
+	}
+
+	if v.endpointURL == "" {
+		return v.Read(longForm)
+	}
+
+	request, err := operation.NewCreateRequest(info)
+	if err != nil {
+		return nil, fmt.Errorf("sidetree-vdr create: %w", err)
+	}
+
+	response, err := v.sendRequest(context.Background(), request)
+	if err != nil {
+		return nil, fmt.Errorf("sidetree-vdr create: %w", err)
+	}
+
+	if len(response) == 0 {
+		return v.Read(longForm)
+	}
+
+	docResolution, err := did.ParseDocumentResolution(response)
+	if err != nil {
+		return nil, fmt.Errorf("sidetree-vdr create: parse document resolution: %w", err)
+	}
+
+	return docResolution, nil
+}
+
+// Update sends an update operation with the changes of didDoc against the document resolved by the Sidetree node.
+// UpdatePublicKeyOpt, UpdateSignerOpt and NextUpdatePublicKeyOpt are required.
+//
+// With RecoverOpt a recover operation replacing the whole document is sent instead, which requires
+// RecoveryPublicKeyOpt, RecoverySignerOpt, NextRecoveryPublicKeyOpt and NextUpdatePublicKeyOpt.
+func (v *VDR) Update(didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) error {
+	didMethodOpts := applyOpts(opts)
+
+	if v.endpointURL == "" {
+		return fmt.Errorf("sidetree-vdr update: %w", errNoEndpoint)
+	}
+
+	suffix, err := didSuffix(didDoc.ID)
+	if err != nil {
+		return fmt.Errorf("sidetree-vdr update: %w", err)
+	}
+
+	var request []byte
+
+	if isRecover, _ := didMethodOpts.Values[RecoverOpt].(bool); isRecover { //nolint:errcheck
+		request, err = recoverRequest(suffix, didDoc, didMethodOpts)
+	} else {
+		request, err = v.updateRequest(suffix, didDoc, didMethodOpts)
+	}
+
+	if err != nil {
+		return fmt.Errorf("sidetree-vdr update: %w", err)
+	}
+
+	if _, err = v.sendRequest(context.Background(), request); err != nil {
+		return fmt.Errorf("sidetree-vdr update: %w", err)
+	}
+
+	return nil
+}
+
+// Deactivate sends a deactivate operation, RecoveryPublicKeyOpt and RecoverySignerOpt are required.
+func (v *VDR) Deactivate(didID string, opts ...vdrapi.DIDMethodOption) error {
+	didMethodOpts := applyOpts(opts)
+
+	if v.endpointURL == "" {
+		return fmt.Errorf("sidetree-vdr deactivate: %w", errNoEndpoint)
+	}
+
+	suffix, err := didSuffix(didID)
+	if err != nil {
+		return fmt.Errorf("sidetree-vdr deactivate: %w", err)
+	}
+
+	recoveryKey, err := keyOpt(didMethodOpts, RecoveryPublicKeyOpt)
+	if err != nil {
+		return fmt.Errorf("sidetree-vdr deactivate: %w", err)
+	}
+
+	signer, err := signerOpt(didMethodOpts, RecoverySignerOpt)
+	if err != nil {
+		return fmt.Errorf("sidetree-vdr deactivate: %w", err)
+	}
+
+	request, err := operation.NewDeactivateRequest(&operation.DeactivateRequestInfo{
+		DIDSuffix:   suffix,
+		RecoveryKey: recoveryKey,
+		Signer:      signer,
+	})
+	if err != nil {
+		return fmt.Errorf("sidetree-vdr deactivate: %w", err)
+	}
+
+	if _, err = v.sendRequest(context.Background(), request); err != nil {
+		return fmt.Errorf("sidetree-vdr deactivate: %w", err)
+	}
+
+	return nil
+}
+
+func (v *VDR) updateRequest(suffix string, didDoc *did.Doc, didMethodOpts *vdrapi.DIDMethodOpts) ([]byte, error) {
+	updateKey, err := keyOpt(didMethodOpts, UpdatePublicKeyOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	nextUpdateKey, err := keyOpt(didMethodOpts, NextUpdatePublicKeyOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := signerOpt(didMethodOpts, UpdateSignerOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	// a long-form DID resolves offline to its create state, the current state is only known to the node
+	shortForm, err := shortFormDID(didDoc.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := v.Read(shortForm)
+	if err != nil {
+		return nil, fmt.Errorf("resolve current document: %w", err)
+	}
+
+	patches, err := updatePatches(current.DIDDocument, didDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	updateCommitment, err := operation.Commitment(nextUpdateKey)
+	if err != nil {
+		return nil, fmt.Errorf("update commitment: %w", err)
+	}
+
+	return operation.NewUpdateRequest(&operation.UpdateRequestInfo{
+		DIDSuffix:        suffix,
+		Patches:          patches,
+		UpdateKey:        updateKey,
+		UpdateCommitment: updateCommitment,
+		Signer:           signer,
+	})
+}
+
+func recoverRequest(suffix string, didDoc *did.Doc, didMethodOpts *vdrapi.DIDMethodOpts) ([]byte, error) {
+	recoveryKey, err := keyOpt(didMethodOpts, RecoveryPublicKeyOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	nextRecoveryKey, err := keyOpt(didMethodOpts, NextRecoveryPublicKeyOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	nextUpdateKey, err := keyOpt(didMethodOpts, NextUpdatePublicKeyOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := signerOpt(didMethodOpts, RecoverySignerOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := sidetreeDocument(didDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &operation.RecoverRequestInfo{
+		DIDSuffix:    suffix,
+		Patches:      []*operation.Patch{{Action: operation.PatchReplace, Document: doc}},
+		RecoveryKey:  recoveryKey,
+		AnchorOrigin: stringOpt(didMethodOpts, AnchorOriginOpt),
+		Signer:       signer,
+	}
+
+	if info.RecoveryCommitment, err = operation.Commitment(nextRecoveryKey); err != nil {
+		return nil, fmt.Errorf("recovery commitment: %w", err)
+	}
+
+	if info.UpdateCommitment, err = operation.Commitment(nextUpdateKey); err != nil {
+		return nil, fmt.Errorf("update commitment: %w", err)
+	}
+
+	return operation.NewRecoverRequest(info)
+}
+
+// updatePatches returns the patches that turn the current document into didDoc, keys and services missing from
+// didDoc are removed and all keys and services of didDoc are added, replacing the current ones with the same id.
+func updatePatches(current, didDoc *did.Doc) ([]*operation.Patch, error) {
+	currentDoc, err := sidetreeDocument(current)
+	if err != nil {
+		return nil, fmt.Errorf("current document: %w", err)
+	}
+
+	doc, err := sidetreeDocument(didDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	var patches []*operation.Patch
+
+	keyIDs := make(map[string]bool)
+
+	for _, key := range doc.PublicKeys {
+		keyIDs[key.ID] = true
+	}
+
+	var removedKeys []string
+
+	for _, key := range currentDoc.PublicKeys {
+		if !keyIDs[key.ID] {
+			removedKeys = append(removedKeys, key.ID)
+		}
+	}
+
+	if len(removedKeys) > 0 {
+		patches = append(patches, &operation.Patch{Action: operation.PatchRemovePublicKeys, IDs: removedKeys})
+	}
+
+	if len(doc.PublicKeys) > 0 {
+		patches = append(patches, &operation.Patch{Action: operation.PatchAddPublicKeys, PublicKeys: doc.PublicKeys})
+	}
+
+	serviceIDs := make(map[string]bool)
+
+	for _, s := range doc.Services {
+		serviceIDs[s.ID] = true
+	}
+
+	var removedServices []string
+
+	for _, s := range currentDoc.Services {
+		if !serviceIDs[s.ID] {
+			removedServices = append(removedServices, s.ID)
+		}
+	}
+
+	if len(removedServices) > 0 {
+		patches = append(patches, &operation.Patch{Action: operation.PatchRemoveServices, IDs: removedServices})
+	}
+
+	if len(doc.Services) > 0 {
+		patches = append(patches, &operation.Patch{Action: operation.PatchAddServices, Services: doc.Services})
+	}
+
+	return patches, nil
+}
+
+func applyOpts(opts []vdrapi.DIDMethodOption) *vdrapi.DIDMethodOpts {
+	didMethodOpts := &vdrapi.DIDMethodOpts{Values: make(map[string]interface{})}
+	// Apply options
+	for _, opt := range opts {
+		opt(didMethodOpts)
+	}
+
+	return didMethodOpts
+}
+
+func keyOpt(didMethodOpts *vdrapi.DIDMethodOpts, name string) (*jwk.JWK, error) {
+	key, ok := didMethodOpts.Values[name].(*jwk.JWK)
+	if !ok || key == nil {
+		return nil, fmt.Errorf("%s opt is required and must be *jwk.JWK", name)
+	}
+
+	return key, nil
+}
+
+func signerOpt(didMethodOpts *vdrapi.DIDMethodOpts, name string) (operation.Signer, error) {
+	signer, ok := didMethodOpts.Values[name].(operation.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%s opt is required and must be operation.Signer", name)
+	}
+
+	return signer, nil
+}
+
+func stringOpt(didMethodOpts *vdrapi.DIDMethodOpts, name string) string {
+	s, _ := didMethodOpts.Values[name].(string) //nolint:errcheck
+
+	return s
+}
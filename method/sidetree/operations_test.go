@@ -0,0 +1,428 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sidetree
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/kms-go/doc/jose/jwk"
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/doc/did/endpoint"
+	"github.com/trustbloc/did-go/method/sidetree/operation"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+type mockSigner struct{}
+
+func (s *mockSigner) Sign([]byte) ([]byte, error) {
+	return []byte("signature"), nil
+}
+
+func (s *mockSigner) Algorithm() string {
+	return "ES256"
+}
+
+type mockNode struct {
+	requests   []map[string]interface{}
+	resolved   []string
+	resolution []byte
+	status     int
+}
+
+func (n *mockNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if n.status != 0 {
+		w.WriteHeader(n.status)
+
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/operations":
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		var request map[string]interface{}
+
+		if err = json.Unmarshal(body, &request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		n.requests = append(n.requests, request)
+
+		if request["type"] == operation.TypeCreate {
+			_, _ = w.Write(n.resolution) //nolint:errcheck
+		}
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/identifiers/"):
+		n.resolved = append(n.resolved, strings.TrimPrefix(r.URL.Path, "/identifiers/"))
+
+		if n.resolution == nil {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, _ = w.Write(n.resolution) //nolint:errcheck
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newJWK(t *testing.T) *jwk.JWK {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	key, err := jwksupport.JWKFromKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+
+	return key
+}
+
+func newDoc(t *testing.T, didID string) *did.Doc {
+	t.Helper()
+
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	vm := did.NewVerificationMethodFromBytes(didID+"#key-1", "Ed25519VerificationKey2018", didID, pubKey)
+
+	return &did.Doc{
+		Context:            []string{did.ContextV1},
+		ID:                 didID,
+		VerificationMethod: []did.VerificationMethod{*vm},
+		Authentication:     []did.Verification{*did.NewReferencedVerification(vm, did.Authentication)},
+		Service: []did.Service{{
+			ID:              didID + "#hub",
+			Type:            "LinkedDomains",
+			ServiceEndpoint: endpoint.NewDIDCoreEndpoint("https://example.com"),
+		}},
+	}
+}
+
+func TestCreate(t *testing.T) {
+	t.Run("test create long-form DID without endpoint", func(t *testing.T) {
+		v := New()
+
+		docResolution, err := v.Create(newDoc(t, ""),
+			vdrapi.WithOption(RecoveryPublicKeyOpt, newJWK(t)),
+			vdrapi.WithOption(UpdatePublicKeyOpt, newJWK(t)),
+			vdrapi.WithOption(AnchorOriginOpt, "https://example.com"))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(docResolution.DIDDocument.ID, "did:ion:"))
+		require.False(t, docResolution.DocumentMetadata.Method.Published)
+		require.Equal(t, "https://example.com", docResolution.DocumentMetadata.Method.AnchorOrigin)
+		require.Len(t, docResolution.DIDDocument.VerificationMethod, 1)
+		require.Len(t, docResolution.DIDDocument.Authentication, 1)
+		require.Len(t, docResolution.DIDDocument.Service, 1)
+
+		// the long-form DID is resolved to the same document.
+		resolved, err := v.Read(docResolution.DIDDocument.ID)
+		require.NoError(t, err)
+		require.Equal(t, docResolution.DIDDocument.ID, resolved.DIDDocument.ID)
+	})
+
+	t.Run("test create with endpoint", func(t *testing.T) {
+		node := &mockNode{}
+
+		server := httptest.NewServer(node)
+		defer server.Close()
+
+		v := New(WithEndpoint(server.URL))
+
+		docResolution, err := v.Create(newDoc(t, ""),
+			vdrapi.WithOption(RecoveryPublicKeyOpt, newJWK(t)),
+			vdrapi.WithOption(UpdatePublicKeyOpt, newJWK(t)))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(docResolution.DIDDocument.ID, "did:ion:"))
+
+		require.Len(t, node.requests, 1)
+		require.Equal(t, operation.TypeCreate, node.requests[0]["type"])
+		require.Contains(t, node.requests[0], "suffixData")
+		require.Contains(t, node.requests[0], "delta")
+
+		// the resolution returned by the node is used when available.
+		node.resolution, err = docResolution.JSONBytes()
+		require.NoError(t, err)
+
+		created, err := v.Create(newDoc(t, ""),
+			vdrapi.WithOption(RecoveryPublicKeyOpt, newJWK(t)),
+			vdrapi.WithOption(UpdatePublicKeyOpt, newJWK(t)))
+		require.NoError(t, err)
+		require.Equal(t, docResolution.DIDDocument.ID, created.DIDDocument.ID)
+	})
+
+	t.Run("test error from node", func(t *testing.T) {
+		server := httptest.NewServer(&mockNode{status: http.StatusInternalServerError})
+		defer server.Close()
+
+		_, err := New(WithEndpoint(server.URL)).Create(newDoc(t, ""),
+			vdrapi.WithOption(RecoveryPublicKeyOpt, newJWK(t)),
+			vdrapi.WithOption(UpdatePublicKeyOpt, newJWK(t)))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "sidetree operation failed [500]")
+	})
+
+	t.Run("test missing keys", func(t *testing.T) {
+		_, err := New().Create(newDoc(t, ""))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "recoveryPublicKey opt is required")
+
+		_, err = New().Create(newDoc(t, ""), vdrapi.WithOption(RecoveryPublicKeyOpt, newJWK(t)))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "updatePublicKey opt is required")
+	})
+
+	t.Run("test invalid service type", func(t *testing.T) {
+		doc := newDoc(t, "")
+		doc.Service[0].Type = []string{"LinkedDomains"}
+
+		_, err := New().Create(doc,
+			vdrapi.WithOption(RecoveryPublicKeyOpt, newJWK(t)),
+			vdrapi.WithOption(UpdatePublicKeyOpt, newJWK(t)))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "type must be a string")
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	const didID = "did:ion:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+
+	newNode := func(t *testing.T) *mockNode {
+		t.Helper()
+
+		resolution, err := (&did.DocResolution{DIDDocument: newDoc(t, didID)}).JSONBytes()
+		require.NoError(t, err)
+
+		return &mockNode{resolution: resolution}
+	}
+
+	t.Run("test update", func(t *testing.T) {
+		node := newNode(t)
+
+		server := httptest.NewServer(node)
+		defer server.Close()
+
+		doc := newDoc(t, didID)
+		doc.Service = nil
+
+		err := New(WithEndpoint(server.URL)).Update(doc,
+			vdrapi.WithOption(UpdatePublicKeyOpt, newJWK(t)),
+			vdrapi.WithOption(NextUpdatePublicKeyOpt, newJWK(t)),
+			vdrapi.WithOption(UpdateSignerOpt, &mockSigner{}))
+		require.NoError(t, err)
+
+		require.Len(t, node.requests, 1)
+		require.Equal(t, operation.TypeUpdate, node.requests[0]["type"])
+		require.Equal(t, "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A", node.requests[0]["didSuffix"])
+
+		delta, ok := node.requests[0]["delta"].(map[string]interface{})
+		require.True(t, ok)
+
+		patches, ok := delta["patches"].([]interface{})
+		require.True(t, ok)
+		require.Len(t, patches, 2)
+		require.Equal(t, operation.PatchAddPublicKeys, patches[0].(map[string]interface{})["action"])
+		require.Equal(t, operation.PatchRemoveServices, patches[1].(map[string]interface{})["action"])
+	})
+
+	t.Run("test update of long-form DID diffs against the node state", func(t *testing.T) {
+		docResolution, err := New().Create(newDoc(t, ""),
+			vdrapi.WithOption(RecoveryPublicKeyOpt, newJWK(t)),
+			vdrapi.WithOption(UpdatePublicKeyOpt, newJWK(t)))
+		require.NoError(t, err)
+
+		longForm := docResolution.DIDDocument.ID
+		require.NotEqual(t, didID, longForm)
+
+		shortForm, err := shortFormDID(longForm)
+		require.NoError(t, err)
+
+		current := newDoc(t, shortForm)
+		current.VerificationMethod[0].ID = shortForm + "#key-2"
+		current.Authentication = nil
+
+		resolution, err := (&did.DocResolution{DIDDocument: current}).JSONBytes()
+		require.NoError(t, err)
+
+		node := &mockNode{resolution: resolution}
+
+		server := httptest.NewServer(node)
+		defer server.Close()
+
+		err = New(WithEndpoint(server.URL)).Update(newDoc(t, longForm),
+			vdrapi.WithOption(UpdatePublicKeyOpt, newJWK(t)),
+			vdrapi.WithOption(NextUpdatePublicKeyOpt, newJWK(t)),
+			vdrapi.WithOption(UpdateSignerOpt, &mockSigner{}))
+		require.NoError(t, err)
+
+		require.Equal(t, []string{shortForm}, node.resolved)
+		require.Len(t, node.requests, 1)
+
+		delta, ok := node.requests[0]["delta"].(map[string]interface{})
+		require.True(t, ok)
+
+		patches, ok := delta["patches"].([]interface{})
+		require.True(t, ok)
+		require.Len(t, patches, 3)
+
+		removeKeys, ok := patches[0].(map[string]interface{})
+		require.True(t, ok)
+		require.Equal(t, operation.PatchRemovePublicKeys, removeKeys["action"])
+		require.Equal(t, []interface{}{"key-2"}, removeKeys["ids"])
+	})
+
+	t.Run("test recover", func(t *testing.T) {
+		node := newNode(t)
+
+		server := httptest.NewServer(node)
+		defer server.Close()
+
+		err := New(WithEndpoint(server.URL)).Update(newDoc(t, didID),
+			vdrapi.WithOption(RecoverOpt, true),
+			vdrapi.WithOption(RecoveryPublicKeyOpt, newJWK(t)),
+			vdrapi.WithOption(NextRecoveryPublicKeyOpt, newJWK(t)),
+			vdrapi.WithOption(NextUpdatePublicKeyOpt, newJWK(t)),
+			vdrapi.WithOption(RecoverySignerOpt, &mockSigner{}))
+		require.NoError(t, err)
+
+		require.Len(t, node.requests, 1)
+		require.Equal(t, operation.TypeRecover, node.requests[0]["type"])
+	})
+
+	t.Run("test document not found", func(t *testing.T) {
+		server := httptest.NewServer(&mockNode{})
+		defer server.Close()
+
+		err := New(WithEndpoint(server.URL)).Update(newDoc(t, didID),
+			vdrapi.WithOption(UpdatePublicKeyOpt, newJWK(t)),
+			vdrapi.WithOption(NextUpdatePublicKeyOpt, newJWK(t)),
+			vdrapi.WithOption(UpdateSignerOpt, &mockSigner{}))
+		require.Error(t, err)
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+	})
+
+	t.Run("test missing options", func(t *testing.T) {
+		v := New(WithEndpoint("https://example.com"))
+
+		err := v.Update(newDoc(t, didID))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "updatePublicKey opt is required")
+
+		err = v.Update(newDoc(t, didID),
+			vdrapi.WithOption(UpdatePublicKeyOpt, newJWK(t)),
+			vdrapi.WithOption(NextUpdatePublicKeyOpt, newJWK(t)))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "updateSigner opt is required")
+
+		err = v.Update(newDoc(t, didID), vdrapi.WithOption(RecoverOpt, true))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "recoveryPublicKey opt is required")
+	})
+
+	t.Run("test invalid DID", func(t *testing.T) {
+		err := New(WithEndpoint("https://example.com")).Update(newDoc(t, "did:ion:invalid"))
+		require.Error(t, err)
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+	})
+
+	t.Run("test missing endpoint", func(t *testing.T) {
+		err := New().Update(newDoc(t, didID))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "sidetree endpoint is not configured")
+	})
+}
+
+func TestDeactivate(t *testing.T) {
+	const didID = "did:ion:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+
+	t.Run("test deactivate", func(t *testing.T) {
+		node := &mockNode{}
+
+		server := httptest.NewServer(node)
+		defer server.Close()
+
+		err := New(WithEndpoint(server.URL)).Deactivate(didID,
+			vdrapi.WithOption(RecoveryPublicKeyOpt, newJWK(t)),
+			vdrapi.WithOption(RecoverySignerOpt, &mockSigner{}))
+		require.NoError(t, err)
+
+		require.Len(t, node.requests, 1)
+		require.Equal(t, operation.TypeDeactivate, node.requests[0]["type"])
+		require.Equal(t, "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A", node.requests[0]["didSuffix"])
+	})
+
+	t.Run("test missing options", func(t *testing.T) {
+		v := New(WithEndpoint("https://example.com"))
+
+		err := v.Deactivate(didID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "recoveryPublicKey opt is required")
+
+		err = v.Deactivate(didID, vdrapi.WithOption(RecoveryPublicKeyOpt, newJWK(t)))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "recoverySigner opt is required")
+	})
+
+	t.Run("test missing endpoint", func(t *testing.T) {
+		err := New().Deactivate(didID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "sidetree endpoint is not configured")
+	})
+}
+
+func TestReadFromNode(t *testing.T) {
+	const didID = "did:ion:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+
+	t.Run("test success", func(t *testing.T) {
+		resolution, err := (&did.DocResolution{DIDDocument: newDoc(t, didID)}).JSONBytes()
+		require.NoError(t, err)
+
+		server := httptest.NewServer(&mockNode{resolution: resolution})
+		defer server.Close()
+
+		docResolution, err := New(WithEndpoint(server.URL)).Read(didID)
+		require.NoError(t, err)
+		require.Equal(t, didID, docResolution.DIDDocument.ID)
+		require.Equal(t, did.ContentTypeDIDLDJSON, docResolution.ResolutionMetadata.ContentType)
+	})
+
+	t.Run("test not found", func(t *testing.T) {
+		server := httptest.NewServer(&mockNode{})
+		defer server.Close()
+
+		_, err := New(WithEndpoint(server.URL)).Read(didID)
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+	})
+
+	t.Run("test unexpected status", func(t *testing.T) {
+		server := httptest.NewServer(&mockNode{status: http.StatusInternalServerError})
+		defer server.Close()
+
+		_, err := New(WithEndpoint(server.URL)).Read(didID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported response from sidetree node [500]")
+	})
+}
@@ -0,0 +1,204 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sidetree
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/method/sidetree/operation"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	schemaResV1 = "https://w3id.org/did-resolution/v1"
+	jwsSuiteV1  = "https://w3id.org/security/suites/jws-2020/v1"
+)
+
+// Read resolves a Sidetree DID. Long-form DIDs are resolved offline to the DID document of their create operation,
+// short-form DIDs are resolved by the Sidetree node set with WithEndpoint and result in vdrapi.ErrNotFound otherwise.
+func (v *VDR) Read(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	return v.ReadContext(context.Background(), didID, opts...)
+}
+
+// ReadContext resolves a Sidetree DID, the request to the Sidetree node is bound to the given context.
+func (v *VDR) ReadContext(ctx context.Context, didID string,
+	_ ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	parsed, err := did.Parse(didID)
+	if err != nil {
+		return nil, fmt.Errorf("sidetree-vdr read: failed to parse DID: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	if parsed.Method != v.method {
+		return nil, fmt.Errorf("sidetree-vdr read: invalid method: %s: %w", parsed.Method, vdrapi.ErrInvalidDID)
+	}
+
+	segments := strings.Split(parsed.MethodSpecificID, ":")
+
+	if !isLongForm(segments) {
+		if v.endpointURL == "" {
+			return nil, fmt.Errorf("sidetree-vdr read: short-form DID %s can only be resolved by a Sidetree node: %w",
+				didID, vdrapi.ErrNotFound)
+		}
+
+		return v.resolveDID(ctx, didID)
+	}
+
+	suffix, encoded := segments[len(segments)-2], segments[len(segments)-1]
+	shortForm := strings.TrimSuffix(didID, ":"+encoded)
+
+	initialState, err := operation.ParseLongForm(suffix, encoded)
+	if err != nil {
+		return nil, fmt.Errorf("sidetree-vdr read: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	state, err := operation.ApplyPatches(&operation.Document{}, initialState.Delta.Patches)
+	if err != nil {
+		return nil, fmt.Errorf("sidetree-vdr read: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	doc, err := buildDoc(didID, state)
+	if err != nil {
+		return nil, fmt.Errorf("sidetree-vdr read: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	return &did.DocResolution{
+		Context:     []string{schemaResV1},
+		DIDDocument: doc,
+		DocumentMetadata: &did.DocumentMetadata{
+			EquivalentID: []string{shortForm},
+			Method: &did.MethodMetadata{
+				Published:          false,
+				RecoveryCommitment: initialState.SuffixData.RecoveryCommitment,
+				UpdateCommitment:   initialState.Delta.UpdateCommitment,
+				AnchorOrigin:       initialState.SuffixData.AnchorOrigin,
+			},
+		},
+		ResolutionMetadata: &did.ResolutionMetadata{ContentType: did.ContentTypeDIDLDJSON},
+	}, nil
+}
+
+// isLongForm checks if the method specific ID segments end with a DID suffix and an encoded initial state.
+func isLongForm(segments []string) bool {
+	return len(segments) >= 2 && operation.ValidateEncodedMultihash(segments[len(segments)-2]) == nil //nolint:mnd
+}
+
+// didSuffix returns the DID suffix of a short-form or long-form DID.
+func didSuffix(didID string) (string, error) {
+	parsed, err := did.Parse(didID)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse DID: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	segments := strings.Split(parsed.MethodSpecificID, ":")
+
+	if isLongForm(segments) {
+		return segments[len(segments)-2], nil
+	}
+
+	suffix := segments[len(segments)-1]
+
+	if err = operation.ValidateEncodedMultihash(suffix); err != nil {
+		return "", fmt.Errorf("invalid DID suffix: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	return suffix, nil
+}
+
+// shortFormDID returns the short form of a short-form or long-form DID.
+func shortFormDID(didID string) (string, error) {
+	parsed, err := did.Parse(didID)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse DID: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	segments := strings.Split(parsed.MethodSpecificID, ":")
+
+	if isLongForm(segments) {
+		return strings.TrimSuffix(didID, ":"+segments[len(segments)-1]), nil
+	}
+
+	return didID, nil
+}
+
+// buildDoc creates the DID document of a Sidetree document.
+func buildDoc(didID string, state *operation.Document) (*did.Doc, error) {
+	rawDoc := map[string]interface{}{
+		"@context": []interface{}{did.ContextV1, jwsSuiteV1},
+		"id":       didID,
+	}
+
+	var verificationMethods []interface{}
+
+	relationships := make(map[string][]interface{})
+
+	for _, key := range state.PublicKeys {
+		controller := key.Controller
+		if controller == "" {
+			controller = didID
+		}
+
+		vm := map[string]interface{}{
+			"id":         didID + "#" + key.ID,
+			"type":       key.Type,
+			"controller": controller,
+		}
+
+		if key.PublicKeyJwk != nil {
+			vm["publicKeyJwk"] = key.PublicKeyJwk
+		} else {
+			vm["publicKeyMultibase"] = key.PublicKeyMultibase
+		}
+
+		verificationMethods = append(verificationMethods, vm)
+
+		for _, purpose := range key.Purposes {
+			relationships[purpose] = append(relationships[purpose], didID+"#"+key.ID)
+		}
+	}
+
+	if len(verificationMethods) > 0 {
+		rawDoc["verificationMethod"] = verificationMethods
+	}
+
+	for purpose, refs := range relationships {
+		rawDoc[purpose] = refs
+	}
+
+	var services []interface{}
+
+	for _, service := range state.Services {
+		services = append(services, map[string]interface{}{
+			"id":              didID + "#" + service.ID,
+			"type":            service.Type,
+			"serviceEndpoint": service.ServiceEndpoint,
+		})
+	}
+
+	if len(services) > 0 {
+		rawDoc["service"] = services
+	}
+
+	docBytes, err := json.Marshal(rawDoc)
+	if err != nil {
+		return nil, fmt.Errorf("marshal document: %w", err)
+	}
+
+	doc, err := did.ParseDocument(docBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse document: %w", err)
+	}
+
+	return doc, nil
+}
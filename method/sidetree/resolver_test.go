@@ -0,0 +1,234 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sidetree
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/method/sidetree/operation"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+func newPublicKey(id string, purposes ...string) *operation.PublicKey {
+	return &operation.PublicKey{
+		ID:   id,
+		Type: "JsonWebKey2020",
+		PublicKeyJwk: map[string]interface{}{
+			"kty": "EC",
+			"crv": "secp256k1",
+			"x":   "nIqlRCx0eyBSXcQnqDpReSv4zuWhwCRWssoc9L_nj6A",
+			"y":   "iG29VK6l2U5sKBZUSJePvyFusXgSlK2dDFlWaCM8F7k",
+		},
+		Purposes: purposes,
+	}
+}
+
+func createLongForm(t *testing.T, method string, delta *operation.Delta) string {
+	t.Helper()
+
+	deltaHash, err := operation.CanonicalHash(delta)
+	require.NoError(t, err)
+
+	longForm, err := operation.LongFormDID(method, &operation.SuffixData{
+		DeltaHash:          deltaHash,
+		RecoveryCommitment: operation.EncodedMultihash([]byte("recovery")),
+		AnchorOrigin:       "https://example.com",
+	}, delta)
+	require.NoError(t, err)
+
+	return longForm
+}
+
+func TestRead(t *testing.T) {
+	t.Run("test replace patch", func(t *testing.T) {
+		longForm := createLongForm(t, DefaultMethod, &operation.Delta{
+			Patches: []*operation.Patch{{
+				Action: operation.PatchReplace,
+				Document: &operation.Document{
+					PublicKeys: []*operation.PublicKey{
+						newPublicKey("key-1", operation.PurposeAuthentication, operation.PurposeAssertionMethod),
+					},
+					Services: []*operation.Service{{
+						ID:              "domain-1",
+						Type:            "LinkedDomains",
+						ServiceEndpoint: "https://foo.example.com",
+					}},
+				},
+			}},
+			UpdateCommitment: operation.EncodedMultihash([]byte("update")),
+		})
+
+		docResolution, err := New().Read(longForm)
+		require.NoError(t, err)
+		require.Equal(t, did.ContentTypeDIDLDJSON, docResolution.ResolutionMetadata.ContentType)
+
+		doc := docResolution.DIDDocument
+		require.Equal(t, longForm, doc.ID)
+		require.Len(t, doc.VerificationMethod, 1)
+		require.Equal(t, longForm+"#key-1", doc.VerificationMethod[0].ID)
+		require.Equal(t, "secp256k1", doc.VerificationMethod[0].JSONWebKey().Crv)
+		require.Len(t, doc.Authentication, 1)
+		require.Len(t, doc.AssertionMethod, 1)
+		require.Empty(t, doc.KeyAgreement)
+		require.Len(t, doc.Service, 1)
+		require.Equal(t, longForm+"#domain-1", doc.Service[0].ID)
+
+		metadata := docResolution.DocumentMetadata
+		require.Equal(t, []string{longForm[:len("did:ion:")+46]}, metadata.EquivalentID)
+		require.False(t, metadata.Method.Published)
+		require.Equal(t, operation.EncodedMultihash([]byte("update")), metadata.Method.UpdateCommitment)
+		require.Equal(t, operation.EncodedMultihash([]byte("recovery")), metadata.Method.RecoveryCommitment)
+		require.Equal(t, "https://example.com", metadata.Method.AnchorOrigin)
+	})
+
+	t.Run("test add and remove patches", func(t *testing.T) {
+		longForm := createLongForm(t, "orb", &operation.Delta{
+			Patches: []*operation.Patch{
+				{
+					Action:     operation.PatchAddPublicKeys,
+					PublicKeys: []*operation.PublicKey{newPublicKey("key-1", operation.PurposeAuthentication), newPublicKey("key-2")},
+				},
+				{
+					Action: operation.PatchAddServices,
+					Services: []*operation.Service{
+						{ID: "s1", Type: "LinkedDomains", ServiceEndpoint: "https://foo.example.com"},
+						{ID: "s2", Type: "Hub", ServiceEndpoint: map[string]interface{}{"origins": []string{"a"}}},
+					},
+				},
+				{Action: operation.PatchRemovePublicKeys, IDs: []string{"key-2"}},
+				{Action: operation.PatchRemoveServices, IDs: []string{"s1"}},
+				{
+					Action:     operation.PatchAddPublicKeys,
+					PublicKeys: []*operation.PublicKey{newPublicKey("key-1", operation.PurposeKeyAgreement)},
+				},
+			},
+			UpdateCommitment: operation.EncodedMultihash([]byte("update")),
+		})
+
+		docResolution, err := New(WithMethod("orb")).Read(longForm)
+		require.NoError(t, err)
+
+		doc := docResolution.DIDDocument
+		require.Len(t, doc.VerificationMethod, 1)
+		require.Empty(t, doc.Authentication)
+		require.Len(t, doc.KeyAgreement, 1)
+		require.Len(t, doc.Service, 1)
+		require.Equal(t, "Hub", doc.Service[0].Type)
+	})
+
+	t.Run("test network segment", func(t *testing.T) {
+		longForm := createLongForm(t, "ion:test", &operation.Delta{
+			Patches:          []*operation.Patch{{Action: operation.PatchReplace, Document: &operation.Document{}}},
+			UpdateCommitment: operation.EncodedMultihash([]byte("update")),
+		})
+
+		docResolution, err := New().Read(longForm)
+		require.NoError(t, err)
+		require.Equal(t, longForm, docResolution.DIDDocument.ID)
+		require.Contains(t, docResolution.DocumentMetadata.EquivalentID[0], "did:ion:test:")
+	})
+
+	t.Run("test short form", func(t *testing.T) {
+		_, err := New().Read("did:ion:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A")
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+	})
+
+	t.Run("test context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := New().ReadContext(ctx, "did:ion:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A")
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestReadInvalid(t *testing.T) {
+	validDelta := func() *operation.Delta {
+		return &operation.Delta{
+			Patches:          []*operation.Patch{{Action: operation.PatchReplace, Document: &operation.Document{}}},
+			UpdateCommitment: operation.EncodedMultihash([]byte("update")),
+		}
+	}
+
+	t.Run("test invalid did", func(t *testing.T) {
+		for _, didID := range []string{"invalid", "did:key:z6MkpTHR8VNsBxYAAWHut2Geadd9jSwuBV8xRoAnwWsdvktH"} {
+			_, err := New().Read(didID)
+			require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+		}
+	})
+
+	t.Run("test tampered initial state", func(t *testing.T) {
+		longForm := createLongForm(t, DefaultMethod, validDelta())
+		otherLongForm := createLongForm(t, DefaultMethod, &operation.Delta{
+			Patches:          []*operation.Patch{{Action: operation.PatchReplace, Document: &operation.Document{}}},
+			UpdateCommitment: operation.EncodedMultihash([]byte("other")),
+		})
+
+		// suffix of one DID with initial state of the other
+		tampered := longForm[:len("did:ion:")+46] + otherLongForm[len("did:ion:")+46:]
+
+		_, err := New().Read(tampered)
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+		require.Contains(t, err.Error(), "did suffix does not match suffix data")
+	})
+
+	t.Run("test non canonical initial state", func(t *testing.T) {
+		longForm := createLongForm(t, DefaultMethod, validDelta())
+		suffix := longForm[len("did:ion:") : len("did:ion:")+46]
+
+		stateBytes, err := base64.RawURLEncoding.DecodeString(longForm[len("did:ion:")+47:])
+		require.NoError(t, err)
+
+		_, err = New().Read("did:ion:" + suffix + ":" +
+			base64.RawURLEncoding.EncodeToString(append([]byte(" "), stateBytes...)))
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+		require.Contains(t, err.Error(), "not JCS canonicalized")
+
+		_, err = New().Read("did:ion:" + suffix + ":invalid!")
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+	})
+
+	t.Run("test invalid commitment", func(t *testing.T) {
+		delta := validDelta()
+		delta.UpdateCommitment = "invalid"
+
+		_, err := New().Read(createLongForm(t, DefaultMethod, delta))
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+		require.Contains(t, err.Error(), "invalid update commitment")
+	})
+
+	t.Run("test invalid patches", func(t *testing.T) {
+		invalidKey := newPublicKey("key-1")
+		invalidKey.PublicKeyJwk["d"] = "private"
+
+		for _, patch := range []*operation.Patch{
+			{Action: "ietf-json-patch"},
+			{Action: operation.PatchReplace},
+			{Action: operation.PatchAddPublicKeys, PublicKeys: []*operation.PublicKey{newPublicKey("key#1")}},
+			{Action: operation.PatchAddPublicKeys, PublicKeys: []*operation.PublicKey{newPublicKey("key-1"), newPublicKey("key-1")}},
+			{Action: operation.PatchAddPublicKeys, PublicKeys: []*operation.PublicKey{newPublicKey("key-1", "unknown")}},
+			{Action: operation.PatchAddPublicKeys, PublicKeys: []*operation.PublicKey{{ID: "key-1", Type: "JsonWebKey2020"}}},
+			{Action: operation.PatchAddPublicKeys, PublicKeys: []*operation.PublicKey{invalidKey}},
+			{Action: operation.PatchRemovePublicKeys, IDs: []string{""}},
+			{Action: operation.PatchAddServices, Services: []*operation.Service{{ID: "s1", Type: "Hub", ServiceEndpoint: "invalid"}}},
+			{Action: operation.PatchAddServices, Services: []*operation.Service{{ID: "s1", ServiceEndpoint: "https://example.com"}}},
+			{Action: operation.PatchAddServices, Services: []*operation.Service{{ID: "s1", Type: "Hub", ServiceEndpoint: 1}}},
+			{Action: operation.PatchRemoveServices, IDs: []string{"s1", "s1"}},
+		} {
+			_, err := New().Read(createLongForm(t, DefaultMethod, &operation.Delta{
+				Patches:          []*operation.Patch{patch},
+				UpdateCommitment: operation.EncodedMultihash([]byte("update")),
+			}))
+			require.ErrorIs(t, err, vdrapi.ErrInvalidDID, patch.Action)
+		}
+	})
+}
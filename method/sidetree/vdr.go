@@ -0,0 +1,94 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package sidetree implements Sidetree (https://identity.foundation/sidetree/spec/) DID methods, such as did:ion.
+// Long-form DIDs are resolved offline, other operations are sent to a Sidetree node REST API.
+package sidetree
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	// DefaultMethod is the did:ion Sidetree method.
+	DefaultMethod = "ion"
+
+	// RecoveryPublicKeyOpt is the *jwk.JWK matching the current recovery commitment.
+	RecoveryPublicKeyOpt = "recoveryPublicKey"
+	// UpdatePublicKeyOpt is the *jwk.JWK matching the current update commitment.
+	UpdatePublicKeyOpt = "updatePublicKey"
+	// NextRecoveryPublicKeyOpt is the *jwk.JWK of the next recovery commitment of a recover operation.
+	NextRecoveryPublicKeyOpt = "nextRecoveryPublicKey"
+	// NextUpdatePublicKeyOpt is the *jwk.JWK of the next update commitment of an update or recover operation.
+	NextUpdatePublicKeyOpt = "nextUpdatePublicKey"
+	// RecoverySignerOpt is the operation.Signer of the recovery key, used to recover and deactivate.
+	RecoverySignerOpt = "recoverySigner"
+	// UpdateSignerOpt is the operation.Signer of the update key, used to update.
+	UpdateSignerOpt = "updateSigner"
+	// RecoverOpt makes Update send a recover operation, which replaces the whole document.
+	RecoverOpt = "recover"
+	// AnchorOriginOpt is the anchor origin of create and recover operations, this option is not mandatory.
+	AnchorOriginOpt = "anchorOrigin"
+)
+
+var errLogger = log.New(os.Stderr, " [did-go/method/sidetree] ", log.Ldate|log.Ltime|log.LUTC)
+
+// VDR implements Sidetree DID methods.
+type VDR struct {
+	method      string
+	endpointURL string
+	client      *http.Client
+}
+
+// Option configures the sidetree vdr.
+type Option func(opts *VDR)
+
+// WithMethod option sets the DID method of the Sidetree network, DefaultMethod is used by default.
+func WithMethod(method string) Option {
+	return func(opts *VDR) {
+		opts.method = method
+	}
+}
+
+// WithEndpoint option sets the URL of the Sidetree node REST API, operations are sent to
+// {endpoint}/operations and short-form DIDs are resolved from {endpoint}/identifiers/{did}.
+func WithEndpoint(endpointURL string) Option {
+	return func(opts *VDR) {
+		opts.endpointURL = endpointURL
+	}
+}
+
+// WithHTTPClient option is for custom http client.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(opts *VDR) {
+		opts.client = httpClient
+	}
+}
+
+// New returns new instance of VDR for Sidetree DIDs.
+func New(opts ...Option) *VDR {
+	v := &VDR{method: DefaultMethod, client: &http.Client{}}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// Accept accepts the configured Sidetree method.
+func (v *VDR) Accept(method string, opts ...vdrapi.DIDMethodOption) bool {
+	return method == v.method
+}
+
+// Close frees resources being maintained by VDR.
+func (v *VDR) Close() error {
+	return nil
+}
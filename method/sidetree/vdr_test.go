@@ -0,0 +1,47 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sidetree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/did-go/vdr/api"
+)
+
+var _ api.VDR = (*VDR)(nil) // verify interface compliance
+
+func TestAccept(t *testing.T) {
+	t.Run("ion method", func(t *testing.T) {
+		v := New()
+		require.NotNil(t, v)
+
+		require.True(t, v.Accept("ion"))
+	})
+
+	t.Run("other method", func(t *testing.T) {
+		v := New()
+		require.NotNil(t, v)
+
+		require.False(t, v.Accept("other"))
+	})
+}
+
+func TestWithMethod(t *testing.T) {
+	v := New(WithMethod("orb"))
+	require.True(t, v.Accept("orb"))
+	require.False(t, v.Accept(DefaultMethod))
+}
+
+func TestClose(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		v := New()
+		require.NotNil(t, v)
+		require.NoError(t, v.Close())
+	})
+}
@@ -0,0 +1,186 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trustbloc/kms-go/spi/storage"
+)
+
+// CacheStoreName is the name of the store the did:web HTTP cache keeps the did.json documents in.
+const CacheStoreName = "didwebcache"
+
+// HTTPCache keeps the did.json documents resolved by the did:web VDR together with their validators, so they
+// are revalidated with conditional requests and, while fresh according to their Cache-Control max-age or
+// Expires header, served without a request.
+type HTTPCache struct {
+	store storage.Store
+}
+
+// cacheEntry is a cached did.json document.
+type cacheEntry struct {
+	Document     []byte    `json:"document"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	Expires      time.Time `json:"expires,omitempty"`
+}
+
+// NewHTTPCache returns a new HTTP cache backed by a store opened from storageProvider.
+func NewHTTPCache(storageProvider storage.Provider) (*HTTPCache, error) {
+	store, err := storageProvider.OpenStore(CacheStoreName)
+	if err != nil {
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+
+	return &HTTPCache{store: store}, nil
+}
+
+// WithHTTPCache sets the HTTP cache of the did.json documents. There is no cache by default.
+func WithHTTPCache(cache *HTTPCache) Option {
+	return func(opts *VDR) {
+		opts.cache = cache
+	}
+}
+
+func (c *HTTPCache) get(address string) *cacheEntry {
+	b, err := c.store.Get(address)
+	if err != nil {
+		if !errors.Is(err, storage.ErrDataNotFound) {
+			errorLogger.Printf("Failed to get cached did doc %s: %v", address, err)
+		}
+
+		return nil
+	}
+
+	var entry cacheEntry
+
+	if err = json.Unmarshal(b, &entry); err != nil {
+		errorLogger.Printf("Failed to unmarshal cached did doc %s: %v", address, err)
+
+		return nil
+	}
+
+	return &entry
+}
+
+// put stores entry under address, a nil entry removes the cached document.
+func (c *HTTPCache) put(address string, entry *cacheEntry) {
+	if entry == nil {
+		if err := c.store.Delete(address); err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+			errorLogger.Printf("Failed to delete cached did doc %s: %v", address, err)
+		}
+
+		return
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		errorLogger.Printf("Failed to marshal cached did doc %s: %v", address, err)
+
+		return
+	}
+
+	if err = c.store.Put(address, b); err != nil {
+		errorLogger.Printf("Failed to put cached did doc %s: %v", address, err)
+	}
+}
+
+func (e *cacheEntry) fresh(now time.Time) bool {
+	return now.Before(e.Expires)
+}
+
+func (e *cacheEntry) setConditionalHeaders(header http.Header) {
+	if e.ETag != "" {
+		header.Set("If-None-Match", e.ETag)
+	}
+
+	if e.LastModified != "" {
+		header.Set("If-Modified-Since", e.LastModified)
+	}
+}
+
+// revalidated returns the entry updated with the headers of a 304 Not Modified response.
+func (e *cacheEntry) revalidated(header http.Header, now time.Time) *cacheEntry {
+	entry := *e
+
+	if etag := header.Get("ETag"); etag != "" {
+		entry.ETag = etag
+	}
+
+	if lastModified := header.Get("Last-Modified"); lastModified != "" {
+		entry.LastModified = lastModified
+	}
+
+	return newCacheEntry(entry.Document, entry.ETag, entry.LastModified, header, now)
+}
+
+// cacheEntryFromResponse returns the entry of a 200 OK response, or nil when the response must not be stored.
+func cacheEntryFromResponse(document []byte, header http.Header, now time.Time) *cacheEntry {
+	return newCacheEntry(document, header.Get("ETag"), header.Get("Last-Modified"), header, now)
+}
+
+func newCacheEntry(document []byte, etag, lastModified string, header http.Header, now time.Time) *cacheEntry {
+	expires, noStore := expiry(header, now)
+	if noStore || (etag == "" && lastModified == "" && !now.Before(expires)) {
+		return nil
+	}
+
+	return &cacheEntry{
+		Document:     document,
+		ETag:         etag,
+		LastModified: lastModified,
+		Expires:      expires,
+	}
+}
+
+// expiry returns the time the response stops being fresh according to its Cache-Control max-age, or its Expires
+// header when there is no max-age, and whether the Cache-Control no-store directive forbids storing it.
+// The Cache-Control no-cache directive makes the response stale right away.
+func expiry(header http.Header, now time.Time) (time.Time, bool) {
+	maxAge, noCache := -1, false
+
+	for _, directive := range strings.Split(strings.Join(header.Values("Cache-Control"), ","), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+
+		switch strings.ToLower(name) {
+		case "no-store":
+			return time.Time{}, true
+		case "no-cache":
+			noCache = true
+		case "max-age":
+			if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil && seconds >= 0 {
+				maxAge = seconds
+			}
+		}
+	}
+
+	if noCache {
+		return time.Time{}, false
+	}
+
+	if maxAge < 0 {
+		expires, err := http.ParseTime(header.Get("Expires"))
+		if err != nil {
+			return time.Time{}, false
+		}
+
+		return expires, false
+	}
+
+	if age, err := strconv.Atoi(header.Get("Age")); err == nil && age > 0 {
+		maxAge -= age
+	}
+
+	return now.Add(time.Duration(maxAge) * time.Second), false
+}
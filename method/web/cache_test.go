@@ -0,0 +1,222 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	didapi "github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/legacy/mem"
+	mockstorage "github.com/trustbloc/did-go/legacy/mock/storage"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+type cachingServer struct {
+	*httptest.Server
+	header          http.Header
+	requests        atomic.Int32
+	notModified     atomic.Int32
+	ifNoneMatch     atomic.Value
+	ifModifiedSince atomic.Value
+}
+
+func newCachingServer(t *testing.T, header http.Header) *cachingServer {
+	t.Helper()
+
+	s := &cachingServer{header: header}
+
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		s.ifNoneMatch.Store(r.Header.Get("If-None-Match"))
+		s.ifModifiedSince.Store(r.Header.Get("If-Modified-Since"))
+
+		for name, values := range s.header {
+			w.Header()[name] = values
+		}
+
+		etag := s.header.Get("ETag")
+		lastModified := s.header.Get("Last-Modified")
+
+		if (etag != "" && r.Header.Get("If-None-Match") == etag) ||
+			(lastModified != "" && r.Header.Get("If-Modified-Since") == lastModified) {
+			s.notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		docHandler(didapi.ContentTypeDIDJSON)(w, r)
+	}))
+
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *cachingServer) read(t *testing.T, v *VDR) {
+	t.Helper()
+
+	docResolution, err := v.Read(didWebFor(s.URL), vdrapi.WithOption(HTTPClientOpt, s.Client()))
+	require.NoError(t, err)
+	require.Equal(t, didWebFor(s.URL), docResolution.DIDDocument.ID)
+}
+
+func newTestHTTPCache(t *testing.T) *HTTPCache {
+	t.Helper()
+
+	cache, err := NewHTTPCache(mem.NewProvider())
+	require.NoError(t, err)
+
+	return cache
+}
+
+func TestHTTPCache(t *testing.T) {
+	t.Run("test fresh document is served from cache", func(t *testing.T) {
+		s := newCachingServer(t, http.Header{"Cache-Control": []string{"public, max-age=60"}})
+		v := New(WithHTTPCache(newTestHTTPCache(t)))
+
+		s.read(t, v)
+		s.read(t, v)
+
+		require.EqualValues(t, 1, s.requests.Load())
+	})
+
+	t.Run("test stale document is revalidated with etag", func(t *testing.T) {
+		s := newCachingServer(t, http.Header{"Cache-Control": []string{"no-cache"}, "Etag": []string{`"v1"`}})
+		v := New(WithHTTPCache(newTestHTTPCache(t)))
+
+		s.read(t, v)
+		require.Empty(t, s.ifNoneMatch.Load())
+
+		s.read(t, v)
+		require.Equal(t, `"v1"`, s.ifNoneMatch.Load())
+		require.EqualValues(t, 2, s.requests.Load())
+		require.EqualValues(t, 1, s.notModified.Load())
+	})
+
+	t.Run("test stale document is revalidated with last modified", func(t *testing.T) {
+		lastModified := time.Now().UTC().Format(http.TimeFormat)
+
+		s := newCachingServer(t, http.Header{"Cache-Control": []string{"max-age=0"},
+			"Last-Modified": []string{lastModified}})
+		v := New(WithHTTPCache(newTestHTTPCache(t)))
+
+		s.read(t, v)
+		s.read(t, v)
+
+		require.Equal(t, lastModified, s.ifModifiedSince.Load())
+		require.EqualValues(t, 1, s.notModified.Load())
+	})
+
+	t.Run("test revalidation refreshes max age", func(t *testing.T) {
+		s := newCachingServer(t, http.Header{"Etag": []string{`"v1"`}})
+		v := New(WithHTTPCache(newTestHTTPCache(t)))
+
+		s.read(t, v)
+		s.header.Set("Cache-Control", "max-age=60")
+		s.read(t, v)
+		s.read(t, v)
+
+		require.EqualValues(t, 2, s.requests.Load())
+		require.EqualValues(t, 1, s.notModified.Load())
+	})
+
+	t.Run("test no-store document is not cached", func(t *testing.T) {
+		s := newCachingServer(t, http.Header{"Cache-Control": []string{"max-age=60, no-store"},
+			"Etag": []string{`"v1"`}})
+		v := New(WithHTTPCache(newTestHTTPCache(t)))
+
+		s.read(t, v)
+		s.read(t, v)
+
+		require.Empty(t, s.ifNoneMatch.Load())
+		require.EqualValues(t, 2, s.requests.Load())
+		require.Zero(t, s.notModified.Load())
+	})
+
+	t.Run("test expired document without validators is fetched again", func(t *testing.T) {
+		s := newCachingServer(t, http.Header{"Cache-Control": []string{"max-age=60"}, "Age": []string{"60"}})
+		v := New(WithHTTPCache(newTestHTTPCache(t)))
+
+		s.read(t, v)
+		s.read(t, v)
+
+		require.EqualValues(t, 2, s.requests.Load())
+	})
+
+	t.Run("test expires header", func(t *testing.T) {
+		s := newCachingServer(t, http.Header{
+			"Expires": []string{time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)},
+		})
+		v := New(WithHTTPCache(newTestHTTPCache(t)))
+
+		s.read(t, v)
+		s.read(t, v)
+
+		require.EqualValues(t, 1, s.requests.Load())
+	})
+
+	t.Run("test cache survives restart", func(t *testing.T) {
+		s := newCachingServer(t, http.Header{"Cache-Control": []string{"max-age=60"}})
+		provider := mem.NewProvider()
+
+		cache, err := NewHTTPCache(provider)
+		require.NoError(t, err)
+
+		s.read(t, New(WithHTTPCache(cache)))
+
+		cache, err = NewHTTPCache(provider)
+		require.NoError(t, err)
+
+		s.read(t, New(WithHTTPCache(cache)))
+
+		require.EqualValues(t, 1, s.requests.Load())
+	})
+
+	t.Run("test invalid document is not cached", func(t *testing.T) {
+		var requests atomic.Int32
+
+		s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "max-age=60")
+
+			if requests.Add(1) == 1 {
+				w.Header().Set("Content-Type", didapi.ContentTypeDIDJSON)
+				_, _ = fmt.Fprintf(w, validDoc, "did:web:example.com") //nolint:errcheck
+
+				return
+			}
+
+			docHandler(didapi.ContentTypeDIDJSON)(w, r)
+		}))
+		defer s.Close()
+
+		v := New(WithHTTPCache(newTestHTTPCache(t)))
+
+		_, err := v.Read(didWebFor(s.URL), vdrapi.WithOption(HTTPClientOpt, s.Client()))
+		require.ErrorContains(t, err, "not matching did")
+
+		_, err = v.Read(didWebFor(s.URL), vdrapi.WithOption(HTTPClientOpt, s.Client()))
+		require.NoError(t, err)
+		require.EqualValues(t, 2, requests.Load())
+	})
+
+	t.Run("test open store failure", func(t *testing.T) {
+		provider := mockstorage.NewMockStoreProvider()
+		provider.ErrOpenStoreHandle = errors.New("open store error")
+
+		_, err := NewHTTPCache(provider)
+		require.ErrorContains(t, err, "open store error")
+	})
+}
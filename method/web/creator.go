@@ -7,13 +7,178 @@ SPDX-License-Identifier: Apache-2.0
 package web
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/trustbloc/did-go/doc/did"
 	vdrapi "github.com/trustbloc/did-go/vdr/api"
 )
 
-// Create creates a did:web diddoc (unsupported at the moment).
+const (
+	// DomainOpt is the domain the did:web DID of Create is built from.
+	DomainOpt = "domain"
+
+	// PortOpt is the optional port (int) of the did:web DID of Create.
+	PortOpt = "port"
+
+	// PathOpt is the optional path of the did:web DID of Create, either a '/' separated string or a []string.
+	PathOpt = "path"
+)
+
+var errNoPublisher = errors.New("publisher is not configured")
+
+// Create creates a did:web DID for didDoc and publishes its did.json document.
+//
+// The DID is built from DomainOpt, PortOpt and PathOpt, the ID of didDoc is used when DomainOpt is not set.
+// Relative DID URLs of didDoc are kept relative and verification methods without controller are controlled by
+// the created DID.
 func (v *VDR) Create(didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
-	return nil, errors.New("error building did:web did doc --> build not supported in http binding vdr")
+	if v.publisher == nil {
+		return nil, fmt.Errorf("error building did:web did doc --> %w", errNoPublisher)
+	}
+
+	didOpts := applyOpts(opts)
+
+	didID := didDoc.ID
+
+	if _, ok := didOpts.Values[DomainOpt]; ok {
+		var err error
+
+		didID, err = buildDIDWeb(didOpts)
+		if err != nil {
+			return nil, fmt.Errorf("error building did:web did doc --> %w", err)
+		}
+	}
+
+	doc, err := withID(didDoc, didID)
+	if err != nil {
+		return nil, fmt.Errorf("error building did:web did doc --> %w", err)
+	}
+
+	artifact, err := newArtifact(doc, didOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error building did:web did doc --> %w", err)
+	}
+
+	if err = v.publisher.Publish(artifact); err != nil {
+		return nil, fmt.Errorf("error building did:web did doc --> failed to publish did doc --> %w", err)
+	}
+
+	return &did.DocResolution{
+		DIDDocument:        doc,
+		ResolutionMetadata: &did.ResolutionMetadata{ContentType: did.ContentTypeDIDLDJSON},
+	}, nil
+}
+
+// buildDIDWeb builds the did:web DID of the domain, port and path options.
+func buildDIDWeb(didOpts *vdrapi.DIDMethodOpts) (string, error) {
+	domain, ok := didOpts.Values[DomainOpt].(string)
+	if !ok || domain == "" {
+		return "", errors.New("domain opt must be a non empty string")
+	}
+
+	if strings.ContainsAny(domain, ":/") {
+		return "", fmt.Errorf("invalid domain %s", domain)
+	}
+
+	if p, exists := didOpts.Values[PortOpt]; exists {
+		port, isInt := p.(int)
+		if !isInt || port <= 0 || port > 65535 {
+			return "", errors.New("port opt must be an int between 1 and 65535")
+		}
+
+		domain += ":" + strconv.Itoa(port)
+	}
+
+	segments := []string{"did", namespace, url.QueryEscape(domain)}
+
+	var path []string
+
+	switch p := didOpts.Values[PathOpt].(type) {
+	case nil:
+	case string:
+		path = strings.Split(strings.Trim(p, "/"), "/")
+	case []string:
+		path = p
+	default:
+		return "", errors.New("path opt must be a string or []string")
+	}
+
+	for _, segment := range path {
+		if segment == "" || segment == "." || segment == ".." || strings.ContainsAny(segment, ":/") {
+			return "", fmt.Errorf("invalid path segment '%s'", segment)
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return strings.Join(segments, ":"), nil
+}
+
+// withID returns a copy of didDoc with the given ID, verification methods without controller are controlled by
+// the DID.
+func withID(didDoc *did.Doc, didID string) (*did.Doc, error) {
+	docBytes, err := didDoc.JSONBytes()
+	if err != nil {
+		return nil, fmt.Errorf("marshal did doc --> %w", err)
+	}
+
+	var rawDoc map[string]interface{}
+
+	if err = json.Unmarshal(docBytes, &rawDoc); err != nil {
+		return nil, fmt.Errorf("unmarshal did doc --> %w", err)
+	}
+
+	rawDoc["id"] = didID
+
+	setController(rawDoc, didID)
+
+	docBytes, err = json.Marshal(rawDoc)
+	if err != nil {
+		return nil, fmt.Errorf("marshal did doc --> %w", err)
+	}
+
+	doc, err := did.ParseDocument(docBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse did doc --> %w", err)
+	}
+
+	return doc, nil
+}
+
+func setController(value interface{}, didID string) {
+	switch val := value.(type) {
+	case map[string]interface{}:
+		if controller, ok := val["controller"]; ok && controller == "" {
+			val["controller"] = didID
+		}
+
+		for _, entry := range val {
+			setController(entry, didID)
+		}
+	case []interface{}:
+		for _, entry := range val {
+			setController(entry, didID)
+		}
+	}
+}
+
+func applyOpts(opts []vdrapi.DIDMethodOption) *vdrapi.DIDMethodOpts {
+	didOpts := &vdrapi.DIDMethodOpts{Values: make(map[string]interface{})}
+	// Apply options
+	for _, opt := range opts {
+		opt(didOpts)
+	}
+
+	return didOpts
+}
+
+func useHTTP(didOpts *vdrapi.DIDMethodOpts) bool {
+	_, ok := didOpts.Values[UseHTTPOpt]
+
+	return ok
 }
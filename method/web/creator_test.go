@@ -7,17 +7,157 @@ SPDX-License-Identifier: Apache-2.0
 package web
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
 )
 
+type failingPublisher struct{}
+
+func (p *failingPublisher) Publish(*Artifact) error {
+	return errors.New("publish error")
+}
+
+func (p *failingPublisher) Remove(*Artifact) error {
+	return errors.New("remove error")
+}
+
+func newDoc(t *testing.T) *did.Doc {
+	t.Helper()
+
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	vm := did.NewVerificationMethodFromBytes("#key-1", "Ed25519VerificationKey2018", "", pubKey)
+
+	return &did.Doc{
+		Context:            []string{did.ContextV1},
+		VerificationMethod: []did.VerificationMethod{*vm},
+		Authentication:     []did.Verification{*did.NewReferencedVerification(vm, did.Authentication)},
+	}
+}
+
 func TestCreateDID(t *testing.T) {
-	t.Run("test create did failure", func(t *testing.T) {
+	t.Run("test create and resolve did", func(t *testing.T) {
+		publisher := NewMemoryPublisher()
+
+		server := httptest.NewTLSServer(publisher)
+		defer server.Close()
+
+		serverURL, err := url.Parse(server.URL)
+		require.NoError(t, err)
+
+		port, err := strconv.Atoi(serverURL.Port())
+		require.NoError(t, err)
+
+		v := New(WithPublisher(publisher))
+
+		docResolution, err := v.Create(newDoc(t),
+			vdrapi.WithOption(DomainOpt, serverURL.Hostname()),
+			vdrapi.WithOption(PortOpt, port),
+			vdrapi.WithOption(PathOpt, "user/alice"))
+		require.NoError(t, err)
+
+		didID := "did:web:" + url.QueryEscape(serverURL.Host) + ":user:alice"
+		require.Equal(t, didID, docResolution.DIDDocument.ID)
+		require.Equal(t, didID+"#key-1", docResolution.DIDDocument.VerificationMethod[0].ID)
+		require.Equal(t, didID, docResolution.DIDDocument.VerificationMethod[0].Controller)
+
+		doc, ok := publisher.Document(serverURL.Host, "/user/alice/did.json")
+		require.True(t, ok)
+		require.Contains(t, string(doc), `"#key-1"`)
+
+		resolved, err := v.Read(didID, vdrapi.WithOption(HTTPClientOpt, server.Client()))
+		require.NoError(t, err)
+		require.Equal(t, didID, resolved.DIDDocument.ID)
+		require.Equal(t, docResolution.DIDDocument.VerificationMethod[0].Value,
+			resolved.DIDDocument.VerificationMethod[0].Value)
+		require.Len(t, resolved.DIDDocument.Authentication, 1)
+	})
+
+	t.Run("test create well-known did from doc id", func(t *testing.T) {
+		publisher := NewMemoryPublisher()
+
+		doc := newDoc(t)
+		doc.ID = "did:web:example.com"
+
+		docResolution, err := New(WithPublisher(publisher)).Create(doc)
+		require.NoError(t, err)
+		require.Equal(t, "did:web:example.com", docResolution.DIDDocument.ID)
+
+		_, ok := publisher.Document("example.com", "/.well-known/did.json")
+		require.True(t, ok)
+	})
+
+	t.Run("test create with path segments", func(t *testing.T) {
+		publisher := NewMemoryPublisher()
+
+		docResolution, err := New(WithPublisher(publisher)).Create(newDoc(t),
+			vdrapi.WithOption(DomainOpt, "example.com"),
+			vdrapi.WithOption(PathOpt, []string{"user", "bob"}))
+		require.NoError(t, err)
+		require.Equal(t, "did:web:example.com:user:bob", docResolution.DIDDocument.ID)
+
+		_, ok := publisher.Document("example.com", "/user/bob/did.json")
+		require.True(t, ok)
+	})
+
+	t.Run("test invalid options", func(t *testing.T) {
+		v := New(WithPublisher(NewMemoryPublisher()))
+
+		_, err := v.Create(newDoc(t), vdrapi.WithOption(DomainOpt, ""))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "domain opt must be a non empty string")
+
+		_, err = v.Create(newDoc(t), vdrapi.WithOption(DomainOpt, "example.com:8080"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid domain")
+
+		_, err = v.Create(newDoc(t), vdrapi.WithOption(DomainOpt, "example.com"), vdrapi.WithOption(PortOpt, "80"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "port opt must be an int")
+
+		_, err = v.Create(newDoc(t), vdrapi.WithOption(DomainOpt, "example.com"), vdrapi.WithOption(PathOpt, 1))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "path opt must be a string or []string")
+
+		_, err = v.Create(newDoc(t), vdrapi.WithOption(DomainOpt, "example.com"),
+			vdrapi.WithOption(PathOpt, "user/../admin"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid path segment '..'")
+
+		_, err = v.Create(newDoc(t))
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+
+		doc := newDoc(t)
+		doc.ID = "did:example:123"
+
+		_, err = v.Create(doc)
+		require.Error(t, err)
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+	})
+
+	t.Run("test publish failure", func(t *testing.T) {
+		_, err := New(WithPublisher(&failingPublisher{})).Create(newDoc(t),
+			vdrapi.WithOption(DomainOpt, "example.com"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to publish did doc --> publish error")
+	})
+
+	t.Run("test missing publisher", func(t *testing.T) {
 		v := New()
-		d, err := v.Create(nil, nil)
+		d, err := v.Create(newDoc(t))
 		require.Nil(t, d)
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "build not supported in http binding vdr")
+		require.Contains(t, err.Error(), "publisher is not configured")
 	})
 }
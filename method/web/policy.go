@@ -0,0 +1,223 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/trustbloc/did-go/doc/did"
+)
+
+const (
+	defaultMaxBodySize  = 1 << 20
+	defaultMaxRedirects = 3
+	defaultTimeout      = 10 * time.Second
+	dialTimeout         = 5 * time.Second
+	httpsScheme         = "https"
+)
+
+var (
+	// ErrBodyTooLarge is returned when the did.json document exceeds the maximum body size.
+	ErrBodyTooLarge = errors.New("response body exceeds the maximum size")
+
+	// ErrTooManyRedirects is returned when resolving the did.json document exceeds the redirect limit.
+	ErrTooManyRedirects = errors.New("too many redirects")
+
+	// ErrInsecureScheme is returned when the did.json document is requested, or redirected to, over plain HTTP
+	// without the UseHTTPOpt option.
+	ErrInsecureScheme = errors.New("only https is allowed")
+
+	// ErrBlockedAddress is returned when the host of the did.json document resolves to a loopback, private,
+	// link-local or otherwise non-public address.
+	ErrBlockedAddress = errors.New("address is not allowed")
+
+	// ErrUnsupportedContentType is returned when the did.json document is served with a content type that is
+	// not a JSON media type.
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+)
+
+// defaultContentTypes are the media types a did.json document may be served with.
+var defaultContentTypes = []string{ //nolint:gochecknoglobals
+	did.ContentTypeDIDJSON,
+	did.ContentTypeDIDLDJSON,
+	"application/json",
+	"application/ld+json",
+}
+
+// blockedPrefixes are the non-public ranges not covered by the netip.Addr classification methods.
+var blockedPrefixes = []netip.Prefix{ //nolint:gochecknoglobals
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+// WithMaxBodySize sets the maximum size in bytes of the did.json document, 1 MiB by default.
+func WithMaxBodySize(size int64) Option {
+	return func(opts *VDR) {
+		opts.maxBodySize = size
+	}
+}
+
+// WithMaxRedirects sets the number of redirects followed when resolving the did.json document, 3 by default.
+// Zero disables redirects.
+func WithMaxRedirects(n int) Option {
+	return func(opts *VDR) {
+		opts.maxRedirects = n
+	}
+}
+
+// WithTimeout sets the time limit of resolving the did.json document, 10 seconds by default.
+func WithTimeout(timeout time.Duration) Option {
+	return func(opts *VDR) {
+		opts.timeout = timeout
+	}
+}
+
+// WithContentTypes sets the media types the did.json document may be served with, by default
+// application/did+json, application/did+ld+json, application/json and application/ld+json.
+func WithContentTypes(mediaTypes ...string) Option {
+	return func(opts *VDR) {
+		opts.contentTypes = mediaTypes
+	}
+}
+
+// WithAllowPrivateAddresses allows resolving did:web DIDs whose host resolves to a loopback, private or
+// link-local address. It should only be used when the resolved DIDs are trusted.
+func WithAllowPrivateAddresses() Option {
+	return func(opts *VDR) {
+		opts.allowPrivateAddresses = true
+	}
+}
+
+// newHTTPClient returns the client used when no HTTPClientOpt option is given. Its transport does not use
+// proxies and, unless private addresses are allowed, checks every address the host resolves to before
+// connecting.
+func (v *VDR) newHTTPClient() *http.Client {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+
+	if !v.allowPrivateAddresses {
+		dialer.Control = checkAddress
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Transport: transport}
+}
+
+// withPolicy returns a copy of client that applies the redirect limit and the scheme restriction. A client
+// given with HTTPClientOpt keeps its own transport, so the address blocklist is only enforced by the default
+// client.
+func (v *VDR) withPolicy(client *http.Client, allowHTTP bool) *http.Client {
+	c := *client
+	checkRedirect := client.CheckRedirect
+
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > v.maxRedirects {
+			return fmt.Errorf("%w: stopped after %d redirects", ErrTooManyRedirects, v.maxRedirects)
+		}
+
+		if req.URL.Scheme != httpsScheme && !allowHTTP {
+			return fmt.Errorf("%w: redirect to %s", ErrInsecureScheme, req.URL.Redacted())
+		}
+
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+
+		return nil
+	}
+
+	return &c
+}
+
+func (v *VDR) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if v.timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, v.timeout)
+}
+
+func (v *VDR) checkContentType(header http.Header) error {
+	contentType := header.Get("Content-Type")
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("%w: [%s]", ErrUnsupportedContentType, contentType)
+	}
+
+	if !slices.Contains(v.contentTypes, mediaType) {
+		return fmt.Errorf("%w: [%s]", ErrUnsupportedContentType, mediaType)
+	}
+
+	return nil
+}
+
+func (v *VDR) readBody(resp *http.Response) ([]byte, error) {
+	if resp.ContentLength > v.maxBodySize {
+		return nil, fmt.Errorf("%w: content length %d, limit %d", ErrBodyTooLarge, resp.ContentLength, v.maxBodySize)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, v.maxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(body)) > v.maxBodySize {
+		return nil, fmt.Errorf("%w: limit %d", ErrBodyTooLarge, v.maxBodySize)
+	}
+
+	return body, nil
+}
+
+// checkAddress is the dialer control function of the default client, it is called with the resolved address
+// of every connection.
+func checkAddress(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: [%s]", ErrBlockedAddress, address)
+	}
+
+	if !isPublic(addrPort.Addr()) {
+		return fmt.Errorf("%w: [%s]", ErrBlockedAddress, addrPort.Addr())
+	}
+
+	return nil
+}
+
+func isPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,179 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package web
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	urlapi "net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	didapi "github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+func didWebFor(serverURL string) string {
+	u, _ := urlapi.Parse(serverURL) //nolint:errcheck
+
+	return "did:web:" + urlapi.QueryEscape(u.Host)
+}
+
+func docHandler(contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		_, _ = fmt.Fprintf(w, validDoc, "did:web:"+urlapi.QueryEscape(r.Host)) //nolint:errcheck
+	}
+}
+
+func TestFetchPolicy(t *testing.T) {
+	t.Run("test private address is blocked", func(t *testing.T) {
+		s := httptest.NewServer(docHandler(didapi.ContentTypeDIDJSON))
+		defer s.Close()
+
+		_, err := New().Read(didWebFor(s.URL), vdrapi.WithOption(UseHTTPOpt, true))
+		require.ErrorIs(t, err, ErrBlockedAddress)
+	})
+
+	t.Run("test private address is allowed", func(t *testing.T) {
+		s := httptest.NewServer(docHandler(didapi.ContentTypeDIDJSON))
+		defer s.Close()
+
+		docResolution, err := New(WithAllowPrivateAddresses()).Read(didWebFor(s.URL),
+			vdrapi.WithOption(UseHTTPOpt, true))
+		require.NoError(t, err)
+		require.Equal(t, didWebFor(s.URL), docResolution.DIDDocument.ID)
+	})
+
+	t.Run("test body too large", func(t *testing.T) {
+		s := httptest.NewTLSServer(docHandler(didapi.ContentTypeDIDJSON))
+		defer s.Close()
+
+		_, err := New(WithMaxBodySize(16)).Read(didWebFor(s.URL), vdrapi.WithOption(HTTPClientOpt, s.Client()))
+		require.ErrorIs(t, err, ErrBodyTooLarge)
+	})
+
+	t.Run("test body too large without content length", func(t *testing.T) {
+		s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", didapi.ContentTypeDIDJSON)
+			w.(http.Flusher).Flush()
+			_, _ = w.Write([]byte(strings.Repeat(" ", 64))) //nolint:errcheck
+		}))
+		defer s.Close()
+
+		_, err := New(WithMaxBodySize(16)).Read(didWebFor(s.URL), vdrapi.WithOption(HTTPClientOpt, s.Client()))
+		require.ErrorIs(t, err, ErrBodyTooLarge)
+	})
+
+	t.Run("test too many redirects", func(t *testing.T) {
+		s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, r.URL.Path, http.StatusFound)
+		}))
+		defer s.Close()
+
+		_, err := New().Read(didWebFor(s.URL), vdrapi.WithOption(HTTPClientOpt, s.Client()))
+		require.ErrorIs(t, err, ErrTooManyRedirects)
+
+		_, err = New(WithMaxRedirects(0)).Read(didWebFor(s.URL), vdrapi.WithOption(HTTPClientOpt, s.Client()))
+		require.ErrorIs(t, err, ErrTooManyRedirects)
+	})
+
+	t.Run("test redirect is followed", func(t *testing.T) {
+		s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == defaultPath {
+				http.Redirect(w, r, "/did.json", http.StatusFound)
+
+				return
+			}
+
+			docHandler(didapi.ContentTypeDIDJSON)(w, r)
+		}))
+		defer s.Close()
+
+		_, err := New().Read(didWebFor(s.URL), vdrapi.WithOption(HTTPClientOpt, s.Client()))
+		require.NoError(t, err)
+	})
+
+	t.Run("test redirect to http", func(t *testing.T) {
+		target := httptest.NewServer(docHandler(didapi.ContentTypeDIDJSON))
+		defer target.Close()
+
+		s := httptest.NewTLSServer(http.RedirectHandler(target.URL+defaultPath, http.StatusFound))
+		defer s.Close()
+
+		_, err := New().Read(didWebFor(s.URL), vdrapi.WithOption(HTTPClientOpt, s.Client()))
+		require.ErrorIs(t, err, ErrInsecureScheme)
+	})
+
+	t.Run("test unsupported content type", func(t *testing.T) {
+		s := httptest.NewTLSServer(docHandler("text/html; charset=utf-8"))
+		defer s.Close()
+
+		_, err := New().Read(didWebFor(s.URL), vdrapi.WithOption(HTTPClientOpt, s.Client()))
+		require.ErrorIs(t, err, ErrUnsupportedContentType)
+
+		docResolution, err := New(WithContentTypes("text/html")).Read(didWebFor(s.URL),
+			vdrapi.WithOption(HTTPClientOpt, s.Client()))
+		require.NoError(t, err)
+		require.Equal(t, didWebFor(s.URL), docResolution.DIDDocument.ID)
+	})
+
+	t.Run("test timeout", func(t *testing.T) {
+		s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}))
+		defer s.Close()
+
+		_, err := New(WithTimeout(50*time.Millisecond)).Read(didWebFor(s.URL),
+			vdrapi.WithOption(HTTPClientOpt, s.Client()))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestIsPublic(t *testing.T) {
+	for addr, public := range map[string]bool{
+		"93.184.216.34":            true,
+		"2606:2800:220:1::":        true,
+		"127.0.0.1":                false,
+		"::1":                      false,
+		"10.1.2.3":                 false,
+		"172.16.0.1":               false,
+		"192.168.1.1":              false,
+		"169.254.169.254":          false,
+		"100.64.0.1":               false,
+		"0.0.0.0":                  false,
+		"fd00::1":                  false,
+		"fe80::1":                  false,
+		"::ffff:127.0.0.1":         false,
+		"::ffff:169.254.169.254":   false,
+		"64:ff9b::a9fe:a9fe":       false,
+		"ff02::1":                  false,
+		"224.0.0.1":                false,
+		"255.255.255.255":          false,
+		"2001:db8::1":              false,
+		"198.18.0.1":               false,
+		"192.0.0.170":              false,
+		"240.0.0.1":                false,
+		"8.8.8.8":                  true,
+		"2001:4860:4860::8888":     true,
+		"::":                       false,
+		"fc00::1":                  false,
+		"::ffff:8.8.8.8":           true,
+		"2001:4860:4860:0:0:0:0:1": true,
+	} {
+		require.Equal(t, public, isPublic(netip.MustParseAddr(addr)), addr)
+	}
+}
@@ -0,0 +1,278 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package web
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+// Artifact is a did.json document and the location it is resolved from.
+type Artifact struct {
+	// DID is the did:web DID of the document.
+	DID string
+	// URL is the URL the document is resolved from.
+	URL string
+	// Host is the host of URL, including the port.
+	Host string
+	// Path is the path of URL, e.g. /.well-known/did.json.
+	Path string
+	// Document is the did.json document, it is empty when the document is removed.
+	Document []byte
+}
+
+// Publisher writes did.json documents to the web server of their did:web DID.
+type Publisher interface {
+	// Publish creates or replaces the document of the artifact.
+	Publish(artifact *Artifact) error
+	// Remove removes the document of the artifact, it is not an error to remove a document that does not exist.
+	Remove(artifact *Artifact) error
+}
+
+func newArtifact(didDoc *did.Doc, didOpts *vdrapi.DIDMethodOpts) (*Artifact, error) {
+	address, _, err := parseDIDWeb(didDoc.ID, useHTTP(didOpts))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse did:web did --> %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	artifact, err := locate(didDoc.ID, address)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	artifact.Document, err = didDoc.JSONBytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal did doc --> %w", err)
+	}
+
+	return artifact, nil
+}
+
+// locate returns the artifact of the DID resolved from address.
+func locate(didID, address string) (*Artifact, error) {
+	if !strings.HasPrefix(didID, "did:"+namespace+":") {
+		return nil, fmt.Errorf("not a did:web did: %s", didID)
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid did:web address --> %w", err)
+	}
+
+	if u.Host == "" || path.Clean(u.Path) != u.Path {
+		return nil, fmt.Errorf("invalid did:web address: %s", address)
+	}
+
+	return &Artifact{DID: didID, URL: address, Host: u.Host, Path: u.Path}, nil
+}
+
+// DirPublisher writes the did.json documents to a local directory, which is served as the web root of the
+// did:web domain.
+type DirPublisher struct {
+	root string
+}
+
+// NewDirPublisher creates a publisher writing the did.json documents below the root directory.
+func NewDirPublisher(root string) *DirPublisher {
+	return &DirPublisher{root: root}
+}
+
+// Publish writes the document to its path below the root directory.
+func (p *DirPublisher) Publish(artifact *Artifact) error {
+	file, err := p.file(artifact)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(file), 0o755); err != nil { //nolint:mnd
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	if err = os.WriteFile(file, artifact.Document, 0o644); err != nil { //nolint:gosec,mnd
+		return fmt.Errorf("write document: %w", err)
+	}
+
+	return nil
+}
+
+// Remove deletes the document from its path below the root directory.
+func (p *DirPublisher) Remove(artifact *Artifact) error {
+	file, err := p.file(artifact)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove document: %w", err)
+	}
+
+	return nil
+}
+
+func (p *DirPublisher) file(artifact *Artifact) (string, error) {
+	file := filepath.Join(p.root, filepath.FromSlash(artifact.Path))
+
+	rel, err := filepath.Rel(p.root, file)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("document path %s is outside of the root directory", artifact.Path)
+	}
+
+	return file, nil
+}
+
+// MemoryPublisher keeps the did.json documents in memory, it serves them over HTTP by host and path.
+type MemoryPublisher struct {
+	mutex     sync.RWMutex
+	documents map[string][]byte
+}
+
+// NewMemoryPublisher creates an in-memory publisher.
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{documents: make(map[string][]byte)}
+}
+
+// Publish stores the document.
+func (p *MemoryPublisher) Publish(artifact *Artifact) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.documents[artifact.Host+artifact.Path] = artifact.Document
+
+	return nil
+}
+
+// Remove deletes the document.
+func (p *MemoryPublisher) Remove(artifact *Artifact) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	delete(p.documents, artifact.Host+artifact.Path)
+
+	return nil
+}
+
+// Document returns the document published at the host (including the port) and path.
+func (p *MemoryPublisher) Document(host, documentPath string) ([]byte, bool) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	doc, ok := p.documents[host+documentPath]
+
+	return doc, ok
+}
+
+// ServeHTTP serves the document published at the host and path of the request.
+func (p *MemoryPublisher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	doc, ok := p.Document(r.Host, r.URL.Path)
+	if !ok || r.Method != http.MethodGet {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", did.ContentTypeDIDJSON)
+
+	if _, err := w.Write(doc); err != nil {
+		errorLogger.Printf("Failed to write did doc: %v", err)
+	}
+}
+
+// HTTPPublisher publishes the did.json documents with HTTP PUT requests to their URL and removes them with HTTP
+// DELETE requests.
+type HTTPPublisher struct {
+	client *http.Client
+	header http.Header
+}
+
+// HTTPPublisherOption configures the HTTP publisher.
+type HTTPPublisherOption func(opts *HTTPPublisher)
+
+// WithPublisherHTTPClient sets the HTTP client of the publisher.
+func WithPublisherHTTPClient(client *http.Client) HTTPPublisherOption {
+	return func(opts *HTTPPublisher) {
+		opts.client = client
+	}
+}
+
+// WithPublisherHeader adds a header to the publisher requests, e.g. an Authorization header.
+func WithPublisherHeader(key, value string) HTTPPublisherOption {
+	return func(opts *HTTPPublisher) {
+		opts.header.Add(key, value)
+	}
+}
+
+// NewHTTPPublisher creates an HTTP publisher.
+func NewHTTPPublisher(opts ...HTTPPublisherOption) *HTTPPublisher {
+	p := &HTTPPublisher{client: &http.Client{}, header: make(http.Header)}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Publish sends the document with an HTTP PUT request to its URL.
+func (p *HTTPPublisher) Publish(artifact *Artifact) error {
+	return p.send(http.MethodPut, artifact.URL, artifact.Document)
+}
+
+// Remove sends an HTTP DELETE request to the URL of the document, a not found response is not an error.
+func (p *HTTPPublisher) Remove(artifact *Artifact) error {
+	return p.send(http.MethodDelete, artifact.URL, nil)
+}
+
+func (p *HTTPPublisher) send(method, address string, body []byte) error {
+	var reader io.Reader
+
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), method, address, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create http request --> %w", err)
+	}
+
+	for key, values := range p.header {
+		req.Header[key] = values
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", did.ContentTypeDIDJSON)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("http request unsuccessful --> %w", err)
+	}
+
+	defer closeResponseBody(resp.Body)
+
+	if method == http.MethodDelete && resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("http server returned status code [%d]", resp.StatusCode)
+	}
+
+	return nil
+}
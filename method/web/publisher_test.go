@@ -0,0 +1,140 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package web
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+func TestDirPublisher(t *testing.T) {
+	t.Run("test publish and remove", func(t *testing.T) {
+		root := t.TempDir()
+		v := New(WithPublisher(NewDirPublisher(root)))
+
+		_, err := v.Create(newDoc(t), vdrapi.WithOption(DomainOpt, "example.com"), vdrapi.WithOption(PathOpt, "alice"))
+		require.NoError(t, err)
+
+		docBytes, err := os.ReadFile(filepath.Join(root, "alice", "did.json"))
+		require.NoError(t, err)
+
+		doc, err := did.ParseDocument(docBytes)
+		require.NoError(t, err)
+		require.Equal(t, "did:web:example.com:alice", doc.ID)
+
+		require.NoError(t, v.Deactivate("did:web:example.com:alice"))
+
+		_, err = os.Stat(filepath.Join(root, "alice", "did.json"))
+		require.True(t, os.IsNotExist(err))
+
+		// removing a document that does not exist is not an error.
+		require.NoError(t, v.Deactivate("did:web:example.com:alice"))
+	})
+
+	t.Run("test path outside of root", func(t *testing.T) {
+		p := NewDirPublisher(t.TempDir())
+
+		err := p.Publish(&Artifact{Path: "/../did.json"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "outside of the root directory")
+
+		err = p.Remove(&Artifact{Path: "/"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "outside of the root directory")
+	})
+}
+
+func TestMemoryPublisher(t *testing.T) {
+	p := NewMemoryPublisher()
+
+	require.NoError(t, p.Publish(&Artifact{Host: "example.com", Path: "/.well-known/did.json", Document: []byte("{}")}))
+
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/.well-known/did.json", nil)
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, did.ContentTypeDIDJSON, rec.Header().Get("Content-Type"))
+	require.Equal(t, "{}", rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "https://other.com/.well-known/did.json", nil)
+	rec = httptest.NewRecorder()
+	p.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	require.NoError(t, p.Remove(&Artifact{Host: "example.com", Path: "/.well-known/did.json"}))
+
+	_, ok := p.Document("example.com", "/.well-known/did.json")
+	require.False(t, ok)
+}
+
+func TestHTTPPublisher(t *testing.T) {
+	t.Run("test publish and remove", func(t *testing.T) {
+		var (
+			method, contentType, authorization string
+			body                               []byte
+		)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method = r.Method
+			contentType = r.Header.Get("Content-Type")
+			authorization = r.Header.Get("Authorization")
+
+			var err error
+
+			body, err = io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			if r.Method == http.MethodDelete {
+				w.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		p := NewHTTPPublisher(WithPublisherHTTPClient(server.Client()),
+			WithPublisherHeader("Authorization", "Bearer token"))
+
+		err := p.Publish(&Artifact{URL: server.URL + "/alice/did.json", Document: []byte("{}")})
+		require.NoError(t, err)
+		require.Equal(t, http.MethodPut, method)
+		require.Equal(t, did.ContentTypeDIDJSON, contentType)
+		require.Equal(t, "Bearer token", authorization)
+		require.Equal(t, "{}", string(body))
+
+		require.NoError(t, p.Remove(&Artifact{URL: server.URL + "/alice/did.json"}))
+		require.Equal(t, http.MethodDelete, method)
+	})
+
+	t.Run("test error status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		err := NewHTTPPublisher().Publish(&Artifact{URL: server.URL + "/did.json", Document: []byte("{}")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "http server returned status code [403]")
+	})
+
+	t.Run("test request failure", func(t *testing.T) {
+		err := NewHTTPPublisher().Publish(&Artifact{URL: "http://[::1]:namedport/did.json"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to create http request")
+	})
+}
@@ -7,13 +7,15 @@ SPDX-License-Identifier: Apache-2.0
 package web
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/trustbloc/did-go/doc/did"
 	vdrapi "github.com/trustbloc/did-go/vdr/api"
@@ -30,8 +32,19 @@ const (
 var errorLogger = log.New(os.Stderr, " [did-go/vdr/web] ", log.Ldate|log.Ltime|log.LUTC)
 
 // Read resolves a did:web did.
-func (v *VDR) Read(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) { //nolint: gocyclo
-	httpClient := &http.Client{}
+func (v *VDR) Read(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	return v.ReadContext(context.Background(), didID, opts...)
+}
+
+// ReadContext resolves a did:web did, the http request is bound to the given context.
+//
+// The did.json document is fetched over https, unless the UseHTTPOpt option is given, following a limited
+// number of redirects, within the timeout of the VDR. Its size and content type are checked, and the default
+// client refuses to connect to hosts resolving to non-public addresses. With an HTTP cache, a fresh cached
+// document is returned without a request and a stale one is revalidated with a conditional request.
+func (v *VDR) ReadContext(ctx context.Context, didID string, //nolint: gocyclo
+	opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	httpClient := v.httpClient
 
 	didOpts := &vdrapi.DIDMethodOpts{Values: make(map[string]interface{})}
 	// Apply options
@@ -57,35 +70,94 @@ func (v *VDR) Read(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolu
 
 	address, _, err := parseDIDWeb(didID, useHTTP)
 	if err != nil {
-		return nil, fmt.Errorf("error resolving did:web did --> could not parse did:web did --> %w", err)
+		return nil, fmt.Errorf("error resolving did:web did --> could not parse did:web did --> %w: %w",
+			err, vdrapi.ErrInvalidDID)
 	}
 
-	resp, err := httpClient.Get(address)
+	body, updateCache, err := v.fetch(ctx, httpClient, address, useHTTP)
 	if err != nil {
-		return nil, fmt.Errorf("error resolving did:web did --> http request unsuccessful --> %w", err)
+		return nil, err
 	}
 
-	defer closeResponseBody(resp.Body)
+	doc, err := did.ParseDocument(body)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving did:web did --> error parsing did doc --> %w", err)
+	}
+
+	if doc.ID != didID {
+		return nil, fmt.Errorf("did id %s not matching did %s", doc.ID, didID)
+	}
+
+	updateCache()
+
+	return &did.DocResolution{
+		DIDDocument:        doc,
+		ResolutionMetadata: &did.ResolutionMetadata{ContentType: did.ContentTypeDIDLDJSON},
+	}, nil
+}
+
+// fetch returns the did.json document located at address and the function updating the HTTP cache of the VDR,
+// to be called once the document is validated. A fresh cached document is returned without a request.
+func (v *VDR) fetch(ctx context.Context, httpClient *http.Client, address string,
+	useHTTP bool) ([]byte, func(), error) {
+	var cached *cacheEntry
+
+	updateCache := func(*cacheEntry) func() {
+		return func() {}
+	}
+
+	if v.cache != nil {
+		cached = v.cache.get(address)
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("http server returned status code [%d]", resp.StatusCode)
+		if cached != nil && cached.fresh(time.Now()) {
+			return cached.Document, func() {}, nil
+		}
+
+		updateCache = func(entry *cacheEntry) func() {
+			return func() { v.cache.put(address, entry) }
+		}
 	}
 
-	body, err := ioutil.ReadAll(resp.Body)
+	ctx, cancel := v.withTimeout(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error resolving did:web did --> error reading http response body: %s --> %w", body, err)
+		return nil, nil, fmt.Errorf("error resolving did:web did --> failed to create http request --> %w", err)
 	}
 
-	doc, err := did.ParseDocument(body)
+	req.Header.Set("Accept", strings.Join(v.contentTypes, ", "))
+
+	if cached != nil {
+		cached.setConditionalHeaders(req.Header)
+	}
+
+	resp, err := v.withPolicy(httpClient, useHTTP).Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error resolving did:web did --> error parsing did doc --> %w", err)
+		return nil, nil, fmt.Errorf("error resolving did:web did --> http request unsuccessful --> %w", err)
 	}
 
-	if doc.ID != didID {
-		return nil, fmt.Errorf("did id %s not matching did %s", doc.ID, didID)
+	defer closeResponseBody(resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		return cached.Document, updateCache(cached.revalidated(resp.Header, time.Now())), nil
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, nil, fmt.Errorf("error resolving did:web did --> %w", vdrapi.ErrNotFound)
+	case resp.StatusCode != http.StatusOK:
+		return nil, nil, fmt.Errorf("http server returned status code [%d]", resp.StatusCode)
+	}
+
+	if err = v.checkContentType(resp.Header); err != nil {
+		return nil, nil, fmt.Errorf("error resolving did:web did --> %w", err)
+	}
+
+	body, err := v.readBody(resp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error resolving did:web did --> error reading http response body --> %w", err)
 	}
 
-	return &did.DocResolution{DIDDocument: doc}, nil
+	return body, updateCache(cacheEntryFromResponse(body, resp.Header, time.Now())), nil
 }
 
 func closeResponseBody(respBody io.Closer) {
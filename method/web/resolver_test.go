@@ -8,6 +8,7 @@ package web
 
 import (
 	"bytes"
+	"context"
 	_ "embed"
 	"fmt"
 	"io"
@@ -85,6 +86,7 @@ func TestParseDID(t *testing.T) {
 func TestResolveDID(t *testing.T) {
 	t.Run("test resolve did with request failure", func(t *testing.T) {
 		s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", didapi.ContentTypeDIDJSON)
 			_, err := w.Write([]byte(invalidDoc))
 			require.NoError(t, err)
 		}))
@@ -98,6 +100,7 @@ func TestResolveDID(t *testing.T) {
 	})
 	t.Run("test resolve did with invalid doc format failure", func(t *testing.T) {
 		s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", didapi.ContentTypeDIDJSON)
 			_, err := w.Write([]byte(invalidDoc))
 			require.NoError(t, err)
 		}))
@@ -112,6 +115,7 @@ func TestResolveDID(t *testing.T) {
 	t.Run("test resolve did success", func(t *testing.T) {
 		s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			data := fmt.Sprintf(validDoc, "did:web:"+urlapi.QueryEscape(r.Host))
+			w.Header().Set("Content-Type", didapi.ContentTypeDIDJSON)
 			_, err := w.Write([]byte(data))
 			require.NoError(t, err)
 		}))
@@ -124,10 +128,12 @@ func TestResolveDID(t *testing.T) {
 		expectedDoc, err := didapi.ParseDocument([]byte(data))
 		require.Nil(t, err)
 		require.Equal(t, expectedDoc, docResolution.DIDDocument)
+		require.Equal(t, didapi.ContentTypeDIDLDJSON, docResolution.ResolutionMetadata.ContentType)
 	})
 	t.Run("test resolve with wrong did id", func(t *testing.T) {
 		s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			data := fmt.Sprintf(validDoc, "did:web:123")
+			w.Header().Set("Content-Type", didapi.ContentTypeDIDJSON)
 			_, err := w.Write([]byte(data))
 			require.NoError(t, err)
 		}))
@@ -141,6 +147,7 @@ func TestResolveDID(t *testing.T) {
 	t.Run("test resolve did with path success", func(t *testing.T) {
 		s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			data := fmt.Sprintf(validDoc, "did:web:"+urlapi.QueryEscape(r.Host)+":user:example")
+			w.Header().Set("Content-Type", didapi.ContentTypeDIDJSON)
 			_, err := w.Write([]byte(data))
 			require.NoError(t, err)
 		}))
@@ -154,6 +161,24 @@ func TestResolveDID(t *testing.T) {
 		require.Nil(t, err)
 		require.Equal(t, expectedDoc, docResolution.DIDDocument)
 	})
+	t.Run("test resolve did with cancelled context", func(t *testing.T) {
+		s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data := fmt.Sprintf(validDoc, "did:web:"+urlapi.QueryEscape(r.Host))
+			w.Header().Set("Content-Type", didapi.ContentTypeDIDJSON)
+			_, err := w.Write([]byte(data))
+			require.NoError(t, err)
+		}))
+		defer s.Close()
+		did := fmt.Sprintf("did:web:%s", urlapi.QueryEscape(strings.TrimPrefix(s.URL, "https://")))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		v := New()
+		doc, err := v.ReadContext(ctx, did, vdrapi.WithOption(HTTPClientOpt, s.Client()))
+		require.Nil(t, doc)
+		require.ErrorIs(t, err, context.Canceled)
+	})
 	t.Run("test not found", func(t *testing.T) {
 		s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			http.NotFound(w, r)
@@ -165,6 +190,7 @@ func TestResolveDID(t *testing.T) {
 		v := New()
 		_, err := v.Read(did, vdrapi.WithOption(HTTPClientOpt, s.Client()))
 		require.Error(t, err)
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
 	})
 }
 
@@ -178,6 +204,7 @@ func TestResolveDomain(t *testing.T) {
 			return
 		}
 		data := fmt.Sprintf(string(aliceDoc), "did:web:"+urlapi.QueryEscape(r.Host))
+		w.Header().Set("Content-Type", didapi.ContentTypeDIDJSON)
 		_, err := w.Write([]byte(data))
 		require.NoError(t, err)
 	}))
@@ -208,6 +235,7 @@ func TestResolveWebFixtures(t *testing.T) {
 		}
 
 		data := fmt.Sprintf(string(aliceDoc), "did:web:"+urlapi.QueryEscape(r.Host)+":alice")
+		w.Header().Set("Content-Type", didapi.ContentTypeDIDJSON)
 		_, err := w.Write([]byte(data))
 		require.NoError(t, err)
 	}))
@@ -236,6 +264,7 @@ func TestResolveUscisDid(t *testing.T) {
 		DoAndReturn(func(request *http.Request) (*http.Response, error) {
 			return &http.Response{
 				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
 				Body:       io.NopCloser(bytes.NewReader(uscisDid)),
 			}, nil
 		})
@@ -7,7 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package web
 
 import (
-	"errors"
+	"fmt"
+	"net/http"
+	"time"
 
 	diddoc "github.com/trustbloc/did-go/doc/did"
 	vdrapi "github.com/trustbloc/did-go/vdr/api"
@@ -18,11 +20,44 @@ const (
 )
 
 // VDR implements the VDR interface.
-type VDR struct{}
+type VDR struct {
+	publisher             Publisher
+	httpClient            *http.Client
+	cache                 *HTTPCache
+	maxBodySize           int64
+	maxRedirects          int
+	timeout               time.Duration
+	contentTypes          []string
+	allowPrivateAddresses bool
+}
+
+// Option configures the did:web vdr.
+type Option func(opts *VDR)
+
+// WithPublisher sets the publisher the did.json documents created, updated and deactivated by the vdr are
+// written to.
+func WithPublisher(publisher Publisher) Option {
+	return func(opts *VDR) {
+		opts.publisher = publisher
+	}
+}
 
 // New creates a new VDR struct.
-func New() *VDR {
-	return &VDR{}
+func New(opts ...Option) *VDR {
+	v := &VDR{
+		maxBodySize:  defaultMaxBodySize,
+		maxRedirects: defaultMaxRedirects,
+		timeout:      defaultTimeout,
+		contentTypes: defaultContentTypes,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	v.httpClient = v.newHTTPClient()
+
+	return v
 }
 
 // Accept method of the VDR interface.
@@ -30,14 +65,46 @@ func (v *VDR) Accept(method string, opts ...vdrapi.DIDMethodOption) bool {
 	return method == namespace
 }
 
-// Update did doc.
+// Update publishes didDoc in place of the did.json document located by its did:web DID.
 func (v *VDR) Update(didDoc *diddoc.Doc, opts ...vdrapi.DIDMethodOption) error {
-	return errors.New("not supported")
+	if v.publisher == nil {
+		return fmt.Errorf("error updating did:web did doc --> %w", errNoPublisher)
+	}
+
+	artifact, err := newArtifact(didDoc, applyOpts(opts))
+	if err != nil {
+		return fmt.Errorf("error updating did:web did doc --> %w", err)
+	}
+
+	if err = v.publisher.Publish(artifact); err != nil {
+		return fmt.Errorf("error updating did:web did doc --> failed to publish did doc --> %w", err)
+	}
+
+	return nil
 }
 
-// Deactivate did doc.
+// Deactivate removes the did.json document located by the did:web DID from the publisher.
 func (v *VDR) Deactivate(did string, opts ...vdrapi.DIDMethodOption) error {
-	return errors.New("not supported")
+	if v.publisher == nil {
+		return fmt.Errorf("error deactivating did:web did --> %w", errNoPublisher)
+	}
+
+	address, _, err := parseDIDWeb(did, useHTTP(applyOpts(opts)))
+	if err != nil {
+		return fmt.Errorf("error deactivating did:web did --> could not parse did:web did --> %w: %w",
+			err, vdrapi.ErrInvalidDID)
+	}
+
+	artifact, err := locate(did, address)
+	if err != nil {
+		return fmt.Errorf("error deactivating did:web did --> %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	if err = v.publisher.Remove(artifact); err != nil {
+		return fmt.Errorf("error deactivating did:web did --> failed to remove did doc --> %w", err)
+	}
+
+	return nil
 }
 
 // Close method of the VDR interface.
@@ -10,6 +10,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
 )
 
 func TestVDRMethods(t *testing.T) {
@@ -24,18 +26,80 @@ func TestVDRMethods(t *testing.T) {
 
 func TestUpdate(t *testing.T) {
 	t.Run("test update", func(t *testing.T) {
-		v := New()
-		err := v.Update(nil)
+		publisher := NewMemoryPublisher()
+		v := New(WithPublisher(publisher))
+
+		docResolution, err := v.Create(newDoc(t), vdrapi.WithOption(DomainOpt, "example.com"))
+		require.NoError(t, err)
+
+		doc := docResolution.DIDDocument
+		doc.Authentication = nil
+
+		require.NoError(t, v.Update(doc))
+
+		published, ok := publisher.Document("example.com", "/.well-known/did.json")
+		require.True(t, ok)
+		require.NotContains(t, string(published), "authentication")
+	})
+
+	t.Run("test invalid did", func(t *testing.T) {
+		doc := newDoc(t)
+		doc.ID = "did:example:123"
+
+		err := New(WithPublisher(NewMemoryPublisher())).Update(doc)
+		require.Error(t, err)
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+	})
+
+	t.Run("test publish failure", func(t *testing.T) {
+		doc := newDoc(t)
+		doc.ID = "did:web:example.com"
+
+		err := New(WithPublisher(&failingPublisher{})).Update(doc)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "publish error")
+	})
+
+	t.Run("test missing publisher", func(t *testing.T) {
+		err := New().Update(newDoc(t))
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "not supported")
+		require.Contains(t, err.Error(), "publisher is not configured")
 	})
 }
 
 func TestDeactivate(t *testing.T) {
 	t.Run("test deactivate", func(t *testing.T) {
-		v := New()
-		err := v.Deactivate("")
+		publisher := NewMemoryPublisher()
+		v := New(WithPublisher(publisher))
+
+		_, err := v.Create(newDoc(t), vdrapi.WithOption(DomainOpt, "example.com"), vdrapi.WithOption(PathOpt, "alice"))
+		require.NoError(t, err)
+
+		require.NoError(t, v.Deactivate("did:web:example.com:alice"))
+
+		_, ok := publisher.Document("example.com", "/alice/did.json")
+		require.False(t, ok)
+	})
+
+	t.Run("test invalid did", func(t *testing.T) {
+		v := New(WithPublisher(NewMemoryPublisher()))
+
+		err := v.Deactivate("did:example:123")
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+
+		err = v.Deactivate("did:web:example.com:..:admin")
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+	})
+
+	t.Run("test remove failure", func(t *testing.T) {
+		err := New(WithPublisher(&failingPublisher{})).Deactivate("did:web:example.com")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "remove error")
+	})
+
+	t.Run("test missing publisher", func(t *testing.T) {
+		err := New().Deactivate("did:web:example.com")
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "not supported")
+		require.Contains(t, err.Error(), "publisher is not configured")
 	})
 }
@@ -0,0 +1,480 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webvh
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/method/web"
+	"github.com/trustbloc/did-go/pkg/canonicalizer"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	// SignerOpt is the Signer of the log entries appended by Create, Update and Deactivate, its key must be one of
+	// the authorized update keys.
+	SignerOpt = "signer"
+
+	// UpdateKeysOpt sets the updateKeys parameter ([]string of multibase Ed25519 keys) of the appended log entry,
+	// Create defaults to the key of the signer.
+	UpdateKeysOpt = "updateKeys"
+
+	// NextKeyHashesOpt sets the nextKeyHashes parameter ([]string) of the appended log entry, which enables
+	// pre-rotation, see NextKeyHash.
+	NextKeyHashesOpt = "nextKeyHashes"
+
+	// LogOpt is the current did.jsonl log ([]byte) Update and Deactivate append to, it is downloaded otherwise.
+	LogOpt = "log"
+)
+
+var errNoPublisher = errors.New("publisher is not configured")
+
+// NextKeyHash returns the hash of an update key to list in the nextKeyHashes parameter, committing to the key the
+// update keys are rotated to.
+func NextKeyHash(updateKey string) string {
+	return keyHash(updateKey)
+}
+
+// Create creates a did:webvh DID for didDoc and publishes its did.jsonl log.
+//
+// The DID is built from the web.DomainOpt, web.PortOpt and web.PathOpt options and the SCID of the first log
+// entry, which is signed by SignerOpt. Verification methods without controller are controlled by the created DID.
+func (v *VDR) Create(didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	if v.publisher == nil {
+		return nil, fmt.Errorf("error creating did:webvh did --> %w", errNoPublisher)
+	}
+
+	didOpts := applyOpts(opts)
+
+	signer, err := signerOpt(didOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error creating did:webvh did --> %w", err)
+	}
+
+	placeholderDID, err := buildDID(didOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error creating did:webvh did --> %w", err)
+	}
+
+	params := map[string]interface{}{
+		"method":     methodVersion,
+		"scid":       scidPlaceholder,
+		"updateKeys": []string{signer.UpdateKey()},
+	}
+
+	if err = setKeyParameters(params, didOpts); err != nil {
+		return nil, fmt.Errorf("error creating did:webvh did --> %w", err)
+	}
+
+	state, err := rawState(didDoc, placeholderDID)
+	if err != nil {
+		return nil, fmt.Errorf("error creating did:webvh did --> %w", err)
+	}
+
+	line, didID, err := firstEntry(params, state, signer, v.now())
+	if err != nil {
+		return nil, fmt.Errorf("error creating did:webvh did --> %w", err)
+	}
+
+	return v.publish(didID, line, "error creating did:webvh did")
+}
+
+// Update appends a log entry with didDoc as the new version of the DID document, signed by SignerOpt, and
+// publishes the log. UpdateKeysOpt and NextKeyHashesOpt rotate the update keys, while pre-rotation is active the
+// update keys default to the key of the signer.
+func (v *VDR) Update(didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) error {
+	if v.publisher == nil {
+		return fmt.Errorf("error updating did:webvh did --> %w", errNoPublisher)
+	}
+
+	didOpts := applyOpts(opts)
+
+	state, err := rawState(didDoc, didDoc.ID)
+	if err != nil {
+		return fmt.Errorf("error updating did:webvh did --> %w", err)
+	}
+
+	log, err := v.appendEntries(didDoc.ID, didOpts, func(latest *version) ([]map[string]interface{}, error) {
+		params := make(map[string]interface{})
+
+		if len(latest.Parameters.NextKeyHashes) > 0 {
+			params["updateKeys"] = []string{mustSigner(didOpts).UpdateKey()}
+		}
+
+		if paramsErr := setKeyParameters(params, didOpts); paramsErr != nil {
+			return nil, paramsErr
+		}
+
+		return []map[string]interface{}{{"parameters": params, "state": state}}, nil
+	})
+	if err != nil {
+		return fmt.Errorf("error updating did:webvh did --> %w", err)
+	}
+
+	_, err = v.publish(didDoc.ID, log, "error updating did:webvh did")
+
+	return err
+}
+
+// Deactivate appends a log entry deactivating the DID, signed by SignerOpt, and publishes the log. While
+// pre-rotation is active, an entry turning it off is appended first.
+func (v *VDR) Deactivate(didID string, opts ...vdrapi.DIDMethodOption) error {
+	if v.publisher == nil {
+		return fmt.Errorf("error deactivating did:webvh did --> %w", errNoPublisher)
+	}
+
+	didOpts := applyOpts(opts)
+
+	log, err := v.appendEntries(didID, didOpts, func(latest *version) ([]map[string]interface{}, error) {
+		state, stateErr := rawState(latest.Doc, didID)
+		if stateErr != nil {
+			return nil, stateErr
+		}
+
+		var entries []map[string]interface{}
+
+		if len(latest.Parameters.NextKeyHashes) > 0 {
+			entries = append(entries, map[string]interface{}{
+				"parameters": map[string]interface{}{
+					"updateKeys":    []string{mustSigner(didOpts).UpdateKey()},
+					"nextKeyHashes": []string{},
+				},
+				"state": state,
+			})
+		}
+
+		return append(entries, map[string]interface{}{
+			"parameters": map[string]interface{}{"deactivated": true, "updateKeys": []string{}},
+			"state":      state,
+		}), nil
+	})
+	if err != nil {
+		return fmt.Errorf("error deactivating did:webvh did --> %w", err)
+	}
+
+	_, err = v.publish(didID, log, "error deactivating did:webvh did")
+
+	return err
+}
+
+// appendEntries verifies the current log of the DID and appends the entries built from its latest version.
+func (v *VDR) appendEntries(didID string, didOpts *vdrapi.DIDMethodOpts,
+	build func(latest *version) ([]map[string]interface{}, error)) ([]byte, error) {
+	signer, err := signerOpt(didOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := parseDIDWebVH(didID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	log, ok := didOpts.Values[LogOpt].([]byte)
+	if !ok {
+		log, err = v.fetchLog(context.Background(), loc)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	versions, err := verifyLog(didID, loc.SCID, log, v.now())
+	if err != nil {
+		return nil, err
+	}
+
+	latest := versions[len(versions)-1]
+	if latest.Parameters.Deactivated {
+		return nil, errors.New("did is deactivated")
+	}
+
+	entries, err := build(latest)
+	if err != nil {
+		return nil, err
+	}
+
+	log = append(slices.Clip(bytes.TrimRight(log, "\n")), '\n')
+	prevVersionID, prevTime := latest.VersionID, latest.VersionTime
+
+	for _, e := range entries {
+		versionTime := v.now().UTC()
+		if !versionTime.After(prevTime) {
+			return nil, errors.New("the versionTime of the latest version is not in the past")
+		}
+
+		number, _, err := splitVersionID(prevVersionID)
+		if err != nil {
+			return nil, err
+		}
+
+		e["versionTime"] = versionTime.Format(time.RFC3339Nano)
+
+		line, versionID, err := signedEntry(e, prevVersionID, number+1, signer, versionTime)
+		if err != nil {
+			return nil, err
+		}
+
+		log = append(append(log, line...), '\n')
+		prevVersionID, prevTime = versionID, versionTime
+	}
+
+	return log, nil
+}
+
+// firstEntry returns the first log entry of a DID and the DID, the SCID being the hash of the entry with
+// placeholders in place of the SCID.
+func firstEntry(params, state map[string]interface{}, signer Signer, now time.Time) ([]byte, string, error) {
+	versionTime := now.UTC()
+
+	preliminary, err := canonicalizer.MarshalCanonical(map[string]interface{}{
+		"versionId":   scidPlaceholder,
+		"versionTime": versionTime.Format(time.RFC3339Nano),
+		"parameters":  params,
+		"state":       state,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("canonicalize log entry: %w", err)
+	}
+
+	scid := multihash(preliminary)
+
+	e, err := unmarshalEntry(bytes.ReplaceAll(preliminary, []byte(scidPlaceholder), []byte(scid)))
+	if err != nil {
+		return nil, "", err
+	}
+
+	line, _, err := signedEntry(e, scid, 1, signer, versionTime)
+	if err != nil {
+		return nil, "", err
+	}
+
+	didID, ok := e["state"].(map[string]interface{})["id"].(string)
+	if !ok {
+		return nil, "", errors.New("missing did document id")
+	}
+
+	return line, didID, nil
+}
+
+// signedEntry sets the versionId of the log entry e from the hash of the entry chained to prevVersionID and signs
+// the entry.
+func signedEntry(e map[string]interface{}, prevVersionID string, number int, signer Signer,
+	versionTime time.Time) ([]byte, string, error) {
+	e["versionId"] = prevVersionID
+
+	hash, err := hashJSON(e)
+	if err != nil {
+		return nil, "", err
+	}
+
+	versionID := strconv.Itoa(number) + "-" + hash
+	e["versionId"] = versionID
+
+	p, err := signEntry(e, signer, versionTime)
+	if err != nil {
+		return nil, "", err
+	}
+
+	e["proof"] = []*proof{p}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return nil, "", fmt.Errorf("marshal log entry: %w", err)
+	}
+
+	return line, versionID, nil
+}
+
+// publish verifies and publishes the log of the DID, and the did.json document of the parallel did:web DID when
+// enabled.
+func (v *VDR) publish(didID string, log []byte, errPrefix string) (*did.DocResolution, error) {
+	loc, err := parseDIDWebVH(didID)
+	if err != nil {
+		return nil, fmt.Errorf("%s --> %w: %w", errPrefix, err, vdrapi.ErrInvalidDID)
+	}
+
+	versions, err := verifyLog(didID, loc.SCID, log, v.now())
+	if err != nil {
+		return nil, fmt.Errorf("%s --> %w", errPrefix, err)
+	}
+
+	err = v.publisher.Publish(&web.Artifact{
+		DID:      didID,
+		URL:      loc.LogURL(),
+		Host:     loc.Host,
+		Path:     loc.LogPath(),
+		Document: log,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s --> failed to publish did log --> %w", errPrefix, err)
+	}
+
+	latest := versions[len(versions)-1]
+
+	if v.parallelDIDWeb {
+		if err = v.publishDIDWeb(loc, latest); err != nil {
+			return nil, fmt.Errorf("%s --> failed to publish did:web did doc --> %w", errPrefix, err)
+		}
+	}
+
+	return resolution(versions, latest), nil
+}
+
+// publishDIDWeb publishes the did.json document of the parallel did:web DID, which is the latest version of the
+// DID document with the did:web DID and the did:webvh DID as alsoKnownAs, or removes it once deactivated.
+func (v *VDR) publishDIDWeb(loc *location, latest *version) error {
+	artifact := &web.Artifact{
+		DID:  loc.WebDID,
+		URL:  "https://" + loc.Host + loc.WebDocPath(),
+		Host: loc.Host,
+		Path: loc.WebDocPath(),
+	}
+
+	if latest.Parameters.Deactivated {
+		return v.publisher.Remove(artifact)
+	}
+
+	docBytes, err := latest.Doc.JSONBytes()
+	if err != nil {
+		return fmt.Errorf("marshal did doc: %w", err)
+	}
+
+	webDoc, err := did.ParseDocument(bytes.ReplaceAll(docBytes, []byte(loc.DID), []byte(loc.WebDID)))
+	if err != nil {
+		return fmt.Errorf("parse did:web did doc: %w", err)
+	}
+
+	if !slices.Contains(webDoc.AlsoKnownAs, loc.DID) {
+		webDoc.AlsoKnownAs = append(webDoc.AlsoKnownAs, loc.DID)
+	}
+
+	artifact.Document, err = webDoc.JSONBytes()
+	if err != nil {
+		return fmt.Errorf("marshal did:web did doc: %w", err)
+	}
+
+	return v.publisher.Publish(artifact)
+}
+
+// buildDID builds the did:webvh DID of the domain, port and path options, with the SCID placeholder.
+func buildDID(didOpts *vdrapi.DIDMethodOpts) (string, error) {
+	domain, ok := didOpts.Values[web.DomainOpt].(string)
+	if !ok || domain == "" || strings.ContainsAny(domain, ":/") {
+		return "", errors.New("domain opt must be a non empty domain name")
+	}
+
+	if p, exists := didOpts.Values[web.PortOpt]; exists {
+		port, isInt := p.(int)
+		if !isInt || port <= 0 || port > 65535 {
+			return "", errors.New("port opt must be an int between 1 and 65535")
+		}
+
+		domain += ":" + strconv.Itoa(port)
+	}
+
+	segments := []string{"did", namespace, scidPlaceholder, url.QueryEscape(domain)}
+
+	switch p := didOpts.Values[web.PathOpt].(type) {
+	case nil:
+	case string:
+		segments = append(segments, strings.Split(strings.Trim(p, "/"), "/")...)
+	case []string:
+		segments = append(segments, p...)
+	default:
+		return "", errors.New("path opt must be a string or []string")
+	}
+
+	for _, segment := range segments[4:] {
+		if segment == "" || segment == "." || segment == ".." || strings.ContainsAny(segment, ":/?#%") {
+			return "", fmt.Errorf("invalid path segment '%s'", segment)
+		}
+	}
+
+	return strings.Join(segments, ":"), nil
+}
+
+// rawState returns didDoc with the given ID as the state of a log entry, verification methods without
+// controller are controlled by the DID.
+func rawState(didDoc *did.Doc, didID string) (map[string]interface{}, error) {
+	docBytes, err := didDoc.JSONBytes()
+	if err != nil {
+		return nil, fmt.Errorf("marshal did doc: %w", err)
+	}
+
+	var state map[string]interface{}
+
+	if err = json.Unmarshal(docBytes, &state); err != nil {
+		return nil, fmt.Errorf("unmarshal did doc: %w", err)
+	}
+
+	state["id"] = didID
+
+	setController(state, didID)
+
+	return state, nil
+}
+
+func setController(value interface{}, didID string) {
+	switch val := value.(type) {
+	case map[string]interface{}:
+		if controller, ok := val["controller"]; ok && controller == "" {
+			val["controller"] = didID
+		}
+
+		for _, entry := range val {
+			setController(entry, didID)
+		}
+	case []interface{}:
+		for _, entry := range val {
+			setController(entry, didID)
+		}
+	}
+}
+
+func setKeyParameters(params map[string]interface{}, didOpts *vdrapi.DIDMethodOpts) error {
+	for _, name := range []string{UpdateKeysOpt, NextKeyHashesOpt} {
+		value, ok := didOpts.Values[name]
+		if !ok {
+			continue
+		}
+
+		keys, ok := value.([]string)
+		if !ok {
+			return fmt.Errorf("%s opt must be a []string", name)
+		}
+
+		params[name] = keys
+	}
+
+	return nil
+}
+
+func signerOpt(didOpts *vdrapi.DIDMethodOpts) (Signer, error) {
+	signer, ok := didOpts.Values[SignerOpt].(Signer)
+	if !ok || signer == nil {
+		return nil, errors.New("signer opt must be a webvh.Signer")
+	}
+
+	return signer, nil
+}
+
+// mustSigner returns the signer option, which appendEntries has checked.
+func mustSigner(didOpts *vdrapi.DIDMethodOpts) Signer {
+	signer, _ := signerOpt(didOpts) //nolint:errcheck
+
+	return signer
+}
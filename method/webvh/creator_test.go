@@ -0,0 +1,336 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webvh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/kms-go/doc/util/fingerprint"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/method/web"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+type failingPublisher struct{}
+
+func (p *failingPublisher) Publish(*web.Artifact) error {
+	return errors.New("publish error")
+}
+
+func (p *failingPublisher) Remove(*web.Artifact) error {
+	return errors.New("remove error")
+}
+
+type fixture struct {
+	publisher *web.MemoryPublisher
+	server    *httptest.Server
+	vdr       *VDR
+	host      string
+	port      int
+	clock     time.Time
+}
+
+func newFixture(t *testing.T, opts ...Option) *fixture {
+	t.Helper()
+
+	f := &fixture{publisher: web.NewMemoryPublisher(), clock: time.Now().Add(-time.Hour)}
+
+	f.server = httptest.NewTLSServer(f.publisher)
+	t.Cleanup(f.server.Close)
+
+	serverURL, err := url.Parse(f.server.URL)
+	require.NoError(t, err)
+
+	f.host = serverURL.Hostname()
+	f.port, err = strconv.Atoi(serverURL.Port())
+	require.NoError(t, err)
+
+	f.vdr = New(append([]Option{WithPublisher(f.publisher), WithHTTPClient(f.server.Client())}, opts...)...)
+	f.vdr.now = f.now
+
+	return f
+}
+
+// now advances the clock of the vdr by a minute on every call.
+func (f *fixture) now() time.Time {
+	f.clock = f.clock.Add(time.Minute)
+
+	return f.clock
+}
+
+func (f *fixture) create(t *testing.T, signer Signer, opts ...vdrapi.DIDMethodOption) *did.DocResolution {
+	t.Helper()
+
+	docResolution, err := f.vdr.Create(newDoc(t), append([]vdrapi.DIDMethodOption{
+		vdrapi.WithOption(SignerOpt, signer),
+		vdrapi.WithOption(web.DomainOpt, f.host),
+		vdrapi.WithOption(web.PortOpt, f.port),
+	}, opts...)...)
+	require.NoError(t, err)
+
+	return docResolution
+}
+
+// newDoc returns a DID document with a Multikey identified by its multibase value, which webvh makes controlled
+// by the DID.
+func newDoc(t *testing.T) *did.Doc {
+	t.Helper()
+
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	multikey := append([]byte{0xed, 0x01}, pubKey...)
+	vm := did.NewVerificationMethodFromBytes("#"+fingerprint.KeyFingerprint(fingerprint.ED25519PubKeyMultiCodec, pubKey),
+		"Multikey", "", multikey)
+
+	return &did.Doc{
+		Context:            []string{did.ContextV1},
+		VerificationMethod: []did.VerificationMethod{*vm},
+		Authentication:     []did.Verification{*did.NewReferencedVerification(vm, did.Authentication)},
+		AssertionMethod:    []did.Verification{*did.NewReferencedVerification(vm, did.AssertionMethod)},
+	}
+}
+
+func newSigner(t *testing.T) Signer {
+	t.Helper()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return NewEd25519Signer(privateKey)
+}
+
+func TestCreate(t *testing.T) {
+	t.Run("test create and resolve did", func(t *testing.T) {
+		f := newFixture(t)
+
+		created := f.create(t, newSigner(t), vdrapi.WithOption(web.PathOpt, "users/alice"))
+
+		didID := created.DIDDocument.ID
+		require.True(t, strings.HasPrefix(didID, "did:webvh:"))
+		require.True(t, strings.HasSuffix(didID, ":"+f.host+"%3A"+strconv.Itoa(f.port)+":users:alice"))
+		require.Equal(t, didID, created.DIDDocument.VerificationMethod[0].Controller)
+		require.True(t, strings.HasPrefix(created.DocumentMetadata.VersionID, "1-"))
+		require.Equal(t, created.DocumentMetadata.Created, created.DocumentMetadata.Updated)
+
+		_, ok := f.publisher.Document(f.server.Listener.Addr().String(), "/users/alice/did.jsonl")
+		require.True(t, ok)
+
+		resolved, err := f.vdr.Read(didID)
+		require.NoError(t, err)
+		require.Equal(t, didID, resolved.DIDDocument.ID)
+		require.Equal(t, created.DocumentMetadata, resolved.DocumentMetadata)
+	})
+
+	t.Run("test create with parallel did:web", func(t *testing.T) {
+		f := newFixture(t, WithParallelDIDWeb())
+
+		created := f.create(t, newSigner(t))
+
+		webDID := "did:web:" + f.host + "%3A" + strconv.Itoa(f.port)
+
+		resolved, err := web.New().Read(webDID, vdrapi.WithOption(web.HTTPClientOpt, f.server.Client()))
+		require.NoError(t, err)
+		require.Equal(t, webDID, resolved.DIDDocument.ID)
+		require.Equal(t, []string{created.DIDDocument.ID}, resolved.DIDDocument.AlsoKnownAs)
+		require.Equal(t, webDID, resolved.DIDDocument.VerificationMethod[0].Controller)
+	})
+
+	t.Run("test invalid options", func(t *testing.T) {
+		f := newFixture(t)
+
+		_, err := f.vdr.Create(newDoc(t), vdrapi.WithOption(web.DomainOpt, f.host))
+		require.ErrorContains(t, err, "signer opt must be a webvh.Signer")
+
+		_, err = f.vdr.Create(newDoc(t), vdrapi.WithOption(SignerOpt, newSigner(t)))
+		require.ErrorContains(t, err, "domain opt must be a non empty domain name")
+
+		_, err = f.vdr.Create(newDoc(t), vdrapi.WithOption(SignerOpt, newSigner(t)),
+			vdrapi.WithOption(web.DomainOpt, f.host), vdrapi.WithOption(web.PortOpt, 0))
+		require.ErrorContains(t, err, "port opt must be an int")
+
+		_, err = f.vdr.Create(newDoc(t), vdrapi.WithOption(SignerOpt, newSigner(t)),
+			vdrapi.WithOption(web.DomainOpt, f.host), vdrapi.WithOption(web.PathOpt, "a/../b"))
+		require.ErrorContains(t, err, "invalid path segment")
+
+		_, err = f.vdr.Create(newDoc(t), vdrapi.WithOption(SignerOpt, newSigner(t)),
+			vdrapi.WithOption(web.DomainOpt, f.host), vdrapi.WithOption(UpdateKeysOpt, "key"))
+		require.ErrorContains(t, err, "updateKeys opt must be a []string")
+
+		_, err = f.vdr.Create(newDoc(t), vdrapi.WithOption(SignerOpt, newSigner(t)),
+			vdrapi.WithOption(web.DomainOpt, f.host), vdrapi.WithOption(UpdateKeysOpt, []string{"z6Mkother"}))
+		require.ErrorContains(t, err, "is not an authorized update key")
+	})
+
+	t.Run("test publisher errors", func(t *testing.T) {
+		_, err := New().Create(newDoc(t))
+		require.ErrorContains(t, err, "publisher is not configured")
+
+		_, err = New(WithPublisher(&failingPublisher{})).Create(newDoc(t),
+			vdrapi.WithOption(SignerOpt, newSigner(t)), vdrapi.WithOption(web.DomainOpt, "example.com"))
+		require.ErrorContains(t, err, "publish error")
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("test update and resolve versions", func(t *testing.T) {
+		f := newFixture(t)
+		signer := newSigner(t)
+
+		created := f.create(t, signer)
+		didID := created.DIDDocument.ID
+
+		doc := newDoc(t)
+		doc.ID = didID
+
+		require.NoError(t, f.vdr.Update(doc, vdrapi.WithOption(SignerOpt, signer)))
+
+		resolved, err := f.vdr.Read(didID)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(resolved.DocumentMetadata.VersionID, "2-"))
+		require.Equal(t, created.DocumentMetadata.Created, resolved.DocumentMetadata.Created)
+		require.NotEqual(t, created.DocumentMetadata.Updated, resolved.DocumentMetadata.Updated)
+		require.Equal(t, doc.VerificationMethod[0].Value, resolved.DIDDocument.VerificationMethod[0].Value)
+
+		first, err := f.vdr.Read(didID, vdrapi.WithOption(VersionNumberOpt, 1))
+		require.NoError(t, err)
+		require.Equal(t, created.DocumentMetadata.VersionID, first.DocumentMetadata.VersionID)
+	})
+
+	t.Run("test rotate update keys", func(t *testing.T) {
+		f := newFixture(t)
+		signer, next := newSigner(t), newSigner(t)
+
+		didID := f.create(t, signer).DIDDocument.ID
+
+		doc := newDoc(t)
+		doc.ID = didID
+
+		require.NoError(t, f.vdr.Update(doc, vdrapi.WithOption(SignerOpt, signer),
+			vdrapi.WithOption(UpdateKeysOpt, []string{next.UpdateKey()})))
+
+		err := f.vdr.Update(doc, vdrapi.WithOption(SignerOpt, signer))
+		require.ErrorIs(t, err, ErrInvalidLog)
+		require.ErrorContains(t, err, "is not an authorized update key")
+
+		require.NoError(t, f.vdr.Update(doc, vdrapi.WithOption(SignerOpt, next)))
+	})
+
+	t.Run("test pre-rotation", func(t *testing.T) {
+		f := newFixture(t)
+		signer, next, other := newSigner(t), newSigner(t), newSigner(t)
+
+		didID := f.create(t, signer,
+			vdrapi.WithOption(NextKeyHashesOpt, []string{NextKeyHash(next.UpdateKey())})).DIDDocument.ID
+
+		doc := newDoc(t)
+		doc.ID = didID
+
+		err := f.vdr.Update(doc, vdrapi.WithOption(SignerOpt, signer))
+		require.ErrorContains(t, err, "is not committed to by nextKeyHashes")
+
+		err = f.vdr.Update(doc, vdrapi.WithOption(SignerOpt, other))
+		require.ErrorContains(t, err, "is not committed to by nextKeyHashes")
+
+		require.NoError(t, f.vdr.Update(doc, vdrapi.WithOption(SignerOpt, next),
+			vdrapi.WithOption(NextKeyHashesOpt, []string{NextKeyHash(other.UpdateKey())})))
+
+		require.NoError(t, f.vdr.Deactivate(didID, vdrapi.WithOption(SignerOpt, other)))
+
+		resolved, err := f.vdr.Read(didID)
+		require.NoError(t, err)
+		require.True(t, resolved.DocumentMetadata.Deactivated)
+		require.True(t, strings.HasPrefix(resolved.DocumentMetadata.VersionID, "4-"))
+	})
+
+	t.Run("test update with log option", func(t *testing.T) {
+		f := newFixture(t)
+		signer := newSigner(t)
+
+		didID := f.create(t, signer).DIDDocument.ID
+
+		log, ok := f.publisher.Document(f.server.Listener.Addr().String(), "/.well-known/did.jsonl")
+		require.True(t, ok)
+
+		doc := newDoc(t)
+		doc.ID = didID
+
+		v := New(WithPublisher(f.publisher))
+		v.now = f.now
+
+		require.NoError(t, v.Update(doc, vdrapi.WithOption(SignerOpt, signer), vdrapi.WithOption(LogOpt, log)))
+
+		resolved, err := f.vdr.Read(didID)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(resolved.DocumentMetadata.VersionID, "2-"))
+	})
+
+	t.Run("test update errors", func(t *testing.T) {
+		f := newFixture(t)
+
+		require.ErrorContains(t, New().Update(newDoc(t)), "publisher is not configured")
+
+		doc := newDoc(t)
+		doc.ID = "did:webvh:invalid"
+
+		err := f.vdr.Update(doc, vdrapi.WithOption(SignerOpt, newSigner(t)))
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+
+		err = f.vdr.Update(doc)
+		require.ErrorContains(t, err, "signer opt must be a webvh.Signer")
+	})
+}
+
+func TestDeactivate(t *testing.T) {
+	t.Run("test deactivate", func(t *testing.T) {
+		f := newFixture(t, WithParallelDIDWeb())
+		signer := newSigner(t)
+
+		didID := f.create(t, signer).DIDDocument.ID
+
+		_, ok := f.publisher.Document(f.server.Listener.Addr().String(), "/.well-known/did.json")
+		require.True(t, ok)
+
+		require.NoError(t, f.vdr.Deactivate(didID, vdrapi.WithOption(SignerOpt, signer)))
+
+		resolved, err := f.vdr.Read(didID)
+		require.NoError(t, err)
+		require.True(t, resolved.DocumentMetadata.Deactivated)
+
+		_, ok = f.publisher.Document(f.server.Listener.Addr().String(), "/.well-known/did.json")
+		require.False(t, ok)
+
+		err = f.vdr.Deactivate(didID, vdrapi.WithOption(SignerOpt, signer))
+		require.ErrorContains(t, err, "did is deactivated")
+
+		doc := newDoc(t)
+		doc.ID = didID
+
+		err = f.vdr.Update(doc, vdrapi.WithOption(SignerOpt, signer))
+		require.ErrorContains(t, err, "did is deactivated")
+	})
+
+	t.Run("test deactivate errors", func(t *testing.T) {
+		require.ErrorContains(t, New().Deactivate("did:webvh:x"), "publisher is not configured")
+
+		f := newFixture(t)
+
+		err := f.vdr.Deactivate("did:webvh:"+strings.Repeat("z", scidMinSize)+":"+f.host+"%3A"+
+			strconv.Itoa(f.port), vdrapi.WithOption(SignerOpt, newSigner(t)))
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+	})
+}
@@ -0,0 +1,335 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webvh
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/pkg/canonicalizer"
+)
+
+const (
+	// scidPlaceholder stands for the SCID in the first log entry when the SCID is computed.
+	scidPlaceholder = "{SCID}"
+
+	sha256MultihashCode = 0x12
+)
+
+// ErrInvalidLog is returned when the did.jsonl log of a DID fails verification.
+var ErrInvalidLog = errors.New("invalid did:webvh log")
+
+// entry is a did.jsonl log entry.
+type entry struct {
+	VersionID   string                     `json:"versionId"`
+	VersionTime string                     `json:"versionTime"`
+	Parameters  map[string]json.RawMessage `json:"parameters"`
+	State       json.RawMessage            `json:"state"`
+	Proof       json.RawMessage            `json:"proof,omitempty"`
+}
+
+// parameters are the DID parameters in effect after a log entry.
+type parameters struct {
+	Method        string
+	SCID          string
+	UpdateKeys    []string
+	NextKeyHashes []string
+	Portable      bool
+	Deactivated   bool
+	TTL           int
+}
+
+// apply returns the parameters updated with the parameters of a log entry, absent parameters keep their value.
+func (p parameters) apply(raw map[string]json.RawMessage) (parameters, error) {
+	next := p
+	// The slices are cloned, so that unmarshalling does not overwrite the parameters of the previous entries.
+	next.UpdateKeys, next.NextKeyHashes = slices.Clone(p.UpdateKeys), slices.Clone(p.NextKeyHashes)
+
+	fields := map[string]interface{}{
+		"method":        &next.Method,
+		"scid":          &next.SCID,
+		"updateKeys":    &next.UpdateKeys,
+		"nextKeyHashes": &next.NextKeyHashes,
+		"portable":      &next.Portable,
+		"deactivated":   &next.Deactivated,
+		"ttl":           &next.TTL,
+	}
+
+	for name, value := range raw {
+		field, ok := fields[name]
+		if !ok {
+			continue
+		}
+
+		if err := json.Unmarshal(value, field); err != nil {
+			return p, fmt.Errorf("parameter %s: %w", name, err)
+		}
+	}
+
+	return next, nil
+}
+
+// version is a verified version of the DID document.
+type version struct {
+	VersionID     string
+	VersionNumber int
+	VersionTime   time.Time
+	Doc           *did.Doc
+	Parameters    parameters
+}
+
+// verifyLog verifies the did.jsonl log of didID whose SCID is scid and returns its versions. The SCID, the entry
+// hash chain, the version numbers and times, the parameters and the proofs of every entry are checked.
+func verifyLog(didID, scid string, log []byte, now time.Time) ([]*version, error) { //nolint:gocyclo
+	lines := splitLines(log)
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%w: empty log", ErrInvalidLog)
+	}
+
+	var (
+		versions    []*version
+		active      parameters
+		prevVersion = scid
+		prevTime    time.Time
+	)
+
+	for i, line := range lines {
+		var e entry
+
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil, fmt.Errorf("%w: entry %d: %w", ErrInvalidLog, i+1, err)
+		}
+
+		if i > 0 && active.Deactivated {
+			return nil, fmt.Errorf("%w: entry %d follows the deactivation", ErrInvalidLog, i+1)
+		}
+
+		number, hash, err := splitVersionID(e.VersionID)
+		if err != nil || number != i+1 {
+			return nil, fmt.Errorf("%w: entry %d: invalid versionId [%s]", ErrInvalidLog, i+1, e.VersionID)
+		}
+
+		versionTime, err := time.Parse(time.RFC3339, e.VersionTime)
+		if err != nil || !versionTime.After(prevTime) || versionTime.After(now) {
+			return nil, fmt.Errorf("%w: entry %d: invalid versionTime [%s]", ErrInvalidLog, i+1, e.VersionTime)
+		}
+
+		if i == 0 {
+			if err = verifySCID(line, scid); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidLog, err)
+			}
+		}
+
+		params, err := active.apply(e.Parameters)
+		if err != nil {
+			return nil, fmt.Errorf("%w: entry %d: %w", ErrInvalidLog, i+1, err)
+		}
+
+		if err = params.check(i == 0, scid); err != nil {
+			return nil, fmt.Errorf("%w: entry %d: %w", ErrInvalidLog, i+1, err)
+		}
+
+		entryHash, err := hashEntry(line, prevVersion)
+		if err != nil {
+			return nil, fmt.Errorf("%w: entry %d: %w", ErrInvalidLog, i+1, err)
+		}
+
+		if entryHash != hash {
+			return nil, fmt.Errorf("%w: entry %d: entry hash does not match versionId [%s]", ErrInvalidLog, i+1,
+				e.VersionID)
+		}
+
+		authorizedKeys, err := authorizedKeys(i == 0, active, params, e.Parameters)
+		if err != nil {
+			return nil, fmt.Errorf("%w: entry %d: %w", ErrInvalidLog, i+1, err)
+		}
+
+		if err = verifyProofs(line, authorizedKeys); err != nil {
+			return nil, fmt.Errorf("%w: entry %d: %w", ErrInvalidLog, i+1, err)
+		}
+
+		doc, err := did.ParseDocument(e.State)
+		if err != nil {
+			return nil, fmt.Errorf("%w: entry %d: parse state: %w", ErrInvalidLog, i+1, err)
+		}
+
+		if doc.ID != didID {
+			return nil, fmt.Errorf("%w: entry %d: state id [%s] does not match did [%s]", ErrInvalidLog, i+1, doc.ID,
+				didID)
+		}
+
+		versions = append(versions, &version{
+			VersionID:     e.VersionID,
+			VersionNumber: number,
+			VersionTime:   versionTime,
+			Doc:           doc,
+			Parameters:    params,
+		})
+
+		active, prevVersion, prevTime = params, e.VersionID, versionTime
+	}
+
+	return versions, nil
+}
+
+func (p parameters) check(first bool, scid string) error {
+	if first && p.Method == "" {
+		return errors.New("missing method parameter")
+	}
+
+	if p.Method != methodVersion {
+		return fmt.Errorf("unsupported method version [%s]", p.Method)
+	}
+
+	if p.SCID != scid {
+		return fmt.Errorf("scid parameter [%s] does not match the did", p.SCID)
+	}
+
+	if first && len(p.UpdateKeys) == 0 {
+		return errors.New("missing updateKeys parameter")
+	}
+
+	return nil
+}
+
+// authorizedKeys returns the update keys authorized to sign a log entry: the keys of the entry itself for the first
+// entry and when pre-rotation is active, in which case they must be committed to by the nextKeyHashes of the
+// previous entry, the keys in effect before the entry otherwise.
+func authorizedKeys(first bool, prev, params parameters, raw map[string]json.RawMessage) ([]string, error) {
+	if first {
+		return params.UpdateKeys, nil
+	}
+
+	if len(prev.NextKeyHashes) == 0 {
+		return prev.UpdateKeys, nil
+	}
+
+	if _, ok := raw["updateKeys"]; !ok {
+		return nil, errors.New("updateKeys must be rotated while pre-rotation is active")
+	}
+
+	for _, key := range params.UpdateKeys {
+		if !slices.Contains(prev.NextKeyHashes, keyHash(key)) {
+			return nil, fmt.Errorf("update key %s is not committed to by nextKeyHashes", key)
+		}
+	}
+
+	return params.UpdateKeys, nil
+}
+
+// verifySCID checks the first log entry hashes to scid once the SCID is replaced with its placeholder.
+func verifySCID(line []byte, scid string) error {
+	e, err := unmarshalEntry(line)
+	if err != nil {
+		return err
+	}
+
+	delete(e, "proof")
+	e["versionId"] = scidPlaceholder
+
+	canonical, err := canonicalizer.MarshalCanonical(e)
+	if err != nil {
+		return fmt.Errorf("canonicalize entry: %w", err)
+	}
+
+	preliminary := bytes.ReplaceAll(canonical, []byte(scid), []byte(scidPlaceholder))
+
+	computed, err := hashJSON(preliminary)
+	if err != nil {
+		return err
+	}
+
+	if computed != scid {
+		return fmt.Errorf("scid [%s] does not match the first entry", scid)
+	}
+
+	return nil
+}
+
+// hashEntry returns the hash of a log entry without its proof, its versionId replaced with the versionId of the
+// previous entry, or the SCID for the first entry.
+func hashEntry(line []byte, prevVersionID string) (string, error) {
+	e, err := unmarshalEntry(line)
+	if err != nil {
+		return "", err
+	}
+
+	delete(e, "proof")
+	e["versionId"] = prevVersionID
+
+	return hashJSON(e)
+}
+
+// hashJSON returns the base58btc encoded sha256 multihash of the JCS canonical form of value.
+func hashJSON(value interface{}) (string, error) {
+	canonical, err := canonicalizer.MarshalCanonical(value)
+	if err != nil {
+		return "", fmt.Errorf("canonicalize: %w", err)
+	}
+
+	return multihash(canonical), nil
+}
+
+// keyHash returns the pre-rotation hash of an update key.
+func keyHash(updateKey string) string {
+	return multihash([]byte(updateKey))
+}
+
+func multihash(data []byte) string {
+	digest := sha256.Sum256(data)
+
+	return base58.Encode(append([]byte{sha256MultihashCode, byte(len(digest))}, digest[:]...))
+}
+
+func unmarshalEntry(line []byte) (map[string]interface{}, error) {
+	var e map[string]interface{}
+
+	d := json.NewDecoder(bytes.NewReader(line))
+	d.UseNumber()
+
+	if err := d.Decode(&e); err != nil {
+		return nil, fmt.Errorf("unmarshal entry: %w", err)
+	}
+
+	return e, nil
+}
+
+func splitVersionID(versionID string) (int, string, error) {
+	n, hash, ok := strings.Cut(versionID, "-")
+	if !ok || hash == "" {
+		return 0, "", fmt.Errorf("invalid versionId [%s]", versionID)
+	}
+
+	number, err := strconv.Atoi(n)
+	if err != nil || number < 1 {
+		return 0, "", fmt.Errorf("invalid versionId [%s]", versionID)
+	}
+
+	return number, hash, nil
+}
+
+func splitLines(log []byte) [][]byte {
+	var lines [][]byte
+
+	for _, line := range bytes.Split(log, []byte("\n")) {
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
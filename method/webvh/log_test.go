@@ -0,0 +1,177 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webvh
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/did-go/method/web"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+// testLog returns the DID and the did.jsonl log of a DID with two versions.
+func testLog(t *testing.T) (string, *location, []byte, time.Time) {
+	t.Helper()
+
+	publisher := web.NewMemoryPublisher()
+	clock := time.Now().Add(-time.Hour)
+
+	v := New(WithPublisher(publisher))
+	v.now = func() time.Time {
+		clock = clock.Add(time.Minute)
+
+		return clock
+	}
+
+	signer := newSigner(t)
+
+	created, err := v.Create(newDoc(t), vdrapi.WithOption(SignerOpt, signer),
+		vdrapi.WithOption(web.DomainOpt, "example.com"))
+	require.NoError(t, err)
+
+	didID := created.DIDDocument.ID
+
+	log, ok := publisher.Document("example.com", "/.well-known/did.jsonl")
+	require.True(t, ok)
+
+	doc := newDoc(t)
+	doc.ID = didID
+
+	require.NoError(t, v.Update(doc, vdrapi.WithOption(SignerOpt, signer), vdrapi.WithOption(LogOpt, log)))
+
+	log, ok = publisher.Document("example.com", "/.well-known/did.jsonl")
+	require.True(t, ok)
+
+	loc, err := parseDIDWebVH(didID)
+	require.NoError(t, err)
+
+	return didID, loc, log, clock
+}
+
+// tamper returns the log with the entry at index i modified by update.
+func tamper(t *testing.T, log []byte, i int, update func(e map[string]interface{})) []byte {
+	t.Helper()
+
+	lines := splitLines(log)
+
+	e, err := unmarshalEntry(lines[i])
+	require.NoError(t, err)
+
+	update(e)
+
+	lines[i], err = json.Marshal(e)
+	require.NoError(t, err)
+
+	return bytes.Join(lines, []byte("\n"))
+}
+
+func TestVerifyLog(t *testing.T) {
+	didID, loc, log, now := testLog(t)
+
+	t.Run("test valid log", func(t *testing.T) {
+		versions, err := verifyLog(didID, loc.SCID, log, now)
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+		require.Equal(t, 1, versions[0].VersionNumber)
+		require.Equal(t, 2, versions[1].VersionNumber)
+		require.Equal(t, methodVersion, versions[1].Parameters.Method)
+		require.Equal(t, loc.SCID, versions[1].Parameters.SCID)
+	})
+
+	t.Run("test invalid logs", func(t *testing.T) {
+		tests := []struct {
+			name string
+			log  []byte
+			err  string
+		}{
+			{name: "empty", log: []byte("\n"), err: "empty log"},
+			{name: "not json", log: []byte("{"), err: "entry 1"},
+			{
+				name: "scid",
+				log: tamper(t, log, 0, func(e map[string]interface{}) {
+					e["state"].(map[string]interface{})["alsoKnownAs"] = []string{"did:example:123"}
+				}),
+				err: "does not match the first entry",
+			},
+			{
+				name: "entry hash",
+				log: tamper(t, log, 1, func(e map[string]interface{}) {
+					e["state"].(map[string]interface{})["alsoKnownAs"] = []string{"did:example:123"}
+				}),
+				err: "entry hash does not match versionId",
+			},
+			{
+				name: "version number",
+				log: tamper(t, log, 1, func(e map[string]interface{}) {
+					e["versionId"] = "3-" + strings.SplitN(e["versionId"].(string), "-", 2)[1]
+				}),
+				err: "invalid versionId",
+			},
+			{
+				name: "version time",
+				log: tamper(t, log, 1, func(e map[string]interface{}) {
+					e["versionTime"] = now.Add(-2 * time.Hour).Format(time.RFC3339)
+				}),
+				err: "invalid versionTime",
+			},
+			{
+				name: "proof",
+				log: tamper(t, log, 1, func(e map[string]interface{}) {
+					e["proof"].([]interface{})[0].(map[string]interface{})["proofValue"] = "z3yMRJ"
+				}),
+				err: "invalid proof signature",
+			},
+			{
+				name: "missing proof",
+				log: tamper(t, log, 1, func(e map[string]interface{}) {
+					delete(e, "proof")
+				}),
+				err: "missing proof",
+			},
+			{
+				name: "method",
+				log: tamper(t, log, 1, func(e map[string]interface{}) {
+					e["parameters"].(map[string]interface{})["method"] = "did:webvh:0.5"
+				}),
+				err: "unsupported method version",
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := verifyLog(didID, loc.SCID, tc.log, now)
+				require.ErrorIs(t, err, ErrInvalidLog)
+				require.ErrorContains(t, err, tc.err)
+			})
+		}
+	})
+
+	t.Run("test log verified later than its last entry", func(t *testing.T) {
+		_, err := verifyLog(didID, loc.SCID, log, now.Add(-30*time.Minute))
+		require.ErrorContains(t, err, "invalid versionTime")
+	})
+
+	t.Run("test log of another did", func(t *testing.T) {
+		otherID := strings.Replace(didID, "example.com", "example.org", 1)
+
+		_, err := verifyLog(otherID, loc.SCID, log, now)
+		require.ErrorContains(t, err, "does not match did")
+	})
+}
+
+func TestKeyHash(t *testing.T) {
+	hash := NextKeyHash("z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK")
+	require.Equal(t, keyHash("z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"), hash)
+	require.True(t, strings.HasPrefix(hash, "Qm"))
+	require.NotEqual(t, hash, NextKeyHash("z6MkjchhfUsD6mmvni8mCdXHw216Xrm9bQe2mBH1P5RDjVJG"))
+}
@@ -0,0 +1,236 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webvh
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/trustbloc/kms-go/doc/util/fingerprint"
+
+	"github.com/trustbloc/did-go/pkg/canonicalizer"
+)
+
+const (
+	proofType       = "DataIntegrityProof"
+	cryptosuite     = "eddsa-jcs-2022"
+	proofPurpose    = "assertionMethod"
+	multibaseBase58 = "z"
+)
+
+// Signer signs the log entries of a DID with one of its update keys.
+type Signer interface {
+	// UpdateKey returns the multibase encoded Ed25519 public key of the signer, as listed in the updateKeys
+	// parameter.
+	UpdateKey() string
+	// Sign returns the Ed25519 signature of data.
+	Sign(data []byte) ([]byte, error)
+}
+
+type ed25519Signer struct {
+	privateKey ed25519.PrivateKey
+	updateKey  string
+}
+
+// NewEd25519Signer creates a signer for the Ed25519 private key.
+func NewEd25519Signer(privateKey ed25519.PrivateKey) Signer {
+	pub, ok := privateKey.Public().(ed25519.PublicKey)
+	if !ok {
+		return &ed25519Signer{privateKey: privateKey}
+	}
+
+	return &ed25519Signer{
+		privateKey: privateKey,
+		updateKey:  fingerprint.KeyFingerprint(fingerprint.ED25519PubKeyMultiCodec, pub),
+	}
+}
+
+// UpdateKey returns the multibase encoded public key of the signer.
+func (s *ed25519Signer) UpdateKey() string {
+	return s.updateKey
+}
+
+// Sign returns the Ed25519 signature of data.
+func (s *ed25519Signer) Sign(data []byte) ([]byte, error) {
+	if len(s.privateKey) != ed25519.PrivateKeySize {
+		return nil, errors.New("invalid ed25519 private key")
+	}
+
+	return ed25519.Sign(s.privateKey, data), nil
+}
+
+// proof is an eddsa-jcs-2022 Data Integrity proof of a log entry.
+type proof struct {
+	Type               string `json:"type"`
+	Cryptosuite        string `json:"cryptosuite"`
+	VerificationMethod string `json:"verificationMethod"`
+	Created            string `json:"created"`
+	ProofPurpose       string `json:"proofPurpose"`
+	ProofValue         string `json:"proofValue,omitempty"`
+}
+
+// verifyProofs checks the log entry carries at least one valid proof by one of the authorized update keys.
+func verifyProofs(line []byte, authorizedKeys []string) error {
+	var e struct {
+		Proof json.RawMessage `json:"proof"`
+	}
+
+	if err := json.Unmarshal(line, &e); err != nil {
+		return fmt.Errorf("unmarshal proof: %w", err)
+	}
+
+	proofs, err := parseProofs(e.Proof)
+	if err != nil {
+		return err
+	}
+
+	if len(proofs) == 0 {
+		return errors.New("missing proof")
+	}
+
+	doc, err := unmarshalEntry(line)
+	if err != nil {
+		return err
+	}
+
+	delete(doc, "proof")
+
+	for _, p := range proofs {
+		if err = verifyProof(doc, p, authorizedKeys); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func parseProofs(raw json.RawMessage) ([]*proof, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var proofs []*proof
+
+	if err := json.Unmarshal(raw, &proofs); err == nil {
+		return proofs, nil
+	}
+
+	var p proof
+
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, fmt.Errorf("unmarshal proof: %w", err)
+	}
+
+	return []*proof{&p}, nil
+}
+
+func verifyProof(doc map[string]interface{}, p *proof, authorizedKeys []string) error {
+	if p.Type != proofType || p.Cryptosuite != cryptosuite {
+		return fmt.Errorf("unsupported proof %s/%s", p.Type, p.Cryptosuite)
+	}
+
+	if p.ProofPurpose != proofPurpose {
+		return fmt.Errorf("unsupported proof purpose [%s]", p.ProofPurpose)
+	}
+
+	updateKey, err := updateKeyOf(p.VerificationMethod)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(authorizedKeys, updateKey) {
+		return fmt.Errorf("proof key %s is not an authorized update key", updateKey)
+	}
+
+	pub, code, err := fingerprint.PubKeyFromFingerprint(updateKey)
+	if err != nil || code != fingerprint.ED25519PubKeyMultiCodec || len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("update key %s is not an ed25519 key", updateKey)
+	}
+
+	if !strings.HasPrefix(p.ProofValue, multibaseBase58) {
+		return errors.New("proof value must be base58btc encoded")
+	}
+
+	signature := base58.Decode(p.ProofValue[len(multibaseBase58):])
+
+	config := *p
+	config.ProofValue = ""
+
+	data, err := hashData(doc, &config)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(pub, data, signature) {
+		return fmt.Errorf("invalid proof signature of key %s", updateKey)
+	}
+
+	return nil
+}
+
+// updateKeyOf returns the update key of a did:key verification method, e.g. z6Mk... of did:key:z6Mk...#z6Mk....
+func updateKeyOf(verificationMethod string) (string, error) {
+	methodID, fragment, ok := strings.Cut(strings.TrimPrefix(verificationMethod, "did:key:"), "#")
+	if !ok || !strings.HasPrefix(verificationMethod, "did:key:") || methodID != fragment {
+		return "", fmt.Errorf("proof verification method %s is not a did:key key", verificationMethod)
+	}
+
+	return methodID, nil
+}
+
+// signEntry returns the eddsa-jcs-2022 proof of the log entry doc, without its proof, signed by signer.
+func signEntry(doc map[string]interface{}, signer Signer, created time.Time) (*proof, error) {
+	updateKey := signer.UpdateKey()
+
+	p := &proof{
+		Type:               proofType,
+		Cryptosuite:        cryptosuite,
+		VerificationMethod: "did:key:" + updateKey + "#" + updateKey,
+		Created:            created.UTC().Format(time.RFC3339),
+		ProofPurpose:       proofPurpose,
+	}
+
+	data, err := hashData(doc, p)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := signer.Sign(data)
+	if err != nil {
+		return nil, fmt.Errorf("sign log entry: %w", err)
+	}
+
+	p.ProofValue = multibaseBase58 + base58.Encode(signature)
+
+	return p, nil
+}
+
+// hashData returns the eddsa-jcs-2022 signing input of doc: the hash of the proof configuration followed by the
+// hash of the document.
+func hashData(doc map[string]interface{}, config *proof) ([]byte, error) {
+	canonicalConfig, err := canonicalizer.MarshalCanonical(config)
+	if err != nil {
+		return nil, fmt.Errorf("canonicalize proof configuration: %w", err)
+	}
+
+	canonicalDoc, err := canonicalizer.MarshalCanonical(doc)
+	if err != nil {
+		return nil, fmt.Errorf("canonicalize log entry: %w", err)
+	}
+
+	configHash := sha256.Sum256(canonicalConfig)
+	docHash := sha256.Sum256(canonicalDoc)
+
+	return append(configHash[:], docHash[:]...), nil
+}
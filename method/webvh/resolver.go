@@ -0,0 +1,270 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webvh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	// VersionNumberOpt requests resolution of the DID document version with the given number (int), the first
+	// version being 1.
+	VersionNumberOpt = "versionNumber"
+
+	logFile     = "did.jsonl"
+	webDocFile  = "did.json"
+	wellKnown   = ".well-known"
+	scidMinSize = 32
+)
+
+// location is the location of the did.jsonl log of a did:webvh DID.
+type location struct {
+	DID    string
+	SCID   string
+	Host   string
+	Path   []string
+	WebDID string
+}
+
+// parseDIDWebVH parses a did:webvh DID of the form did:webvh:<SCID>:<host>[:<path>...].
+func parseDIDWebVH(didID string) (*location, error) {
+	parsed, err := did.Parse(didID)
+	if err != nil {
+		return nil, fmt.Errorf("parse did: %w", err)
+	}
+
+	if parsed.Method != namespace {
+		return nil, fmt.Errorf("not a did:webvh did [%s]", didID)
+	}
+
+	segments := strings.Split(parsed.MethodSpecificID, ":")
+	if len(segments) < 2 || len(segments[0]) < scidMinSize || segments[1] == "" { //nolint:mnd
+		return nil, fmt.Errorf("invalid did:webvh did [%s]", didID)
+	}
+
+	host, err := url.PathUnescape(segments[1])
+	if err != nil || strings.ContainsAny(host, "/?#@") {
+		return nil, fmt.Errorf("invalid did:webvh host [%s]", segments[1])
+	}
+
+	for _, segment := range segments[2:] {
+		if segment == "" || segment == "." || segment == ".." || strings.ContainsAny(segment, "/?#%") {
+			return nil, fmt.Errorf("invalid did:webvh path segment [%s]", segment)
+		}
+	}
+
+	return &location{
+		DID:    didID,
+		SCID:   segments[0],
+		Host:   host,
+		Path:   segments[2:],
+		WebDID: strings.Join(append([]string{"did", "web"}, segments[1:]...), ":"),
+	}, nil
+}
+
+// LogPath returns the path of the did.jsonl log, /.well-known/did.jsonl when the DID has no path.
+func (l *location) LogPath() string {
+	return l.filePath(logFile)
+}
+
+// LogURL returns the URL the did.jsonl log is downloaded from.
+func (l *location) LogURL() string {
+	return "https://" + l.Host + l.LogPath()
+}
+
+// WebDocPath returns the path of the did.json document of the parallel did:web DID.
+func (l *location) WebDocPath() string {
+	return l.filePath(webDocFile)
+}
+
+func (l *location) filePath(file string) string {
+	if len(l.Path) == 0 {
+		return "/" + wellKnown + "/" + file
+	}
+
+	return "/" + strings.Join(l.Path, "/") + "/" + file
+}
+
+// Read resolves a did:webvh DID.
+func (v *VDR) Read(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	return v.ReadContext(context.Background(), didID, opts...)
+}
+
+// ReadContext resolves a did:webvh DID, the download of its log is bound to the given context.
+//
+// The whole did.jsonl log is verified before the latest version of the DID document, or the version selected by
+// one of the VersionIDOpt, VersionTimeOpt or VersionNumberOpt options, is returned.
+func (v *VDR) ReadContext(ctx context.Context, didID string,
+	opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	loc, err := parseDIDWebVH(didID)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving did:webvh did --> %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	log, err := v.fetchLog(ctx, loc)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving did:webvh did --> %w", err)
+	}
+
+	versions, err := verifyLog(didID, loc.SCID, log, v.now())
+	if err != nil {
+		return nil, fmt.Errorf("error resolving did:webvh did --> %w", err)
+	}
+
+	selected, err := selectVersion(versions, applyOpts(opts))
+	if err != nil {
+		return nil, fmt.Errorf("error resolving did:webvh did --> %w", err)
+	}
+
+	return resolution(versions, selected), nil
+}
+
+// resolution returns the resolution of a version of the DID document.
+func resolution(versions []*version, selected *version) *did.DocResolution {
+	return &did.DocResolution{
+		DIDDocument: selected.Doc,
+		DocumentMetadata: &did.DocumentMetadata{
+			VersionID:   selected.VersionID,
+			Created:     versions[0].VersionTime.UTC().Format(time.RFC3339Nano),
+			Updated:     selected.VersionTime.UTC().Format(time.RFC3339Nano),
+			Deactivated: selected.Parameters.Deactivated,
+		},
+		ResolutionMetadata: &did.ResolutionMetadata{ContentType: did.ContentTypeDIDJSON},
+	}
+}
+
+// selectVersion returns the version requested by the options, the latest version by default.
+func selectVersion(versions []*version, didOpts *vdrapi.DIDMethodOpts) (*version, error) { //nolint:gocyclo
+	versionID, hasID := didOpts.Values[vdrapi.VersionIDOpt]
+	versionTime, hasTime := didOpts.Values[vdrapi.VersionTimeOpt]
+	versionNumber, hasNumber := didOpts.Values[VersionNumberOpt]
+
+	count := 0
+
+	for _, has := range []bool{hasID, hasTime, hasNumber} {
+		if has {
+			count++
+		}
+	}
+
+	if count > 1 {
+		return nil, errors.New("versionID, versionTime and versionNumber can not be set at the same time")
+	}
+
+	switch {
+	case hasID:
+		id, ok := versionID.(string)
+		if !ok {
+			return nil, errors.New("versionID opt must be a string")
+		}
+
+		for _, ver := range versions {
+			if ver.VersionID == id {
+				return ver, nil
+			}
+		}
+
+		return nil, fmt.Errorf("version %s: %w", id, vdrapi.ErrNotFound)
+	case hasNumber:
+		number, ok := versionNumber.(int)
+		if !ok {
+			return nil, errors.New("versionNumber opt must be an int")
+		}
+
+		if number < 1 || number > len(versions) {
+			return nil, fmt.Errorf("version number %d: %w", number, vdrapi.ErrNotFound)
+		}
+
+		return versions[number-1], nil
+	case hasTime:
+		t, err := parseVersionTime(versionTime)
+		if err != nil {
+			return nil, err
+		}
+
+		var selected *version
+
+		for _, ver := range versions {
+			if ver.VersionTime.After(t) {
+				break
+			}
+
+			selected = ver
+		}
+
+		if selected == nil {
+			return nil, fmt.Errorf("version at %s: %w", t.Format(time.RFC3339), vdrapi.ErrNotFound)
+		}
+
+		return selected, nil
+	default:
+		return versions[len(versions)-1], nil
+	}
+}
+
+func parseVersionTime(value interface{}) (time.Time, error) {
+	switch t := value.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		parsed, err := time.Parse(time.RFC3339, t)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("versionTime opt: %w", err)
+		}
+
+		return parsed, nil
+	default:
+		return time.Time{}, errors.New("versionTime opt must be an RFC 3339 string or a time.Time")
+	}
+}
+
+// fetchLog downloads the did.jsonl log of the DID.
+func (v *VDR) fetchLog(ctx context.Context, loc *location) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, loc.LogURL(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http request failed: %w", err)
+	}
+
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			errorLogger.Printf("Failed to close response body: %v", closeErr)
+		}
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("log %s: %w", loc.LogURL(), vdrapi.ErrNotFound)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("http request for %s returned status %d", loc.LogURL(), resp.StatusCode)
+	}
+
+	log, err := io.ReadAll(io.LimitReader(resp.Body, v.maxLogSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read log: %w", err)
+	}
+
+	if int64(len(log)) > v.maxLogSize {
+		return nil, fmt.Errorf("log %s exceeds %d bytes", loc.LogURL(), v.maxLogSize)
+	}
+
+	return log, nil
+}
@@ -0,0 +1,155 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webvh
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+func TestParseDIDWebVH(t *testing.T) {
+	scid := strings.Repeat("Q", scidMinSize)
+
+	t.Run("test did locations", func(t *testing.T) {
+		loc, err := parseDIDWebVH("did:webvh:" + scid + ":example.com")
+		require.NoError(t, err)
+		require.Equal(t, scid, loc.SCID)
+		require.Equal(t, "https://example.com/.well-known/did.jsonl", loc.LogURL())
+		require.Equal(t, "/.well-known/did.json", loc.WebDocPath())
+		require.Equal(t, "did:web:example.com", loc.WebDID)
+
+		loc, err = parseDIDWebVH("did:webvh:" + scid + ":example.com%3A3000:dids:issuer")
+		require.NoError(t, err)
+		require.Equal(t, "https://example.com:3000/dids/issuer/did.jsonl", loc.LogURL())
+		require.Equal(t, "/dids/issuer/did.json", loc.WebDocPath())
+		require.Equal(t, "did:web:example.com%3A3000:dids:issuer", loc.WebDID)
+	})
+
+	t.Run("test invalid dids", func(t *testing.T) {
+		for _, didID := range []string{
+			"webvh:" + scid + ":example.com",
+			"did:web:example.com",
+			"did:webvh:" + scid,
+			"did:webvh:short:example.com",
+			"did:webvh:" + scid + ":example.com%2Fpath",
+			"did:webvh:" + scid + ":example.com:..",
+		} {
+			_, err := parseDIDWebVH(didID)
+			require.Error(t, err, didID)
+		}
+	})
+}
+
+func TestRead(t *testing.T) {
+	t.Run("test resolve versions", func(t *testing.T) {
+		f := newFixture(t)
+		signer := newSigner(t)
+
+		created := f.create(t, signer)
+		didID := created.DIDDocument.ID
+
+		doc := newDoc(t)
+		doc.ID = didID
+
+		require.NoError(t, f.vdr.Update(doc, vdrapi.WithOption(SignerOpt, signer)))
+
+		latest, err := f.vdr.Read(didID)
+		require.NoError(t, err)
+
+		byID, err := f.vdr.Read(didID, vdrapi.WithOption(vdrapi.VersionIDOpt, created.DocumentMetadata.VersionID))
+		require.NoError(t, err)
+		require.Equal(t, created.DocumentMetadata, byID.DocumentMetadata)
+
+		byNumber, err := f.vdr.Read(didID, vdrapi.WithOption(VersionNumberOpt, 2))
+		require.NoError(t, err)
+		require.Equal(t, latest.DocumentMetadata, byNumber.DocumentMetadata)
+
+		byTime, err := f.vdr.Read(didID, vdrapi.WithOption(vdrapi.VersionTimeOpt, created.DocumentMetadata.Updated))
+		require.NoError(t, err)
+		require.Equal(t, created.DocumentMetadata, byTime.DocumentMetadata)
+
+		updated, err := time.Parse(time.RFC3339, latest.DocumentMetadata.Updated)
+		require.NoError(t, err)
+
+		byTime, err = f.vdr.Read(didID, vdrapi.WithOption(vdrapi.VersionTimeOpt, updated.Add(time.Second)))
+		require.NoError(t, err)
+		require.Equal(t, latest.DocumentMetadata, byTime.DocumentMetadata)
+
+		_, err = f.vdr.Read(didID, vdrapi.WithOption(vdrapi.VersionIDOpt, "3-Qm"))
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+
+		_, err = f.vdr.Read(didID, vdrapi.WithOption(VersionNumberOpt, 3))
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+
+		_, err = f.vdr.Read(didID, vdrapi.WithOption(vdrapi.VersionTimeOpt, "2000-01-01T00:00:00Z"))
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+	})
+
+	t.Run("test invalid version options", func(t *testing.T) {
+		f := newFixture(t)
+		didID := f.create(t, newSigner(t)).DIDDocument.ID
+
+		_, err := f.vdr.Read(didID, vdrapi.WithOption(vdrapi.VersionIDOpt, "1-Qm"),
+			vdrapi.WithOption(VersionNumberOpt, 1))
+		require.ErrorContains(t, err, "can not be set at the same time")
+
+		_, err = f.vdr.Read(didID, vdrapi.WithOption(vdrapi.VersionIDOpt, 1))
+		require.ErrorContains(t, err, "versionID opt must be a string")
+
+		_, err = f.vdr.Read(didID, vdrapi.WithOption(VersionNumberOpt, "1"))
+		require.ErrorContains(t, err, "versionNumber opt must be an int")
+
+		_, err = f.vdr.Read(didID, vdrapi.WithOption(vdrapi.VersionTimeOpt, "yesterday"))
+		require.ErrorContains(t, err, "versionTime opt")
+
+		_, err = f.vdr.Read(didID, vdrapi.WithOption(vdrapi.VersionTimeOpt, 1))
+		require.ErrorContains(t, err, "versionTime opt must be an RFC 3339 string or a time.Time")
+	})
+
+	t.Run("test resolution errors", func(t *testing.T) {
+		_, err := New().Read("did:webvh:invalid")
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+
+		f := newFixture(t)
+		didID := f.create(t, newSigner(t)).DIDDocument.ID
+
+		_, err = f.vdr.Read(strings.Replace(didID, "did:webvh:Qm", "did:webvh:Qn", 1))
+		require.ErrorIs(t, err, ErrInvalidLog)
+
+		_, err = f.vdr.Read(didID + ":missing")
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+
+		v := New(WithHTTPClient(f.server.Client()), WithMaxLogSize(10))
+		_, err = v.Read(didID)
+		require.ErrorContains(t, err, "exceeds 10 bytes")
+
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		host := strings.TrimPrefix(server.URL, "https://")
+
+		_, err = New(WithHTTPClient(server.Client())).Read("did:webvh:" + strings.Repeat("Q", scidMinSize) + ":" +
+			strings.ReplaceAll(host, ":", "%3A"))
+		require.ErrorContains(t, err, "returned status 500")
+	})
+
+	t.Run("test accept", func(t *testing.T) {
+		v := New()
+		require.True(t, v.Accept(namespace))
+		require.False(t, v.Accept("web"))
+		require.NoError(t, v.Close())
+	})
+}
@@ -0,0 +1,108 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package webvh implements the did:webvh (did:web + verifiable history) method. The history of a DID is a did.jsonl
+// log of signed entries, hosted at the did:web location of the DID, that the VDR verifies before resolving a
+// version of the DID document.
+package webvh
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/trustbloc/did-go/method/web"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	namespace = "webvh"
+
+	// methodVersion is the method parameter of the log entries, the version of the specification they follow.
+	methodVersion = "did:webvh:1.0"
+
+	defaultMaxLogSize = 10 << 20
+	defaultTimeout    = 10 * time.Second
+)
+
+var errorLogger = log.New(os.Stderr, " [did-go/vdr/webvh] ", log.Ldate|log.Ltime|log.LUTC)
+
+// VDR implements the did:webvh VDR.
+type VDR struct {
+	publisher      web.Publisher
+	httpClient     *http.Client
+	maxLogSize     int64
+	parallelDIDWeb bool
+	now            func() time.Time
+}
+
+// Option configures the did:webvh vdr.
+type Option func(opts *VDR)
+
+// WithPublisher sets the publisher the did.jsonl logs created and updated by the vdr are written to.
+func WithPublisher(publisher web.Publisher) Option {
+	return func(opts *VDR) {
+		opts.publisher = publisher
+	}
+}
+
+// WithHTTPClient sets the http client the did.jsonl logs are downloaded with.
+func WithHTTPClient(client *http.Client) Option {
+	return func(opts *VDR) {
+		opts.httpClient = client
+	}
+}
+
+// WithMaxLogSize limits the size of the downloaded did.jsonl logs, 10 MiB by default.
+func WithMaxLogSize(size int64) Option {
+	return func(opts *VDR) {
+		opts.maxLogSize = size
+	}
+}
+
+// WithParallelDIDWeb makes the vdr publish the did:web did.json document of the latest version along with the
+// did.jsonl log, so that the DID can also be resolved as a did:web DID.
+func WithParallelDIDWeb() Option {
+	return func(opts *VDR) {
+		opts.parallelDIDWeb = true
+	}
+}
+
+// New creates a new did:webvh VDR.
+func New(opts ...Option) *VDR {
+	v := &VDR{
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		maxLogSize: defaultMaxLogSize,
+		now:        time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// Accept method of the VDR interface.
+func (v *VDR) Accept(method string, opts ...vdrapi.DIDMethodOption) bool {
+	return method == namespace
+}
+
+// Close method of the VDR interface.
+func (v *VDR) Close() error {
+	return nil
+}
+
+func applyOpts(opts []vdrapi.DIDMethodOption) *vdrapi.DIDMethodOpts {
+	didOpts := &vdrapi.DIDMethodOpts{Values: make(map[string]interface{})}
+	// Apply options
+	for _, opt := range opts {
+		opt(didOpts)
+	}
+
+	return didOpts
+}
@@ -0,0 +1,257 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package x509
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+const (
+	version = "0"
+
+	// SubjectPolicy requires the leaf certificate subject to contain the given attributes.
+	SubjectPolicy = "subject"
+	// SANPolicy requires the leaf certificate to have the given subject alternative name.
+	SANPolicy = "san"
+	// EKUPolicy requires the leaf certificate to have the given extended key usage.
+	EKUPolicy = "eku"
+	// FulcioIssuerPolicy requires the leaf certificate to be issued by Fulcio for the given OIDC issuer.
+	FulcioIssuerPolicy = "fulcio-issuer"
+)
+
+var (
+	// ErrInvalidChain is returned when the x5c certificate chain is missing or not valid.
+	ErrInvalidChain = errors.New("invalid x5c certificate chain")
+	// ErrPolicyNotSatisfied is returned when the certificate chain does not satisfy the DID.
+	ErrPolicyNotSatisfied = errors.New("did:x509 policy not satisfied")
+)
+
+var (
+	oidExtKeyUsage       = asn1.ObjectIdentifier{2, 5, 29, 37}
+	oidFulcioIssuer      = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	oidFulcioIssuerV2    = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+	subjectAttributeOIDs = map[string]asn1.ObjectIdentifier{
+		"CN":     {2, 5, 4, 3},
+		"C":      {2, 5, 4, 6},
+		"L":      {2, 5, 4, 7},
+		"ST":     {2, 5, 4, 8},
+		"STREET": {2, 5, 4, 9},
+		"O":      {2, 5, 4, 10},
+		"OU":     {2, 5, 4, 11},
+	}
+)
+
+// policy is a policy of a did:x509 DID, its name and its colon separated, percent-encoded, arguments.
+type policy struct {
+	name string
+	args []string
+}
+
+// parsedDID is a did:x509 DID.
+type parsedDID struct {
+	fingerprintAlg string
+	fingerprint    string
+	policies       []*policy
+}
+
+// parseDID parses a DID of the form did:x509:0:<alg>:<ca-fingerprint>::<policy>:<args>(::<policy>:<args>)*.
+func parseDID(methodSpecificID string) (*parsedDID, error) {
+	parts := strings.Split(methodSpecificID, "::")
+	if len(parts) < 2 { //nolint:mnd
+		return nil, errors.New("missing policy")
+	}
+
+	ca := strings.Split(parts[0], ":")
+	if len(ca) != 3 || ca[0] != version { //nolint:mnd
+		return nil, fmt.Errorf("unsupported version or ca fingerprint [%s]", parts[0])
+	}
+
+	if _, ok := fingerprintHashes[ca[1]]; !ok {
+		return nil, fmt.Errorf("unsupported fingerprint algorithm [%s]", ca[1])
+	}
+
+	parsed := &parsedDID{fingerprintAlg: ca[1], fingerprint: ca[2]}
+
+	for _, part := range parts[1:] {
+		name, args, ok := strings.Cut(part, ":")
+		if !ok || args == "" {
+			return nil, fmt.Errorf("invalid policy [%s]", part)
+		}
+
+		parsed.policies = append(parsed.policies, &policy{name: name, args: strings.Split(args, ":")})
+	}
+
+	return parsed, nil
+}
+
+var fingerprintHashes = map[string]func([]byte) []byte{
+	"sha256": func(b []byte) []byte { h := sha256.Sum256(b); return h[:] },
+	"sha384": func(b []byte) []byte { h := sha512.Sum384(b); return h[:] },
+	"sha512": func(b []byte) []byte { h := sha512.Sum512(b); return h[:] },
+}
+
+// fingerprint returns the base64url encoded hash of the certificate.
+func fingerprint(cert *x509.Certificate, alg string) string {
+	return base64.RawURLEncoding.EncodeToString(fingerprintHashes[alg](cert.Raw))
+}
+
+// check checks the leaf certificate satisfies the policy.
+func (p *policy) check(leaf *x509.Certificate) error {
+	args := make([]string, len(p.args))
+
+	for i, arg := range p.args {
+		var err error
+
+		args[i], err = url.PathUnescape(arg)
+		if err != nil {
+			return fmt.Errorf("%s policy: invalid percent-encoding: %w", p.name, err)
+		}
+	}
+
+	switch p.name {
+	case SubjectPolicy:
+		return checkSubject(leaf, args)
+	case SANPolicy:
+		return checkSAN(leaf, args)
+	case EKUPolicy:
+		return checkEKU(leaf, args)
+	case FulcioIssuerPolicy:
+		return checkFulcioIssuer(leaf, args)
+	default:
+		return fmt.Errorf("unsupported policy [%s]", p.name)
+	}
+}
+
+func checkSubject(leaf *x509.Certificate, args []string) error {
+	if len(args)%2 != 0 {
+		return errors.New("subject policy: attributes must be key:value pairs")
+	}
+
+	seen := make(map[string]bool)
+
+	for i := 0; i < len(args); i += 2 {
+		key, value := args[i], args[i+1]
+
+		oid, ok := subjectAttributeOIDs[key]
+		if !ok {
+			return fmt.Errorf("subject policy: unsupported attribute [%s]", key)
+		}
+
+		if seen[key] {
+			return fmt.Errorf("subject policy: duplicate attribute [%s]", key)
+		}
+
+		seen[key] = true
+
+		found := false
+
+		for _, name := range leaf.Subject.Names {
+			if name.Type.Equal(oid) && name.Value == value {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("subject policy: subject has no %s [%s]: %w", key, value, ErrPolicyNotSatisfied)
+		}
+	}
+
+	return nil
+}
+
+func checkSAN(leaf *x509.Certificate, args []string) error {
+	if len(args) != 2 { //nolint:mnd
+		return errors.New("san policy: expected type:value")
+	}
+
+	var names []string
+
+	switch args[0] {
+	case "email":
+		names = leaf.EmailAddresses
+	case "dns":
+		names = leaf.DNSNames
+	case "uri":
+		for _, u := range leaf.URIs {
+			names = append(names, u.String())
+		}
+	default:
+		return fmt.Errorf("san policy: unsupported type [%s]", args[0])
+	}
+
+	if !slices.Contains(names, args[1]) {
+		return fmt.Errorf("san policy: no %s san [%s]: %w", args[0], args[1], ErrPolicyNotSatisfied)
+	}
+
+	return nil
+}
+
+func checkEKU(leaf *x509.Certificate, args []string) error {
+	if len(args) != 1 {
+		return errors.New("eku policy: expected an oid")
+	}
+
+	for _, ext := range leaf.Extensions {
+		if !ext.Id.Equal(oidExtKeyUsage) {
+			continue
+		}
+
+		var oids []asn1.ObjectIdentifier
+
+		if _, err := asn1.Unmarshal(ext.Value, &oids); err != nil {
+			return fmt.Errorf("eku policy: parse extended key usage: %w", err)
+		}
+
+		for _, oid := range oids {
+			if oid.String() == args[0] {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("eku policy: no extended key usage [%s]: %w", args[0], ErrPolicyNotSatisfied)
+}
+
+func checkFulcioIssuer(leaf *x509.Certificate, args []string) error {
+	if len(args) != 1 {
+		return errors.New("fulcio-issuer policy: expected an issuer")
+	}
+
+	expected := "https://" + args[0]
+
+	for _, ext := range leaf.Extensions {
+		var issuer string
+
+		switch {
+		case ext.Id.Equal(oidFulcioIssuerV2):
+			if _, err := asn1.UnmarshalWithParams(ext.Value, &issuer, "utf8"); err != nil {
+				return fmt.Errorf("fulcio-issuer policy: parse issuer: %w", err)
+			}
+		case ext.Id.Equal(oidFulcioIssuer):
+			issuer = string(ext.Value)
+		default:
+			continue
+		}
+
+		if issuer == expected {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("fulcio-issuer policy: not issued for [%s]: %w", expected, ErrPolicyNotSatisfied)
+}
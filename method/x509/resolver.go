@@ -0,0 +1,204 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package x509
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/kms-go/doc/jose/jwk/jwksupport"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	// X5COpt is the certificate chain of the DID, leaf first, either as a []string of base64 encoded DER
+	// certificates, as in the x5c JOSE header, or as a []*x509.Certificate.
+	X5COpt = "x5c"
+
+	jwsSuiteV1     = "https://w3id.org/security/suites/jws-2020/v1"
+	jsonWebKey2020 = "JsonWebKey2020"
+	keyID          = "#key-1"
+)
+
+// Read resolves a did:x509 DID from the certificate chain given with the X5COpt option.
+//
+// The chain is verified, with its last certificate as trust anchor, one of its CA certificates must match the CA
+// fingerprint of the DID and its leaf certificate must satisfy every policy of the DID. The leaf key is the
+// JsonWebKey2020 verification method of the DID document.
+func (v *VDR) Read(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	parsed, err := did.Parse(didID)
+	if err != nil {
+		return nil, fmt.Errorf("x509-vdr read: failed to parse DID: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	if parsed.Method != DIDMethod {
+		return nil, fmt.Errorf("x509-vdr read: invalid method: %s: %w", parsed.Method, vdrapi.ErrInvalidDID)
+	}
+
+	x509DID, err := parseDID(parsed.MethodSpecificID)
+	if err != nil {
+		return nil, fmt.Errorf("x509-vdr read: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	chain, err := chainOpt(opts)
+	if err != nil {
+		return nil, fmt.Errorf("x509-vdr read: %w: %w", err, ErrInvalidChain)
+	}
+
+	if err = v.verifyChain(chain); err != nil {
+		return nil, fmt.Errorf("x509-vdr read: %w: %w", err, ErrInvalidChain)
+	}
+
+	if err = x509DID.check(chain); err != nil {
+		if errors.Is(err, ErrPolicyNotSatisfied) {
+			return nil, fmt.Errorf("x509-vdr read: %w", err)
+		}
+
+		return nil, fmt.Errorf("x509-vdr read: %w: %w", err, vdrapi.ErrInvalidDID)
+	}
+
+	doc, err := createDoc(didID, chain[0])
+	if err != nil {
+		return nil, fmt.Errorf("x509-vdr read: %w", err)
+	}
+
+	return &did.DocResolution{
+		DIDDocument:        doc,
+		ResolutionMetadata: &did.ResolutionMetadata{ContentType: did.ContentTypeDIDLDJSON},
+	}, nil
+}
+
+// ReadContext resolves a did:x509 DID. Resolution is done offline, so the context is only checked for
+// cancellation.
+func (v *VDR) ReadContext(ctx context.Context, didID string,
+	opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return v.Read(didID, opts...)
+}
+
+// check checks a CA certificate of the chain matches the CA fingerprint and the leaf satisfies the policies.
+func (d *parsedDID) check(chain []*x509.Certificate) error {
+	matched := false
+
+	for _, cert := range chain[1:] {
+		if fingerprint(cert, d.fingerprintAlg) == d.fingerprint {
+			matched = true
+
+			break
+		}
+	}
+
+	if !matched {
+		return fmt.Errorf("no ca certificate matches the fingerprint: %w", ErrPolicyNotSatisfied)
+	}
+
+	for _, p := range d.policies {
+		if err := p.check(chain[0]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyChain verifies the chain up to its last certificate.
+func (v *VDR) verifyChain(chain []*x509.Certificate) error {
+	if len(chain) < 2 { //nolint:mnd
+		return errors.New("the chain must contain the leaf and at least one ca certificate")
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(chain[len(chain)-1])
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1 : len(chain)-1] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := chain[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   v.now(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return fmt.Errorf("verify chain: %w", err)
+	}
+
+	return nil
+}
+
+func chainOpt(opts []vdrapi.DIDMethodOption) ([]*x509.Certificate, error) {
+	didOpts := &vdrapi.DIDMethodOpts{Values: make(map[string]interface{})}
+	// Apply options
+	for _, opt := range opts {
+		opt(didOpts)
+	}
+
+	switch x5c := didOpts.Values[X5COpt].(type) {
+	case []*x509.Certificate:
+		return x5c, nil
+	case []string:
+		chain := make([]*x509.Certificate, len(x5c))
+
+		for i, encoded := range x5c {
+			der, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("decode certificate %d: %w", i, err)
+			}
+
+			chain[i], err = x509.ParseCertificate(der)
+			if err != nil {
+				return nil, fmt.Errorf("parse certificate %d: %w", i, err)
+			}
+		}
+
+		return chain, nil
+	case nil:
+		return nil, errors.New("missing x5c opt")
+	default:
+		return nil, errors.New("x5c opt must be a []string or []*x509.Certificate")
+	}
+}
+
+func createDoc(didID string, leaf *x509.Certificate) (*did.Doc, error) {
+	key, err := jwksupport.JWKFromKey(leaf.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("create leaf jwk: %w", err)
+	}
+
+	vm, err := did.NewVerificationMethodFromJWK(didID+keyID, jsonWebKey2020, didID, key)
+	if err != nil {
+		return nil, fmt.Errorf("create verification method: %w", err)
+	}
+
+	doc := &did.Doc{
+		Context:            []string{did.ContextV1, jwsSuiteV1},
+		ID:                 didID,
+		VerificationMethod: []did.VerificationMethod{*vm},
+	}
+
+	// Without key usage extension, the key is a signing key.
+	if leaf.KeyUsage == 0 || leaf.KeyUsage&x509.KeyUsageDigitalSignature != 0 {
+		doc.Authentication = []did.Verification{*did.NewReferencedVerification(vm, did.Authentication)}
+		doc.AssertionMethod = []did.Verification{*did.NewReferencedVerification(vm, did.AssertionMethod)}
+	}
+
+	if leaf.KeyUsage&x509.KeyUsageKeyAgreement != 0 {
+		doc.KeyAgreement = []did.Verification{*did.NewReferencedVerification(vm, did.KeyAgreement)}
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,279 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package x509
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+type testChain struct {
+	root, intermediate, leaf *x509.Certificate
+}
+
+func (c *testChain) x5c() []string {
+	return []string{
+		base64.StdEncoding.EncodeToString(c.leaf.Raw),
+		base64.StdEncoding.EncodeToString(c.intermediate.Raw),
+		base64.StdEncoding.EncodeToString(c.root.Raw),
+	}
+}
+
+func (c *testChain) did(policies string) string {
+	return "did:x509:0:sha256:" + fingerprint(c.root, "sha256") + "::" + policies
+}
+
+func newCert(t *testing.T, template, parent *x509.Certificate, pub crypto.PublicKey,
+	signer crypto.Signer) *x509.Certificate {
+	t.Helper()
+
+	if parent == nil {
+		parent = template
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, signer)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return key
+}
+
+// newChain creates a root, intermediate and leaf certificate chain, the leaf is customized by update.
+func newChain(t *testing.T, leafKey crypto.Signer, update func(leaf *x509.Certificate)) *testChain {
+	t.Helper()
+
+	now := time.Now()
+
+	rootKey, intermediateKey := newKey(t), newKey(t)
+
+	ca := func(serial int64, cn string) *x509.Certificate {
+		return &x509.Certificate{
+			SerialNumber:          big.NewInt(serial),
+			Subject:               pkix.Name{CommonName: cn},
+			NotBefore:             now.Add(-time.Hour),
+			NotAfter:              now.Add(time.Hour),
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign,
+		}
+	}
+
+	root := newCert(t, ca(1, "Root CA"), nil, rootKey.Public(), rootKey)
+	intermediate := newCert(t, ca(2, "Intermediate CA"), root, intermediateKey.Public(), rootKey)
+
+	fulcioIssuer, err := asn1.MarshalWithParams("https://token.actions.githubusercontent.com", "utf8")
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject: pkix.Name{
+			CommonName:   "Example Signer",
+			Organization: []string{"Example Inc."},
+			Country:      []string{"US"},
+		},
+		NotBefore:      now.Add(-time.Hour),
+		NotAfter:       now.Add(time.Hour),
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		EmailAddresses: []string{"signer@example.com"},
+		DNSNames:       []string{"signer.example.com"},
+		URIs:           []*url.URL{{Scheme: "https", Host: "example.com", Path: "/signer"}},
+		ExtraExtensions: []pkix.Extension{
+			{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}, Value: fulcioIssuer},
+		},
+	}
+
+	if update != nil {
+		update(template)
+	}
+
+	leaf := newCert(t, template, intermediate, leafKey.Public(), intermediateKey)
+
+	return &testChain{root: root, intermediate: intermediate, leaf: leaf}
+}
+
+func TestRead(t *testing.T) {
+	t.Run("test resolve did", func(t *testing.T) {
+		chain := newChain(t, newKey(t), nil)
+
+		for _, policies := range []string{
+			"subject:C:US:O:Example%20Inc.",
+			"subject:CN:Example%20Signer",
+			"san:email:signer%40example.com",
+			"san:dns:signer.example.com",
+			"san:uri:https%3A%2F%2Fexample.com%2Fsigner",
+			"eku:1.3.6.1.5.5.7.3.3",
+			"fulcio-issuer:token.actions.githubusercontent.com",
+			"eku:1.3.6.1.5.5.7.3.3::subject:O:Example%20Inc.",
+		} {
+			didID := chain.did(policies)
+
+			docResolution, err := New().Read(didID, vdrapi.WithOption(X5COpt, chain.x5c()))
+			require.NoError(t, err, policies)
+
+			doc := docResolution.DIDDocument
+			require.Equal(t, didID, doc.ID)
+			require.Len(t, doc.VerificationMethod, 1)
+			require.Equal(t, didID+"#key-1", doc.VerificationMethod[0].ID)
+			require.Equal(t, "JsonWebKey2020", doc.VerificationMethod[0].Type)
+			require.Equal(t, "EC", doc.VerificationMethod[0].JSONWebKey().Kty)
+			require.Len(t, doc.AssertionMethod, 1)
+			require.Len(t, doc.Authentication, 1)
+			require.Empty(t, doc.KeyAgreement)
+		}
+	})
+
+	t.Run("test intermediate fingerprint and certificates option", func(t *testing.T) {
+		chain := newChain(t, newKey(t), nil)
+
+		didID := "did:x509:0:sha384:" + fingerprint(chain.intermediate, "sha384") + "::eku:1.3.6.1.5.5.7.3.3"
+
+		_, err := New().Read(didID,
+			vdrapi.WithOption(X5COpt, []*x509.Certificate{chain.leaf, chain.intermediate, chain.root}))
+		require.NoError(t, err)
+	})
+
+	t.Run("test rsa key agreement leaf", func(t *testing.T) {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		chain := newChain(t, rsaKey, func(leaf *x509.Certificate) {
+			leaf.KeyUsage = x509.KeyUsageKeyAgreement
+		})
+
+		docResolution, err := New().ReadContext(context.Background(), chain.did("subject:C:US"),
+			vdrapi.WithOption(X5COpt, chain.x5c()))
+		require.NoError(t, err)
+		require.Equal(t, "RSA", docResolution.DIDDocument.VerificationMethod[0].JSONWebKey().Kty)
+		require.Len(t, docResolution.DIDDocument.KeyAgreement, 1)
+		require.Empty(t, docResolution.DIDDocument.AssertionMethod)
+	})
+
+	t.Run("test policies not satisfied", func(t *testing.T) {
+		chain := newChain(t, newKey(t), nil)
+
+		for _, policies := range []string{
+			"subject:C:DE",
+			"subject:OU:Example",
+			"san:email:other%40example.com",
+			"san:dns:other.example.com",
+			"eku:1.3.6.1.5.5.7.3.1",
+			"fulcio-issuer:accounts.google.com",
+			"subject:C:US::eku:1.3.6.1.5.5.7.3.1",
+		} {
+			_, err := New().Read(chain.did(policies), vdrapi.WithOption(X5COpt, chain.x5c()))
+			require.ErrorIs(t, err, ErrPolicyNotSatisfied, policies)
+		}
+
+		other := newChain(t, newKey(t), nil)
+
+		_, err := New().Read(other.did("subject:C:US"), vdrapi.WithOption(X5COpt, chain.x5c()))
+		require.ErrorIs(t, err, ErrPolicyNotSatisfied)
+		require.ErrorContains(t, err, "no ca certificate matches the fingerprint")
+	})
+
+	t.Run("test invalid dids", func(t *testing.T) {
+		chain := newChain(t, newKey(t), nil)
+
+		for _, didID := range []string{
+			"did:x509",
+			"did:web:example.com",
+			"did:x509:0:sha256:" + fingerprint(chain.root, "sha256"),
+			"did:x509:1:sha256:" + fingerprint(chain.root, "sha256") + "::subject:C:US",
+			"did:x509:0:md5:" + fingerprint(chain.root, "sha256") + "::subject:C:US",
+			chain.did("subject"),
+			chain.did("subject:C"),
+			chain.did("subject:X:US"),
+			chain.did("subject:C:US:C:US"),
+			chain.did("subject:C:%zz"),
+			chain.did("san:ip:127.0.0.1"),
+			chain.did("san:dns"),
+			chain.did("eku:1.2:3.4"),
+			chain.did("fulcio-issuer:a:b"),
+			chain.did("unknown:value"),
+		} {
+			_, err := New().Read(didID, vdrapi.WithOption(X5COpt, chain.x5c()))
+			require.ErrorIs(t, err, vdrapi.ErrInvalidDID, didID)
+		}
+	})
+
+	t.Run("test invalid chains", func(t *testing.T) {
+		chain := newChain(t, newKey(t), nil)
+		didID := chain.did("subject:C:US")
+
+		_, err := New().Read(didID)
+		require.ErrorIs(t, err, ErrInvalidChain)
+		require.ErrorContains(t, err, "missing x5c opt")
+
+		_, err = New().Read(didID, vdrapi.WithOption(X5COpt, "chain"))
+		require.ErrorContains(t, err, "x5c opt must be a []string or []*x509.Certificate")
+
+		_, err = New().Read(didID, vdrapi.WithOption(X5COpt, []string{"%"}))
+		require.ErrorContains(t, err, "decode certificate 0")
+
+		_, err = New().Read(didID, vdrapi.WithOption(X5COpt, []string{"AAAA"}))
+		require.ErrorContains(t, err, "parse certificate 0")
+
+		_, err = New().Read(didID, vdrapi.WithOption(X5COpt, chain.x5c()[:1]))
+		require.ErrorContains(t, err, "at least one ca certificate")
+
+		_, err = New().Read(didID, vdrapi.WithOption(X5COpt, []string{chain.x5c()[0], chain.x5c()[2]}))
+		require.ErrorIs(t, err, ErrInvalidChain)
+
+		v := New()
+		v.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+		_, err = v.Read(didID, vdrapi.WithOption(X5COpt, chain.x5c()))
+		require.ErrorIs(t, err, ErrInvalidChain)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err = New().ReadContext(ctx, didID, vdrapi.WithOption(X5COpt, chain.x5c()))
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestVDR(t *testing.T) {
+	v := New()
+	require.True(t, v.Accept(DIDMethod))
+	require.False(t, v.Accept("web"))
+	require.NoError(t, v.Close())
+
+	_, err := v.Create(&did.Doc{})
+	require.ErrorContains(t, err, "not supported")
+	require.ErrorContains(t, v.Update(&did.Doc{}), "not supported")
+	require.ErrorContains(t, v.Deactivate("did:x509:0"), "not supported")
+}
@@ -0,0 +1,56 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package x509 implements did:x509 method (https://github.com/microsoft/did-x509/blob/main/specification.md)
+package x509
+
+import (
+	"errors"
+	"time"
+
+	diddoc "github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	// DIDMethod did method.
+	DIDMethod = "x509"
+)
+
+// VDR implements did:x509 method support.
+type VDR struct {
+	now func() time.Time
+}
+
+// New returns new instance of VDR that works with did:x509 method.
+func New() *VDR {
+	return &VDR{now: time.Now}
+}
+
+// Accept accepts did:x509 method.
+func (v *VDR) Accept(method string, opts ...vdrapi.DIDMethodOption) bool {
+	return method == DIDMethod
+}
+
+// Close frees resources being maintained by VDR.
+func (v *VDR) Close() error {
+	return nil
+}
+
+// Create did doc.
+func (v *VDR) Create(didDoc *diddoc.Doc, opts ...vdrapi.DIDMethodOption) (*diddoc.DocResolution, error) {
+	return nil, errors.New("not supported")
+}
+
+// Update did doc.
+func (v *VDR) Update(didDoc *diddoc.Doc, opts ...vdrapi.DIDMethodOption) error {
+	return errors.New("not supported")
+}
+
+// Deactivate did doc.
+func (v *VDR) Deactivate(didID string, opts ...vdrapi.DIDMethodOption) error {
+	return errors.New("not supported")
+}
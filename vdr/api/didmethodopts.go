@@ -5,6 +5,13 @@ SPDX-License-Identifier: Apache-2.0
 
 package api
 
+const (
+	// VersionIDOpt requests resolution of a specific version of the DID document.
+	VersionIDOpt = "versionID"
+	// VersionTimeOpt requests resolution of the DID document version valid at the given time.
+	VersionTimeOpt = "versionTime"
+)
+
 // DIDMethodOpts did method opts.
 type DIDMethodOpts struct {
 	Values map[string]interface{}
@@ -8,6 +8,7 @@ SPDX-License-Identifier: Apache-2.0
 package api
 
 import (
+	"context"
 	"errors"
 
 	"github.com/trustbloc/did-go/doc/did"
@@ -16,6 +17,65 @@ import (
 // ErrNotFound is returned when a DID resolver does not find the DID.
 var ErrNotFound = errors.New("DID does not exist")
 
+var (
+	// ErrInvalidDID is returned when the DID does not conform to the DID syntax or the method rules.
+	ErrInvalidDID = errors.New("invalid DID")
+	// ErrInvalidDIDURL is returned when the DID URL to dereference is not valid.
+	ErrInvalidDIDURL = errors.New("invalid DID URL")
+	// ErrRepresentationNotSupported is returned when the DID document representation is not supported.
+	ErrRepresentationNotSupported = errors.New("DID document representation not supported")
+	// ErrMethodNotSupported is returned when no VDR supports the DID method.
+	ErrMethodNotSupported = errors.New("DID method not supported")
+	// ErrInvalidPublicKeyType is returned when the DID public key type or the requested public key format is not
+	// valid.
+	ErrInvalidPublicKeyType = errors.New("invalid public key type")
+	// ErrInternal is returned when resolution fails for any other reason.
+	ErrInternal = errors.New("DID resolution internal error")
+)
+
+// ErrorCode returns the DID Resolution error code matching the given error,
+// errors not wrapping one of the sentinel errors are reported as internal errors.
+func ErrorCode(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrNotFound):
+		return did.ResolutionErrorNotFound
+	case errors.Is(err, ErrInvalidDIDURL):
+		return did.ResolutionErrorInvalidDIDURL
+	case errors.Is(err, ErrInvalidDID):
+		return did.ResolutionErrorInvalidDID
+	case errors.Is(err, ErrRepresentationNotSupported):
+		return did.ResolutionErrorRepresentationNotSupported
+	case errors.Is(err, ErrMethodNotSupported):
+		return did.ResolutionErrorMethodNotSupported
+	case errors.Is(err, ErrInvalidPublicKeyType):
+		return did.ResolutionErrorInvalidPublicKeyType
+	default:
+		return did.ResolutionErrorInternal
+	}
+}
+
+// ErrorFromCode returns the sentinel error matching the given DID Resolution error code.
+func ErrorFromCode(code string) error {
+	switch code {
+	case did.ResolutionErrorNotFound:
+		return ErrNotFound
+	case did.ResolutionErrorInvalidDID:
+		return ErrInvalidDID
+	case did.ResolutionErrorInvalidDIDURL:
+		return ErrInvalidDIDURL
+	case did.ResolutionErrorRepresentationNotSupported:
+		return ErrRepresentationNotSupported
+	case did.ResolutionErrorMethodNotSupported:
+		return ErrMethodNotSupported
+	case did.ResolutionErrorInvalidPublicKeyType:
+		return ErrInvalidPublicKeyType
+	default:
+		return ErrInternal
+	}
+}
+
 const (
 	// DIDCommServiceType default DID Communication service endpoint type.
 	DIDCommServiceType = "did-communication"
@@ -36,6 +96,12 @@ type Registry interface {
 	Close() error
 }
 
+// ContextRegistry is a vdr registry that supports context-aware resolution.
+type ContextRegistry interface {
+	Registry
+	ResolveContext(ctx context.Context, did string, opts ...DIDMethodOption) (*did.DocResolution, error)
+}
+
 // VDR verifiable data registry interface.
 // TODO https://github.com/hyperledger/aries-framework-go/issues/2475
 type VDR interface {
@@ -46,3 +112,14 @@ type VDR interface {
 	Deactivate(did string, opts ...DIDMethodOption) error
 	Close() error
 }
+
+// DocProcessor rewrites a resolved DID document, e.g. to convert documents of a legacy ledger into a usable form.
+// It may modify and return the given document or return a new one.
+type DocProcessor func(doc *did.Doc) (*did.Doc, error)
+
+// ContextVDR is an optional interface implemented by VDRs whose Read can be cancelled or bounded
+// by a deadline through the given context.
+type ContextVDR interface {
+	VDR
+	ReadContext(ctx context.Context, did string, opts ...DIDMethodOption) (*did.DocResolution, error)
+}
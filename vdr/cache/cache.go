@@ -0,0 +1,371 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package cache implements a caching vdr registry decorator.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	diddoc "github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/vdr"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	defaultTTL         = 5 * time.Minute
+	defaultNotFoundTTL = 30 * time.Second
+	defaultMaxSize     = 1000
+	// defaultResolveTimeout bounds the upstream call shared by concurrent resolutions of a DID.
+	defaultResolveTimeout = 30 * time.Second
+)
+
+// Option is a caching registry option.
+type Option func(opts *Registry)
+
+// Registry is a vdrapi.Registry that caches resolution results of the wrapped registry.
+//
+// Results are kept in a bounded LRU with a TTL per DID method, vdrapi.ErrNotFound results are cached with
+// a separate (usually shorter) TTL and concurrent resolutions of the same DID are collapsed into a single
+// upstream call. Resolutions requesting a specific version are never cached.
+//
+// Cached DocResolution values are shared between callers and must not be modified.
+type Registry struct {
+	registry    vdrapi.Registry
+	ttl         time.Duration
+	methodTTL   map[string]time.Duration
+	notFoundTTL time.Duration
+	maxSize     int
+	timeout     time.Duration
+	now         func() time.Time
+
+	mutex    sync.Mutex
+	entries  map[string]*list.Element
+	lru      *list.List
+	inflight map[string]*call
+}
+
+type entry struct {
+	key      string
+	did      string
+	res      *diddoc.DocResolution
+	err      error
+	expireAt time.Time
+}
+
+type call struct {
+	done chan struct{}
+	res  *diddoc.DocResolution
+	err  error
+}
+
+// New returns a caching registry decorating the given registry.
+func New(registry vdrapi.Registry, opts ...Option) *Registry {
+	r := &Registry{
+		registry:    registry,
+		ttl:         defaultTTL,
+		methodTTL:   make(map[string]time.Duration),
+		notFoundTTL: defaultNotFoundTTL,
+		maxSize:     defaultMaxSize,
+		timeout:     defaultResolveTimeout,
+		now:         time.Now,
+		entries:     make(map[string]*list.Element),
+		lru:         list.New(),
+		inflight:    make(map[string]*call),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Resolve did document, returning a cached result if available.
+func (r *Registry) Resolve(did string, opts ...vdrapi.DIDMethodOption) (*diddoc.DocResolution, error) {
+	return r.ResolveContext(context.Background(), did, opts...)
+}
+
+// ResolveContext resolves did document, returning a cached result if available.
+//
+// Concurrent callers resolving the same DID wait for the upstream call made on behalf of the first caller,
+// each of them is released early if its own context is done. The upstream call keeps the values of the first
+// caller's context but is not cancelled with it, it is abandoned after the resolve timeout instead.
+func (r *Registry) ResolveContext(ctx context.Context, did string,
+	opts ...vdrapi.DIDMethodOption) (*diddoc.DocResolution, error) {
+	key, cacheable := cacheKey(did, opts...)
+	if !cacheable {
+		return r.resolve(ctx, did, opts...)
+	}
+
+	r.mutex.Lock()
+
+	if e := r.get(key); e != nil {
+		r.mutex.Unlock()
+
+		return e.res, e.err
+	}
+
+	c, ok := r.inflight[key]
+	if !ok {
+		c = &call{done: make(chan struct{})}
+		r.inflight[key] = c
+
+		go r.fetch(context.WithoutCancel(ctx), key, did, c, opts...)
+	}
+
+	r.mutex.Unlock()
+
+	select {
+	case <-c.done:
+		return c.res, c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *Registry) fetch(ctx context.Context, key, did string, c *call, opts ...vdrapi.DIDMethodOption) {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	c.res, c.err = r.resolveUntilDone(ctx, did, opts...)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.inflight, key)
+
+	if ttl, ok := r.ttlFor(did, c.err); ok {
+		r.add(&entry{key: key, did: did, res: c.res, err: c.err, expireAt: r.now().Add(ttl)})
+	}
+
+	close(c.done)
+}
+
+// resolveUntilDone resolves the DID, giving up when the context is done even if the wrapped registry does not
+// support contexts.
+func (r *Registry) resolveUntilDone(ctx context.Context, did string,
+	opts ...vdrapi.DIDMethodOption) (*diddoc.DocResolution, error) {
+	done := make(chan *call, 1)
+
+	go func() {
+		res, err := r.resolve(ctx, did, opts...)
+		done <- &call{res: res, err: err}
+	}()
+
+	select {
+	case result := <-done:
+		return result.res, result.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("resolve %s: %w", did, ctx.Err())
+	}
+}
+
+func (r *Registry) resolve(ctx context.Context, did string,
+	opts ...vdrapi.DIDMethodOption) (*diddoc.DocResolution, error) {
+	if ctxRegistry, ok := r.registry.(vdrapi.ContextRegistry); ok {
+		return ctxRegistry.ResolveContext(ctx, did, opts...)
+	}
+
+	return r.registry.Resolve(did, opts...)
+}
+
+// ttlFor returns the ttl of a resolution result and whether the result can be cached at all.
+func (r *Registry) ttlFor(did string, err error) (time.Duration, bool) {
+	var ttl time.Duration
+
+	switch {
+	case err == nil:
+		ttl = r.ttl
+
+		if method, e := vdr.GetDidMethod(did); e == nil {
+			if methodTTL, ok := r.methodTTL[method]; ok {
+				ttl = methodTTL
+			}
+		}
+	case errors.Is(err, vdrapi.ErrNotFound):
+		ttl = r.notFoundTTL
+	default:
+		return 0, false
+	}
+
+	return ttl, ttl > 0 && r.maxSize > 0
+}
+
+// get returns the unexpired cache entry of the given key or nil.
+func (r *Registry) get(key string) *entry {
+	elem, ok := r.entries[key]
+	if !ok {
+		return nil
+	}
+
+	e := elem.Value.(*entry) //nolint:errcheck,forcetypeassert
+
+	if !r.now().Before(e.expireAt) {
+		r.remove(elem)
+
+		return nil
+	}
+
+	r.lru.MoveToFront(elem)
+
+	return e
+}
+
+func (r *Registry) add(e *entry) {
+	if elem, ok := r.entries[e.key]; ok {
+		r.remove(elem)
+	}
+
+	r.entries[e.key] = r.lru.PushFront(e)
+
+	for r.lru.Len() > r.maxSize {
+		r.remove(r.lru.Back())
+	}
+}
+
+func (r *Registry) remove(elem *list.Element) {
+	e := r.lru.Remove(elem).(*entry) //nolint:errcheck,forcetypeassert
+
+	delete(r.entries, e.key)
+}
+
+// Purge removes all cached results of the given DID.
+func (r *Registry) Purge(did string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for elem := r.lru.Front(); elem != nil; {
+		next := elem.Next()
+
+		if elem.Value.(*entry).did == did { //nolint:forcetypeassert
+			r.remove(elem)
+		}
+
+		elem = next
+	}
+}
+
+// Create a new DID Document through the wrapped registry.
+func (r *Registry) Create(method string, did *diddoc.Doc,
+	opts ...vdrapi.DIDMethodOption) (*diddoc.DocResolution, error) {
+	res, err := r.registry.Create(method, did, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if res != nil && res.DIDDocument != nil {
+		r.Purge(res.DIDDocument.ID)
+	}
+
+	return res, nil
+}
+
+// Update did document through the wrapped registry and drop its cached results.
+func (r *Registry) Update(didDoc *diddoc.Doc, opts ...vdrapi.DIDMethodOption) error {
+	defer r.Purge(didDoc.ID)
+
+	return r.registry.Update(didDoc, opts...)
+}
+
+// Deactivate did document through the wrapped registry and drop its cached results.
+func (r *Registry) Deactivate(did string, opts ...vdrapi.DIDMethodOption) error {
+	defer r.Purge(did)
+
+	return r.registry.Deactivate(did, opts...)
+}
+
+// Close frees resources being maintained by the wrapped registry.
+func (r *Registry) Close() error {
+	r.mutex.Lock()
+	r.entries = make(map[string]*list.Element)
+	r.lru.Init()
+	r.mutex.Unlock()
+
+	return r.registry.Close()
+}
+
+// cacheKey builds the cache key of a resolution, the second return value is false if the resolution
+// requests a specific version and must not be cached.
+func cacheKey(did string, opts ...vdrapi.DIDMethodOption) (string, bool) {
+	didOpts := &vdrapi.DIDMethodOpts{Values: make(map[string]interface{})}
+
+	for _, opt := range opts {
+		opt(didOpts)
+	}
+
+	if didOpts.Values[vdrapi.VersionIDOpt] != nil || didOpts.Values[vdrapi.VersionTimeOpt] != nil {
+		return "", false
+	}
+
+	if len(didOpts.Values) == 0 {
+		return did, true
+	}
+
+	names := make([]string, 0, len(didOpts.Values))
+	for name := range didOpts.Values {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var sb strings.Builder
+
+	sb.WriteString(did)
+
+	for _, name := range names {
+		fmt.Fprintf(&sb, "|%s=%v", name, didOpts.Values[name])
+	}
+
+	return sb.String(), true
+}
+
+// WithTTL sets the ttl of resolved DID documents, a zero ttl disables caching of resolved documents.
+func WithTTL(ttl time.Duration) Option {
+	return func(opts *Registry) {
+		opts.ttl = ttl
+	}
+}
+
+// WithMethodTTL sets the ttl of resolved DID documents of the given DID method, overriding WithTTL.
+func WithMethodTTL(method string, ttl time.Duration) Option {
+	return func(opts *Registry) {
+		opts.methodTTL[method] = ttl
+	}
+}
+
+// WithNotFoundTTL sets the ttl of vdrapi.ErrNotFound results, a zero ttl disables negative caching.
+func WithNotFoundTTL(ttl time.Duration) Option {
+	return func(opts *Registry) {
+		opts.notFoundTTL = ttl
+	}
+}
+
+// WithMaxSize sets the maximum number of cached results, least recently used results are evicted first.
+func WithMaxSize(size int) Option {
+	return func(opts *Registry) {
+		opts.maxSize = size
+	}
+}
+
+// WithResolveTimeout sets the timeout of the upstream call shared by concurrent resolutions of a DID, which is
+// not cancelled with the context of its callers. A zero timeout disables it.
+func WithResolveTimeout(timeout time.Duration) Option {
+	return func(opts *Registry) {
+		opts.timeout = timeout
+	}
+}
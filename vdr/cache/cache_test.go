@@ -0,0 +1,379 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+	mockvdr "github.com/trustbloc/did-go/vdr/mock"
+)
+
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+func newCountingRegistry(calls *int32, err error) *mockvdr.VDRegistry {
+	return &mockvdr.VDRegistry{
+		ResolveFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+			atomic.AddInt32(calls, 1)
+
+			if err != nil {
+				return nil, err
+			}
+
+			return &did.DocResolution{DIDDocument: &did.Doc{ID: didID}}, nil
+		},
+	}
+}
+
+func TestRegistry_Resolve(t *testing.T) {
+	t.Run("test result cached until ttl expires", func(t *testing.T) {
+		var calls int32
+
+		c := &clock{t: time.Now()}
+		r := New(newCountingRegistry(&calls, nil), WithTTL(time.Minute))
+		r.now = c.now
+
+		res, err := r.Resolve("did:web:example.com")
+		require.NoError(t, err)
+		require.Equal(t, "did:web:example.com", res.DIDDocument.ID)
+
+		_, err = r.Resolve("did:web:example.com")
+		require.NoError(t, err)
+		require.EqualValues(t, 1, atomic.LoadInt32(&calls))
+
+		c.t = c.t.Add(time.Minute)
+
+		_, err = r.Resolve("did:web:example.com")
+		require.NoError(t, err)
+		require.EqualValues(t, 2, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("test method ttl", func(t *testing.T) {
+		var calls int32
+
+		c := &clock{t: time.Now()}
+		r := New(newCountingRegistry(&calls, nil), WithTTL(time.Hour), WithMethodTTL("web", time.Second))
+		r.now = c.now
+
+		_, err := r.Resolve("did:web:example.com")
+		require.NoError(t, err)
+
+		_, err = r.Resolve("did:key:z6MkpTHR8VNsBxYAAWHut2Geadd9jSwuBV8xRoAnwWsdvktH")
+		require.NoError(t, err)
+
+		c.t = c.t.Add(2 * time.Second)
+
+		_, err = r.Resolve("did:web:example.com")
+		require.NoError(t, err)
+
+		_, err = r.Resolve("did:key:z6MkpTHR8VNsBxYAAWHut2Geadd9jSwuBV8xRoAnwWsdvktH")
+		require.NoError(t, err)
+
+		require.EqualValues(t, 3, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("test not found cached with not found ttl", func(t *testing.T) {
+		var calls int32
+
+		c := &clock{t: time.Now()}
+		r := New(newCountingRegistry(&calls, vdrapi.ErrNotFound), WithNotFoundTTL(time.Second))
+		r.now = c.now
+
+		_, err := r.Resolve("did:web:example.com")
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+
+		_, err = r.Resolve("did:web:example.com")
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+		require.EqualValues(t, 1, atomic.LoadInt32(&calls))
+
+		c.t = c.t.Add(time.Second)
+
+		_, err = r.Resolve("did:web:example.com")
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+		require.EqualValues(t, 2, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("test other errors not cached", func(t *testing.T) {
+		var calls int32
+
+		r := New(newCountingRegistry(&calls, errors.New("resolve error")))
+
+		for i := 0; i < 2; i++ {
+			_, err := r.Resolve("did:web:example.com")
+			require.EqualError(t, err, "resolve error")
+		}
+
+		require.EqualValues(t, 2, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("test version options bypass cache", func(t *testing.T) {
+		var calls int32
+
+		r := New(newCountingRegistry(&calls, nil))
+
+		for i := 0; i < 2; i++ {
+			_, err := r.Resolve("did:web:example.com", vdrapi.WithOption(vdrapi.VersionIDOpt, "1"))
+			require.NoError(t, err)
+
+			_, err = r.Resolve("did:web:example.com", vdrapi.WithOption(vdrapi.VersionTimeOpt, "2021-05-10T17:00:00Z"))
+			require.NoError(t, err)
+		}
+
+		require.EqualValues(t, 4, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("test options are part of the cache key", func(t *testing.T) {
+		var calls int32
+
+		r := New(newCountingRegistry(&calls, nil))
+
+		_, err := r.Resolve("did:web:example.com", vdrapi.WithOption("k1", "v1"))
+		require.NoError(t, err)
+
+		_, err = r.Resolve("did:web:example.com", vdrapi.WithOption("k1", "v2"))
+		require.NoError(t, err)
+
+		_, err = r.Resolve("did:web:example.com", vdrapi.WithOption("k1", "v1"))
+		require.NoError(t, err)
+
+		require.EqualValues(t, 2, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("test lru eviction", func(t *testing.T) {
+		var calls int32
+
+		r := New(newCountingRegistry(&calls, nil), WithMaxSize(2))
+
+		for _, id := range []string{"did:web:a", "did:web:b", "did:web:a", "did:web:c", "did:web:a", "did:web:b"} {
+			_, err := r.Resolve(id)
+			require.NoError(t, err)
+		}
+
+		// did:web:b is evicted by did:web:c as did:web:a was used more recently.
+		require.EqualValues(t, 4, atomic.LoadInt32(&calls))
+		require.Equal(t, 2, r.lru.Len())
+	})
+
+	t.Run("test concurrent resolves collapsed", func(t *testing.T) {
+		var calls int32
+
+		release := make(chan struct{})
+
+		r := New(&mockvdr.VDRegistry{
+			ResolveFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				atomic.AddInt32(&calls, 1)
+				<-release
+
+				return &did.DocResolution{DIDDocument: &did.Doc{ID: didID}}, nil
+			},
+		})
+
+		const n = 10
+
+		var wg sync.WaitGroup
+
+		wg.Add(n)
+
+		for i := 0; i < n; i++ {
+			go func() {
+				defer wg.Done()
+
+				res, err := r.Resolve("did:web:example.com")
+				require.NoError(t, err)
+				require.Equal(t, "did:web:example.com", res.DIDDocument.ID)
+			}()
+		}
+
+		require.Eventually(t, func() bool {
+			return atomic.LoadInt32(&calls) == 1
+		}, time.Second, time.Millisecond)
+
+		close(release)
+		wg.Wait()
+
+		require.EqualValues(t, 1, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("test waiting caller released on context done", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		r := New(&mockvdr.VDRegistry{
+			ResolveFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				<-release
+
+				return &did.DocResolution{DIDDocument: &did.Doc{ID: didID}}, nil
+			},
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := r.ResolveContext(ctx, "did:web:example.com")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("test shared call not cancelled with the first caller", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		r := New(&mockvdr.VDRegistry{
+			ResolveCtxFunc: func(ctx context.Context, didID string,
+				opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				close(started)
+
+				select {
+				case <-release:
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+
+				return &did.DocResolution{DIDDocument: &did.Doc{ID: didID}}, nil
+			},
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		firstDone := make(chan error)
+
+		go func() {
+			_, err := r.ResolveContext(ctx, "did:web:example.com")
+			firstDone <- err
+		}()
+
+		<-started
+
+		secondDone := make(chan *did.DocResolution)
+
+		go func() {
+			res, err := r.ResolveContext(context.Background(), "did:web:example.com")
+			require.NoError(t, err)
+			secondDone <- res
+		}()
+
+		cancel()
+		require.ErrorIs(t, <-firstDone, context.Canceled)
+
+		close(release)
+		require.Equal(t, "did:web:example.com", (<-secondDone).DIDDocument.ID)
+	})
+
+	t.Run("test shared call abandoned after the resolve timeout", func(t *testing.T) {
+		var calls int32
+
+		release := make(chan struct{})
+		defer close(release)
+
+		r := New(&mockvdr.VDRegistry{
+			ResolveFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					<-release
+				}
+
+				return &did.DocResolution{DIDDocument: &did.Doc{ID: didID}}, nil
+			},
+		}, WithResolveTimeout(10*time.Millisecond))
+
+		_, err := r.ResolveContext(context.Background(), "did:web:example.com")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		res, err := r.ResolveContext(context.Background(), "did:web:example.com")
+		require.NoError(t, err)
+		require.Equal(t, "did:web:example.com", res.DIDDocument.ID)
+		require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("test context passed to context registry", func(t *testing.T) {
+		type ctxKey struct{}
+
+		r := New(&mockvdr.VDRegistry{
+			ResolveCtxFunc: func(ctx context.Context, didID string,
+				opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				require.Equal(t, "v1", ctx.Value(ctxKey{}))
+
+				return &did.DocResolution{DIDDocument: &did.Doc{ID: didID}}, nil
+			},
+		})
+
+		_, err := r.ResolveContext(context.WithValue(context.Background(), ctxKey{}, "v1"), "did:web:example.com")
+		require.NoError(t, err)
+	})
+}
+
+func TestRegistry_Invalidation(t *testing.T) {
+	t.Run("test update and deactivate purge cached results", func(t *testing.T) {
+		var calls int32
+
+		r := New(newCountingRegistry(&calls, nil))
+
+		_, err := r.Resolve("did:web:example.com")
+		require.NoError(t, err)
+
+		require.NoError(t, r.Update(&did.Doc{ID: "did:web:example.com"}))
+
+		_, err = r.Resolve("did:web:example.com", vdrapi.WithOption("k1", "v1"))
+		require.NoError(t, err)
+
+		require.NoError(t, r.Deactivate("did:web:example.com"))
+
+		_, err = r.Resolve("did:web:example.com")
+		require.NoError(t, err)
+
+		require.EqualValues(t, 3, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("test create purges cached not found result", func(t *testing.T) {
+		var calls int32
+
+		r := New(newCountingRegistry(&calls, vdrapi.ErrNotFound))
+
+		_, err := r.Resolve("did:local:abc")
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+
+		res, err := r.Create("local", nil)
+		require.NoError(t, err)
+		require.Equal(t, "did:local:abc", res.DIDDocument.ID)
+
+		_, err = r.Resolve("did:local:abc")
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+
+		require.EqualValues(t, 2, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("test create error", func(t *testing.T) {
+		r := New(&mockvdr.VDRegistry{CreateErr: errors.New("create error")})
+
+		_, err := r.Create("local", nil)
+		require.EqualError(t, err, "create error")
+	})
+
+	t.Run("test close", func(t *testing.T) {
+		var calls int32
+
+		r := New(newCountingRegistry(&calls, nil))
+
+		_, err := r.Resolve("did:web:example.com")
+		require.NoError(t, err)
+
+		require.NoError(t, r.Close())
+		require.Equal(t, 0, r.lru.Len())
+	})
+}
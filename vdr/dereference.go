@@ -0,0 +1,188 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vdr
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	diddoc "github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	serviceQuery     = "service"
+	relativeRefQuery = "relativeRef"
+	versionIDQuery   = "versionId"
+	versionTimeQuery = "versionTime"
+)
+
+// Dereference dereferences a DID URL (https://w3c-ccg.github.io/did-resolution/#dereferencing).
+func (r *Registry) Dereference(didURL string, opts ...vdrapi.DIDMethodOption) (*diddoc.DereferencingResult, error) {
+	return r.DereferenceContext(context.Background(), didURL, opts...)
+}
+
+// DereferenceContext dereferences a DID URL, the given context is used to resolve the DID.
+//
+// A fragment selects the matching verification method or service of the resolved DID document, the service and
+// relativeRef query parameters select a service endpoint URL and the versionId and versionTime query parameters
+// are passed to the DID method.
+func (r *Registry) DereferenceContext(ctx context.Context, didURL string,
+	opts ...vdrapi.DIDMethodOption) (*diddoc.DereferencingResult, error) {
+	parsed, err := diddoc.ParseDIDURL(didURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse did url: %w: %w", err, vdrapi.ErrInvalidDIDURL)
+	}
+
+	queries := url.Values(parsed.Queries)
+
+	resolveOpts, err := versionOpts(queries)
+	if err != nil {
+		return nil, err
+	}
+
+	docResolution, err := r.ResolveContext(ctx, parsed.DID.String(), append(slices.Clip(opts), resolveOpts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if docResolution == nil || docResolution.DIDDocument == nil {
+		return nil, fmt.Errorf("resolve %s: %w", parsed.DID.String(), vdrapi.ErrNotFound)
+	}
+
+	if service := queries.Get(serviceQuery); service != "" {
+		return dereferenceService(docResolution, parsed, service)
+	}
+
+	if parsed.Path != "" && parsed.Path != "/" {
+		return nil, fmt.Errorf("dereference path %s: %w", parsed.Path, vdrapi.ErrNotFound)
+	}
+
+	result := &diddoc.DereferencingResult{
+		ContentMetadata:       docResolution.DocumentMetadata,
+		DereferencingMetadata: &diddoc.DereferencingMetadata{ContentType: diddoc.ContentTypeDIDLDJSON},
+	}
+
+	if parsed.Fragment == "" {
+		result.ContentStream = docResolution.DIDDocument
+
+		return result, nil
+	}
+
+	resource, ok := lookupFragment(docResolution.DIDDocument, parsed.Fragment)
+	if !ok {
+		return nil, fmt.Errorf("dereference fragment %s: %w", parsed.Fragment, vdrapi.ErrNotFound)
+	}
+
+	result.ContentStream = resource
+
+	return result, nil
+}
+
+func versionOpts(queries url.Values) ([]vdrapi.DIDMethodOption, error) {
+	var opts []vdrapi.DIDMethodOption
+
+	versionID := queries.Get(versionIDQuery)
+	versionTime := queries.Get(versionTimeQuery)
+
+	if versionID != "" && versionTime != "" {
+		return nil, fmt.Errorf("versionId and versionTime can not be set at same time: %w", vdrapi.ErrInvalidDIDURL)
+	}
+
+	if versionID != "" {
+		opts = append(opts, vdrapi.WithOption(vdrapi.VersionIDOpt, versionID))
+	}
+
+	if versionTime != "" {
+		opts = append(opts, vdrapi.WithOption(vdrapi.VersionTimeOpt, versionTime))
+	}
+
+	return opts, nil
+}
+
+func dereferenceService(docResolution *diddoc.DocResolution, parsed *diddoc.DIDURL,
+	service string) (*diddoc.DereferencingResult, error) {
+	s, ok := lookupService(docResolution.DIDDocument, service)
+	if !ok {
+		return nil, fmt.Errorf("dereference service %s: %w", service, vdrapi.ErrNotFound)
+	}
+
+	uri, err := s.ServiceEndpoint.URI()
+	if err != nil {
+		return nil, fmt.Errorf("dereference service %s: %w", service, err)
+	}
+
+	endpointURL, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("parse service endpoint %s: %w", uri, err)
+	}
+
+	if relativeRef := url.Values(parsed.Queries).Get(relativeRefQuery); relativeRef != "" {
+		ref, e := url.Parse(relativeRef)
+		if e != nil {
+			return nil, fmt.Errorf("parse relativeRef %s: %w: %w", relativeRef, e, vdrapi.ErrInvalidDIDURL)
+		}
+
+		endpointURL = endpointURL.ResolveReference(ref)
+	}
+
+	if parsed.Fragment != "" && endpointURL.Fragment == "" {
+		endpointURL.Fragment = parsed.Fragment
+	}
+
+	return &diddoc.DereferencingResult{
+		ContentStream:         endpointURL.String(),
+		ContentMetadata:       docResolution.DocumentMetadata,
+		DereferencingMetadata: &diddoc.DereferencingMetadata{ContentType: diddoc.ContentTypeURIList},
+	}, nil
+}
+
+func lookupFragment(doc *diddoc.Doc, fragment string) (interface{}, bool) {
+	for i := range doc.VerificationMethod {
+		if matchesFragment(doc.VerificationMethod[i].ID, fragment) {
+			return &doc.VerificationMethod[i], true
+		}
+	}
+
+	relationships := [][]diddoc.Verification{
+		doc.Authentication, doc.AssertionMethod, doc.CapabilityDelegation, doc.CapabilityInvocation, doc.KeyAgreement,
+	}
+
+	for _, verifications := range relationships {
+		for i := range verifications {
+			if verifications[i].Embedded && matchesFragment(verifications[i].VerificationMethod.ID, fragment) {
+				return &verifications[i].VerificationMethod, true
+			}
+		}
+	}
+
+	if s, ok := lookupService(doc, fragment); ok {
+		return s, true
+	}
+
+	return nil, false
+}
+
+func lookupService(doc *diddoc.Doc, fragment string) (*diddoc.Service, bool) {
+	for i := range doc.Service {
+		if matchesFragment(doc.Service[i].ID, fragment) {
+			return &doc.Service[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// matchesFragment checks if the absolute or relative DID URL id has the given fragment.
+func matchesFragment(id, fragment string) bool {
+	i := strings.LastIndex(id, "#")
+
+	return i >= 0 && id[i+1:] == fragment
+}
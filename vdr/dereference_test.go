@@ -0,0 +1,190 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vdr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+	mockvdr "github.com/trustbloc/did-go/vdr/mock"
+)
+
+const dereferenceDoc = `{
+  "@context": ["https://www.w3.org/ns/did/v1"],
+  "id": "did:example:123",
+  "verificationMethod": [
+    {
+      "id": "did:example:123#key-1",
+      "type": "Ed25519VerificationKey2018",
+      "controller": "did:example:123",
+      "publicKeyBase58": "B12NYF8RrR3h41TDCTJojY59usg3mbtbjnFs7Eud1Y6u"
+    }
+  ],
+  "keyAgreement": [
+    {
+      "id": "#key-agreement",
+      "type": "X25519KeyAgreementKey2019",
+      "controller": "did:example:123",
+      "publicKeyBase58": "JhNWeSVLMYccCk7iopQW4guaSJTojqpMEELgSLhKwRr"
+    }
+  ],
+  "service": [
+    {
+      "id": "#files",
+      "type": "LinkedDomains",
+      "serviceEndpoint": "https://example.com/files/"
+    }
+  ]
+}`
+
+func newDereferenceRegistry(t *testing.T, readOpts *vdrapi.DIDMethodOpts) *Registry {
+	t.Helper()
+
+	doc, err := did.ParseDocument([]byte(dereferenceDoc))
+	require.NoError(t, err)
+
+	return New(WithVDR(&mockvdr.VDR{
+		AcceptValue: true,
+		ReadFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+			if readOpts != nil {
+				for _, opt := range opts {
+					opt(readOpts)
+				}
+			}
+
+			if didID != doc.ID {
+				return nil, vdrapi.ErrNotFound
+			}
+
+			return &did.DocResolution{
+				DIDDocument:      doc,
+				DocumentMetadata: &did.DocumentMetadata{VersionID: "1"},
+			}, nil
+		},
+	}))
+}
+
+func TestRegistry_Dereference(t *testing.T) {
+	t.Run("test dereference did", func(t *testing.T) {
+		registry := newDereferenceRegistry(t, nil)
+
+		result, err := registry.Dereference("did:example:123")
+		require.NoError(t, err)
+
+		doc, ok := result.ContentStream.(*did.Doc)
+		require.True(t, ok)
+		require.Equal(t, "did:example:123", doc.ID)
+		require.Equal(t, "1", result.ContentMetadata.VersionID)
+		require.Equal(t, did.ContentTypeDIDLDJSON, result.DereferencingMetadata.ContentType)
+	})
+
+	t.Run("test dereference verification method", func(t *testing.T) {
+		registry := newDereferenceRegistry(t, nil)
+
+		result, err := registry.Dereference("did:example:123#key-1")
+		require.NoError(t, err)
+
+		vm, ok := result.ContentStream.(*did.VerificationMethod)
+		require.True(t, ok)
+		require.Equal(t, "did:example:123#key-1", vm.ID)
+		require.Equal(t, "1", result.ContentMetadata.VersionID)
+	})
+
+	t.Run("test dereference embedded verification method", func(t *testing.T) {
+		registry := newDereferenceRegistry(t, nil)
+
+		result, err := registry.Dereference("did:example:123#key-agreement")
+		require.NoError(t, err)
+
+		vm, ok := result.ContentStream.(*did.VerificationMethod)
+		require.True(t, ok)
+		require.Equal(t, "X25519KeyAgreementKey2019", vm.Type)
+	})
+
+	t.Run("test dereference service", func(t *testing.T) {
+		registry := newDereferenceRegistry(t, nil)
+
+		result, err := registry.Dereference("did:example:123#files")
+		require.NoError(t, err)
+
+		s, ok := result.ContentStream.(*did.Service)
+		require.True(t, ok)
+		require.Equal(t, "LinkedDomains", s.Type)
+	})
+
+	t.Run("test dereference service endpoint", func(t *testing.T) {
+		registry := newDereferenceRegistry(t, nil)
+
+		result, err := registry.Dereference("did:example:123?service=files")
+		require.NoError(t, err)
+		require.Equal(t, "https://example.com/files/", result.ContentStream)
+		require.Equal(t, did.ContentTypeURIList, result.DereferencingMetadata.ContentType)
+
+		result, err = registry.Dereference(
+			"did:example:123?service=files&relativeRef=%2Fresume.pdf%3Fformat%3Dfull#page-2")
+		require.NoError(t, err)
+		require.Equal(t, "https://example.com/resume.pdf?format=full#page-2", result.ContentStream)
+
+		result, err = registry.Dereference("did:example:123?service=files&relativeRef=docs/resume.pdf")
+		require.NoError(t, err)
+		require.Equal(t, "https://example.com/files/docs/resume.pdf", result.ContentStream)
+	})
+
+	t.Run("test version query passed to method", func(t *testing.T) {
+		readOpts := &vdrapi.DIDMethodOpts{Values: make(map[string]interface{})}
+		registry := newDereferenceRegistry(t, readOpts)
+
+		_, err := registry.Dereference("did:example:123?versionId=1#key-1")
+		require.NoError(t, err)
+		require.Equal(t, "1", readOpts.Values[vdrapi.VersionIDOpt])
+
+		_, err = registry.Dereference("did:example:123?versionTime=2021-05-10T17:00:00Z")
+		require.NoError(t, err)
+		require.Equal(t, "2021-05-10T17:00:00Z", readOpts.Values[vdrapi.VersionTimeOpt])
+
+		_, err = registry.Dereference("did:example:123?versionId=1&versionTime=2021-05-10T17:00:00Z")
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDIDURL)
+	})
+
+	t.Run("test caller options not modified", func(t *testing.T) {
+		registry := newDereferenceRegistry(t, nil)
+
+		callerOpt := vdrapi.WithOption("k", "v")
+
+		opts := make([]vdrapi.DIDMethodOption, 1, 2)
+		opts[0] = callerOpt
+
+		_, err := registry.Dereference("did:example:123?versionId=1", opts...)
+		require.NoError(t, err)
+		require.Nil(t, opts[:2][1])
+	})
+
+	t.Run("test not found", func(t *testing.T) {
+		registry := newDereferenceRegistry(t, nil)
+
+		for _, didURL := range []string{
+			"did:example:456#key-1",
+			"did:example:123#key-2",
+			"did:example:123?service=unknown",
+			"did:example:123/path",
+		} {
+			_, err := registry.Dereference(didURL)
+			require.ErrorIs(t, err, vdrapi.ErrNotFound, didURL)
+		}
+	})
+
+	t.Run("test invalid did url", func(t *testing.T) {
+		registry := newDereferenceRegistry(t, nil)
+
+		_, err := registry.Dereference("invalid")
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDIDURL)
+		require.Equal(t, did.ResolutionErrorInvalidDIDURL, vdrapi.ErrorCode(err))
+	})
+}
@@ -0,0 +1,157 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didconfig
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	diddoc "github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/doc/did/endpoint"
+	"github.com/trustbloc/did-go/doc/ld/processor"
+	"github.com/trustbloc/did-go/doc/signature/api"
+)
+
+const webMethod = "web"
+
+// JWTSigner signs Domain Linkage Credentials in the JWT format, it is implemented by signature suites.
+type JWTSigner interface {
+	// Sign returns the JWS signature of data, ECDSA signatures are in the R || S form.
+	Sign(data []byte) ([]byte, error)
+	// Alg returns the JWS algorithm of the signatures, e.g. EdDSA or ES256.
+	Alg() string
+}
+
+// Credential is a Domain Linkage Credential linking DID to Origin.
+type Credential struct {
+	DID            string
+	Origin         string
+	IssuanceDate   time.Time
+	ExpirationDate time.Time
+}
+
+// JWT returns the credential in the JWT format, signed by the verification method keyID of the DID.
+func (c *Credential) JWT(keyID string, signer JWTSigner) (string, error) {
+	vc, err := c.credential()
+	if err != nil {
+		return "", fmt.Errorf("create jwt credential: %w", err)
+	}
+
+	// The vc claim of a JWT credential leaves the proof to the JWS.
+	header, err := json.Marshal(map[string]string{"alg": signer.Alg(), "kid": keyID})
+	if err != nil {
+		return "", fmt.Errorf("create jwt credential: %w", err)
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"iss": c.DID,
+		"sub": c.DID,
+		"nbf": c.IssuanceDate.Unix(),
+		"exp": c.ExpirationDate.Unix(),
+		"vc":  vc,
+	})
+	if err != nil {
+		return "", fmt.Errorf("create jwt credential: %w", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	signature, err := signer.Sign([]byte(signingInput))
+	if err != nil {
+		return "", fmt.Errorf("create jwt credential: sign: %w", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// LinkedData returns the credential as a JSON-LD credential with a Linked Data proof created by signer. The
+// verification method of signCtx must be a key of the DID.
+func (c *Credential) LinkedData(signer api.Signer, signCtx *api.Context, opts ...processor.Opts) (json.RawMessage,
+	error) {
+	vc, err := c.credential()
+	if err != nil {
+		return nil, fmt.Errorf("create linked data credential: %w", err)
+	}
+
+	b, err := json.Marshal(vc)
+	if err != nil {
+		return nil, fmt.Errorf("create linked data credential: %w", err)
+	}
+
+	signed, err := signer.Sign(signCtx, b, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create linked data credential: sign: %w", err)
+	}
+
+	return signed, nil
+}
+
+func (c *Credential) credential() (*credential, error) {
+	origin, err := NormalizeOrigin(c.Origin)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.DID == "" {
+		return nil, errors.New("missing did")
+	}
+
+	if !c.IssuanceDate.Before(c.ExpirationDate) {
+		return nil, errors.New("expiration date must be after the issuance date")
+	}
+
+	return &credential{
+		Context:           []string{ContextCredentialsV1, ContextV1},
+		Type:              []string{verifiableCredentialType, DomainLinkageCredentialType},
+		Issuer:            c.DID,
+		IssuanceDate:      c.IssuanceDate.UTC().Format(time.RFC3339),
+		ExpirationDate:    c.ExpirationDate.UTC().Format(time.RFC3339),
+		CredentialSubject: credentialSubject{ID: c.DID, Origin: origin},
+	}, nil
+}
+
+// WebOrigin returns the origin hosting the did:web DID, e.g. https://example.com:8443 for
+// did:web:example.com%3A8443:user:alice.
+func WebOrigin(didWeb string) (string, error) {
+	parsed, err := diddoc.Parse(didWeb)
+	if err != nil {
+		return "", fmt.Errorf("parse did: %w", err)
+	}
+
+	if parsed.Method != webMethod {
+		return "", fmt.Errorf("not a did:web did [%s]", didWeb)
+	}
+
+	host, err := url.PathUnescape(strings.Split(parsed.MethodSpecificID, ":")[0])
+	if err != nil {
+		return "", fmt.Errorf("parse did:web host: %w", err)
+	}
+
+	return NormalizeOrigin("https://" + host)
+}
+
+// NewLinkedDomainsService returns the LinkedDomains service of a DID document linking the DID to origins.
+func NewLinkedDomainsService(id string, origins ...string) diddoc.Service {
+	var serviceEndpoint endpoint.Endpoint
+
+	if len(origins) == 1 {
+		serviceEndpoint = endpoint.NewDIDCommV1Endpoint(origins[0])
+	} else {
+		serviceEndpoint = endpoint.NewDIDCoreEndpoint(map[string]interface{}{"origins": origins})
+	}
+
+	return diddoc.Service{
+		ID:              id,
+		Type:            LinkedDomainsType,
+		ServiceEndpoint: serviceEndpoint,
+	}
+}
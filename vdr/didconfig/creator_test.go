@@ -0,0 +1,124 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didconfig
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type failingSigner struct{}
+
+func (failingSigner) Sign([]byte) ([]byte, error) {
+	return nil, errors.New("sign error")
+}
+
+func (failingSigner) Alg() string {
+	return "EdDSA"
+}
+
+func TestCredential_JWT(t *testing.T) {
+	t.Run("test jwt claims", func(t *testing.T) {
+		f := newFixture(t)
+
+		jwt := f.jwt(t, f.credential("https://Example.com/"))
+		parts := strings.Split(jwt, ".")
+		require.Len(t, parts, 3)
+
+		header, err := base64.RawURLEncoding.DecodeString(parts[0])
+		require.NoError(t, err)
+		require.JSONEq(t, `{"alg":"EdDSA","kid":"`+testKeyID+`"}`, string(header))
+
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+
+		var claims jwtClaims
+
+		require.NoError(t, json.Unmarshal(payload, &claims))
+		require.Equal(t, testDID, claims.Issuer)
+		require.Equal(t, testDID, claims.Subject)
+		require.NotNil(t, claims.NotBefore)
+		require.NotNil(t, claims.Expiry)
+		require.Equal(t, "https://example.com", claims.VC.CredentialSubject.Origin)
+		require.NoError(t, claims.VC.validate(testDID, "https://example.com", time.Now(), true))
+	})
+
+	t.Run("test invalid credential", func(t *testing.T) {
+		f := newFixture(t)
+
+		c := f.credential("https://example.com/path")
+		_, err := c.JWT(testKeyID, f.suite)
+		require.ErrorContains(t, err, "invalid origin")
+
+		c = f.credential("https://example.com")
+		c.DID = ""
+		_, err = c.JWT(testKeyID, f.suite)
+		require.ErrorContains(t, err, "missing did")
+
+		c = f.credential("https://example.com")
+		c.ExpirationDate = c.IssuanceDate
+		_, err = c.JWT(testKeyID, f.suite)
+		require.ErrorContains(t, err, "expiration date must be after the issuance date")
+	})
+
+	t.Run("test sign failure", func(t *testing.T) {
+		_, err := newFixture(t).credential("https://example.com").JWT(testKeyID, failingSigner{})
+		require.ErrorContains(t, err, "sign error")
+	})
+}
+
+func TestConfiguration(t *testing.T) {
+	c := NewConfiguration()
+	c.AddJWT("header.payload.signature")
+	c.AddLinkedData(json.RawMessage(`{"id":"vc"}`))
+
+	b, err := json.Marshal(c)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"@context": "https://identity.foundation/.well-known/did-configuration/v1",
+		"linked_dids": ["header.payload.signature", {"id":"vc"}]
+	}`, string(b))
+
+	b, err = json.Marshal(NewConfiguration())
+	require.NoError(t, err)
+	require.JSONEq(t, `{"@context":"`+ContextV1+`","linked_dids":[]}`, string(b))
+}
+
+func TestWebOrigin(t *testing.T) {
+	origin, err := WebOrigin("did:web:example.com")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com", origin)
+
+	origin, err = WebOrigin("did:web:example.com%3A8443:user:alice")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com:8443", origin)
+
+	_, err = WebOrigin("did:key:z6Mk")
+	require.ErrorContains(t, err, "not a did:web did")
+
+	_, err = WebOrigin("web:example.com")
+	require.ErrorContains(t, err, "parse did")
+}
+
+func TestNewLinkedDomainsService(t *testing.T) {
+	s := NewLinkedDomainsService("#domains", "https://example.com")
+	b, err := s.ServiceEndpoint.MarshalJSON()
+	require.NoError(t, err)
+	require.JSONEq(t, `"https://example.com"`, string(b))
+
+	s = NewLinkedDomainsService("#domains", "https://example.com", "https://example.org")
+	b, err = s.ServiceEndpoint.MarshalJSON()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"origins":["https://example.com","https://example.org"]}`, string(b))
+	require.Equal(t, LinkedDomainsType, s.Type)
+}
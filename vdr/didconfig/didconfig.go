@@ -0,0 +1,205 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package didconfig implements the DIF Well-Known DID Configuration
+// (https://identity.foundation/.well-known/resources/did-configuration/): it verifies the Domain Linkage
+// Credentials an origin publishes at /.well-known/did-configuration.json against the LinkedDomains services of the
+// linked DIDs, and creates the DID Configuration resource of an origin.
+package didconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// ContextV1 is the JSON-LD context of the DID Configuration resource and of Domain Linkage Credentials.
+	ContextV1 = "https://identity.foundation/.well-known/did-configuration/v1"
+
+	// ContextCredentialsV1 is the W3C Verifiable Credentials JSON-LD context.
+	ContextCredentialsV1 = "https://www.w3.org/2018/credentials/v1"
+
+	// WellKnownPath is the path of the DID Configuration resource of an origin.
+	WellKnownPath = "/.well-known/did-configuration.json"
+
+	// LinkedDomainsType is the type of the DID document service listing the origins linked to the DID.
+	LinkedDomainsType = "LinkedDomains"
+
+	// DomainLinkageCredentialType is the type of Domain Linkage Credentials.
+	DomainLinkageCredentialType = "DomainLinkageCredential"
+
+	verifiableCredentialType = "VerifiableCredential"
+)
+
+var (
+	// ErrInvalidConfiguration is returned when the DID Configuration resource is malformed.
+	ErrInvalidConfiguration = errors.New("invalid did configuration")
+
+	// ErrInvalidCredential is returned when a Domain Linkage Credential is malformed, expired or its proof is
+	// invalid.
+	ErrInvalidCredential = errors.New("invalid domain linkage credential")
+
+	// ErrNotLinked is returned when the DID document of a valid Domain Linkage Credential has no LinkedDomains
+	// service for the origin.
+	ErrNotLinked = errors.New("did is not linked to the origin")
+)
+
+// Format is the format of a Domain Linkage Credential.
+type Format string
+
+const (
+	// FormatJWT is a credential in the JWT format.
+	FormatJWT Format = "jwt"
+	// FormatLinkedData is a JSON-LD credential with a Linked Data proof.
+	FormatLinkedData Format = "ld"
+)
+
+// Configuration is a DID Configuration resource.
+type Configuration struct {
+	Context    string            `json:"@context"`
+	LinkedDIDs []json.RawMessage `json:"linked_dids"`
+}
+
+// NewConfiguration returns an empty DID Configuration resource.
+func NewConfiguration() *Configuration {
+	return &Configuration{Context: ContextV1, LinkedDIDs: []json.RawMessage{}}
+}
+
+// AddJWT adds a Domain Linkage Credential in the JWT format.
+func (c *Configuration) AddJWT(jwt string) {
+	b, _ := json.Marshal(jwt) //nolint:errcheck,errchkjson // a string is always marshalled
+
+	c.LinkedDIDs = append(c.LinkedDIDs, b)
+}
+
+// AddLinkedData adds a JSON-LD Domain Linkage Credential.
+func (c *Configuration) AddLinkedData(vc json.RawMessage) {
+	c.LinkedDIDs = append(c.LinkedDIDs, vc)
+}
+
+// credential is the part of a Domain Linkage Credential checked by the verifier.
+type credential struct {
+	Context           interface{}       `json:"@context"`
+	Type              interface{}       `json:"type"`
+	Issuer            interface{}       `json:"issuer,omitempty"`
+	IssuanceDate      string            `json:"issuanceDate,omitempty"`
+	ExpirationDate    string            `json:"expirationDate,omitempty"`
+	CredentialSubject credentialSubject `json:"credentialSubject"`
+}
+
+type credentialSubject struct {
+	ID     string `json:"id"`
+	Origin string `json:"origin"`
+}
+
+// validate checks the credential links didID to origin and is valid at now. The issuance and expiration dates are
+// required unless the credential is a JWT, whose nbf and exp claims take their place.
+func (c *credential) validate(didID, origin string, now time.Time, requireDates bool) error {
+	if !contains(c.Context, ContextCredentialsV1) || !contains(c.Context, ContextV1) {
+		return fmt.Errorf("missing %s or %s context", ContextCredentialsV1, ContextV1)
+	}
+
+	if !contains(c.Type, verifiableCredentialType) || !contains(c.Type, DomainLinkageCredentialType) {
+		return fmt.Errorf("type must include %s and %s", verifiableCredentialType, DomainLinkageCredentialType)
+	}
+
+	if issuer := issuerID(c.Issuer); (issuer != "" || requireDates) && issuer != didID {
+		return fmt.Errorf("issuer [%s] does not match did [%s]", issuer, didID)
+	}
+
+	if c.CredentialSubject.ID != didID {
+		return fmt.Errorf("credential subject [%s] does not match did [%s]", c.CredentialSubject.ID, didID)
+	}
+
+	subjectOrigin, err := NormalizeOrigin(c.CredentialSubject.Origin)
+	if err != nil || subjectOrigin != origin {
+		return fmt.Errorf("credential subject origin [%s] does not match origin [%s]",
+			c.CredentialSubject.Origin, origin)
+	}
+
+	if err = checkDate(c.IssuanceDate, requireDates, func(t time.Time) bool { return !t.After(now) }); err != nil {
+		return fmt.Errorf("issuance date: %w", err)
+	}
+
+	if err = checkDate(c.ExpirationDate, requireDates, func(t time.Time) bool { return now.Before(t) }); err != nil {
+		return fmt.Errorf("expiration date: %w", err)
+	}
+
+	return nil
+}
+
+func checkDate(value string, required bool, valid func(time.Time) bool) error {
+	if value == "" {
+		if required {
+			return errors.New("missing")
+		}
+
+		return nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return err
+	}
+
+	if !valid(t) {
+		return fmt.Errorf("%s is out of range", value)
+	}
+
+	return nil
+}
+
+// NormalizeOrigin returns the scheme://host[:port] form of origin, it fails if origin has a path, query or
+// fragment.
+func NormalizeOrigin(origin string) (string, error) {
+	u, err := url.Parse(origin)
+	if err != nil {
+		return "", fmt.Errorf("parse origin: %w", err)
+	}
+
+	if u.Scheme == "" || u.Host == "" || strings.Trim(u.Path, "/") != "" || u.RawQuery != "" || u.Fragment != "" ||
+		u.User != nil {
+		return "", fmt.Errorf("invalid origin [%s]", origin)
+	}
+
+	return strings.ToLower(u.Scheme) + "://" + strings.ToLower(u.Host), nil
+}
+
+func issuerID(issuer interface{}) string {
+	switch i := issuer.(type) {
+	case string:
+		return i
+	case map[string]interface{}:
+		id, _ := i["id"].(string) //nolint:errcheck
+
+		return id
+	}
+
+	return ""
+}
+
+// contains reports whether value, a string or an array of strings, contains s.
+func contains(value interface{}, s string) bool {
+	switch v := value.(type) {
+	case string:
+		return v == s
+	case []string:
+		return slices.Contains(v, s)
+	case []interface{}:
+		for _, e := range v {
+			if e == s {
+				return true
+			}
+		}
+	}
+
+	return false
+}
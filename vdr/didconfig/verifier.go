@@ -0,0 +1,479 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didconfig
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	jsonld "github.com/piprate/json-gold/ld"
+
+	diddoc "github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/doc/ld/processor"
+	"github.com/trustbloc/did-go/doc/signature/api"
+	"github.com/trustbloc/did-go/doc/signature/verifier"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	defaultMaxBodySize = 1 << 20
+	defaultTimeout     = 10 * time.Second
+
+	ed25519VerificationKey2018 = "Ed25519VerificationKey2018"
+	ed25519VerificationKey2020 = "Ed25519VerificationKey2020"
+)
+
+var errLogger = log.New(os.Stderr, " [did-go/vdr/didconfig] ", log.Ldate|log.Ltime|log.LUTC)
+
+// didResolver resolves the DIDs of the Domain Linkage Credentials, it is implemented by vdr.Registry.
+type didResolver interface {
+	ResolveContext(ctx context.Context, did string, opts ...vdrapi.DIDMethodOption) (*diddoc.DocResolution, error)
+}
+
+// Verifier verifies the DID Configuration resources of origins.
+type Verifier struct {
+	resolver    didResolver
+	httpClient  *http.Client
+	suites      []api.VerifierSuite
+	ldOpts      []processor.Opts
+	maxBodySize int64
+}
+
+// Option configures the verifier.
+type Option func(opts *Verifier)
+
+// WithHTTPClient sets the HTTP client the DID Configuration resources are fetched with.
+func WithHTTPClient(client *http.Client) Option {
+	return func(opts *Verifier) {
+		opts.httpClient = client
+	}
+}
+
+// WithLDProofSuites sets the signature suites verifying the proofs of JSON-LD Domain Linkage Credentials. Without
+// suites only credentials in the JWT format are verified.
+func WithLDProofSuites(suites ...api.VerifierSuite) Option {
+	return func(opts *Verifier) {
+		opts.suites = suites
+	}
+}
+
+// WithJSONLDDocumentLoader sets the loader of the JSON-LD contexts of JSON-LD Domain Linkage Credentials.
+func WithJSONLDDocumentLoader(loader jsonld.DocumentLoader) Option {
+	return func(opts *Verifier) {
+		opts.ldOpts = append(opts.ldOpts, processor.WithDocumentLoader(loader))
+	}
+}
+
+// WithMaxBodySize sets the maximum size in bytes of a DID Configuration resource, 1 MiB by default.
+func WithMaxBodySize(size int64) Option {
+	return func(opts *Verifier) {
+		opts.maxBodySize = size
+	}
+}
+
+// NewVerifier returns a verifier resolving the DIDs of the Domain Linkage Credentials with resolver.
+func NewVerifier(resolver didResolver, opts ...Option) *Verifier {
+	v := &Verifier{
+		resolver:    resolver,
+		httpClient:  &http.Client{Timeout: defaultTimeout},
+		maxBodySize: defaultMaxBodySize,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// DomainLinkage is the result of verifying the DID Configuration resource of an origin.
+type DomainLinkage struct {
+	// Origin is the normalized origin.
+	Origin string
+	// LinkedDIDs are the DIDs the origin is bidirectionally linked to: the resource has a valid Domain Linkage
+	// Credential of the DID and the DID document has a LinkedDomains service for the origin.
+	LinkedDIDs []string
+	// Credentials are the results of the Domain Linkage Credentials of the resource, in order.
+	Credentials []CredentialResult
+}
+
+// CredentialResult is the result of verifying a Domain Linkage Credential.
+type CredentialResult struct {
+	// DID is the DID the credential links the origin to, it is empty when the credential cannot be parsed.
+	DID string
+	// Format is the format of the credential.
+	Format Format
+	// Err is nil when the credential is valid and the DID is linked to the origin. It wraps ErrInvalidCredential
+	// or ErrNotLinked otherwise.
+	Err error
+}
+
+// VerifyDomain fetches the DID Configuration resource of origin and verifies it.
+func (v *Verifier) VerifyDomain(ctx context.Context, origin string) (*DomainLinkage, error) {
+	origin, err := NormalizeOrigin(origin)
+	if err != nil {
+		return nil, fmt.Errorf("verify domain: %w", err)
+	}
+
+	config, err := v.fetch(ctx, origin)
+	if err != nil {
+		return nil, fmt.Errorf("verify domain %s: %w", origin, err)
+	}
+
+	return v.VerifyConfiguration(ctx, origin, config)
+}
+
+// VerifyConfiguration verifies the Domain Linkage Credentials of the DID Configuration resource config published
+// by origin.
+func (v *Verifier) VerifyConfiguration(ctx context.Context, origin string, config []byte) (*DomainLinkage, error) {
+	origin, err := NormalizeOrigin(origin)
+	if err != nil {
+		return nil, fmt.Errorf("verify configuration: %w", err)
+	}
+
+	var c Configuration
+
+	if err = json.Unmarshal(config, &c); err != nil {
+		return nil, fmt.Errorf("verify configuration: %w: %w", ErrInvalidConfiguration, err)
+	}
+
+	if c.Context != ContextV1 {
+		return nil, fmt.Errorf("verify configuration: %w: unexpected context [%s]", ErrInvalidConfiguration, c.Context)
+	}
+
+	if len(c.LinkedDIDs) == 0 {
+		return nil, fmt.Errorf("verify configuration: %w: no linked dids", ErrInvalidConfiguration)
+	}
+
+	linkage := &DomainLinkage{Origin: origin}
+
+	for _, raw := range c.LinkedDIDs {
+		result := v.verifyCredential(ctx, origin, raw)
+
+		if result.Err == nil && !slices.Contains(linkage.LinkedDIDs, result.DID) {
+			linkage.LinkedDIDs = append(linkage.LinkedDIDs, result.DID)
+		}
+
+		linkage.Credentials = append(linkage.Credentials, result)
+	}
+
+	return linkage, nil
+}
+
+func (v *Verifier) verifyCredential(ctx context.Context, origin string, raw json.RawMessage) CredentialResult {
+	var (
+		result CredentialResult
+		doc    *diddoc.Doc
+		err    error
+	)
+
+	var jwt string
+
+	if json.Unmarshal(raw, &jwt) == nil {
+		result.Format = FormatJWT
+		result.DID, doc, err = v.verifyJWT(ctx, origin, jwt)
+	} else {
+		result.Format = FormatLinkedData
+		result.DID, doc, err = v.verifyLinkedData(ctx, origin, raw)
+	}
+
+	if err != nil {
+		result.Err = fmt.Errorf("%w: %w", ErrInvalidCredential, err)
+
+		return result
+	}
+
+	if !linksOrigin(doc, origin) {
+		result.Err = fmt.Errorf("%w: no %s service for %s in %s", ErrNotLinked, LinkedDomainsType, origin, result.DID)
+	}
+
+	return result
+}
+
+type jwtClaims struct {
+	Issuer    string     `json:"iss"`
+	Subject   string     `json:"sub"`
+	NotBefore *int64     `json:"nbf,omitempty"`
+	Expiry    *int64     `json:"exp,omitempty"`
+	VC        credential `json:"vc"`
+}
+
+func (v *Verifier) verifyJWT(ctx context.Context, origin, jwt string) (string, *diddoc.Doc, error) {
+	jws, err := jose.ParseSigned(jwt)
+	if err != nil {
+		return "", nil, fmt.Errorf("parse jwt: %w", err)
+	}
+
+	if len(jws.Signatures) != 1 {
+		return "", nil, errors.New("jwt must have one signature")
+	}
+
+	kid := jws.Signatures[0].Header.KeyID
+
+	didURL, err := diddoc.ParseDIDURL(kid)
+	if err != nil || didURL.Fragment == "" {
+		return "", nil, fmt.Errorf("jwt kid [%s] is not a did url with a fragment", kid)
+	}
+
+	didID := didURL.DID.String()
+
+	doc, err := v.resolve(ctx, didID)
+	if err != nil {
+		return didID, nil, err
+	}
+
+	vm, ok := verificationMethod(doc, didURL.Fragment)
+	if !ok {
+		return didID, nil, fmt.Errorf("verification method %s not found", kid)
+	}
+
+	key, err := publicKey(vm)
+	if err != nil {
+		return didID, nil, err
+	}
+
+	payload, err := jws.Verify(key)
+	if err != nil {
+		return didID, nil, fmt.Errorf("verify jwt signature: %w", err)
+	}
+
+	var claims jwtClaims
+
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return didID, nil, fmt.Errorf("unmarshal jwt claims: %w", err)
+	}
+
+	if claims.Issuer != didID || claims.Subject != didID {
+		return didID, nil, fmt.Errorf("jwt iss [%s] and sub [%s] must be the did of kid [%s]",
+			claims.Issuer, claims.Subject, didID)
+	}
+
+	now := time.Now()
+
+	if claims.NotBefore != nil && now.Before(time.Unix(*claims.NotBefore, 0)) {
+		return didID, nil, errors.New("jwt is not valid yet")
+	}
+
+	if claims.Expiry != nil && !now.Before(time.Unix(*claims.Expiry, 0)) {
+		return didID, nil, errors.New("jwt is expired")
+	}
+
+	if err = claims.VC.validate(didID, origin, now, false); err != nil {
+		return didID, nil, err
+	}
+
+	return didID, doc, nil
+}
+
+func (v *Verifier) verifyLinkedData(ctx context.Context, origin string,
+	raw json.RawMessage) (string, *diddoc.Doc, error) {
+	var vc credential
+
+	if err := json.Unmarshal(raw, &vc); err != nil {
+		return "", nil, fmt.Errorf("unmarshal credential: %w", err)
+	}
+
+	didID := issuerID(vc.Issuer)
+
+	if err := vc.validate(didID, origin, time.Now(), true); err != nil {
+		return didID, nil, err
+	}
+
+	if len(v.suites) == 0 {
+		return didID, nil, errors.New("no linked data proof suites configured")
+	}
+
+	doc, err := v.resolve(ctx, didID)
+	if err != nil {
+		return didID, nil, err
+	}
+
+	ldVerifier, err := verifier.New(&keyResolver{doc: doc}, v.suites...)
+	if err != nil {
+		return didID, nil, err
+	}
+
+	if err = ldVerifier.Verify(raw, v.ldOpts...); err != nil {
+		return didID, nil, fmt.Errorf("verify proof: %w", err)
+	}
+
+	return didID, doc, nil
+}
+
+func (v *Verifier) resolve(ctx context.Context, didID string) (*diddoc.Doc, error) {
+	docResolution, err := v.resolver.ResolveContext(ctx, didID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", didID, err)
+	}
+
+	if docResolution.DocumentMetadata != nil && docResolution.DocumentMetadata.Deactivated {
+		return nil, fmt.Errorf("resolve %s: did is deactivated", didID)
+	}
+
+	return docResolution.DIDDocument, nil
+}
+
+func (v *Verifier) fetch(ctx context.Context, origin string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+WellKnownPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch did configuration: %w", err)
+	}
+
+	defer func() {
+		if e := resp.Body.Close(); e != nil {
+			errLogger.Printf("Failed to close response body: %v", e)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch did configuration: status code [%d]", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, v.maxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read did configuration: %w", err)
+	}
+
+	if int64(len(body)) > v.maxBodySize {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", ErrInvalidConfiguration, v.maxBodySize)
+	}
+
+	return body, nil
+}
+
+// keyResolver resolves the verification methods of the proofs of JSON-LD credentials from the issuer DID document.
+type keyResolver struct {
+	doc *diddoc.Doc
+}
+
+func (r *keyResolver) Resolve(id string) (*api.PublicKey, error) {
+	didURL, err := diddoc.ParseDIDURL(id)
+	if err != nil || didURL.DID.String() != r.doc.ID || didURL.Fragment == "" {
+		return nil, fmt.Errorf("verification method [%s] is not a key of %s", id, r.doc.ID)
+	}
+
+	vm, ok := verificationMethod(r.doc, didURL.Fragment)
+	if !ok {
+		return nil, fmt.Errorf("verification method %s not found", id)
+	}
+
+	return &api.PublicKey{Type: vm.Type, Value: vm.Value, JWK: vm.JSONWebKey()}, nil
+}
+
+// verificationMethod returns the verification method of doc, listed or embedded in a verification relationship,
+// with the given fragment.
+func verificationMethod(doc *diddoc.Doc, fragment string) (*diddoc.VerificationMethod, bool) {
+	matches := func(id string) bool {
+		return id == "#"+fragment || id == doc.ID+"#"+fragment
+	}
+
+	for i := range doc.VerificationMethod {
+		if matches(doc.VerificationMethod[i].ID) {
+			return &doc.VerificationMethod[i], true
+		}
+	}
+
+	for _, verifications := range [][]diddoc.Verification{
+		doc.Authentication, doc.AssertionMethod, doc.CapabilityDelegation, doc.CapabilityInvocation,
+	} {
+		for i := range verifications {
+			if verifications[i].Embedded && matches(verifications[i].VerificationMethod.ID) {
+				return &verifications[i].VerificationMethod, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// publicKey returns the public key of a JsonWebKey2020, Ed25519VerificationKey2018 or Ed25519VerificationKey2020
+// verification method.
+func publicKey(vm *diddoc.VerificationMethod) (interface{}, error) {
+	if j := vm.JSONWebKey(); j != nil {
+		return j.Key, nil
+	}
+
+	switch vm.Type {
+	case ed25519VerificationKey2018, ed25519VerificationKey2020:
+		if len(vm.Value) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid ed25519 key size of %s", vm.ID)
+		}
+
+		return ed25519.PublicKey(vm.Value), nil
+	}
+
+	return nil, fmt.Errorf("unsupported verification method type %s of %s", vm.Type, vm.ID)
+}
+
+// linksOrigin reports whether doc has a LinkedDomains service whose endpoint, a URL, an array of URLs or an
+// object with an origins array, includes origin.
+func linksOrigin(doc *diddoc.Doc, origin string) bool {
+	for i := range doc.Service {
+		if !contains(doc.Service[i].Type, LinkedDomainsType) {
+			continue
+		}
+
+		b, err := doc.Service[i].ServiceEndpoint.MarshalJSON()
+		if err != nil {
+			continue
+		}
+
+		var endpoint interface{}
+
+		if err = json.Unmarshal(b, &endpoint); err != nil {
+			continue
+		}
+
+		if o, ok := endpoint.(map[string]interface{}); ok {
+			endpoint = o["origins"]
+		}
+
+		for _, e := range endpointURLs(endpoint) {
+			if normalized, err := NormalizeOrigin(e); err == nil && normalized == origin {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func endpointURLs(endpoint interface{}) []string {
+	switch e := endpoint.(type) {
+	case string:
+		return []string{e}
+	case []interface{}:
+		var urls []string
+
+		for _, u := range e {
+			if s, ok := u.(string); ok {
+				urls = append(urls, s)
+			}
+		}
+
+		return urls
+	}
+
+	return nil
+}
@@ -0,0 +1,429 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didconfig
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	diddoc "github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/doc/ld/processor"
+	ldtestutil "github.com/trustbloc/did-go/doc/ld/testutil"
+	"github.com/trustbloc/did-go/doc/signature/api"
+	"github.com/trustbloc/did-go/doc/signature/signer"
+	"github.com/trustbloc/did-go/vdr"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+	mockvdr "github.com/trustbloc/did-go/vdr/mock"
+)
+
+const (
+	testDID          = "did:example:alice"
+	testKeyID        = testDID + "#key-1"
+	testSignatureLDP = "Ed25519Signature2018"
+)
+
+// testSuite is an Ed25519 signature suite signing and verifying JWTs and Linked Data proofs.
+type testSuite struct {
+	privateKey ed25519.PrivateKey
+}
+
+func (s *testSuite) GetCanonicalDocument(doc map[string]interface{}, opts ...processor.Opts) ([]byte, error) {
+	return processor.Default().GetCanonicalDocument(doc, opts...)
+}
+
+func (s *testSuite) GetDigest(doc []byte) []byte {
+	digest := sha256.Sum256(doc)
+
+	return digest[:]
+}
+
+func (s *testSuite) Accept(signatureType string) bool {
+	return signatureType == testSignatureLDP
+}
+
+func (s *testSuite) CompactProof() bool {
+	return false
+}
+
+func (s *testSuite) Sign(doc []byte) ([]byte, error) {
+	return ed25519.Sign(s.privateKey, doc), nil
+}
+
+func (s *testSuite) Alg() string {
+	return "EdDSA"
+}
+
+func (s *testSuite) Verify(pubKey *api.PublicKey, doc, signature []byte) error {
+	if !ed25519.Verify(pubKey.Value, doc, signature) {
+		return errors.New("invalid signature")
+	}
+
+	return nil
+}
+
+type fixture struct {
+	suite    *testSuite
+	docs     map[string]*diddoc.Doc
+	registry *vdr.Registry
+	ldOpts   []processor.Opts
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	loader, err := ldtestutil.DocumentLoader()
+	require.NoError(t, err)
+
+	f := &fixture{
+		suite:  &testSuite{privateKey: privKey},
+		docs:   map[string]*diddoc.Doc{},
+		ldOpts: []processor.Opts{processor.WithDocumentLoader(loader)},
+	}
+
+	vm := diddoc.NewVerificationMethodFromBytes(testKeyID, ed25519VerificationKey2018, testDID, pubKey)
+
+	f.docs[testDID] = &diddoc.Doc{
+		Context:            []string{diddoc.ContextV1},
+		ID:                 testDID,
+		VerificationMethod: []diddoc.VerificationMethod{*vm},
+		AssertionMethod:    []diddoc.Verification{*diddoc.NewReferencedVerification(vm, diddoc.AssertionMethod)},
+	}
+
+	f.registry = vdr.New(vdr.WithVDR(&mockvdr.VDR{
+		AcceptValue: true,
+		ReadFunc: func(didID string, _ ...vdrapi.DIDMethodOption) (*diddoc.DocResolution, error) {
+			doc, ok := f.docs[didID]
+			if !ok {
+				return nil, vdrapi.ErrNotFound
+			}
+
+			return &diddoc.DocResolution{DIDDocument: doc}, nil
+		},
+	}))
+
+	return f
+}
+
+func (f *fixture) link(origins ...string) {
+	f.docs[testDID].Service = []diddoc.Service{NewLinkedDomainsService(testDID+"#domains", origins...)}
+}
+
+func (f *fixture) credential(origin string) *Credential {
+	return &Credential{
+		DID:            testDID,
+		Origin:         origin,
+		IssuanceDate:   time.Now().Add(-time.Hour),
+		ExpirationDate: time.Now().Add(time.Hour),
+	}
+}
+
+func (f *fixture) jwt(t *testing.T, c *Credential) string {
+	t.Helper()
+
+	jwt, err := c.JWT(testKeyID, f.suite)
+	require.NoError(t, err)
+
+	return jwt
+}
+
+func (f *fixture) linkedData(t *testing.T, c *Credential) json.RawMessage {
+	t.Helper()
+
+	vc, err := c.LinkedData(signer.New(f.suite), &api.Context{
+		SignatureType:      testSignatureLDP,
+		VerificationMethod: testKeyID,
+		Purpose:            "assertionMethod",
+	}, f.ldOpts...)
+	require.NoError(t, err)
+
+	return vc
+}
+
+func (f *fixture) verifier(opts ...Option) *Verifier {
+	loader, _ := ldtestutil.DocumentLoader() //nolint:errcheck
+
+	return NewVerifier(f.registry, append([]Option{
+		WithLDProofSuites(f.suite), WithJSONLDDocumentLoader(loader),
+	}, opts...)...)
+}
+
+func configuration(jwts []string, vcs ...json.RawMessage) []byte {
+	c := NewConfiguration()
+
+	for _, jwt := range jwts {
+		c.AddJWT(jwt)
+	}
+
+	for _, vc := range vcs {
+		c.AddLinkedData(vc)
+	}
+
+	b, _ := json.Marshal(c) //nolint:errcheck,errchkjson
+
+	return b
+}
+
+func TestVerifier_VerifyDomain(t *testing.T) {
+	t.Run("test domain linked to did", func(t *testing.T) {
+		f := newFixture(t)
+
+		var config []byte
+
+		s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != WellKnownPath {
+				http.NotFound(w, r)
+
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write(config) //nolint:errcheck
+		}))
+		defer s.Close()
+
+		f.link(s.URL)
+		config = configuration([]string{f.jwt(t, f.credential(s.URL))}, f.linkedData(t, f.credential(s.URL)))
+
+		linkage, err := f.verifier(WithHTTPClient(s.Client())).VerifyDomain(context.Background(), s.URL+"/")
+		require.NoError(t, err)
+		require.Equal(t, s.URL, linkage.Origin)
+		require.Equal(t, []string{testDID}, linkage.LinkedDIDs)
+		require.Len(t, linkage.Credentials, 2)
+		require.Equal(t, CredentialResult{DID: testDID, Format: FormatJWT}, linkage.Credentials[0])
+		require.Equal(t, CredentialResult{DID: testDID, Format: FormatLinkedData}, linkage.Credentials[1])
+	})
+
+	t.Run("test fetch failure", func(t *testing.T) {
+		f := newFixture(t)
+
+		s := httptest.NewTLSServer(http.NotFoundHandler())
+		defer s.Close()
+
+		_, err := f.verifier(WithHTTPClient(s.Client())).VerifyDomain(context.Background(), s.URL)
+		require.ErrorContains(t, err, "status code [404]")
+	})
+
+	t.Run("test configuration too large", func(t *testing.T) {
+		f := newFixture(t)
+
+		s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(strings.Repeat(" ", 64))) //nolint:errcheck
+		}))
+		defer s.Close()
+
+		_, err := f.verifier(WithHTTPClient(s.Client()), WithMaxBodySize(16)).VerifyDomain(context.Background(), s.URL)
+		require.ErrorIs(t, err, ErrInvalidConfiguration)
+	})
+
+	t.Run("test invalid origin", func(t *testing.T) {
+		_, err := newFixture(t).verifier().VerifyDomain(context.Background(), "https://example.com/path")
+		require.ErrorContains(t, err, "invalid origin")
+	})
+}
+
+func TestVerifier_VerifyConfiguration(t *testing.T) {
+	const origin = "https://example.com"
+
+	ctx := context.Background()
+
+	t.Run("test linked by origins object", func(t *testing.T) {
+		f := newFixture(t)
+		f.link("https://other.example.com", "https://EXAMPLE.com/")
+
+		linkage, err := f.verifier().VerifyConfiguration(ctx, origin,
+			configuration([]string{f.jwt(t, f.credential(origin))}))
+		require.NoError(t, err)
+		require.Equal(t, []string{testDID}, linkage.LinkedDIDs)
+	})
+
+	t.Run("test did without linked domains service", func(t *testing.T) {
+		f := newFixture(t)
+
+		linkage, err := f.verifier().VerifyConfiguration(ctx, origin,
+			configuration([]string{f.jwt(t, f.credential(origin))}, f.linkedData(t, f.credential(origin))))
+		require.NoError(t, err)
+		require.Empty(t, linkage.LinkedDIDs)
+		require.ErrorIs(t, linkage.Credentials[0].Err, ErrNotLinked)
+		require.ErrorIs(t, linkage.Credentials[1].Err, ErrNotLinked)
+	})
+
+	t.Run("test did linked to another origin", func(t *testing.T) {
+		f := newFixture(t)
+		f.link("https://other.example.com")
+
+		linkage, err := f.verifier().VerifyConfiguration(ctx, origin,
+			configuration([]string{f.jwt(t, f.credential(origin))}))
+		require.NoError(t, err)
+		require.ErrorIs(t, linkage.Credentials[0].Err, ErrNotLinked)
+	})
+
+	t.Run("test credential for another origin", func(t *testing.T) {
+		f := newFixture(t)
+		f.link(origin)
+
+		linkage, err := f.verifier().VerifyConfiguration(ctx, origin, configuration(
+			[]string{f.jwt(t, f.credential("https://other.example.com"))},
+			f.linkedData(t, f.credential("https://other.example.com"))))
+		require.NoError(t, err)
+		require.Empty(t, linkage.LinkedDIDs)
+
+		for _, result := range linkage.Credentials {
+			require.ErrorIs(t, result.Err, ErrInvalidCredential)
+			require.ErrorContains(t, result.Err, "does not match origin")
+		}
+	})
+
+	t.Run("test expired credential", func(t *testing.T) {
+		f := newFixture(t)
+		f.link(origin)
+
+		c := f.credential(origin)
+		c.IssuanceDate = time.Now().Add(-2 * time.Hour)
+		c.ExpirationDate = time.Now().Add(-time.Hour)
+
+		linkage, err := f.verifier().VerifyConfiguration(ctx, origin,
+			configuration([]string{f.jwt(t, c)}, f.linkedData(t, c)))
+		require.NoError(t, err)
+		require.ErrorContains(t, linkage.Credentials[0].Err, "jwt is expired")
+		require.ErrorContains(t, linkage.Credentials[1].Err, "expiration date")
+	})
+
+	t.Run("test tampered credential", func(t *testing.T) {
+		f := newFixture(t)
+		f.link(origin)
+
+		jwt := f.jwt(t, f.credential(origin))
+		parts := strings.Split(jwt, ".")
+		other := strings.Split(f.jwt(t, f.credential("https://other.example.com")), ".")
+
+		var vc map[string]interface{}
+
+		require.NoError(t, json.Unmarshal(f.linkedData(t, f.credential(origin)), &vc))
+		vc["expirationDate"] = time.Now().Add(2 * time.Hour).UTC().Format(time.RFC3339)
+
+		tampered, err := json.Marshal(vc)
+		require.NoError(t, err)
+
+		linkage, err := f.verifier().VerifyConfiguration(ctx, origin,
+			configuration([]string{parts[0] + "." + parts[1] + "." + other[2]}, tampered))
+		require.NoError(t, err)
+		require.ErrorContains(t, linkage.Credentials[0].Err, "verify jwt signature")
+		require.ErrorContains(t, linkage.Credentials[1].Err, "verify proof")
+	})
+
+	t.Run("test jwt signed by another did", func(t *testing.T) {
+		f := newFixture(t)
+		f.link(origin)
+
+		f.docs["did:example:bob"] = &diddoc.Doc{
+			ID: "did:example:bob",
+			VerificationMethod: []diddoc.VerificationMethod{*diddoc.NewVerificationMethodFromBytes("#key-1",
+				ed25519VerificationKey2018, "did:example:bob", f.docs[testDID].VerificationMethod[0].Value)},
+		}
+
+		jwt, err := f.credential(origin).JWT("did:example:bob#key-1", f.suite)
+		require.NoError(t, err)
+
+		linkage, err := f.verifier().VerifyConfiguration(ctx, origin, configuration([]string{jwt}))
+		require.NoError(t, err)
+		require.ErrorContains(t, linkage.Credentials[0].Err, "must be the did of kid")
+	})
+
+	t.Run("test unknown verification method", func(t *testing.T) {
+		f := newFixture(t)
+		f.link(origin)
+
+		jwt, err := f.credential(origin).JWT(testDID+"#key-2", f.suite)
+		require.NoError(t, err)
+
+		linkage, err := f.verifier().VerifyConfiguration(ctx, origin, configuration([]string{jwt}))
+		require.NoError(t, err)
+		require.ErrorContains(t, linkage.Credentials[0].Err, "verification method "+testDID+"#key-2 not found")
+	})
+
+	t.Run("test unresolvable did", func(t *testing.T) {
+		f := newFixture(t)
+		delete(f.docs, testDID)
+
+		linkage, err := f.verifier().VerifyConfiguration(ctx, origin, configuration(
+			[]string{f.jwt(t, f.credential(origin))}, f.linkedData(t, f.credential(origin))))
+		require.NoError(t, err)
+		require.ErrorIs(t, linkage.Credentials[0].Err, vdrapi.ErrNotFound)
+		require.ErrorIs(t, linkage.Credentials[1].Err, vdrapi.ErrNotFound)
+	})
+
+	t.Run("test linked data credential without proof suites", func(t *testing.T) {
+		f := newFixture(t)
+		f.link(origin)
+
+		linkage, err := NewVerifier(f.registry).VerifyConfiguration(ctx, origin,
+			configuration(nil, f.linkedData(t, f.credential(origin))))
+		require.NoError(t, err)
+		require.ErrorContains(t, linkage.Credentials[0].Err, "no linked data proof suites configured")
+	})
+
+	t.Run("test malformed credentials", func(t *testing.T) {
+		f := newFixture(t)
+
+		linkage, err := f.verifier().VerifyConfiguration(ctx, origin,
+			configuration([]string{"not a jwt"}, json.RawMessage(`{"type":"VerifiableCredential"}`)))
+		require.NoError(t, err)
+		require.ErrorContains(t, linkage.Credentials[0].Err, "parse jwt")
+		require.Equal(t, FormatLinkedData, linkage.Credentials[1].Format)
+		require.ErrorContains(t, linkage.Credentials[1].Err, "context")
+	})
+
+	t.Run("test invalid configuration", func(t *testing.T) {
+		v := newFixture(t).verifier()
+
+		_, err := v.VerifyConfiguration(ctx, origin, []byte("{"))
+		require.ErrorIs(t, err, ErrInvalidConfiguration)
+
+		_, err = v.VerifyConfiguration(ctx, origin, []byte(`{"@context":"https://example.com","linked_dids":[""]}`))
+		require.ErrorIs(t, err, ErrInvalidConfiguration)
+
+		_, err = v.VerifyConfiguration(ctx, origin, configuration(nil))
+		require.ErrorIs(t, err, ErrInvalidConfiguration)
+	})
+}
+
+func TestNormalizeOrigin(t *testing.T) {
+	for origin, expected := range map[string]string{
+		"https://example.com":       "https://example.com",
+		"https://Example.COM/":      "https://example.com",
+		"https://example.com:8443":  "https://example.com:8443",
+		"https://example.com/a":     "",
+		"https://example.com/?q=1":  "",
+		"https://user@example.com":  "",
+		"example.com":               "",
+		"https://example.com/#frag": "",
+	} {
+		normalized, err := NormalizeOrigin(origin)
+		if expected == "" {
+			require.Error(t, err, origin)
+
+			continue
+		}
+
+		require.NoError(t, err, origin)
+		require.Equal(t, expected, normalized)
+	}
+}
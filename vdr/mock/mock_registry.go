@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package mock
 
 import (
+	"context"
 	"crypto/ed25519"
 	"crypto/rand"
 	"time"
@@ -27,6 +28,7 @@ type VDRegistry struct {
 	ResolveErr     error
 	ResolveValue   *did.Doc
 	ResolveFunc    func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error)
+	ResolveCtxFunc func(ctx context.Context, didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error)
 }
 
 // Create mock implementation of create DID.
@@ -65,6 +67,16 @@ func (m *VDRegistry) Resolve(didID string, opts ...vdrapi.DIDMethodOption) (*did
 	return &did.DocResolution{DIDDocument: m.ResolveValue}, nil
 }
 
+// ResolveContext did document.
+func (m *VDRegistry) ResolveContext(ctx context.Context, didID string,
+	opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	if m.ResolveCtxFunc != nil {
+		return m.ResolveCtxFunc(ctx, didID, opts...)
+	}
+
+	return m.Resolve(didID, opts...)
+}
+
 // Update did.
 func (m *VDRegistry) Update(didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) error {
 	if m.UpdateFunc != nil {
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package mock
 
 import (
+	"context"
+
 	"github.com/trustbloc/did-go/doc/did"
 	vdrapi "github.com/trustbloc/did-go/vdr/api"
 )
@@ -18,6 +20,7 @@ type VDR struct {
 	StoreErr       error
 	AcceptFunc     func(method string, opts ...vdrapi.DIDMethodOption) bool
 	ReadFunc       func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error)
+	ReadCtxFunc    func(ctx context.Context, didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error)
 	CreateFunc     func(did *did.Doc, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error)
 	UpdateFunc     func(didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) error
 	DeactivateFunc func(did string, opts ...vdrapi.DIDMethodOption) error
@@ -33,6 +36,16 @@ func (m *VDR) Read(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolu
 	return nil, nil
 }
 
+// ReadContext did.
+func (m *VDR) ReadContext(ctx context.Context, didID string,
+	opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	if m.ReadCtxFunc != nil {
+		return m.ReadCtxFunc(ctx, didID, opts...)
+	}
+
+	return m.Read(didID, opts...)
+}
+
 // Create did.
 func (m *VDR) Create(didDoc *did.Doc, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
 	if m.CreateFunc != nil {
@@ -7,9 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package vdr
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	diddoc "github.com/trustbloc/did-go/doc/did"
 	vdrapi "github.com/trustbloc/did-go/vdr/api"
@@ -23,13 +25,14 @@ type Option func(opts *Registry)
 // Registry vdr registry.
 type Registry struct {
 	vdr                []vdrapi.VDR
+	processors         map[string][]vdrapi.DocProcessor
 	defServiceEndpoint string
 	defServiceType     string
 }
 
 // New return new instance of vdr.
 func New(opts ...Option) *Registry {
-	baseVDR := &Registry{}
+	baseVDR := &Registry{processors: make(map[string][]vdrapi.DocProcessor)}
 
 	// Apply options
 	for _, opt := range opts {
@@ -41,6 +44,14 @@ func New(opts ...Option) *Registry {
 
 // Resolve did document.
 func (r *Registry) Resolve(did string, opts ...vdrapi.DIDMethodOption) (*diddoc.DocResolution, error) {
+	return r.ResolveContext(context.Background(), did, opts...)
+}
+
+// ResolveContext resolves did document, the given context is passed to VDRs implementing vdrapi.ContextVDR.
+func (r *Registry) ResolveContext(ctx context.Context, did string,
+	opts ...vdrapi.DIDMethodOption) (*diddoc.DocResolution, error) {
+	start := time.Now()
+
 	didMethod, err := GetDidMethod(did)
 	if err != nil {
 		return nil, err
@@ -57,7 +68,7 @@ func (r *Registry) Resolve(did string, opts ...vdrapi.DIDMethodOption) (*diddoc.
 	}
 
 	// Obtain the DID Document
-	didDocResolution, err := method.Read(did, opts...)
+	didDocResolution, err := readContext(ctx, method, did, opts...)
 	if err != nil {
 		if errors.Is(err, vdrapi.ErrNotFound) {
 			return nil, err
@@ -66,6 +77,26 @@ func (r *Registry) Resolve(did string, opts ...vdrapi.DIDMethodOption) (*diddoc.
 		return nil, fmt.Errorf("did method read failed failed: %w", err)
 	}
 
+	if didDocResolution != nil && didDocResolution.DIDDocument != nil {
+		for _, process := range r.processors[didMethod] {
+			if didDocResolution.DIDDocument, err = process(didDocResolution.DIDDocument); err != nil {
+				return nil, fmt.Errorf("process did document: %w", err)
+			}
+		}
+	}
+
+	if didDocResolution != nil {
+		if didDocResolution.ResolutionMetadata == nil {
+			didDocResolution.ResolutionMetadata = &diddoc.ResolutionMetadata{}
+		}
+
+		if didDocResolution.ResolutionMetadata.ContentType == "" {
+			didDocResolution.ResolutionMetadata.ContentType = diddoc.ContentTypeDIDLDJSON
+		}
+
+		didDocResolution.ResolutionMetadata.Duration = time.Since(start).Milliseconds()
+	}
+
 	return didDocResolution, nil
 }
 
@@ -142,6 +173,21 @@ func (r *Registry) Close() error {
 	return nil
 }
 
+// readContext reads the did through ReadContext if supported, otherwise falls back to Read
+// once the context has been checked for cancellation.
+func readContext(ctx context.Context, method vdrapi.VDR, did string,
+	opts ...vdrapi.DIDMethodOption) (*diddoc.DocResolution, error) {
+	if ctxMethod, ok := method.(vdrapi.ContextVDR); ok {
+		return ctxMethod.ReadContext(ctx, did, opts...)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return method.Read(did, opts...)
+}
+
 func (r *Registry) resolveVDR(method string, opts ...vdrapi.DIDMethodOption) (vdrapi.VDR, error) {
 	for _, v := range r.vdr {
 		if v.Accept(method, opts...) {
@@ -149,7 +195,7 @@ func (r *Registry) resolveVDR(method string, opts ...vdrapi.DIDMethodOption) (vd
 		}
 	}
 
-	return nil, fmt.Errorf("did method %s not supported for vdr", method)
+	return nil, fmt.Errorf("did method %s not supported for vdr: %w", method, vdrapi.ErrMethodNotSupported)
 }
 
 // WithVDR adds did method implementation for store.
@@ -159,6 +205,14 @@ func WithVDR(method vdrapi.VDR) Option {
 	}
 }
 
+// WithDocProcessor adds a processor rewriting the resolved DID documents of the given DID method, processors
+// run in the order they were added.
+func WithDocProcessor(method string, processor vdrapi.DocProcessor) Option {
+	return func(opts *Registry) {
+		opts.processors[method] = append(opts.processors[method], processor)
+	}
+}
+
 // WithDefaultServiceType is default service type for this creator.
 func WithDefaultServiceType(serviceType string) Option {
 	return func(opts *Registry) {
@@ -182,7 +236,7 @@ func GetDidMethod(didID string) (string, error) {
 
 	didParts := strings.Split(didID, ":")
 	if len(didParts) < numPartsDID {
-		return "", fmt.Errorf("wrong format did input: %s", didID)
+		return "", fmt.Errorf("wrong format did input: %s: %w", didID, vdrapi.ErrInvalidDID)
 	}
 
 	return didParts[1], nil
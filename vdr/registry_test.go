@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package vdr
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -51,6 +53,8 @@ func TestRegistry_Resolve(t *testing.T) {
 		d, err := registry.Resolve("id")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "wrong format did input")
+		require.ErrorIs(t, err, vdrapi.ErrInvalidDID)
+		require.Equal(t, did.ResolutionErrorInvalidDID, vdrapi.ErrorCode(err))
 		require.Nil(t, d)
 	})
 
@@ -59,9 +63,38 @@ func TestRegistry_Resolve(t *testing.T) {
 		d, err := registry.Resolve("1:id:123")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "did method id not supported for vdr")
+		require.ErrorIs(t, err, vdrapi.ErrMethodNotSupported)
+		require.Equal(t, did.ResolutionErrorMethodNotSupported, vdrapi.ErrorCode(err))
 		require.Nil(t, d)
 	})
 
+	t.Run("test resolution metadata populated", func(t *testing.T) {
+		registry := New(WithVDR(&mockvdr.VDR{
+			AcceptValue: true, ReadFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				return &did.DocResolution{DIDDocument: &did.Doc{ID: didID}}, nil
+			},
+		}))
+		d, err := registry.Resolve("1:id:123")
+		require.NoError(t, err)
+		require.NotNil(t, d.ResolutionMetadata)
+		require.Equal(t, did.ContentTypeDIDLDJSON, d.ResolutionMetadata.ContentType)
+		require.Empty(t, d.ResolutionMetadata.Error)
+	})
+
+	t.Run("test resolution metadata content type kept", func(t *testing.T) {
+		registry := New(WithVDR(&mockvdr.VDR{
+			AcceptValue: true, ReadFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				return &did.DocResolution{
+					DIDDocument:        &did.Doc{ID: didID},
+					ResolutionMetadata: &did.ResolutionMetadata{ContentType: did.ContentTypeDIDJSON},
+				}, nil
+			},
+		}))
+		d, err := registry.Resolve("1:id:123")
+		require.NoError(t, err)
+		require.Equal(t, did.ContentTypeDIDJSON, d.ResolutionMetadata.ContentType)
+	})
+
 	t.Run("test DID not found", func(t *testing.T) {
 		registry := New(WithVDR(&mockvdr.VDR{
 			AcceptValue: true, ReadFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
@@ -83,6 +116,7 @@ func TestRegistry_Resolve(t *testing.T) {
 		d, err := registry.Resolve("1:id:123")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "read error")
+		require.Equal(t, did.ResolutionErrorInternal, vdrapi.ErrorCode(err))
 		require.Nil(t, d)
 	})
 
@@ -128,6 +162,102 @@ func TestRegistry_Resolve(t *testing.T) {
 	})
 }
 
+func TestRegistry_ResolveContext(t *testing.T) {
+	type ctxKey struct{}
+
+	t.Run("test context passed to context vdr", func(t *testing.T) {
+		registry := New(WithVDR(&mockvdr.VDR{
+			AcceptValue: true,
+			ReadCtxFunc: func(ctx context.Context, didID string,
+				opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				require.Equal(t, "v1", ctx.Value(ctxKey{}))
+
+				return &did.DocResolution{DIDDocument: &did.Doc{ID: didID}}, nil
+			},
+		}))
+
+		ctx := context.WithValue(context.Background(), ctxKey{}, "v1")
+
+		d, err := registry.ResolveContext(ctx, "1:id:123")
+		require.NoError(t, err)
+		require.Equal(t, "1:id:123", d.DIDDocument.ID)
+	})
+
+	t.Run("test cancelled context with non context vdr", func(t *testing.T) {
+		registry := New(WithVDR(&nonContextVDR{VDR: &mockvdr.VDR{
+			AcceptValue: true, ReadFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				require.Fail(t, "read should not be called")
+
+				return nil, nil
+			},
+		}}))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		d, err := registry.ResolveContext(ctx, "1:id:123")
+		require.ErrorIs(t, err, context.Canceled)
+		require.Nil(t, d)
+	})
+
+	t.Run("test context error from context vdr", func(t *testing.T) {
+		registry := New(WithVDR(&mockvdr.VDR{
+			AcceptValue: true,
+			ReadCtxFunc: func(ctx context.Context, didID string,
+				opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				return nil, ctx.Err()
+			},
+		}))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		d, err := registry.ResolveContext(ctx, "1:id:123")
+		require.ErrorIs(t, err, context.Canceled)
+		require.Contains(t, err.Error(), "did method read failed")
+		require.Nil(t, d)
+	})
+}
+
+func TestRegistry_DocProcessor(t *testing.T) {
+	registry := New(
+		WithVDR(&mockvdr.VDR{
+			AcceptValue: true,
+			ReadFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				return &did.DocResolution{DIDDocument: &did.Doc{ID: didID}}, nil
+			},
+		}),
+		WithDocProcessor("sov", func(doc *did.Doc) (*did.Doc, error) {
+			doc.AlsoKnownAs = append(doc.AlsoKnownAs, "first")
+
+			return doc, nil
+		}),
+		WithDocProcessor("sov", func(doc *did.Doc) (*did.Doc, error) {
+			return &did.Doc{ID: doc.ID, AlsoKnownAs: append(doc.AlsoKnownAs, "second")}, nil
+		}),
+		WithDocProcessor("fail", func(doc *did.Doc) (*did.Doc, error) {
+			return nil, errors.New("process error")
+		}),
+	)
+
+	d, err := registry.Resolve("did:sov:123")
+	require.NoError(t, err)
+	require.Equal(t, []string{"first", "second"}, d.DIDDocument.AlsoKnownAs)
+
+	d, err = registry.Resolve("did:other:123")
+	require.NoError(t, err)
+	require.Empty(t, d.DIDDocument.AlsoKnownAs)
+
+	_, err = registry.Resolve("did:fail:123")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "process did document: process error")
+}
+
+// nonContextVDR exposes only the vdrapi.VDR methods of the wrapped VDR.
+type nonContextVDR struct {
+	vdrapi.VDR
+}
+
 func TestRegistry_Update(t *testing.T) {
 	t.Run("test invalid did input", func(t *testing.T) {
 		registry := New()
@@ -346,3 +476,21 @@ func TestRegistry_Create(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestErrorCode(t *testing.T) {
+	for code, sentinel := range map[string]error{
+		did.ResolutionErrorNotFound:                   vdrapi.ErrNotFound,
+		did.ResolutionErrorInvalidDID:                 vdrapi.ErrInvalidDID,
+		did.ResolutionErrorInvalidDIDURL:              vdrapi.ErrInvalidDIDURL,
+		did.ResolutionErrorRepresentationNotSupported: vdrapi.ErrRepresentationNotSupported,
+		did.ResolutionErrorMethodNotSupported:         vdrapi.ErrMethodNotSupported,
+		did.ResolutionErrorInvalidPublicKeyType:       vdrapi.ErrInvalidPublicKeyType,
+		did.ResolutionErrorInternal:                   vdrapi.ErrInternal,
+	} {
+		require.Equal(t, code, vdrapi.ErrorCode(fmt.Errorf("wrapped: %w", sentinel)))
+		require.Equal(t, sentinel, vdrapi.ErrorFromCode(code))
+	}
+
+	require.Empty(t, vdrapi.ErrorCode(nil))
+	require.Equal(t, vdrapi.ErrInternal, vdrapi.ErrorFromCode("unknown"))
+}
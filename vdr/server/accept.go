@@ -0,0 +1,71 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package server
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/trustbloc/did-go/doc/did"
+)
+
+const resolutionProfile = "https://w3id.org/did-resolution"
+
+// negotiate returns the supported media type with the highest quality in the Accept header, the DID resolution
+// result is returned when the header is empty or accepts any media type.
+func negotiate(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return did.ContentTypeDIDResolution, true
+	}
+
+	var (
+		best    string
+		quality float64
+	)
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(mediaRange)
+		if err != nil {
+			continue
+		}
+
+		contentType, ok := supported(mediaType, params)
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+
+		if value, exists := params["q"]; exists {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+
+		if q > quality {
+			best, quality = contentType, q
+		}
+	}
+
+	return best, best != ""
+}
+
+func supported(mediaType string, params map[string]string) (string, bool) {
+	switch mediaType {
+	case did.ContentTypeDIDLDJSON, did.ContentTypeDIDJSON:
+		return mediaType, true
+	case "application/ld+json":
+		if params["profile"] == resolutionProfile {
+			return did.ContentTypeDIDResolution, true
+		}
+	case "*/*", "application/*":
+		return did.ContentTypeDIDResolution, true
+	}
+
+	return "", false
+}
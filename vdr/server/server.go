@@ -0,0 +1,276 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package server serves DID resolution over the DID Resolution HTTP(S) binding
+// (https://w3c-ccg.github.io/did-resolution/#bindings-https), compatible with the Universal Resolver.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/trustbloc/did-go/doc/did"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+)
+
+const (
+	// DefaultBasePath is the path DIDs are resolved under, GET {DefaultBasePath}{did}.
+	DefaultBasePath = "/1.0/identifiers/"
+
+	resolutionContext = "https://w3id.org/did-resolution/v1"
+	versionIDQuery    = "versionId"
+	versionTimeQuery  = "versionTime"
+)
+
+var errLogger = log.New(os.Stderr, " [did-go/vdr/server] ", log.Ldate|log.Ltime|log.LUTC)
+
+// Handler resolves DIDs with a vdr registry and writes the negotiated representation of the resolution result.
+type Handler struct {
+	registry vdrapi.ContextRegistry
+	basePath string
+	// escapedBasePath is matched against the escaped request path, so that the DID keeps its percent-encoding.
+	escapedBasePath string
+	opts            []vdrapi.DIDMethodOption
+}
+
+// Option configures the handler.
+type Option func(opts *Handler)
+
+// WithBasePath sets the path DIDs are resolved under, DefaultBasePath is used by default.
+func WithBasePath(basePath string) Option {
+	return func(opts *Handler) {
+		opts.basePath = basePath
+	}
+}
+
+// WithResolveOptions sets options passed to the registry on every resolution.
+func WithResolveOptions(resolveOpts ...vdrapi.DIDMethodOption) Option {
+	return func(opts *Handler) {
+		opts.opts = append(opts.opts, resolveOpts...)
+	}
+}
+
+// New creates a handler resolving DIDs with the given registry, e.g. a *vdr.Registry.
+func New(registry vdrapi.ContextRegistry, opts ...Option) *Handler {
+	h := &Handler{registry: registry, basePath: DefaultBasePath}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	if !strings.HasSuffix(h.basePath, "/") {
+		h.basePath += "/"
+	}
+
+	h.escapedBasePath = (&url.URL{Path: h.basePath}).EscapedPath()
+
+	return h
+}
+
+// rawResolution is the DID resolution result representation.
+type rawResolution struct {
+	Context            interface{}             `json:"@context"`
+	DIDDocument        json.RawMessage         `json:"didDocument"`
+	DocumentMetadata   *did.DocumentMetadata   `json:"didDocumentMetadata"`
+	ResolutionMetadata *did.ResolutionMetadata `json:"didResolutionMetadata"`
+}
+
+// ServeHTTP resolves the DID of GET {basePath}{did}[?versionId=...|versionTime=...].
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	didID, ok := h.didFromPath(r.URL)
+	if !ok {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	contentType, ok := negotiate(r.Header.Get("Accept"))
+	if !ok {
+		writeError(w, fmt.Errorf("accept %s: %w", r.Header.Get("Accept"), vdrapi.ErrRepresentationNotSupported))
+
+		return
+	}
+
+	if _, err := did.Parse(didID); err != nil {
+		writeError(w, fmt.Errorf("%w: %w", err, vdrapi.ErrInvalidDID))
+
+		return
+	}
+
+	versionOpts, err := versionOpts(r)
+	if err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	docResolution, err := h.registry.ResolveContext(r.Context(), didID,
+		append(slices.Clip(h.opts), versionOpts...)...)
+	if err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	if docResolution == nil || docResolution.DIDDocument == nil {
+		writeError(w, fmt.Errorf("resolve %s: %w", didID, vdrapi.ErrNotFound))
+
+		return
+	}
+
+	body, err := representation(docResolution, contentType)
+	if err != nil {
+		writeError(w, fmt.Errorf("%w: %w", err, vdrapi.ErrInternal))
+
+		return
+	}
+
+	status := http.StatusOK
+	if docResolution.DocumentMetadata != nil && docResolution.DocumentMetadata.Deactivated {
+		status = http.StatusGone
+	}
+
+	write(w, status, contentType, body)
+}
+
+// didFromPath returns the DID of the request path. Percent-encoded characters are part of the DID syntax and are
+// kept as sent, only a DID that is percent-encoded as a whole (did%3A...) is decoded.
+func (h *Handler) didFromPath(u *url.URL) (string, bool) {
+	didID, ok := strings.CutPrefix(u.EscapedPath(), h.escapedBasePath)
+	if !ok {
+		return "", false
+	}
+
+	if len(didID) > len("did%3A") && strings.EqualFold(didID[:len("did%3A")], "did%3A") {
+		if decoded, err := url.PathUnescape(didID); err == nil {
+			return decoded, true
+		}
+	}
+
+	return didID, true
+}
+
+func versionOpts(r *http.Request) ([]vdrapi.DIDMethodOption, error) {
+	var opts []vdrapi.DIDMethodOption
+
+	versionID := r.URL.Query().Get(versionIDQuery)
+	versionTime := r.URL.Query().Get(versionTimeQuery)
+
+	if versionID != "" && versionTime != "" {
+		return nil, fmt.Errorf("versionId and versionTime can not be set at same time: %w", vdrapi.ErrInvalidDID)
+	}
+
+	if versionID != "" {
+		opts = append(opts, vdrapi.WithOption(vdrapi.VersionIDOpt, versionID))
+	}
+
+	if versionTime != "" {
+		if _, err := time.Parse(time.RFC3339, versionTime); err != nil {
+			return nil, fmt.Errorf("versionTime must be an RFC3339 timestamp: %w", vdrapi.ErrInvalidDID)
+		}
+
+		opts = append(opts, vdrapi.WithOption(vdrapi.VersionTimeOpt, versionTime))
+	}
+
+	return opts, nil
+}
+
+// representation returns the DID document for DID document media types, the DID resolution result otherwise.
+func representation(docResolution *did.DocResolution, contentType string) ([]byte, error) {
+	docBytes, err := docResolution.DIDDocument.JSONBytes()
+	if err != nil {
+		return nil, fmt.Errorf("marshal did document: %w", err)
+	}
+
+	switch contentType {
+	case did.ContentTypeDIDLDJSON:
+		return docBytes, nil
+	case did.ContentTypeDIDJSON:
+		var rawDoc map[string]interface{}
+
+		if err = json.Unmarshal(docBytes, &rawDoc); err != nil {
+			return nil, fmt.Errorf("unmarshal did document: %w", err)
+		}
+
+		delete(rawDoc, "@context")
+
+		return json.Marshal(rawDoc)
+	}
+
+	resolutionMetadata := &did.ResolutionMetadata{}
+	if docResolution.ResolutionMetadata != nil {
+		*resolutionMetadata = *docResolution.ResolutionMetadata
+	}
+
+	resolutionMetadata.ContentType = did.ContentTypeDIDLDJSON
+
+	documentMetadata := docResolution.DocumentMetadata
+	if documentMetadata == nil {
+		documentMetadata = &did.DocumentMetadata{}
+	}
+
+	return json.Marshal(&rawResolution{
+		Context:            resolutionContext,
+		DIDDocument:        docBytes,
+		DocumentMetadata:   documentMetadata,
+		ResolutionMetadata: resolutionMetadata,
+	})
+}
+
+// writeError writes a DID resolution result with the error code of err and the matching status code.
+func writeError(w http.ResponseWriter, err error) {
+	code := vdrapi.ErrorCode(err)
+
+	body, e := json.Marshal(&rawResolution{
+		Context:            resolutionContext,
+		DIDDocument:        json.RawMessage("null"),
+		DocumentMetadata:   &did.DocumentMetadata{},
+		ResolutionMetadata: &did.ResolutionMetadata{Error: code, ErrorMessage: err.Error()},
+	})
+	if e != nil {
+		errLogger.Printf("Failed to marshal resolution error: %v", e)
+	}
+
+	write(w, statusCode(code), did.ContentTypeDIDResolution, body)
+}
+
+func statusCode(code string) int {
+	switch code {
+	case did.ResolutionErrorInvalidDID, did.ResolutionErrorInvalidDIDURL, did.ResolutionErrorInvalidPublicKeyType:
+		return http.StatusBadRequest
+	case did.ResolutionErrorNotFound:
+		return http.StatusNotFound
+	case did.ResolutionErrorRepresentationNotSupported:
+		return http.StatusNotAcceptable
+	case did.ResolutionErrorMethodNotSupported:
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func write(w http.ResponseWriter, status int, contentType string, body []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+
+	if _, err := w.Write(body); err != nil {
+		errLogger.Printf("Failed to write response: %v", err)
+	}
+}
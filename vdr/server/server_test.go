@@ -0,0 +1,383 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/did-go/doc/did"
+	"github.com/trustbloc/did-go/method/httpbinding"
+	"github.com/trustbloc/did-go/vdr"
+	vdrapi "github.com/trustbloc/did-go/vdr/api"
+	"github.com/trustbloc/did-go/vdr/mock"
+)
+
+const (
+	testDID = "did:example:123"
+
+	docTemplate = `{
+  "@context": ["https://www.w3.org/ns/did/v1"],
+  "id": "%s",
+  "verificationMethod": [{
+    "id": "%s#key-1",
+    "type": "Ed25519VerificationKey2018",
+    "controller": "%s",
+    "publicKeyBase58": "H3C2AVvLMv6gmMNam3uVAjZpfkcJCwDwnZn6z3wXmqPV"
+  }]
+}`
+)
+
+func newDocResolution(t *testing.T, didID string) *did.DocResolution {
+	t.Helper()
+
+	doc, err := did.ParseDocument([]byte(fmt.Sprintf(docTemplate, didID, didID, didID)))
+	require.NoError(t, err)
+
+	return &did.DocResolution{DIDDocument: doc, DocumentMetadata: &did.DocumentMetadata{VersionID: "1"}}
+}
+
+func newRegistry(t *testing.T, resolveErr error) *mock.VDRegistry {
+	t.Helper()
+
+	return &mock.VDRegistry{
+		ResolveCtxFunc: func(_ context.Context, didID string, _ ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+			if resolveErr != nil {
+				return nil, resolveErr
+			}
+
+			return newDocResolution(t, didID), nil
+		},
+	}
+}
+
+func get(t *testing.T, h http.Handler, target, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestServeHTTP(t *testing.T) {
+	t.Run("test resolution result", func(t *testing.T) {
+		for _, accept := range []string{"", "*/*", `application/ld+json;profile="https://w3id.org/did-resolution"`} {
+			rec := get(t, New(newRegistry(t, nil)), DefaultBasePath+testDID, accept)
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.Equal(t, did.ContentTypeDIDResolution, rec.Header().Get("Content-Type"))
+
+			docResolution, err := did.ParseDocumentResolution(rec.Body.Bytes())
+			require.NoError(t, err)
+			require.Equal(t, testDID, docResolution.DIDDocument.ID)
+			require.Equal(t, "1", docResolution.DocumentMetadata.VersionID)
+			require.Equal(t, did.ContentTypeDIDLDJSON, docResolution.ResolutionMetadata.ContentType)
+		}
+	})
+
+	t.Run("test did document representations", func(t *testing.T) {
+		rec := get(t, New(newRegistry(t, nil)), DefaultBasePath+testDID, did.ContentTypeDIDLDJSON)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, did.ContentTypeDIDLDJSON, rec.Header().Get("Content-Type"))
+
+		doc, err := did.ParseDocument(rec.Body.Bytes())
+		require.NoError(t, err)
+		require.Equal(t, testDID, doc.ID)
+
+		rec = get(t, New(newRegistry(t, nil)), DefaultBasePath+testDID,
+			"application/did+ld+json;q=0.5, application/did+json")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, did.ContentTypeDIDJSON, rec.Header().Get("Content-Type"))
+
+		var rawDoc map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rawDoc))
+		require.Equal(t, testDID, rawDoc["id"])
+		require.NotContains(t, rawDoc, "@context")
+	})
+
+	t.Run("test representation not supported", func(t *testing.T) {
+		rec := get(t, New(newRegistry(t, nil)), DefaultBasePath+testDID, "text/html, application/ld+json")
+		require.Equal(t, http.StatusNotAcceptable, rec.Code)
+		require.Equal(t, did.ResolutionErrorRepresentationNotSupported, resolutionError(t, rec))
+	})
+
+	t.Run("test deactivated", func(t *testing.T) {
+		registry := &mock.VDRegistry{
+			ResolveCtxFunc: func(context.Context, string, ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				docResolution := newDocResolution(t, testDID)
+				docResolution.DocumentMetadata.Deactivated = true
+
+				return docResolution, nil
+			},
+		}
+
+		rec := get(t, New(registry), DefaultBasePath+testDID, "")
+		require.Equal(t, http.StatusGone, rec.Code)
+
+		docResolution, err := did.ParseDocumentResolution(rec.Body.Bytes())
+		require.NoError(t, err)
+		require.True(t, docResolution.DocumentMetadata.Deactivated)
+	})
+
+	t.Run("test resolution errors", func(t *testing.T) {
+		tests := []struct {
+			err    error
+			status int
+			code   string
+		}{
+			{vdrapi.ErrNotFound, http.StatusNotFound, did.ResolutionErrorNotFound},
+			{fmt.Errorf("wrapped: %w", vdrapi.ErrInvalidDID), http.StatusBadRequest, did.ResolutionErrorInvalidDID},
+			{vdrapi.ErrMethodNotSupported, http.StatusNotImplemented, did.ResolutionErrorMethodNotSupported},
+			{vdrapi.ErrInvalidPublicKeyType, http.StatusBadRequest, did.ResolutionErrorInvalidPublicKeyType},
+			{errors.New("failure"), http.StatusInternalServerError, did.ResolutionErrorInternal},
+		}
+
+		for _, tc := range tests {
+			rec := get(t, New(newRegistry(t, tc.err)), DefaultBasePath+testDID, did.ContentTypeDIDLDJSON)
+			require.Equal(t, tc.status, rec.Code)
+			require.Equal(t, did.ContentTypeDIDResolution, rec.Header().Get("Content-Type"))
+			require.Equal(t, tc.code, resolutionError(t, rec))
+		}
+
+		registry := &mock.VDRegistry{
+			ResolveCtxFunc: func(context.Context, string, ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				return nil, nil
+			},
+		}
+
+		rec := get(t, New(registry), DefaultBasePath+testDID, "")
+		require.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("test invalid did", func(t *testing.T) {
+		rec := get(t, New(newRegistry(t, nil)), DefaultBasePath+"not-a-did", "")
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Equal(t, did.ResolutionErrorInvalidDID, resolutionError(t, rec))
+	})
+
+	t.Run("test version query", func(t *testing.T) {
+		var values map[string]interface{}
+
+		registry := &mock.VDRegistry{
+			ResolveCtxFunc: func(_ context.Context, didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution,
+				error) {
+				didOpts := &vdrapi.DIDMethodOpts{Values: make(map[string]interface{})}
+				for _, opt := range opts {
+					opt(didOpts)
+				}
+
+				values = didOpts.Values
+
+				return newDocResolution(t, didID), nil
+			},
+		}
+
+		h := New(registry, WithResolveOptions(vdrapi.WithOption("custom", "value")))
+
+		rec := get(t, h, DefaultBasePath+testDID+"?versionId=2", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "2", values[vdrapi.VersionIDOpt])
+		require.Equal(t, "value", values["custom"])
+
+		rec = get(t, h, DefaultBasePath+testDID+"?versionTime=2021-05-10T17:00:00Z", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "2021-05-10T17:00:00Z", values[vdrapi.VersionTimeOpt])
+
+		rec = get(t, h, DefaultBasePath+testDID+"?versionTime=yesterday", "")
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = get(t, h, DefaultBasePath+testDID+"?versionId=2&versionTime=2021-05-10T17:00:00Z", "")
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("test percent-encoded did", func(t *testing.T) {
+		const webDID = "did:web:example.com%3A8080"
+
+		var resolvedDID string
+
+		registry := &mock.VDRegistry{
+			ResolveCtxFunc: func(_ context.Context, didID string, _ ...vdrapi.DIDMethodOption) (*did.DocResolution,
+				error) {
+				resolvedDID = didID
+
+				return newDocResolution(t, didID), nil
+			},
+		}
+
+		server := httptest.NewServer(New(registry))
+		defer server.Close()
+
+		for _, target := range []string{webDID, "did%3Aweb%3Aexample.com%253A8080"} {
+			resp, err := http.Get(server.URL + DefaultBasePath + target) //nolint:noctx
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Equal(t, webDID, resolvedDID)
+		}
+	})
+
+	t.Run("test concurrent requests do not share options", func(t *testing.T) {
+		const n = 20
+
+		// every request has built its options before any of them is applied
+		var arrived sync.WaitGroup
+
+		arrived.Add(n)
+
+		registry := &mock.VDRegistry{
+			ResolveCtxFunc: func(_ context.Context, didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution,
+				error) {
+				arrived.Done()
+				arrived.Wait()
+
+				didOpts := &vdrapi.DIDMethodOpts{Values: make(map[string]interface{})}
+				for _, opt := range opts {
+					opt(didOpts)
+				}
+
+				docResolution := newDocResolution(t, didID)
+				docResolution.DocumentMetadata.VersionID, _ = didOpts.Values[vdrapi.VersionIDOpt].(string) //nolint:errcheck
+
+				return docResolution, nil
+			},
+		}
+
+		// five options leave spare capacity in the handler's option slice
+		h := New(registry, WithResolveOptions(
+			vdrapi.WithOption("a", 1), vdrapi.WithOption("b", 2), vdrapi.WithOption("c", 3),
+			vdrapi.WithOption("d", 4), vdrapi.WithOption("e", 5)))
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+
+			go func(versionID string) {
+				defer wg.Done()
+
+				rec := get(t, h, DefaultBasePath+testDID+"?versionId="+versionID, "")
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				docResolution, err := did.ParseDocumentResolution(rec.Body.Bytes())
+				require.NoError(t, err)
+				require.Equal(t, versionID, docResolution.DocumentMetadata.VersionID)
+			}(fmt.Sprint(i))
+		}
+
+		wg.Wait()
+	})
+
+	t.Run("test base path and method", func(t *testing.T) {
+		h := New(newRegistry(t, nil), WithBasePath("/resolve"))
+
+		require.Equal(t, http.StatusOK, get(t, h, "/resolve/"+testDID, "").Code)
+		require.Equal(t, http.StatusNotFound, get(t, h, DefaultBasePath+testDID, "").Code)
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/resolve/"+testDID, nil))
+		require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
+
+func TestHTTPBindingInterop(t *testing.T) {
+	const webDID = "did:web:example.com%3A8080:alice"
+
+	resolved := make(map[string]*did.DocResolution)
+
+	registry := vdr.New(vdr.WithVDR(&mock.VDR{
+		AcceptFunc: func(method string, _ ...vdrapi.DIDMethodOption) bool {
+			return method == "example" || method == "web"
+		},
+		ReadFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+			didOpts := &vdrapi.DIDMethodOpts{Values: make(map[string]interface{})}
+			for _, opt := range opts {
+				opt(didOpts)
+			}
+
+			docResolution, ok := resolved[didID]
+			if !ok {
+				return nil, vdrapi.ErrNotFound
+			}
+
+			if versionID, ok := didOpts.Values[vdrapi.VersionIDOpt].(string); ok {
+				docResolution.DocumentMetadata.VersionID = versionID
+			}
+
+			return docResolution, nil
+		},
+	}))
+
+	resolved[testDID] = newDocResolution(t, testDID)
+	resolved[webDID] = newDocResolution(t, webDID)
+
+	deactivated := newDocResolution(t, "did:example:deactivated")
+	deactivated.DocumentMetadata.Deactivated = true
+	resolved["did:example:deactivated"] = deactivated
+
+	server := httptest.NewServer(New(registry))
+	defer server.Close()
+
+	client, err := httpbinding.New(server.URL + strings.TrimSuffix(DefaultBasePath, "/"))
+	require.NoError(t, err)
+
+	t.Run("test resolve", func(t *testing.T) {
+		docResolution, err := client.Read(testDID)
+		require.NoError(t, err)
+		require.Equal(t, testDID, docResolution.DIDDocument.ID)
+
+		docResolution, err = client.Read(webDID)
+		require.NoError(t, err)
+		require.Equal(t, webDID, docResolution.DIDDocument.ID)
+	})
+
+	t.Run("test resolve version", func(t *testing.T) {
+		docResolution, err := client.Read(testDID, vdrapi.WithOption(httpbinding.VersionIDOpt, "7"))
+		require.NoError(t, err)
+		require.Equal(t, testDID, docResolution.DIDDocument.ID)
+		require.Equal(t, "7", resolved[testDID].DocumentMetadata.VersionID)
+	})
+
+	t.Run("test deactivated", func(t *testing.T) {
+		docResolution, err := client.Read("did:example:deactivated")
+		require.NoError(t, err)
+		require.True(t, docResolution.DocumentMetadata.Deactivated)
+	})
+
+	t.Run("test errors", func(t *testing.T) {
+		_, err := client.Read("did:example:unknown")
+		require.ErrorIs(t, err, vdrapi.ErrNotFound)
+
+		_, err = client.Read("did:other:123")
+		require.ErrorIs(t, err, vdrapi.ErrMethodNotSupported)
+	})
+}
+
+func resolutionError(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+
+	var result struct {
+		ResolutionMetadata did.ResolutionMetadata `json:"didResolutionMetadata"`
+	}
+
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+
+	return result.ResolutionMetadata.Error
+}